@@ -13,8 +13,10 @@ var AiPromptType = &struct {
 	ProductDetails postgres.StringExpression
 	Receipt        postgres.StringExpression
 	Nutrition      postgres.StringExpression
+	ShelfTag       postgres.StringExpression
 }{
 	ProductDetails: postgres.NewEnumValue("PRODUCT_DETAILS"),
 	Receipt:        postgres.NewEnumValue("RECEIPT"),
 	Nutrition:      postgres.NewEnumValue("NUTRITION"),
+	ShelfTag:       postgres.NewEnumValue("SHELF_TAG"),
 }
@@ -0,0 +1,22 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package enum
+
+import "github.com/go-jet/jet/v2/postgres"
+
+var JobStatus = &struct {
+	Pending   postgres.StringExpression
+	Running   postgres.StringExpression
+	Succeeded postgres.StringExpression
+	Failed    postgres.StringExpression
+}{
+	Pending:   postgres.NewEnumValue("PENDING"),
+	Running:   postgres.NewEnumValue("RUNNING"),
+	Succeeded: postgres.NewEnumValue("SUCCEEDED"),
+	Failed:    postgres.NewEnumValue("FAILED"),
+}
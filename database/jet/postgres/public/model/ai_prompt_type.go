@@ -15,6 +15,7 @@ const (
 	AiPromptType_ProductDetails AiPromptType = "PRODUCT_DETAILS"
 	AiPromptType_Receipt        AiPromptType = "RECEIPT"
 	AiPromptType_Nutrition      AiPromptType = "NUTRITION"
+	AiPromptType_ShelfTag       AiPromptType = "SHELF_TAG"
 )
 
 func (e *AiPromptType) Scan(value interface{}) error {
@@ -35,6 +36,8 @@ func (e *AiPromptType) Scan(value interface{}) error {
 		*e = AiPromptType_Receipt
 	case "NUTRITION":
 		*e = AiPromptType_Nutrition
+	case "SHELF_TAG":
+		*e = AiPromptType_ShelfTag
 	default:
 		return errors.New("jet: Invalid scan value '" + enumValue + "' for AiPromptType enum")
 	}
@@ -13,11 +13,12 @@ import (
 )
 
 type AuthState struct {
-	ID            uuid.UUID `sql:"primary_key"`
-	LoggedInAt    time.Time
-	UserID        int64
-	IPAddress     *string
-	Platform      UserAuthPlatformType
-	DeviceType    *AuthDeviceType
-	ExpoPushToken *string
+	ID                  uuid.UUID `sql:"primary_key"`
+	LoggedInAt          time.Time
+	UserID              int64
+	IPAddress           *string
+	Platform            UserAuthPlatformType
+	DeviceType          *AuthDeviceType
+	ExpoPushToken       *string
+	TwoFactorVerifiedAt *time.Time
 }
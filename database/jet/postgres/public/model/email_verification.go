@@ -16,4 +16,6 @@ type EmailVerification struct {
 	Code      string
 	UserID    int64
 	CreatedAt time.Time
+	NewEmail  *string
+	Tries     int32
 }
@@ -0,0 +1,50 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package model
+
+import "errors"
+
+type JobStatus string
+
+const (
+	JobStatus_Pending   JobStatus = "PENDING"
+	JobStatus_Running   JobStatus = "RUNNING"
+	JobStatus_Succeeded JobStatus = "SUCCEEDED"
+	JobStatus_Failed    JobStatus = "FAILED"
+)
+
+func (e *JobStatus) Scan(value interface{}) error {
+	var enumValue string
+	switch val := value.(type) {
+	case string:
+		enumValue = val
+	case []byte:
+		enumValue = string(val)
+	default:
+		return errors.New("jet: Invalid scan value for AllTypesEnum enum. Enum value has to be of type string or []byte")
+	}
+
+	switch enumValue {
+	case "PENDING":
+		*e = JobStatus_Pending
+	case "RUNNING":
+		*e = JobStatus_Running
+	case "SUCCEEDED":
+		*e = JobStatus_Succeeded
+	case "FAILED":
+		*e = JobStatus_Failed
+	default:
+		return errors.New("jet: Invalid scan value '" + enumValue + "' for JobStatus enum")
+	}
+
+	return nil
+}
+
+func (e JobStatus) String() string {
+	return string(e)
+}
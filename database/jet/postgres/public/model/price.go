@@ -12,21 +12,41 @@ import (
 )
 
 type Price struct {
-	ID            int64 `sql:"primary_key"`
-	Amount        float64
-	CurrencyCode  string
-	ProductID     int64
-	StoreID       int64
-	BranchID      int64
-	StockID       int64
-	CreatedByID   *int64
-	UpdatedByID   *int64
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	Sale          bool
-	OriginalPrice *float64
-	Condition     *string
-	UnitType      string
-	ImageID       *string
-	ExpiresAt     *time.Time
+	ID                    int64 `sql:"primary_key"`
+	Amount                float64
+	CurrencyCode          string
+	ProductID             int64
+	StoreID               int64
+	BranchID              int64
+	StockID               int64
+	CreatedByID           *int64
+	UpdatedByID           *int64
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+	Sale                  bool
+	OriginalPrice         *float64
+	Condition             *string
+	UnitType              string
+	ImageID               *string
+	ExpiresAt             *time.Time
+	Official              bool
+	ImageURL              *string
+	OcrAmount             *float64
+	ReviewStatus          *PriceReviewStatus
+	ReviewReason          *string
+	ReviewedByID          *int64
+	ReviewedAt            *time.Time
+	DealType              *PriceDealType
+	DealQuantity          *int32
+	DealPrice             *float64
+	DealFreeQuantity      *int32
+	DealDiscountPercent   *float64
+	DealDiscountAmount    *float64
+	RequiresMembership    bool
+	PurchaseLimit         *int32
+	MinimumQuantity       *int32
+	MinimumPurchaseAmount *float64
+	EffectivePrice        *float64
+	MemberOnly            bool
+	LoyaltyProgramID      *int64
 }
@@ -30,4 +30,5 @@ type Product struct {
 	QuantityValue int32
 	QuantityType  string
 	SearchVector  *string
+	Gtin          *string
 }
@@ -21,4 +21,12 @@ type ProductBilling struct {
 	NewData         *string
 	OldData         *string
 	PaidAt          *time.Time
+	CurrencyCode    string
+	PayoutID        *int64
+	RateID          *int64
+	BranchID        *int64
+	Status          ProductBillingStatus
+	StatusReason    *string
+	ReviewedByID    *int64
+	ReviewedAt      *time.Time
 }
@@ -7,8 +7,21 @@
 
 package model
 
+import (
+	"time"
+)
+
 type ProductBillingRate struct {
-	Type         ProductBillingType `sql:"primary_key"`
-	Rate         float64
-	CurrencyCode string
+	Type                   ProductBillingType
+	Rate                   float64
+	CurrencyCode           string
+	ID                     int64 `sql:"primary_key"`
+	CategoryID             *int64
+	CountryCode            *CountryCodeAlpha2
+	AdministrativeDivision *string
+	EffectiveFrom          time.Time
+	EffectiveTo            *time.Time
+	CreatedByID            *int64
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
 }
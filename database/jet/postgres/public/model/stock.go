@@ -12,13 +12,14 @@ import (
 )
 
 type Stock struct {
-	ID            int64 `sql:"primary_key"`
-	ProductID     int64
-	StoreID       int64
-	BranchID      int64
-	CreatedByID   *int64
-	UpdatedByID   *int64
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	LatestPriceID *int64
+	ID                  int64 `sql:"primary_key"`
+	ProductID           int64
+	StoreID             int64
+	BranchID            int64
+	CreatedByID         *int64
+	UpdatedByID         *int64
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	LatestPriceID       *int64
+	LatestMemberPriceID *int64
 }
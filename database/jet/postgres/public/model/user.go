@@ -12,16 +12,23 @@ import (
 )
 
 type User struct {
-	ID          int64 `sql:"primary_key"`
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Email       string
-	PhoneNumber *string
-	Name        string
-	Password    *string
-	Avatar      *string
-	BirthDate   *time.Time
-	Active      bool
-	Role        UserRoleType
-	AddressID   *int64
+	ID                  int64 `sql:"primary_key"`
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	Email               string
+	PhoneNumber         *string
+	Name                string
+	Password            *string
+	Avatar              *string
+	BirthDate           *time.Time
+	Active              bool
+	Role                UserRoleType
+	AddressID           *int64
+	TwoFactorSecret     *string
+	TwoFactorEnabled    bool
+	DeletionRequestedAt *time.Time
+	DeletionScheduledAt *time.Time
+	ReputationScore     float64
+	ReputationUpdatedAt *time.Time
+	TwoFactorLastStep   *int64
 }
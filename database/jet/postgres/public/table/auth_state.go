@@ -17,13 +17,14 @@ type authStateTable struct {
 	postgres.Table
 
 	// Columns
-	ID            postgres.ColumnString
-	LoggedInAt    postgres.ColumnTimestampz
-	UserID        postgres.ColumnInteger
-	IPAddress     postgres.ColumnString
-	Platform      postgres.ColumnString
-	DeviceType    postgres.ColumnString
-	ExpoPushToken postgres.ColumnString
+	ID                  postgres.ColumnString
+	LoggedInAt          postgres.ColumnTimestampz
+	UserID              postgres.ColumnInteger
+	IPAddress           postgres.ColumnString
+	Platform            postgres.ColumnString
+	DeviceType          postgres.ColumnString
+	ExpoPushToken       postgres.ColumnString
+	TwoFactorVerifiedAt postgres.ColumnTimestampz
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -64,28 +65,30 @@ func newAuthStateTable(schemaName, tableName, alias string) *AuthStateTable {
 
 func newAuthStateTableImpl(schemaName, tableName, alias string) authStateTable {
 	var (
-		IDColumn            = postgres.StringColumn("id")
-		LoggedInAtColumn    = postgres.TimestampzColumn("logged_in_at")
-		UserIDColumn        = postgres.IntegerColumn("user_id")
-		IPAddressColumn     = postgres.StringColumn("ip_address")
-		PlatformColumn      = postgres.StringColumn("platform")
-		DeviceTypeColumn    = postgres.StringColumn("device_type")
-		ExpoPushTokenColumn = postgres.StringColumn("expo_push_token")
-		allColumns          = postgres.ColumnList{IDColumn, LoggedInAtColumn, UserIDColumn, IPAddressColumn, PlatformColumn, DeviceTypeColumn, ExpoPushTokenColumn}
-		mutableColumns      = postgres.ColumnList{LoggedInAtColumn, UserIDColumn, IPAddressColumn, PlatformColumn, DeviceTypeColumn, ExpoPushTokenColumn}
+		IDColumn                  = postgres.StringColumn("id")
+		LoggedInAtColumn          = postgres.TimestampzColumn("logged_in_at")
+		UserIDColumn              = postgres.IntegerColumn("user_id")
+		IPAddressColumn           = postgres.StringColumn("ip_address")
+		PlatformColumn            = postgres.StringColumn("platform")
+		DeviceTypeColumn          = postgres.StringColumn("device_type")
+		ExpoPushTokenColumn       = postgres.StringColumn("expo_push_token")
+		TwoFactorVerifiedAtColumn = postgres.TimestampzColumn("two_factor_verified_at")
+		allColumns                = postgres.ColumnList{IDColumn, LoggedInAtColumn, UserIDColumn, IPAddressColumn, PlatformColumn, DeviceTypeColumn, ExpoPushTokenColumn, TwoFactorVerifiedAtColumn}
+		mutableColumns            = postgres.ColumnList{LoggedInAtColumn, UserIDColumn, IPAddressColumn, PlatformColumn, DeviceTypeColumn, ExpoPushTokenColumn, TwoFactorVerifiedAtColumn}
 	)
 
 	return authStateTable{
 		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
 
 		//Columns
-		ID:            IDColumn,
-		LoggedInAt:    LoggedInAtColumn,
-		UserID:        UserIDColumn,
-		IPAddress:     IPAddressColumn,
-		Platform:      PlatformColumn,
-		DeviceType:    DeviceTypeColumn,
-		ExpoPushToken: ExpoPushTokenColumn,
+		ID:                  IDColumn,
+		LoggedInAt:          LoggedInAtColumn,
+		UserID:              UserIDColumn,
+		IPAddress:           IPAddressColumn,
+		Platform:            PlatformColumn,
+		DeviceType:          DeviceTypeColumn,
+		ExpoPushToken:       ExpoPushTokenColumn,
+		TwoFactorVerifiedAt: TwoFactorVerifiedAtColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
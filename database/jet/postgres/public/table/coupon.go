@@ -0,0 +1,144 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/postgres"
+)
+
+var Coupon = newCouponTable("public", "coupon", "")
+
+type couponTable struct {
+	postgres.Table
+
+	// Columns
+	ID              postgres.ColumnInteger
+	Type            postgres.ColumnString
+	StoreID         postgres.ColumnInteger
+	Scope           postgres.ColumnString
+	ProductID       postgres.ColumnInteger
+	Brand           postgres.ColumnString
+	CategoryID      postgres.ColumnInteger
+	Title           postgres.ColumnString
+	Description     postgres.ColumnString
+	Code            postgres.ColumnString
+	DiscountAmount  postgres.ColumnFloat
+	DiscountPercent postgres.ColumnFloat
+	MinimumQuantity postgres.ColumnInteger
+	StartsAt        postgres.ColumnTimestampz
+	ExpiresAt       postgres.ColumnTimestampz
+	Stackable       postgres.ColumnBool
+	Status          postgres.ColumnString
+	ReviewReason    postgres.ColumnString
+	ReviewedByID    postgres.ColumnInteger
+	ReviewedAt      postgres.ColumnTimestampz
+	CreatedByID     postgres.ColumnInteger
+	UpdatedByID     postgres.ColumnInteger
+	CreatedAt       postgres.ColumnTimestampz
+	UpdatedAt       postgres.ColumnTimestampz
+
+	AllColumns     postgres.ColumnList
+	MutableColumns postgres.ColumnList
+}
+
+type CouponTable struct {
+	couponTable
+
+	EXCLUDED couponTable
+}
+
+// AS creates new CouponTable with assigned alias
+func (a CouponTable) AS(alias string) *CouponTable {
+	return newCouponTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new CouponTable with assigned schema name
+func (a CouponTable) FromSchema(schemaName string) *CouponTable {
+	return newCouponTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new CouponTable with assigned table prefix
+func (a CouponTable) WithPrefix(prefix string) *CouponTable {
+	return newCouponTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new CouponTable with assigned table suffix
+func (a CouponTable) WithSuffix(suffix string) *CouponTable {
+	return newCouponTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newCouponTable(schemaName, tableName, alias string) *CouponTable {
+	return &CouponTable{
+		couponTable: newCouponTableImpl(schemaName, tableName, alias),
+		EXCLUDED:    newCouponTableImpl("", "excluded", ""),
+	}
+}
+
+func newCouponTableImpl(schemaName, tableName, alias string) couponTable {
+	var (
+		IDColumn              = postgres.IntegerColumn("id")
+		TypeColumn            = postgres.StringColumn("type")
+		StoreIDColumn         = postgres.IntegerColumn("store_id")
+		ScopeColumn           = postgres.StringColumn("scope")
+		ProductIDColumn       = postgres.IntegerColumn("product_id")
+		BrandColumn           = postgres.StringColumn("brand")
+		CategoryIDColumn      = postgres.IntegerColumn("category_id")
+		TitleColumn           = postgres.StringColumn("title")
+		DescriptionColumn     = postgres.StringColumn("description")
+		CodeColumn            = postgres.StringColumn("code")
+		DiscountAmountColumn  = postgres.FloatColumn("discount_amount")
+		DiscountPercentColumn = postgres.FloatColumn("discount_percent")
+		MinimumQuantityColumn = postgres.IntegerColumn("minimum_quantity")
+		StartsAtColumn        = postgres.TimestampzColumn("starts_at")
+		ExpiresAtColumn       = postgres.TimestampzColumn("expires_at")
+		StackableColumn       = postgres.BoolColumn("stackable")
+		StatusColumn          = postgres.StringColumn("status")
+		ReviewReasonColumn    = postgres.StringColumn("review_reason")
+		ReviewedByIDColumn    = postgres.IntegerColumn("reviewed_by_id")
+		ReviewedAtColumn      = postgres.TimestampzColumn("reviewed_at")
+		CreatedByIDColumn     = postgres.IntegerColumn("created_by_id")
+		UpdatedByIDColumn     = postgres.IntegerColumn("updated_by_id")
+		CreatedAtColumn       = postgres.TimestampzColumn("created_at")
+		UpdatedAtColumn       = postgres.TimestampzColumn("updated_at")
+		allColumns            = postgres.ColumnList{IDColumn, TypeColumn, StoreIDColumn, ScopeColumn, ProductIDColumn, BrandColumn, CategoryIDColumn, TitleColumn, DescriptionColumn, CodeColumn, DiscountAmountColumn, DiscountPercentColumn, MinimumQuantityColumn, StartsAtColumn, ExpiresAtColumn, StackableColumn, StatusColumn, ReviewReasonColumn, ReviewedByIDColumn, ReviewedAtColumn, CreatedByIDColumn, UpdatedByIDColumn, CreatedAtColumn, UpdatedAtColumn}
+		mutableColumns        = postgres.ColumnList{TypeColumn, StoreIDColumn, ScopeColumn, ProductIDColumn, BrandColumn, CategoryIDColumn, TitleColumn, DescriptionColumn, CodeColumn, DiscountAmountColumn, DiscountPercentColumn, MinimumQuantityColumn, StartsAtColumn, ExpiresAtColumn, StackableColumn, StatusColumn, ReviewReasonColumn, ReviewedByIDColumn, ReviewedAtColumn, CreatedByIDColumn, UpdatedByIDColumn, CreatedAtColumn, UpdatedAtColumn}
+	)
+
+	return couponTable{
+		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ID:              IDColumn,
+		Type:            TypeColumn,
+		StoreID:         StoreIDColumn,
+		Scope:           ScopeColumn,
+		ProductID:       ProductIDColumn,
+		Brand:           BrandColumn,
+		CategoryID:      CategoryIDColumn,
+		Title:           TitleColumn,
+		Description:     DescriptionColumn,
+		Code:            CodeColumn,
+		DiscountAmount:  DiscountAmountColumn,
+		DiscountPercent: DiscountPercentColumn,
+		MinimumQuantity: MinimumQuantityColumn,
+		StartsAt:        StartsAtColumn,
+		ExpiresAt:       ExpiresAtColumn,
+		Stackable:       StackableColumn,
+		Status:          StatusColumn,
+		ReviewReason:    ReviewReasonColumn,
+		ReviewedByID:    ReviewedByIDColumn,
+		ReviewedAt:      ReviewedAtColumn,
+		CreatedByID:     CreatedByIDColumn,
+		UpdatedByID:     UpdatedByIDColumn,
+		CreatedAt:       CreatedAtColumn,
+		UpdatedAt:       UpdatedAtColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
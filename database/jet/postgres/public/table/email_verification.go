@@ -21,6 +21,8 @@ type emailVerificationTable struct {
 	Code      postgres.ColumnString
 	UserID    postgres.ColumnInteger
 	CreatedAt postgres.ColumnTimestampz
+	NewEmail  postgres.ColumnString
+	Tries     postgres.ColumnInteger
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -65,8 +67,10 @@ func newEmailVerificationTableImpl(schemaName, tableName, alias string) emailVer
 		CodeColumn      = postgres.StringColumn("code")
 		UserIDColumn    = postgres.IntegerColumn("user_id")
 		CreatedAtColumn = postgres.TimestampzColumn("created_at")
-		allColumns      = postgres.ColumnList{IDColumn, CodeColumn, UserIDColumn, CreatedAtColumn}
-		mutableColumns  = postgres.ColumnList{CodeColumn, UserIDColumn, CreatedAtColumn}
+		NewEmailColumn  = postgres.StringColumn("new_email")
+		TriesColumn     = postgres.IntegerColumn("tries")
+		allColumns      = postgres.ColumnList{IDColumn, CodeColumn, UserIDColumn, CreatedAtColumn, NewEmailColumn, TriesColumn}
+		mutableColumns  = postgres.ColumnList{CodeColumn, UserIDColumn, CreatedAtColumn, NewEmailColumn, TriesColumn}
 	)
 
 	return emailVerificationTable{
@@ -77,6 +81,8 @@ func newEmailVerificationTableImpl(schemaName, tableName, alias string) emailVer
 		Code:      CodeColumn,
 		UserID:    UserIDColumn,
 		CreatedAt: CreatedAtColumn,
+		NewEmail:  NewEmailColumn,
+		Tries:     TriesColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
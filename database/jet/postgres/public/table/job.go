@@ -0,0 +1,114 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/postgres"
+)
+
+var Job = newJobTable("public", "job", "")
+
+type jobTable struct {
+	postgres.Table
+
+	// Columns
+	ID          postgres.ColumnInteger
+	Type        postgres.ColumnString
+	Payload     postgres.ColumnString
+	Status      postgres.ColumnString
+	UniqueKey   postgres.ColumnString
+	ScheduleID  postgres.ColumnInteger
+	Attempts    postgres.ColumnInteger
+	MaxAttempts postgres.ColumnInteger
+	RunAt       postgres.ColumnTimestampz
+	LockedAt    postgres.ColumnTimestampz
+	LastError   postgres.ColumnString
+	CompletedAt postgres.ColumnTimestampz
+	CreatedAt   postgres.ColumnTimestampz
+	UpdatedAt   postgres.ColumnTimestampz
+
+	AllColumns     postgres.ColumnList
+	MutableColumns postgres.ColumnList
+}
+
+type JobTable struct {
+	jobTable
+
+	EXCLUDED jobTable
+}
+
+// AS creates new JobTable with assigned alias
+func (a JobTable) AS(alias string) *JobTable {
+	return newJobTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new JobTable with assigned schema name
+func (a JobTable) FromSchema(schemaName string) *JobTable {
+	return newJobTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new JobTable with assigned table prefix
+func (a JobTable) WithPrefix(prefix string) *JobTable {
+	return newJobTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new JobTable with assigned table suffix
+func (a JobTable) WithSuffix(suffix string) *JobTable {
+	return newJobTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newJobTable(schemaName, tableName, alias string) *JobTable {
+	return &JobTable{
+		jobTable: newJobTableImpl(schemaName, tableName, alias),
+		EXCLUDED: newJobTableImpl("", "excluded", ""),
+	}
+}
+
+func newJobTableImpl(schemaName, tableName, alias string) jobTable {
+	var (
+		IDColumn          = postgres.IntegerColumn("id")
+		TypeColumn        = postgres.StringColumn("type")
+		PayloadColumn     = postgres.StringColumn("payload")
+		StatusColumn      = postgres.StringColumn("status")
+		UniqueKeyColumn   = postgres.StringColumn("unique_key")
+		ScheduleIDColumn  = postgres.IntegerColumn("schedule_id")
+		AttemptsColumn    = postgres.IntegerColumn("attempts")
+		MaxAttemptsColumn = postgres.IntegerColumn("max_attempts")
+		RunAtColumn       = postgres.TimestampzColumn("run_at")
+		LockedAtColumn    = postgres.TimestampzColumn("locked_at")
+		LastErrorColumn   = postgres.StringColumn("last_error")
+		CompletedAtColumn = postgres.TimestampzColumn("completed_at")
+		CreatedAtColumn   = postgres.TimestampzColumn("created_at")
+		UpdatedAtColumn   = postgres.TimestampzColumn("updated_at")
+		allColumns        = postgres.ColumnList{IDColumn, TypeColumn, PayloadColumn, StatusColumn, UniqueKeyColumn, ScheduleIDColumn, AttemptsColumn, MaxAttemptsColumn, RunAtColumn, LockedAtColumn, LastErrorColumn, CompletedAtColumn, CreatedAtColumn, UpdatedAtColumn}
+		mutableColumns    = postgres.ColumnList{TypeColumn, PayloadColumn, StatusColumn, UniqueKeyColumn, ScheduleIDColumn, AttemptsColumn, MaxAttemptsColumn, RunAtColumn, LockedAtColumn, LastErrorColumn, CompletedAtColumn, CreatedAtColumn, UpdatedAtColumn}
+	)
+
+	return jobTable{
+		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ID:          IDColumn,
+		Type:        TypeColumn,
+		Payload:     PayloadColumn,
+		Status:      StatusColumn,
+		UniqueKey:   UniqueKeyColumn,
+		ScheduleID:  ScheduleIDColumn,
+		Attempts:    AttemptsColumn,
+		MaxAttempts: MaxAttemptsColumn,
+		RunAt:       RunAtColumn,
+		LockedAt:    LockedAtColumn,
+		LastError:   LastErrorColumn,
+		CompletedAt: CompletedAtColumn,
+		CreatedAt:   CreatedAtColumn,
+		UpdatedAt:   UpdatedAtColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
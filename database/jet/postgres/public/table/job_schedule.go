@@ -0,0 +1,102 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/postgres"
+)
+
+var JobSchedule = newJobScheduleTable("public", "job_schedule", "")
+
+type jobScheduleTable struct {
+	postgres.Table
+
+	// Columns
+	ID             postgres.ColumnInteger
+	Name           postgres.ColumnString
+	Type           postgres.ColumnString
+	CronExpression postgres.ColumnString
+	Payload        postgres.ColumnString
+	Enabled        postgres.ColumnBool
+	NextRunAt      postgres.ColumnTimestampz
+	LastRunAt      postgres.ColumnTimestampz
+	CreatedAt      postgres.ColumnTimestampz
+	UpdatedAt      postgres.ColumnTimestampz
+
+	AllColumns     postgres.ColumnList
+	MutableColumns postgres.ColumnList
+}
+
+type JobScheduleTable struct {
+	jobScheduleTable
+
+	EXCLUDED jobScheduleTable
+}
+
+// AS creates new JobScheduleTable with assigned alias
+func (a JobScheduleTable) AS(alias string) *JobScheduleTable {
+	return newJobScheduleTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new JobScheduleTable with assigned schema name
+func (a JobScheduleTable) FromSchema(schemaName string) *JobScheduleTable {
+	return newJobScheduleTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new JobScheduleTable with assigned table prefix
+func (a JobScheduleTable) WithPrefix(prefix string) *JobScheduleTable {
+	return newJobScheduleTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new JobScheduleTable with assigned table suffix
+func (a JobScheduleTable) WithSuffix(suffix string) *JobScheduleTable {
+	return newJobScheduleTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newJobScheduleTable(schemaName, tableName, alias string) *JobScheduleTable {
+	return &JobScheduleTable{
+		jobScheduleTable: newJobScheduleTableImpl(schemaName, tableName, alias),
+		EXCLUDED:         newJobScheduleTableImpl("", "excluded", ""),
+	}
+}
+
+func newJobScheduleTableImpl(schemaName, tableName, alias string) jobScheduleTable {
+	var (
+		IDColumn             = postgres.IntegerColumn("id")
+		NameColumn           = postgres.StringColumn("name")
+		TypeColumn           = postgres.StringColumn("type")
+		CronExpressionColumn = postgres.StringColumn("cron_expression")
+		PayloadColumn        = postgres.StringColumn("payload")
+		EnabledColumn        = postgres.BoolColumn("enabled")
+		NextRunAtColumn      = postgres.TimestampzColumn("next_run_at")
+		LastRunAtColumn      = postgres.TimestampzColumn("last_run_at")
+		CreatedAtColumn      = postgres.TimestampzColumn("created_at")
+		UpdatedAtColumn      = postgres.TimestampzColumn("updated_at")
+		allColumns           = postgres.ColumnList{IDColumn, NameColumn, TypeColumn, CronExpressionColumn, PayloadColumn, EnabledColumn, NextRunAtColumn, LastRunAtColumn, CreatedAtColumn, UpdatedAtColumn}
+		mutableColumns       = postgres.ColumnList{NameColumn, TypeColumn, CronExpressionColumn, PayloadColumn, EnabledColumn, NextRunAtColumn, LastRunAtColumn, CreatedAtColumn, UpdatedAtColumn}
+	)
+
+	return jobScheduleTable{
+		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ID:             IDColumn,
+		Name:           NameColumn,
+		Type:           TypeColumn,
+		CronExpression: CronExpressionColumn,
+		Payload:        PayloadColumn,
+		Enabled:        EnabledColumn,
+		NextRunAt:      NextRunAtColumn,
+		LastRunAt:      LastRunAtColumn,
+		CreatedAt:      CreatedAtColumn,
+		UpdatedAt:      UpdatedAtColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
@@ -17,23 +17,43 @@ type priceTable struct {
 	postgres.Table
 
 	// Columns
-	ID            postgres.ColumnInteger
-	Amount        postgres.ColumnFloat
-	CurrencyCode  postgres.ColumnString
-	ProductID     postgres.ColumnInteger
-	StoreID       postgres.ColumnInteger
-	BranchID      postgres.ColumnInteger
-	StockID       postgres.ColumnInteger
-	CreatedByID   postgres.ColumnInteger
-	UpdatedByID   postgres.ColumnInteger
-	CreatedAt     postgres.ColumnTimestampz
-	UpdatedAt     postgres.ColumnTimestampz
-	Sale          postgres.ColumnBool
-	OriginalPrice postgres.ColumnFloat
-	Condition     postgres.ColumnString
-	UnitType      postgres.ColumnString
-	ImageID       postgres.ColumnString
-	ExpiresAt     postgres.ColumnTimestampz
+	ID                    postgres.ColumnInteger
+	Amount                postgres.ColumnFloat
+	CurrencyCode          postgres.ColumnString
+	ProductID             postgres.ColumnInteger
+	StoreID               postgres.ColumnInteger
+	BranchID              postgres.ColumnInteger
+	StockID               postgres.ColumnInteger
+	CreatedByID           postgres.ColumnInteger
+	UpdatedByID           postgres.ColumnInteger
+	CreatedAt             postgres.ColumnTimestampz
+	UpdatedAt             postgres.ColumnTimestampz
+	Sale                  postgres.ColumnBool
+	OriginalPrice         postgres.ColumnFloat
+	Condition             postgres.ColumnString
+	UnitType              postgres.ColumnString
+	ImageID               postgres.ColumnString
+	ExpiresAt             postgres.ColumnTimestampz
+	Official              postgres.ColumnBool
+	ImageURL              postgres.ColumnString
+	OcrAmount             postgres.ColumnFloat
+	ReviewStatus          postgres.ColumnString
+	ReviewReason          postgres.ColumnString
+	ReviewedByID          postgres.ColumnInteger
+	ReviewedAt            postgres.ColumnTimestampz
+	DealType              postgres.ColumnString
+	DealQuantity          postgres.ColumnInteger
+	DealPrice             postgres.ColumnFloat
+	DealFreeQuantity      postgres.ColumnInteger
+	DealDiscountPercent   postgres.ColumnFloat
+	DealDiscountAmount    postgres.ColumnFloat
+	RequiresMembership    postgres.ColumnBool
+	PurchaseLimit         postgres.ColumnInteger
+	MinimumQuantity       postgres.ColumnInteger
+	MinimumPurchaseAmount postgres.ColumnFloat
+	EffectivePrice        postgres.ColumnFloat
+	MemberOnly            postgres.ColumnBool
+	LoyaltyProgramID      postgres.ColumnInteger
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -74,48 +94,88 @@ func newPriceTable(schemaName, tableName, alias string) *PriceTable {
 
 func newPriceTableImpl(schemaName, tableName, alias string) priceTable {
 	var (
-		IDColumn            = postgres.IntegerColumn("id")
-		AmountColumn        = postgres.FloatColumn("amount")
-		CurrencyCodeColumn  = postgres.StringColumn("currency_code")
-		ProductIDColumn     = postgres.IntegerColumn("product_id")
-		StoreIDColumn       = postgres.IntegerColumn("store_id")
-		BranchIDColumn      = postgres.IntegerColumn("branch_id")
-		StockIDColumn       = postgres.IntegerColumn("stock_id")
-		CreatedByIDColumn   = postgres.IntegerColumn("created_by_id")
-		UpdatedByIDColumn   = postgres.IntegerColumn("updated_by_id")
-		CreatedAtColumn     = postgres.TimestampzColumn("created_at")
-		UpdatedAtColumn     = postgres.TimestampzColumn("updated_at")
-		SaleColumn          = postgres.BoolColumn("sale")
-		OriginalPriceColumn = postgres.FloatColumn("original_price")
-		ConditionColumn     = postgres.StringColumn("condition")
-		UnitTypeColumn      = postgres.StringColumn("unit_type")
-		ImageIDColumn       = postgres.StringColumn("image_id")
-		ExpiresAtColumn     = postgres.TimestampzColumn("expires_at")
-		allColumns          = postgres.ColumnList{IDColumn, AmountColumn, CurrencyCodeColumn, ProductIDColumn, StoreIDColumn, BranchIDColumn, StockIDColumn, CreatedByIDColumn, UpdatedByIDColumn, CreatedAtColumn, UpdatedAtColumn, SaleColumn, OriginalPriceColumn, ConditionColumn, UnitTypeColumn, ImageIDColumn, ExpiresAtColumn}
-		mutableColumns      = postgres.ColumnList{AmountColumn, CurrencyCodeColumn, ProductIDColumn, StoreIDColumn, BranchIDColumn, StockIDColumn, CreatedByIDColumn, UpdatedByIDColumn, CreatedAtColumn, UpdatedAtColumn, SaleColumn, OriginalPriceColumn, ConditionColumn, UnitTypeColumn, ImageIDColumn, ExpiresAtColumn}
+		IDColumn                    = postgres.IntegerColumn("id")
+		AmountColumn                = postgres.FloatColumn("amount")
+		CurrencyCodeColumn          = postgres.StringColumn("currency_code")
+		ProductIDColumn             = postgres.IntegerColumn("product_id")
+		StoreIDColumn               = postgres.IntegerColumn("store_id")
+		BranchIDColumn              = postgres.IntegerColumn("branch_id")
+		StockIDColumn               = postgres.IntegerColumn("stock_id")
+		CreatedByIDColumn           = postgres.IntegerColumn("created_by_id")
+		UpdatedByIDColumn           = postgres.IntegerColumn("updated_by_id")
+		CreatedAtColumn             = postgres.TimestampzColumn("created_at")
+		UpdatedAtColumn             = postgres.TimestampzColumn("updated_at")
+		SaleColumn                  = postgres.BoolColumn("sale")
+		OriginalPriceColumn         = postgres.FloatColumn("original_price")
+		ConditionColumn             = postgres.StringColumn("condition")
+		UnitTypeColumn              = postgres.StringColumn("unit_type")
+		ImageIDColumn               = postgres.StringColumn("image_id")
+		ExpiresAtColumn             = postgres.TimestampzColumn("expires_at")
+		OfficialColumn              = postgres.BoolColumn("official")
+		ImageURLColumn              = postgres.StringColumn("image_url")
+		OcrAmountColumn             = postgres.FloatColumn("ocr_amount")
+		ReviewStatusColumn          = postgres.StringColumn("review_status")
+		ReviewReasonColumn          = postgres.StringColumn("review_reason")
+		ReviewedByIDColumn          = postgres.IntegerColumn("reviewed_by_id")
+		ReviewedAtColumn            = postgres.TimestampzColumn("reviewed_at")
+		DealTypeColumn              = postgres.StringColumn("deal_type")
+		DealQuantityColumn          = postgres.IntegerColumn("deal_quantity")
+		DealPriceColumn             = postgres.FloatColumn("deal_price")
+		DealFreeQuantityColumn      = postgres.IntegerColumn("deal_free_quantity")
+		DealDiscountPercentColumn   = postgres.FloatColumn("deal_discount_percent")
+		DealDiscountAmountColumn    = postgres.FloatColumn("deal_discount_amount")
+		RequiresMembershipColumn    = postgres.BoolColumn("requires_membership")
+		PurchaseLimitColumn         = postgres.IntegerColumn("purchase_limit")
+		MinimumQuantityColumn       = postgres.IntegerColumn("minimum_quantity")
+		MinimumPurchaseAmountColumn = postgres.FloatColumn("minimum_purchase_amount")
+		EffectivePriceColumn        = postgres.FloatColumn("effective_price")
+		MemberOnlyColumn            = postgres.BoolColumn("member_only")
+		LoyaltyProgramIDColumn      = postgres.IntegerColumn("loyalty_program_id")
+		allColumns                  = postgres.ColumnList{IDColumn, AmountColumn, CurrencyCodeColumn, ProductIDColumn, StoreIDColumn, BranchIDColumn, StockIDColumn, CreatedByIDColumn, UpdatedByIDColumn, CreatedAtColumn, UpdatedAtColumn, SaleColumn, OriginalPriceColumn, ConditionColumn, UnitTypeColumn, ImageIDColumn, ExpiresAtColumn, OfficialColumn, ImageURLColumn, OcrAmountColumn, ReviewStatusColumn, ReviewReasonColumn, ReviewedByIDColumn, ReviewedAtColumn, DealTypeColumn, DealQuantityColumn, DealPriceColumn, DealFreeQuantityColumn, DealDiscountPercentColumn, DealDiscountAmountColumn, RequiresMembershipColumn, PurchaseLimitColumn, MinimumQuantityColumn, MinimumPurchaseAmountColumn, EffectivePriceColumn, MemberOnlyColumn, LoyaltyProgramIDColumn}
+		mutableColumns              = postgres.ColumnList{AmountColumn, CurrencyCodeColumn, ProductIDColumn, StoreIDColumn, BranchIDColumn, StockIDColumn, CreatedByIDColumn, UpdatedByIDColumn, CreatedAtColumn, UpdatedAtColumn, SaleColumn, OriginalPriceColumn, ConditionColumn, UnitTypeColumn, ImageIDColumn, ExpiresAtColumn, OfficialColumn, ImageURLColumn, OcrAmountColumn, ReviewStatusColumn, ReviewReasonColumn, ReviewedByIDColumn, ReviewedAtColumn, DealTypeColumn, DealQuantityColumn, DealPriceColumn, DealFreeQuantityColumn, DealDiscountPercentColumn, DealDiscountAmountColumn, RequiresMembershipColumn, PurchaseLimitColumn, MinimumQuantityColumn, MinimumPurchaseAmountColumn, EffectivePriceColumn, MemberOnlyColumn, LoyaltyProgramIDColumn}
 	)
 
 	return priceTable{
 		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
 
 		//Columns
-		ID:            IDColumn,
-		Amount:        AmountColumn,
-		CurrencyCode:  CurrencyCodeColumn,
-		ProductID:     ProductIDColumn,
-		StoreID:       StoreIDColumn,
-		BranchID:      BranchIDColumn,
-		StockID:       StockIDColumn,
-		CreatedByID:   CreatedByIDColumn,
-		UpdatedByID:   UpdatedByIDColumn,
-		CreatedAt:     CreatedAtColumn,
-		UpdatedAt:     UpdatedAtColumn,
-		Sale:          SaleColumn,
-		OriginalPrice: OriginalPriceColumn,
-		Condition:     ConditionColumn,
-		UnitType:      UnitTypeColumn,
-		ImageID:       ImageIDColumn,
-		ExpiresAt:     ExpiresAtColumn,
+		ID:                    IDColumn,
+		Amount:                AmountColumn,
+		CurrencyCode:          CurrencyCodeColumn,
+		ProductID:             ProductIDColumn,
+		StoreID:               StoreIDColumn,
+		BranchID:              BranchIDColumn,
+		StockID:               StockIDColumn,
+		CreatedByID:           CreatedByIDColumn,
+		UpdatedByID:           UpdatedByIDColumn,
+		CreatedAt:             CreatedAtColumn,
+		UpdatedAt:             UpdatedAtColumn,
+		Sale:                  SaleColumn,
+		OriginalPrice:         OriginalPriceColumn,
+		Condition:             ConditionColumn,
+		UnitType:              UnitTypeColumn,
+		ImageID:               ImageIDColumn,
+		ExpiresAt:             ExpiresAtColumn,
+		Official:              OfficialColumn,
+		ImageURL:              ImageURLColumn,
+		OcrAmount:             OcrAmountColumn,
+		ReviewStatus:          ReviewStatusColumn,
+		ReviewReason:          ReviewReasonColumn,
+		ReviewedByID:          ReviewedByIDColumn,
+		ReviewedAt:            ReviewedAtColumn,
+		DealType:              DealTypeColumn,
+		DealQuantity:          DealQuantityColumn,
+		DealPrice:             DealPriceColumn,
+		DealFreeQuantity:      DealFreeQuantityColumn,
+		DealDiscountPercent:   DealDiscountPercentColumn,
+		DealDiscountAmount:    DealDiscountAmountColumn,
+		RequiresMembership:    RequiresMembershipColumn,
+		PurchaseLimit:         PurchaseLimitColumn,
+		MinimumQuantity:       MinimumQuantityColumn,
+		MinimumPurchaseAmount: MinimumPurchaseAmountColumn,
+		EffectivePrice:        EffectivePriceColumn,
+		MemberOnly:            MemberOnlyColumn,
+		LoyaltyProgramID:      LoyaltyProgramIDColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
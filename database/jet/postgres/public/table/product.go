@@ -35,6 +35,7 @@ type productTable struct {
 	QuantityValue postgres.ColumnInteger
 	QuantityType  postgres.ColumnString
 	SearchVector  postgres.ColumnString
+	Gtin          postgres.ColumnString
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -93,8 +94,9 @@ func newProductTableImpl(schemaName, tableName, alias string) productTable {
 		QuantityValueColumn = postgres.IntegerColumn("quantity_value")
 		QuantityTypeColumn  = postgres.StringColumn("quantity_type")
 		SearchVectorColumn  = postgres.StringColumn("search_vector")
-		allColumns          = postgres.ColumnList{IDColumn, NameColumn, ImageColumn, DescriptionColumn, BrandColumn, CodeColumn, CreatedByIDColumn, UpdatedByIDColumn, CreatedAtColumn, UpdatedAtColumn, SourceColumn, CategoryIDColumn, ViewsColumn, WeightTypeColumn, WeightValueColumn, QuantityValueColumn, QuantityTypeColumn, SearchVectorColumn}
-		mutableColumns      = postgres.ColumnList{NameColumn, ImageColumn, DescriptionColumn, BrandColumn, CodeColumn, CreatedByIDColumn, UpdatedByIDColumn, CreatedAtColumn, UpdatedAtColumn, SourceColumn, CategoryIDColumn, ViewsColumn, WeightTypeColumn, WeightValueColumn, QuantityValueColumn, QuantityTypeColumn}
+		GtinColumn          = postgres.StringColumn("gtin")
+		allColumns          = postgres.ColumnList{IDColumn, NameColumn, ImageColumn, DescriptionColumn, BrandColumn, CodeColumn, CreatedByIDColumn, UpdatedByIDColumn, CreatedAtColumn, UpdatedAtColumn, SourceColumn, CategoryIDColumn, ViewsColumn, WeightTypeColumn, WeightValueColumn, QuantityValueColumn, QuantityTypeColumn, SearchVectorColumn, GtinColumn}
+		mutableColumns      = postgres.ColumnList{NameColumn, ImageColumn, DescriptionColumn, BrandColumn, CodeColumn, CreatedByIDColumn, UpdatedByIDColumn, CreatedAtColumn, UpdatedAtColumn, SourceColumn, CategoryIDColumn, ViewsColumn, WeightTypeColumn, WeightValueColumn, QuantityValueColumn, QuantityTypeColumn, GtinColumn}
 	)
 
 	return productTable{
@@ -119,6 +121,7 @@ func newProductTableImpl(schemaName, tableName, alias string) productTable {
 		QuantityValue: QuantityValueColumn,
 		QuantityType:  QuantityTypeColumn,
 		SearchVector:  SearchVectorColumn,
+		Gtin:          GtinColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
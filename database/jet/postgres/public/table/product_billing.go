@@ -26,6 +26,14 @@ type productBillingTable struct {
 	NewData         postgres.ColumnString
 	OldData         postgres.ColumnString
 	PaidAt          postgres.ColumnTimestampz
+	CurrencyCode    postgres.ColumnString
+	PayoutID        postgres.ColumnInteger
+	RateID          postgres.ColumnInteger
+	BranchID        postgres.ColumnInteger
+	Status          postgres.ColumnString
+	StatusReason    postgres.ColumnString
+	ReviewedByID    postgres.ColumnInteger
+	ReviewedAt      postgres.ColumnTimestampz
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -75,8 +83,16 @@ func newProductBillingTableImpl(schemaName, tableName, alias string) productBill
 		NewDataColumn         = postgres.StringColumn("new_data")
 		OldDataColumn         = postgres.StringColumn("old_data")
 		PaidAtColumn          = postgres.TimestampzColumn("paid_at")
-		allColumns            = postgres.ColumnList{IDColumn, ProductIDColumn, UserIDColumn, CreatedAtColumn, RateColumn, BillingRateTypeColumn, NewDataColumn, OldDataColumn, PaidAtColumn}
-		mutableColumns        = postgres.ColumnList{ProductIDColumn, UserIDColumn, CreatedAtColumn, RateColumn, BillingRateTypeColumn, NewDataColumn, OldDataColumn, PaidAtColumn}
+		CurrencyCodeColumn    = postgres.StringColumn("currency_code")
+		PayoutIDColumn        = postgres.IntegerColumn("payout_id")
+		RateIDColumn          = postgres.IntegerColumn("rate_id")
+		BranchIDColumn        = postgres.IntegerColumn("branch_id")
+		StatusColumn          = postgres.StringColumn("status")
+		StatusReasonColumn    = postgres.StringColumn("status_reason")
+		ReviewedByIDColumn    = postgres.IntegerColumn("reviewed_by_id")
+		ReviewedAtColumn      = postgres.TimestampzColumn("reviewed_at")
+		allColumns            = postgres.ColumnList{IDColumn, ProductIDColumn, UserIDColumn, CreatedAtColumn, RateColumn, BillingRateTypeColumn, NewDataColumn, OldDataColumn, PaidAtColumn, CurrencyCodeColumn, PayoutIDColumn, RateIDColumn, BranchIDColumn, StatusColumn, StatusReasonColumn, ReviewedByIDColumn, ReviewedAtColumn}
+		mutableColumns        = postgres.ColumnList{ProductIDColumn, UserIDColumn, CreatedAtColumn, RateColumn, BillingRateTypeColumn, NewDataColumn, OldDataColumn, PaidAtColumn, CurrencyCodeColumn, PayoutIDColumn, RateIDColumn, BranchIDColumn, StatusColumn, StatusReasonColumn, ReviewedByIDColumn, ReviewedAtColumn}
 	)
 
 	return productBillingTable{
@@ -92,6 +108,14 @@ func newProductBillingTableImpl(schemaName, tableName, alias string) productBill
 		NewData:         NewDataColumn,
 		OldData:         OldDataColumn,
 		PaidAt:          PaidAtColumn,
+		CurrencyCode:    CurrencyCodeColumn,
+		PayoutID:        PayoutIDColumn,
+		RateID:          RateIDColumn,
+		BranchID:        BranchIDColumn,
+		Status:          StatusColumn,
+		StatusReason:    StatusReasonColumn,
+		ReviewedByID:    ReviewedByIDColumn,
+		ReviewedAt:      ReviewedAtColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
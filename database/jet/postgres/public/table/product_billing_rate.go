@@ -17,9 +17,18 @@ type productBillingRateTable struct {
 	postgres.Table
 
 	// Columns
-	Type         postgres.ColumnString
-	Rate         postgres.ColumnFloat
-	CurrencyCode postgres.ColumnString
+	Type                   postgres.ColumnString
+	Rate                   postgres.ColumnFloat
+	CurrencyCode           postgres.ColumnString
+	ID                     postgres.ColumnInteger
+	CategoryID             postgres.ColumnInteger
+	CountryCode            postgres.ColumnString
+	AdministrativeDivision postgres.ColumnString
+	EffectiveFrom          postgres.ColumnTimestampz
+	EffectiveTo            postgres.ColumnTimestampz
+	CreatedByID            postgres.ColumnInteger
+	CreatedAt              postgres.ColumnTimestampz
+	UpdatedAt              postgres.ColumnTimestampz
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -60,20 +69,38 @@ func newProductBillingRateTable(schemaName, tableName, alias string) *ProductBil
 
 func newProductBillingRateTableImpl(schemaName, tableName, alias string) productBillingRateTable {
 	var (
-		TypeColumn         = postgres.StringColumn("type")
-		RateColumn         = postgres.FloatColumn("rate")
-		CurrencyCodeColumn = postgres.StringColumn("currency_code")
-		allColumns         = postgres.ColumnList{TypeColumn, RateColumn, CurrencyCodeColumn}
-		mutableColumns     = postgres.ColumnList{RateColumn, CurrencyCodeColumn}
+		TypeColumn                   = postgres.StringColumn("type")
+		RateColumn                   = postgres.FloatColumn("rate")
+		CurrencyCodeColumn           = postgres.StringColumn("currency_code")
+		IDColumn                     = postgres.IntegerColumn("id")
+		CategoryIDColumn             = postgres.IntegerColumn("category_id")
+		CountryCodeColumn            = postgres.StringColumn("country_code")
+		AdministrativeDivisionColumn = postgres.StringColumn("administrative_division")
+		EffectiveFromColumn          = postgres.TimestampzColumn("effective_from")
+		EffectiveToColumn            = postgres.TimestampzColumn("effective_to")
+		CreatedByIDColumn            = postgres.IntegerColumn("created_by_id")
+		CreatedAtColumn              = postgres.TimestampzColumn("created_at")
+		UpdatedAtColumn              = postgres.TimestampzColumn("updated_at")
+		allColumns                   = postgres.ColumnList{TypeColumn, RateColumn, CurrencyCodeColumn, IDColumn, CategoryIDColumn, CountryCodeColumn, AdministrativeDivisionColumn, EffectiveFromColumn, EffectiveToColumn, CreatedByIDColumn, CreatedAtColumn, UpdatedAtColumn}
+		mutableColumns               = postgres.ColumnList{TypeColumn, RateColumn, CurrencyCodeColumn, CategoryIDColumn, CountryCodeColumn, AdministrativeDivisionColumn, EffectiveFromColumn, EffectiveToColumn, CreatedByIDColumn, CreatedAtColumn, UpdatedAtColumn}
 	)
 
 	return productBillingRateTable{
 		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
 
 		//Columns
-		Type:         TypeColumn,
-		Rate:         RateColumn,
-		CurrencyCode: CurrencyCodeColumn,
+		Type:                   TypeColumn,
+		Rate:                   RateColumn,
+		CurrencyCode:           CurrencyCodeColumn,
+		ID:                     IDColumn,
+		CategoryID:             CategoryIDColumn,
+		CountryCode:            CountryCodeColumn,
+		AdministrativeDivision: AdministrativeDivisionColumn,
+		EffectiveFrom:          EffectiveFromColumn,
+		EffectiveTo:            EffectiveToColumn,
+		CreatedByID:            CreatedByIDColumn,
+		CreatedAt:              CreatedAtColumn,
+		UpdatedAt:              UpdatedAtColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
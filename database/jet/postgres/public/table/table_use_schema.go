@@ -15,30 +15,50 @@ func UseSchema(schema string) {
 	AiPromptResponse = AiPromptResponse.FromSchema(schema)
 	AiPromptTemplate = AiPromptTemplate.FromSchema(schema)
 	AppVersionRequirement = AppVersionRequirement.FromSchema(schema)
+	AuditLog = AuditLog.FromSchema(schema)
 	AuthState = AuthState.FromSchema(schema)
 	Branch = Branch.FromSchema(schema)
 	BranchList = BranchList.FromSchema(schema)
 	Category = Category.FromSchema(schema)
 	Country = Country.FromSchema(schema)
+	Coupon = Coupon.FromSchema(schema)
 	Currency = Currency.FromSchema(schema)
 	EmailVerification = EmailVerification.FromSchema(schema)
 	GroceryList = GroceryList.FromSchema(schema)
 	GroceryListItem = GroceryListItem.FromSchema(schema)
 	GroceryListResult = GroceryListResult.FromSchema(schema)
+	ImageHash = ImageHash.FromSchema(schema)
+	Job = Job.FromSchema(schema)
+	JobSchedule = JobSchedule.FromSchema(schema)
 	List = List.FromSchema(schema)
+	LoyaltyProgram = LoyaltyProgram.FromSchema(schema)
 	Migration = Migration.FromSchema(schema)
 	PasswordReset = PasswordReset.FromSchema(schema)
+	Payout = Payout.FromSchema(schema)
+	PayoutBatch = PayoutBatch.FromSchema(schema)
 	Price = Price.FromSchema(schema)
 	Product = Product.FromSchema(schema)
 	ProductBilling = ProductBilling.FromSchema(schema)
 	ProductBillingRate = ProductBillingRate.FromSchema(schema)
+	ProductEditProposal = ProductEditProposal.FromSchema(schema)
+	ProductFamily = ProductFamily.FromSchema(schema)
+	ProductImage = ProductImage.FromSchema(schema)
 	ProductList = ProductList.FromSchema(schema)
+	ProductMerge = ProductMerge.FromSchema(schema)
 	ProductNutrition = ProductNutrition.FromSchema(schema)
+	ProductRevision = ProductRevision.FromSchema(schema)
+	ProductVariant = ProductVariant.FromSchema(schema)
 	ProductView = ProductView.FromSchema(schema)
 	PushNotification = PushNotification.FromSchema(schema)
 	SearchHistory = SearchHistory.FromSchema(schema)
 	SpatialRefSys = SpatialRefSys.FromSchema(schema)
 	Stock = Stock.FromSchema(schema)
+	StockPriceTransition = StockPriceTransition.FromSchema(schema)
 	Store = Store.FromSchema(schema)
+	StoreMember = StoreMember.FromSchema(schema)
+	TwoFactorChallenge = TwoFactorChallenge.FromSchema(schema)
+	TwoFactorRecoveryCode = TwoFactorRecoveryCode.FromSchema(schema)
 	User = User.FromSchema(schema)
+	UserAchievement = UserAchievement.FromSchema(schema)
+	UserLoyaltyProgram = UserLoyaltyProgram.FromSchema(schema)
 }
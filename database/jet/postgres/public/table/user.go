@@ -17,18 +17,25 @@ type userTable struct {
 	postgres.Table
 
 	// Columns
-	ID          postgres.ColumnInteger
-	CreatedAt   postgres.ColumnTimestampz
-	UpdatedAt   postgres.ColumnTimestampz
-	Email       postgres.ColumnString
-	PhoneNumber postgres.ColumnString
-	Name        postgres.ColumnString
-	Password    postgres.ColumnString
-	Avatar      postgres.ColumnString
-	BirthDate   postgres.ColumnDate
-	Active      postgres.ColumnBool
-	Role        postgres.ColumnString
-	AddressID   postgres.ColumnInteger
+	ID                  postgres.ColumnInteger
+	CreatedAt           postgres.ColumnTimestampz
+	UpdatedAt           postgres.ColumnTimestampz
+	Email               postgres.ColumnString
+	PhoneNumber         postgres.ColumnString
+	Name                postgres.ColumnString
+	Password            postgres.ColumnString
+	Avatar              postgres.ColumnString
+	BirthDate           postgres.ColumnDate
+	Active              postgres.ColumnBool
+	Role                postgres.ColumnString
+	AddressID           postgres.ColumnInteger
+	TwoFactorSecret     postgres.ColumnString
+	TwoFactorEnabled    postgres.ColumnBool
+	DeletionRequestedAt postgres.ColumnTimestampz
+	DeletionScheduledAt postgres.ColumnTimestampz
+	ReputationScore     postgres.ColumnFloat
+	ReputationUpdatedAt postgres.ColumnTimestampz
+	TwoFactorLastStep   postgres.ColumnInteger
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -69,38 +76,52 @@ func newUserTable(schemaName, tableName, alias string) *UserTable {
 
 func newUserTableImpl(schemaName, tableName, alias string) userTable {
 	var (
-		IDColumn          = postgres.IntegerColumn("id")
-		CreatedAtColumn   = postgres.TimestampzColumn("created_at")
-		UpdatedAtColumn   = postgres.TimestampzColumn("updated_at")
-		EmailColumn       = postgres.StringColumn("email")
-		PhoneNumberColumn = postgres.StringColumn("phone_number")
-		NameColumn        = postgres.StringColumn("name")
-		PasswordColumn    = postgres.StringColumn("password")
-		AvatarColumn      = postgres.StringColumn("avatar")
-		BirthDateColumn   = postgres.DateColumn("birth_date")
-		ActiveColumn      = postgres.BoolColumn("active")
-		RoleColumn        = postgres.StringColumn("role")
-		AddressIDColumn   = postgres.IntegerColumn("address_id")
-		allColumns        = postgres.ColumnList{IDColumn, CreatedAtColumn, UpdatedAtColumn, EmailColumn, PhoneNumberColumn, NameColumn, PasswordColumn, AvatarColumn, BirthDateColumn, ActiveColumn, RoleColumn, AddressIDColumn}
-		mutableColumns    = postgres.ColumnList{CreatedAtColumn, UpdatedAtColumn, EmailColumn, PhoneNumberColumn, NameColumn, PasswordColumn, AvatarColumn, BirthDateColumn, ActiveColumn, RoleColumn, AddressIDColumn}
+		IDColumn                  = postgres.IntegerColumn("id")
+		CreatedAtColumn           = postgres.TimestampzColumn("created_at")
+		UpdatedAtColumn           = postgres.TimestampzColumn("updated_at")
+		EmailColumn               = postgres.StringColumn("email")
+		PhoneNumberColumn         = postgres.StringColumn("phone_number")
+		NameColumn                = postgres.StringColumn("name")
+		PasswordColumn            = postgres.StringColumn("password")
+		AvatarColumn              = postgres.StringColumn("avatar")
+		BirthDateColumn           = postgres.DateColumn("birth_date")
+		ActiveColumn              = postgres.BoolColumn("active")
+		RoleColumn                = postgres.StringColumn("role")
+		AddressIDColumn           = postgres.IntegerColumn("address_id")
+		TwoFactorSecretColumn     = postgres.StringColumn("two_factor_secret")
+		TwoFactorEnabledColumn    = postgres.BoolColumn("two_factor_enabled")
+		DeletionRequestedAtColumn = postgres.TimestampzColumn("deletion_requested_at")
+		DeletionScheduledAtColumn = postgres.TimestampzColumn("deletion_scheduled_at")
+		ReputationScoreColumn     = postgres.FloatColumn("reputation_score")
+		ReputationUpdatedAtColumn = postgres.TimestampzColumn("reputation_updated_at")
+		TwoFactorLastStepColumn   = postgres.IntegerColumn("two_factor_last_step")
+		allColumns                = postgres.ColumnList{IDColumn, CreatedAtColumn, UpdatedAtColumn, EmailColumn, PhoneNumberColumn, NameColumn, PasswordColumn, AvatarColumn, BirthDateColumn, ActiveColumn, RoleColumn, AddressIDColumn, TwoFactorSecretColumn, TwoFactorEnabledColumn, DeletionRequestedAtColumn, DeletionScheduledAtColumn, ReputationScoreColumn, ReputationUpdatedAtColumn, TwoFactorLastStepColumn}
+		mutableColumns            = postgres.ColumnList{CreatedAtColumn, UpdatedAtColumn, EmailColumn, PhoneNumberColumn, NameColumn, PasswordColumn, AvatarColumn, BirthDateColumn, ActiveColumn, RoleColumn, AddressIDColumn, TwoFactorSecretColumn, TwoFactorEnabledColumn, DeletionRequestedAtColumn, DeletionScheduledAtColumn, ReputationScoreColumn, ReputationUpdatedAtColumn, TwoFactorLastStepColumn}
 	)
 
 	return userTable{
 		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
 
 		//Columns
-		ID:          IDColumn,
-		CreatedAt:   CreatedAtColumn,
-		UpdatedAt:   UpdatedAtColumn,
-		Email:       EmailColumn,
-		PhoneNumber: PhoneNumberColumn,
-		Name:        NameColumn,
-		Password:    PasswordColumn,
-		Avatar:      AvatarColumn,
-		BirthDate:   BirthDateColumn,
-		Active:      ActiveColumn,
-		Role:        RoleColumn,
-		AddressID:   AddressIDColumn,
+		ID:                  IDColumn,
+		CreatedAt:           CreatedAtColumn,
+		UpdatedAt:           UpdatedAtColumn,
+		Email:               EmailColumn,
+		PhoneNumber:         PhoneNumberColumn,
+		Name:                NameColumn,
+		Password:            PasswordColumn,
+		Avatar:              AvatarColumn,
+		BirthDate:           BirthDateColumn,
+		Active:              ActiveColumn,
+		Role:                RoleColumn,
+		AddressID:           AddressIDColumn,
+		TwoFactorSecret:     TwoFactorSecretColumn,
+		TwoFactorEnabled:    TwoFactorEnabledColumn,
+		DeletionRequestedAt: DeletionRequestedAtColumn,
+		DeletionScheduledAt: DeletionScheduledAtColumn,
+		ReputationScore:     ReputationScoreColumn,
+		ReputationUpdatedAt: ReputationUpdatedAtColumn,
+		TwoFactorLastStep:   TwoFactorLastStepColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
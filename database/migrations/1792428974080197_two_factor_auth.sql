@@ -0,0 +1,27 @@
+alter table "user"
+add column "two_factor_secret" text,
+add column "two_factor_enabled" boolean not null default false,
+-- last accepted TOTP time step so a code can't be used twice
+add column "two_factor_last_step" bigint;
+
+alter table "auth_state"
+add column "two_factor_verified_at" timestamp with time zone;
+
+create table "two_factor_recovery_code" (
+    "id" bigserial unique primary key,
+    "user_id" bigint references "user"("id") on delete cascade not null,
+    "code_hash" text not null,
+    "used_at" timestamp with time zone,
+    "created_at" timestamp with time zone default now() not null
+);
+create index "two_factor_recovery_code_user_id_idx" on "two_factor_recovery_code"("user_id");
+
+create table "two_factor_challenge" (
+    "id" uuid unique primary key,
+    "user_id" bigint references "user"("id") on delete cascade not null,
+    "platform" "user_auth_platform_type" not null,
+    "ip_address" text,
+    "device_type" "auth_device_type",
+    "tries" int not null default 0,
+    "created_at" timestamp with time zone default now() not null
+);
@@ -0,0 +1,6 @@
+alter table "user"
+add column "deletion_requested_at" timestamp with time zone,
+add column "deletion_scheduled_at" timestamp with time zone;
+
+create index "user_deletion_scheduled_at_idx" on "user"("deletion_scheduled_at")
+where "deletion_scheduled_at" is not null;
@@ -0,0 +1,8 @@
+-- Pending email address changes reuse email verification entries.
+-- Entries with a "new_email" value are only used to confirm an email change
+alter table "email_verification"
+add column "new_email" text;
+
+-- Failed email change confirmations
+alter table "email_verification"
+add column "tries" int not null default 0;
@@ -0,0 +1,18 @@
+create type "store_role_type" as enum ('STAFF', 'MANAGER', 'OWNER');
+
+create table "store_member" (
+    "id" bigserial unique primary key,
+    "user_id" bigint references "user"("id") on delete cascade not null,
+    "store_id" bigint references "store"("id") on delete cascade not null,
+    "role" "store_role_type" default 'STAFF'::"store_role_type" not null,
+    "created_by_id" bigint references "user"("id") on delete set null,
+    "created_at" timestamp with time zone default now() not null,
+    "updated_at" timestamp with time zone default now() not null,
+    unique("user_id", "store_id")
+);
+
+create index "store_member_store_id_idx" on "store_member"("store_id");
+
+-- Prices submitted by store members are marked as official
+alter table "price"
+add column "official" boolean not null default false;
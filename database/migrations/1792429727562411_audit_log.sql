@@ -0,0 +1,37 @@
+-- Append-only audit trail for privileged mutations.
+-- "actor_id" intentionally has no foreign key so entries survive user deletion
+create table "audit_log" (
+    "id" bigserial unique primary key,
+    "actor_id" bigint,
+    "actor_email" text,
+    "actor_role" "user_role_type",
+    "action" text not null,
+    "entity" text,
+    "entity_id" bigint,
+    "arguments" jsonb,
+    "before" jsonb,
+    "after" jsonb,
+    "success" boolean not null default true,
+    "error" text,
+    "ip_address" text,
+    "user_agent" text,
+    "auth_state_id" uuid,
+    "created_at" timestamp with time zone default now() not null
+);
+
+create index "audit_log_actor_id_idx" on "audit_log"("actor_id");
+create index "audit_log_entity_idx" on "audit_log"("entity", "entity_id");
+create index "audit_log_action_idx" on "audit_log"("action");
+create index "audit_log_created_at_idx" on "audit_log"("created_at");
+
+create or replace function audit_log_prevent_mutation() returns trigger as $$
+begin
+    raise exception 'audit_log is append-only';
+end;
+$$ language plpgsql;
+
+create trigger audit_log_append_only before
+update or delete on "audit_log" for each row execute function audit_log_prevent_mutation();
+
+create trigger audit_log_no_truncate before
+truncate on "audit_log" for each statement execute function audit_log_prevent_mutation();
@@ -0,0 +1,31 @@
+alter table "product_billing"
+    add column "currency_code" varchar(3) references "currency"("currency_code") default 'USD' not null;
+update "product_billing" "pb"
+    set "currency_code" = "pbr"."currency_code"
+    from "product_billing_rate" "pbr"
+    where "pbr"."type" = "pb"."billing_rate_type";
+
+create table "payout_batch" (
+    "id" bigserial unique primary key,
+    "cutoff" timestamp with time zone not null,
+    "created_by_id" bigint references "user"("id") on delete set null,
+    "payout_count" integer default 0 not null,
+    "billing_count" integer default 0 not null,
+    "created_at" timestamp with time zone default now() not null
+);
+
+create table "payout" (
+    "id" bigserial unique primary key,
+    "payout_batch_id" bigint references "payout_batch"("id") on delete cascade not null,
+    "user_id" bigint references "user"("id") on delete set null,
+    "currency_code" varchar(3) references "currency"("currency_code") not null,
+    "amount" numeric(12, 2) not null,
+    "billing_count" integer not null,
+    "created_at" timestamp with time zone default now() not null,
+    unique("payout_batch_id", "user_id", "currency_code")
+);
+create index "payout_user_id_idx" on "payout"("user_id");
+
+alter table "product_billing"
+    add column "payout_id" bigint references "payout"("id") on delete set null;
+create index "product_billing_unpaid_idx" on "product_billing"("user_id", "created_at") where "paid_at" is null;
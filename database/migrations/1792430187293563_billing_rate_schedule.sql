@@ -0,0 +1,29 @@
+alter table "product_billing" drop constraint "product_billing_billing_rate_type_fkey";
+alter table "product_billing_rate" drop constraint "product_billing_rate_pkey";
+alter table "product_billing_rate" drop constraint if exists "product_billing_rate_type_key";
+
+alter table "product_billing_rate"
+    add column "id" bigserial unique primary key,
+    add column "category_id" bigint references "category"("id") on delete cascade,
+    add column "country_code" "country_code_alpha_2" references "country"("code"),
+    add column "administrative_division" varchar(100),
+    add column "effective_from" timestamp with time zone default now() not null,
+    add column "effective_to" timestamp with time zone,
+    add column "created_by_id" bigint references "user"("id") on delete set null,
+    add column "created_at" timestamp with time zone default now() not null,
+    add column "updated_at" timestamp with time zone default now() not null,
+    add constraint "product_billing_rate_effective_range_check" check ("effective_to" is null or "effective_to" > "effective_from");
+alter table "product_billing_rate" alter column "rate" type numeric(10, 2);
+
+-- existing rates have applied since the beginning
+update "product_billing_rate" set "effective_from" = '2025-01-01T00:00:00Z';
+
+create index "product_billing_rate_lookup_idx" on "product_billing_rate"("type", "effective_from", "effective_to");
+
+alter table "product_billing" alter column "rate" type numeric(10, 2);
+alter table "product_billing"
+    add column "rate_id" bigint references "product_billing_rate"("id") on delete set null;
+update "product_billing" "pb"
+    set "rate_id" = "pbr"."id"
+    from "product_billing_rate" "pbr"
+    where "pbr"."type" = "pb"."billing_rate_type";
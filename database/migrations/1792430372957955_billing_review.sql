@@ -0,0 +1,11 @@
+create type "product_billing_status" as enum ('APPROVED', 'HELD', 'REJECTED');
+
+alter table "product_billing"
+    add column "branch_id" bigint references "branch"("id") on delete set null,
+    add column "status" "product_billing_status" default 'APPROVED'::"product_billing_status" not null,
+    add column "status_reason" text,
+    add column "reviewed_by_id" bigint references "user"("id") on delete set null,
+    add column "reviewed_at" timestamp with time zone;
+
+create index "product_billing_status_idx" on "product_billing"("status") where "status" != 'APPROVED'::"product_billing_status";
+create index "product_billing_user_product_idx" on "product_billing"("user_id", "product_id", "created_at");
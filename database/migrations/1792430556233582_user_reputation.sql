@@ -0,0 +1,6 @@
+alter table "user"
+    add column "reputation_score" numeric(5, 2) default 50 not null,
+    add column "reputation_updated_at" timestamp with time zone;
+
+create index "user_reputation_score_idx" on "user"("reputation_score");
+create index "price_stock_id_created_at_idx" on "price"("stock_id", "created_at");
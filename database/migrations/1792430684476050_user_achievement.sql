@@ -0,0 +1,18 @@
+create type "achievement_type" as enum (
+    'FIRST_SCAN',
+    'FIRST_PRICE',
+    'FIRST_PRODUCT',
+    'HUNDRED_PRICES',
+    'THOUSAND_PRICES',
+    'NEW_STORE_COVERAGE'
+);
+
+create table "user_achievement" (
+    "id" bigserial unique primary key,
+    "user_id" bigint references "user"("id") on delete cascade not null,
+    "achievement" "achievement_type" not null,
+    "awarded_at" timestamp with time zone default now() not null,
+    unique("user_id", "achievement")
+);
+create index "user_achievement_user_id_idx" on "user_achievement"("user_id");
+create index "product_billing_status_created_at_idx" on "product_billing"("status", "created_at");
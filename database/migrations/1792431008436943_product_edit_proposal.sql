@@ -0,0 +1,21 @@
+create type "product_edit_proposal_status" as enum ('PENDING', 'APPROVED', 'PARTIALLY_APPROVED', 'REJECTED');
+
+create table "product_edit_proposal" (
+    "id" bigserial unique primary key,
+    "product_id" bigint references "product"("id") on delete cascade not null,
+    "user_id" bigint references "user"("id") on delete cascade not null,
+    "status" "product_edit_proposal_status" default 'PENDING'::"product_edit_proposal_status" not null,
+    "changes" jsonb not null,
+    "old_data" jsonb not null,
+    "image" text,
+    "applied_changes" jsonb,
+    "review_notes" text,
+    "reviewed_by_id" bigint references "user"("id") on delete set null,
+    "reviewed_at" timestamp with time zone,
+    "created_at" timestamp with time zone default now() not null,
+    "updated_at" timestamp with time zone default now() not null
+);
+
+create index "product_edit_proposal_status_idx" on "product_edit_proposal"("status", "created_at");
+create index "product_edit_proposal_product_idx" on "product_edit_proposal"("product_id");
+create index "product_edit_proposal_user_idx" on "product_edit_proposal"("user_id", "created_at");
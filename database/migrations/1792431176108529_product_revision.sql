@@ -0,0 +1,38 @@
+create type "product_revision_action" as enum ('CREATE', 'UPDATE', 'REVERT');
+
+create table "product_revision" (
+    "id" bigserial unique primary key,
+    "product_id" bigint references "product"("id") on delete cascade not null,
+    "revision" integer not null,
+    "user_id" bigint references "user"("id") on delete set null,
+    "action" "product_revision_action" not null,
+    "data" jsonb not null,
+    "changes" jsonb not null,
+    "image_version" text,
+    "reverted_from_id" bigint references "product_revision"("id") on delete set null,
+    "created_at" timestamp with time zone default now() not null,
+    unique("product_id", "revision")
+);
+
+-- baseline revision for existing products
+insert into "product_revision" ("product_id", "revision", "user_id", "action", "data", "changes", "created_at")
+select
+    "id",
+    1,
+    "created_by_id",
+    'CREATE'::"product_revision_action",
+    jsonb_build_object(
+        'name', "name",
+        'description', "description",
+        'brand', "brand",
+        'code', "code",
+        'categoryId', "category_id",
+        'weightValue', "weight_value",
+        'weightType', "weight_type",
+        'quantityValue', "quantity_value",
+        'quantityType', "quantity_type",
+        'image', "image"
+    ),
+    '{}'::jsonb,
+    "updated_at"
+from "product";
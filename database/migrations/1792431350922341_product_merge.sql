@@ -0,0 +1,20 @@
+create extension if not exists pg_trgm;
+
+create index "product_brand_name_trgm_idx" on "product"
+    using gin ((lower("brand" || ' ' || "name")) gin_trgm_ops);
+create index "product_normalized_code_idx" on "product"
+    (ltrim(regexp_replace("code", '[^0-9]', '', 'g'), '0'));
+
+create table "product_merge" (
+    "id" bigserial unique primary key,
+    "source_product_id" bigint not null,
+    "source_code" text not null,
+    "source_data" jsonb not null,
+    "target_product_id" bigint references "product"("id") on delete cascade not null,
+    "merged_by_id" bigint references "user"("id") on delete set null,
+    "stock_count" integer default 0 not null,
+    "price_count" integer default 0 not null,
+    "created_at" timestamp with time zone default now() not null
+);
+
+create index "product_merge_source_product_idx" on "product_merge"("source_product_id");
@@ -0,0 +1,85 @@
+-- mirrors utils.GtinCheckDigit, utils.ExpandUpcE and utils.ParseGtin
+create or replace function "gtin_check_digit"(digits text) returns integer as $$
+declare
+    total integer := 0;
+    d integer;
+begin
+    for i in 1..length(digits) loop
+        d := substr(digits, length(digits) - i + 1, 1)::integer;
+        if i % 2 = 1 then
+            d := d * 3;
+        end if;
+        total := total + d;
+    end loop;
+    return (10 - total % 10) % 10;
+end;
+$$ language plpgsql immutable;
+
+create or replace function "expand_upc_e"(code text) returns text as $$
+declare
+    number_system text;
+    d text;
+    body text;
+    upc_a text;
+    check_digit text;
+begin
+    if length(code) = 6 then
+        code := '0' || code;
+    elsif length(code) = 8 then
+        check_digit := substr(code, 8, 1);
+        code := substr(code, 1, 7);
+    elsif length(code) != 7 then
+        return null;
+    end if;
+    number_system := substr(code, 1, 1);
+    if number_system not in ('0', '1') then
+        return null;
+    end if;
+
+    d := substr(code, 2, 6);
+    case substr(d, 6, 1)
+        when '0', '1', '2' then body := substr(d, 1, 2) || substr(d, 6, 1) || '0000' || substr(d, 3, 3);
+        when '3' then body := substr(d, 1, 3) || '00000' || substr(d, 4, 2);
+        when '4' then body := substr(d, 1, 4) || '00000' || substr(d, 5, 1);
+        else body := substr(d, 1, 5) || '0000' || substr(d, 6, 1);
+    end case;
+    upc_a := number_system || body;
+    if check_digit is not null and check_digit::integer != gtin_check_digit(upc_a) then
+        return null;
+    end if;
+    return upc_a || gtin_check_digit(upc_a)::text;
+end;
+$$ language plpgsql immutable;
+
+create or replace function "gtin14"(raw_code text) returns text as $$
+declare
+    code text;
+    expanded text;
+begin
+    code := regexp_replace(trim(raw_code), '[\s-]', '', 'g');
+    if code !~ '^[0-9]+$' then
+        return null;
+    end if;
+    if length(code) in (6, 7) then
+        code := expand_upc_e(code);
+        if code is null then
+            return null;
+        end if;
+    elsif length(code) = 8 and substr(code, 1, 1) in ('0', '1') then
+        expanded := expand_upc_e(code);
+        if expanded is not null then
+            code := expanded;
+        end if;
+    elsif length(code) not in (8, 11, 12, 13, 14) then
+        return null;
+    end if;
+    if gtin_check_digit(substr(code, 1, length(code) - 1)) != substr(code, length(code), 1)::integer then
+        return null;
+    end if;
+    return lpad(code, 14, '0');
+end;
+$$ language plpgsql immutable;
+
+alter table "product" add column "gtin" text;
+update "product" set "gtin" = gtin14("code");
+create index "product_gtin_idx" on "product"("gtin");
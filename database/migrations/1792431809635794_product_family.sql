@@ -0,0 +1,22 @@
+create table "product_family" (
+    "id" bigserial unique primary key,
+    "name" text not null,
+    "brand" text not null,
+    "category_id" bigint references "category"("id") on delete set null,
+    "created_by_id" bigint references "user"("id") on delete set null,
+    "updated_by_id" bigint references "user"("id") on delete set null,
+    "created_at" timestamp with time zone default now() not null,
+    "updated_at" timestamp with time zone default now() not null
+);
+
+create table "product_variant" (
+    "product_id" bigint references "product"("id") on delete cascade primary key,
+    "product_family_id" bigint references "product_family"("id") on delete cascade not null,
+    "size" text,
+    "flavor" text,
+    "pack_count" integer default 1 not null check ("pack_count" > 0),
+    "created_by_id" bigint references "user"("id") on delete set null,
+    "created_at" timestamp with time zone default now() not null
+);
+
+create index "product_variant_product_family_idx" on "product_variant"("product_family_id");
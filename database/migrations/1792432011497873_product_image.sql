@@ -0,0 +1,37 @@
+create type "product_image_role" as enum ('FRONT', 'BACK', 'NUTRITION', 'INGREDIENTS', 'SHELF_TAG');
+create type "product_image_status" as enum ('PENDING', 'APPROVED', 'REJECTED');
+
+create table "product_image" (
+    "id" bigserial unique primary key,
+    "product_id" bigint references "product"("id") on delete cascade not null,
+    "role" "product_image_role" default 'FRONT' not null,
+    "public_id" text not null,
+    "url" text not null,
+    "position" integer default 0 not null,
+    "is_primary" boolean default false not null,
+    "status" "product_image_status" default 'PENDING' not null,
+    "uploaded_by_id" bigint references "user"("id") on delete set null,
+    "review_notes" text,
+    "reviewed_by_id" bigint references "user"("id") on delete set null,
+    "reviewed_at" timestamp with time zone,
+    "created_at" timestamp with time zone default now() not null,
+    "updated_at" timestamp with time zone default now() not null
+);
+
+create index "product_image_product_idx" on "product_image"("product_id", "position");
+-- only one primary image per product
+create unique index "product_image_primary_idx" on "product_image"("product_id") where "is_primary";
+
+-- existing images are keyed by the product code
+insert into "product_image" ("product_id", "role", "public_id", "url", "is_primary", "status", "uploaded_by_id", "created_at")
+select
+    "id",
+    'FRONT'::"product_image_role",
+    "code",
+    "image",
+    true,
+    'APPROVED'::"product_image_status",
+    "created_by_id",
+    "created_at"
+from "product"
+where "image" <> '';
@@ -0,0 +1,11 @@
+-- perceptual hashes of uploaded images, used to reject duplicate uploads within a scope
+create table "image_hash" (
+    "public_id" text unique primary key,
+    "scope" text not null,
+    "hash" varchar(16) not null,
+    "width" integer not null,
+    "height" integer not null,
+    "created_at" timestamp with time zone default now() not null
+);
+
+create index "image_hash_scope_idx" on "image_hash"("scope");
@@ -0,0 +1,12 @@
+create type "price_review_status" as enum ('FLAGGED', 'APPROVED', 'REJECTED');
+
+-- evidence photos and moderation of submitted prices
+alter table "price"
+    add column "image_url" text,
+    add column "ocr_amount" numeric,
+    add column "review_status" "price_review_status",
+    add column "review_reason" text,
+    add column "reviewed_by_id" bigint references "user"("id") on delete set null,
+    add column "reviewed_at" timestamp with time zone;
+
+create index "price_review_status_idx" on "price"("review_status") where "review_status" is not null;
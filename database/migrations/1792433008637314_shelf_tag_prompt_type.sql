@@ -0,0 +1 @@
+alter type "ai_prompt_type" add value 'SHELF_TAG';
@@ -0,0 +1,18 @@
+insert into "ai_prompt_template" ("type", "prompt", "variable", "max_tokens") values(
+    'SHELF_TAG'::"ai_prompt_type",
+    'This OCR string was read from a grocery store shelf tag: "{{ocr_string}}". Extract the following:
+- Product name and brand as printed.
+- UPC (optional - digits only, exactly as printed).
+- Price (the price to pay, the sale price if the item is on sale).
+- Regular price (optional - only if a separate regular / was price is printed).
+- Sale (true if the tag is a sale or promotional tag).
+- Unit price and its unit (optional - ex: 0.25 per ''oz'').
+- Price unit (''item'' unless the price is per weight, ex: ''lb'').
+- Member only (true if the price requires a loyalty card or membership) and the program name (optional).
+- Condition (optional - other requirements like ''2 for $5'', ''must buy 2'', ''limit 4'').
+- Expiration date (optional - YYYY-MM-DD, only if an end date is printed).
+Respond with a single JSON object only, using this schema:
+`{"productName"?:string,"brand"?:string,"upc"?:string,"price"?:number,"regularPrice"?:number,"sale"?:boolean,"unitPrice"?:number,"unitPriceUnit"?:string,"priceUnit"?:string,"memberOnly"?:boolean,"memberProgram"?:string,"condition"?:string,"expiresAt"?:string}`.'::text,
+    '{{ocr_string}}',
+    400
+);
@@ -0,0 +1,18 @@
+create type "price_deal_type" as enum ('MULTI_BUY', 'BUY_X_GET_Y', 'DISCOUNT');
+
+-- structured deals. legacy "condition" strings are parsed on startup
+alter table "price"
+    add column "deal_type" "price_deal_type",
+    add column "deal_quantity" integer check ("deal_quantity" > 0),
+    add column "deal_price" numeric,
+    add column "deal_free_quantity" integer check ("deal_free_quantity" > 0),
+    add column "deal_discount_percent" numeric check ("deal_discount_percent" > 0 and "deal_discount_percent" <= 100),
+    add column "deal_discount_amount" numeric,
+    add column "requires_membership" boolean default false not null,
+    add column "purchase_limit" integer check ("purchase_limit" > 0),
+    add column "minimum_quantity" integer check ("minimum_quantity" > 0),
+    add column "minimum_purchase_amount" numeric,
+    -- amount per unit after the deal
+    add column "effective_price" numeric;
+
+update "price" set "effective_price" = "amount" where "condition" is null;
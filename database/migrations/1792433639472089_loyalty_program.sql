@@ -0,0 +1,32 @@
+create table "loyalty_program" (
+    "id" bigserial unique primary key,
+    "store_id" bigint references "store"("id") on delete cascade not null,
+    "name" text not null,
+    "description" text,
+    "signup_url" text,
+    "created_by_id" bigint references "user"("id") on delete set null,
+    "updated_by_id" bigint references "user"("id") on delete set null,
+    "created_at" timestamp with time zone default now() not null,
+    "updated_at" timestamp with time zone default now() not null,
+    unique("store_id", "name")
+);
+
+create table "user_loyalty_program" (
+    "id" bigserial unique primary key,
+    "user_id" bigint references "user"("id") on delete cascade not null,
+    "loyalty_program_id" bigint references "loyalty_program"("id") on delete cascade not null,
+    "created_at" timestamp with time zone default now() not null,
+    unique("user_id", "loyalty_program_id")
+);
+
+create index "user_loyalty_program_user_id_idx" on "user_loyalty_program"("user_id");
+
+-- member prices only apply to users in the program.
+-- prices without a program apply to members of any program of the store
+alter table "price"
+    add column "member_only" boolean default false not null,
+    add column "loyalty_program_id" bigint references "loyalty_program"("id") on delete set null;
+
+-- member prices don't replace the regular latest price of a stock
+alter table "stock"
+    add column "latest_member_price_id" bigint references "price"("id") on delete set null;
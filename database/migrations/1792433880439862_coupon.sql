@@ -0,0 +1,43 @@
+create type "coupon_type" as enum ('STORE', 'MANUFACTURER');
+create type "coupon_scope" as enum ('PRODUCT', 'BRAND', 'CATEGORY');
+create type "coupon_status" as enum ('PENDING', 'APPROVED', 'REJECTED');
+
+create table "coupon" (
+    "id" bigserial unique primary key,
+    "type" "coupon_type" not null,
+    -- manufacturer coupons without a store are accepted everywhere
+    "store_id" bigint references "store"("id") on delete cascade,
+    "scope" "coupon_scope" not null,
+    "product_id" bigint references "product"("id") on delete cascade,
+    "brand" text,
+    "category_id" bigint references "category"("id") on delete cascade,
+    "title" text not null,
+    "description" text,
+    "code" text,
+    "discount_amount" numeric check ("discount_amount" > 0),
+    "discount_percent" numeric check ("discount_percent" > 0 and "discount_percent" <= 100),
+    "minimum_quantity" integer default 1 not null check ("minimum_quantity" > 0),
+    "starts_at" timestamp with time zone,
+    "expires_at" timestamp with time zone,
+    "stackable" boolean default false not null,
+    "status" "coupon_status" default 'PENDING'::"coupon_status" not null,
+    "review_reason" text,
+    "reviewed_by_id" bigint references "user"("id") on delete set null,
+    "reviewed_at" timestamp with time zone,
+    "created_by_id" bigint references "user"("id") on delete set null,
+    "updated_by_id" bigint references "user"("id") on delete set null,
+    "created_at" timestamp with time zone default now() not null,
+    "updated_at" timestamp with time zone default now() not null,
+    check (("discount_amount" is null) <> ("discount_percent" is null)),
+    check ("type" = 'MANUFACTURER' or "store_id" is not null),
+    check (
+        ("scope" = 'PRODUCT' and "product_id" is not null) or
+        ("scope" = 'BRAND' and "brand" is not null) or
+        ("scope" = 'CATEGORY' and "category_id" is not null)
+    )
+);
+
+create index "coupon_status_idx" on "coupon"("status");
+create index "coupon_product_id_idx" on "coupon"("product_id");
+create index "coupon_brand_idx" on "coupon"(lower("brand"));
+create index "coupon_category_id_idx" on "coupon"("category_id");
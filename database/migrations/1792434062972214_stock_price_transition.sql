@@ -0,0 +1,15 @@
+create type "stock_price_transition_reason" as enum ('SALE_EXPIRED');
+
+-- changes of a stock's latest price made by the system
+create table "stock_price_transition" (
+    "id" bigserial unique primary key,
+    "stock_id" bigint references "stock"("id") on delete cascade not null,
+    "from_price_id" bigint references "price"("id") on delete set null,
+    -- null if the stock has no other price to fall back to
+    "to_price_id" bigint references "price"("id") on delete set null,
+    "reason" "stock_price_transition_reason" not null,
+    "created_at" timestamp with time zone default now() not null
+);
+
+create index "stock_price_transition_stock_id_idx" on "stock_price_transition"("stock_id");
+create index "price_sale_expires_at_idx" on "price"("expires_at") where "sale" = true;
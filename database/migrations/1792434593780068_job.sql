@@ -0,0 +1,36 @@
+create type "job_status" as enum ('PENDING', 'RUNNING', 'SUCCEEDED', 'FAILED');
+
+create table "job_schedule" (
+    "id" bigserial unique primary key,
+    "name" text unique not null,
+    "type" text not null,
+    "cron_expression" text not null,
+    "payload" jsonb default '{}'::jsonb not null,
+    "enabled" boolean default true not null,
+    "next_run_at" timestamp with time zone not null,
+    "last_run_at" timestamp with time zone,
+    "created_at" timestamp with time zone default now() not null,
+    "updated_at" timestamp with time zone default now() not null
+);
+
+create table "job" (
+    "id" bigserial unique primary key,
+    "type" text not null,
+    "payload" jsonb default '{}'::jsonb not null,
+    "status" "job_status" default 'PENDING'::"job_status" not null,
+    -- only one pending or running job can have the same key
+    "unique_key" text,
+    "schedule_id" bigint references "job_schedule"("id") on delete set null,
+    "attempts" integer default 0 not null,
+    "max_attempts" integer default 5 not null check ("max_attempts" > 0),
+    "run_at" timestamp with time zone default now() not null,
+    "locked_at" timestamp with time zone,
+    "last_error" text,
+    "completed_at" timestamp with time zone,
+    "created_at" timestamp with time zone default now() not null,
+    "updated_at" timestamp with time zone default now() not null
+);
+
+create unique index "job_unique_key_idx" on "job"("unique_key") where "status" in ('PENDING', 'RUNNING');
+create index "job_pending_run_at_idx" on "job"("run_at") where "status" = 'PENDING';
+create index "job_status_type_idx" on "job"("status", "type");
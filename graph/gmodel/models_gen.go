@@ -11,6 +11,12 @@ import (
 	"github.com/99designs/gqlgen/graphql"
 )
 
+type AddProductImage struct {
+	Role        ProductImageRole `json:"role"`
+	ImageFile   *graphql.Upload  `json:"imageFile,omitempty"`
+	ImageBase64 *string          `json:"imageBase64,omitempty"`
+}
+
 type Address struct {
 	ID                     int64     `json:"id" sql:"primary_key"`
 	CreatedAt              time.Time `json:"createdAt"`
@@ -33,10 +39,48 @@ type AdministrativeDivision struct {
 	Cities string `json:"cities"`
 }
 
+type AuditLog struct {
+	ID          int64     `json:"id" sql:"primary_key"`
+	ActorID     *int64    `json:"actorId,omitempty"`
+	ActorEmail  *string   `json:"actorEmail,omitempty"`
+	ActorRole   *UserRole `json:"actorRole,omitempty"`
+	Action      string    `json:"action"`
+	Entity      *string   `json:"entity,omitempty"`
+	EntityID    *int64    `json:"entityId,omitempty"`
+	Arguments   *string   `json:"arguments,omitempty"`
+	Before      *string   `json:"before,omitempty"`
+	After       *string   `json:"after,omitempty"`
+	Success     bool      `json:"success"`
+	Error       *string   `json:"error,omitempty"`
+	IPAddress   *string   `json:"ipAddress,omitempty"`
+	UserAgent   *string   `json:"userAgent,omitempty"`
+	AuthStateID *string   `json:"authStateId,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type AuditLogFilter struct {
+	ActorID  *int64     `json:"actorId,omitempty"`
+	Action   *string    `json:"action,omitempty"`
+	Entity   *string    `json:"entity,omitempty"`
+	EntityID *int64     `json:"entityId,omitempty"`
+	Success  *bool      `json:"success,omitempty"`
+	From     *time.Time `json:"from,omitempty"`
+	To       *time.Time `json:"to,omitempty"`
+}
+
 type Auth struct {
-	Token     string `json:"token"`
-	User      *User  `json:"user"`
-	IsNewUser *bool  `json:"isNewUser,omitempty"`
+	Token                string  `json:"token"`
+	User                 *User   `json:"user"`
+	IsNewUser            *bool   `json:"isNewUser,omitempty"`
+	TwoFactorChallengeID *string `json:"twoFactorChallengeId,omitempty"`
+}
+
+type BillingRateFilter struct {
+	Type                   *ProductBillingType `json:"type,omitempty"`
+	CategoryID             *int64              `json:"categoryId,omitempty"`
+	CountryCode            *string             `json:"countryCode,omitempty"`
+	AdministrativeDivision *string             `json:"administrativeDivision,omitempty"`
+	EffectiveAt            *time.Time          `json:"effectiveAt,omitempty"`
 }
 
 type Branch struct {
@@ -98,6 +142,38 @@ type Country struct {
 	Language                *string                   `json:"language,omitempty"`
 }
 
+type Coupon struct {
+	ID              int64          `json:"id" sql:"primary_key"`
+	Type            CouponType     `json:"type"`
+	StoreID         *int64         `json:"storeId,omitempty"`
+	Scope           CouponScope    `json:"scope"`
+	ProductID       *int64         `json:"productId,omitempty"`
+	Brand           *string        `json:"brand,omitempty"`
+	CategoryID      *int64         `json:"categoryId,omitempty"`
+	Title           string         `json:"title"`
+	Description     *string        `json:"description,omitempty"`
+	Code            *string        `json:"code,omitempty"`
+	DiscountAmount  *float64       `json:"discountAmount,omitempty"`
+	DiscountPercent *float64       `json:"discountPercent,omitempty"`
+	MinimumQuantity int            `json:"minimumQuantity"`
+	StartsAt        *time.Time     `json:"startsAt,omitempty"`
+	ExpiresAt       *time.Time     `json:"expiresAt,omitempty"`
+	Stackable       bool           `json:"stackable"`
+	Status          CouponStatus   `json:"status"`
+	ReviewReason    *string        `json:"reviewReason,omitempty"`
+	ReviewedByID    *int64         `json:"reviewedById,omitempty"`
+	ReviewedAt      *time.Time     `json:"reviewedAt,omitempty"`
+	CreatedAt       time.Time      `json:"createdAt"`
+	UpdatedAt       time.Time      `json:"updatedAt"`
+	CreatedByID     *int64         `json:"createdById,omitempty"`
+	CreatedBy       *CreatedByUser `json:"createdBy,omitempty"`
+}
+
+type CouponFilter struct {
+	StoreID   *int64 `json:"storeId,omitempty"`
+	ProductID *int64 `json:"productId,omitempty"`
+}
+
 type CreateAccountInput struct {
 	Email       string  `json:"email" validate:"required,email"`
 	PhoneNumber *string `json:"phoneNumber,omitempty" validate:"omitempty,e164"`
@@ -117,6 +193,17 @@ type CreateAddress struct {
 	ZipCode                int     `json:"zipCode" validate:"required"`
 }
 
+type CreateBillingRate struct {
+	Type                   ProductBillingType `json:"type"`
+	Rate                   float64            `json:"rate" validate:"gte=0,lt=100000000"`
+	CurrencyCode           string             `json:"currencyCode" validate:"len=3"`
+	CategoryID             *int64             `json:"categoryId,omitempty"`
+	CountryCode            *string            `json:"countryCode,omitempty"`
+	AdministrativeDivision *string            `json:"administrativeDivision,omitempty"`
+	EffectiveFrom          *time.Time         `json:"effectiveFrom,omitempty"`
+	EffectiveTo            *time.Time         `json:"effectiveTo,omitempty"`
+}
+
 type CreateBranch struct {
 	Name    string         `json:"name" validate:"required"`
 	Address *CreateAddress `json:"address"`
@@ -128,6 +215,24 @@ type CreateCategory struct {
 	ParentPath []int  `json:"parentPath"`
 }
 
+type CreateCoupon struct {
+	Type            CouponType  `json:"type"`
+	StoreID         *int64      `json:"storeId,omitempty"`
+	Scope           CouponScope `json:"scope"`
+	ProductID       *int64      `json:"productId,omitempty"`
+	Brand           *string     `json:"brand,omitempty"`
+	CategoryID      *int64      `json:"categoryId,omitempty"`
+	Title           string      `json:"title" validate:"required"`
+	Description     *string     `json:"description,omitempty"`
+	Code            *string     `json:"code,omitempty"`
+	DiscountAmount  *float64    `json:"discountAmount,omitempty" validate:"omitempty,gt=0"`
+	DiscountPercent *float64    `json:"discountPercent,omitempty" validate:"omitempty,gt=0,lte=100"`
+	MinimumQuantity *int        `json:"minimumQuantity,omitempty" validate:"omitempty,gt=0"`
+	StartsAt        *time.Time  `json:"startsAt,omitempty"`
+	ExpiresAt       *time.Time  `json:"expiresAt,omitempty"`
+	Stackable       *bool       `json:"stackable,omitempty"`
+}
+
 type CreateGroceryListInput struct {
 	Name string `json:"name"`
 }
@@ -141,18 +246,28 @@ type CreateGroceryListItemInput struct {
 	Completed *bool   `json:"completed,omitempty"`
 }
 
+type CreateLoyaltyProgram struct {
+	StoreID     int64   `json:"storeId"`
+	Name        string  `json:"name" validate:"required"`
+	Description *string `json:"description,omitempty"`
+	SignupURL   *string `json:"signupUrl,omitempty" validate:"omitempty,http_url"`
+}
+
 type CreatePrice struct {
-	ProductID     int64           `json:"productId"`
-	BranchID      int64           `json:"branchId"`
-	Amount        float64         `json:"amount" validate:"gt=0"`
-	CurrencyCode  *string         `json:"currencyCode,omitempty"`
-	Sale          bool            `json:"sale"`
-	OriginalPrice *float64        `json:"originalPrice,omitempty"`
-	Condition     *string         `json:"condition,omitempty"`
-	UnitType      string          `json:"unitType"`
-	ImageID       *string         `json:"imageId,omitempty"`
-	ExpiresAt     *time.Time      `json:"expiresAt,omitempty"`
-	ImageFile     *graphql.Upload `json:"imageFile,omitempty"`
+	ProductID        int64           `json:"productId"`
+	BranchID         int64           `json:"branchId"`
+	Amount           float64         `json:"amount" validate:"gt=0"`
+	CurrencyCode     *string         `json:"currencyCode,omitempty"`
+	Sale             bool            `json:"sale"`
+	OriginalPrice    *float64        `json:"originalPrice,omitempty"`
+	Condition        *string         `json:"condition,omitempty"`
+	UnitType         string          `json:"unitType"`
+	ImageID          *string         `json:"imageId,omitempty"`
+	ExpiresAt        *time.Time      `json:"expiresAt,omitempty"`
+	ImageFile        *graphql.Upload `json:"imageFile,omitempty"`
+	Deal             *PriceDealInput `json:"deal,omitempty"`
+	MemberOnly       *bool           `json:"memberOnly,omitempty"`
+	LoyaltyProgramID *int64          `json:"loyaltyProgramId,omitempty"`
 }
 
 type CreateProduct struct {
@@ -170,6 +285,13 @@ type CreateProduct struct {
 	ImageURL      *string         `json:"imageUrl,omitempty" validate:"omitempty,http_url"`
 }
 
+type CreateProductFamily struct {
+	Name       string  `json:"name"`
+	Brand      string  `json:"brand"`
+	CategoryID *int64  `json:"categoryId,omitempty"`
+	ProductIds []int64 `json:"productIds,omitempty"`
+}
+
 type CreateStock struct {
 	ProductID int64 `json:"productId"`
 	StoreID   int64 `json:"storeId"`
@@ -199,6 +321,38 @@ type Currency struct {
 	NumToBasic   *int   `json:"numToBasic,omitempty"`
 }
 
+type DataExport struct {
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"contentType"`
+	Data        string    `json:"data"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type DuplicateProductCandidate struct {
+	Product *Product `json:"product"`
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+type EarningsSummary struct {
+	CurrencyCode  string     `json:"currencyCode"`
+	PendingAmount float64    `json:"pendingAmount"`
+	PendingCount  int        `json:"pendingCount"`
+	HeldAmount    float64    `json:"heldAmount"`
+	HeldCount     int        `json:"heldCount"`
+	PaidAmount    float64    `json:"paidAmount"`
+	PaidCount     int        `json:"paidCount"`
+	LastPaidAt    *time.Time `json:"lastPaidAt,omitempty"`
+}
+
+type EnqueueJob struct {
+	Type        string     `json:"type" validate:"required"`
+	Payload     *string    `json:"payload,omitempty" validate:"omitempty,json"`
+	UniqueKey   *string    `json:"uniqueKey,omitempty"`
+	RunAt       *time.Time `json:"runAt,omitempty"`
+	MaxAttempts *int       `json:"maxAttempts,omitempty" validate:"omitempty,gt=0"`
+}
+
 type GroceryList struct {
 	ID               int64              `json:"id" sql:"primary_key"`
 	UserID           int64              `json:"userId"`
@@ -224,6 +378,50 @@ type GroceryListItem struct {
 	UpdatedAt     time.Time    `json:"updatedAt"`
 }
 
+type Job struct {
+	ID          int64      `json:"id" sql:"primary_key"`
+	Type        string     `json:"type"`
+	Payload     string     `json:"payload"`
+	Status      JobStatus  `json:"status"`
+	UniqueKey   *string    `json:"uniqueKey,omitempty"`
+	ScheduleID  *int64     `json:"scheduleId,omitempty"`
+	Attempts    int        `json:"attempts"`
+	MaxAttempts int        `json:"maxAttempts"`
+	RunAt       time.Time  `json:"runAt"`
+	LockedAt    *time.Time `json:"lockedAt,omitempty"`
+	LastError   *string    `json:"lastError,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+type JobFilter struct {
+	Status *JobStatus `json:"status,omitempty"`
+	Type   *string    `json:"type,omitempty"`
+}
+
+type JobSchedule struct {
+	ID             int64      `json:"id" sql:"primary_key"`
+	Name           string     `json:"name"`
+	Type           string     `json:"type"`
+	CronExpression string     `json:"cronExpression"`
+	Payload        string     `json:"payload"`
+	Enabled        bool       `json:"enabled"`
+	NextRunAt      time.Time  `json:"nextRunAt"`
+	LastRunAt      *time.Time `json:"lastRunAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
+
+type LeaderboardEntry struct {
+	Rank          int          `json:"rank"`
+	User          *UserShallow `json:"user"`
+	Points        int          `json:"points"`
+	Prices        int          `json:"prices"`
+	Products      int          `json:"products"`
+	Confirmations int          `json:"confirmations"`
+}
+
 type List struct {
 	ID          int64          `json:"id" sql:"primary_key"`
 	Name        string         `json:"name"`
@@ -240,14 +438,51 @@ type LocationInput struct {
 	RadiusMeters *int    `json:"radiusMeters,omitempty"`
 }
 
+type LoyaltyProgram struct {
+	ID          int64     `json:"id" sql:"primary_key"`
+	StoreID     int64     `json:"storeId"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description,omitempty"`
+	SignupURL   *string   `json:"signupUrl,omitempty"`
+	CreatedByID *int64    `json:"createdById,omitempty"`
+	UpdatedByID *int64    `json:"updatedById,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
 type Mutation struct {
 }
 
+type PaginatedAuditLogs struct {
+	AuditLogs []*AuditLog `json:"auditLogs"`
+	Paginator *Paginator  `json:"paginator"`
+}
+
 type PaginatedBranches struct {
 	Branches  []*Branch  `json:"branches"`
 	Paginator *Paginator `json:"paginator"`
 }
 
+type PaginatedCoupons struct {
+	Coupons   []*Coupon  `json:"coupons"`
+	Paginator *Paginator `json:"paginator"`
+}
+
+type PaginatedJobs struct {
+	Jobs      []*Job     `json:"jobs"`
+	Paginator *Paginator `json:"paginator"`
+}
+
+type PaginatedPayoutBatches struct {
+	PayoutBatches []*PayoutBatch `json:"payoutBatches"`
+	Paginator     *Paginator     `json:"paginator"`
+}
+
+type PaginatedPayouts struct {
+	Payouts   []*Payout  `json:"payouts"`
+	Paginator *Paginator `json:"paginator"`
+}
+
 type PaginatedPriceHistory struct {
 	Prices    []*Price   `json:"prices"`
 	Paginator *Paginator `json:"paginator"`
@@ -258,6 +493,26 @@ type PaginatedProductBilling struct {
 	Paginator *Paginator        `json:"paginator"`
 }
 
+type PaginatedProductEditProposals struct {
+	Data      []*ProductEditProposal `json:"data"`
+	Paginator *Paginator             `json:"paginator"`
+}
+
+type PaginatedProductFamilies struct {
+	Data      []*ProductFamily `json:"data"`
+	Paginator *Paginator       `json:"paginator"`
+}
+
+type PaginatedProductImages struct {
+	Data      []*ProductImage `json:"data"`
+	Paginator *Paginator      `json:"paginator"`
+}
+
+type PaginatedProductRevisions struct {
+	Data      []*ProductRevision `json:"data"`
+	Paginator *Paginator         `json:"paginator"`
+}
+
 type PaginatedProducts struct {
 	Products  []*Product `json:"products"`
 	Paginator *Paginator `json:"paginator"`
@@ -297,23 +552,106 @@ type PaginatorInput struct {
 	Page  int `json:"page" validate:"required,gt=0"`
 }
 
+type Payout struct {
+	ID            int64        `json:"id" sql:"primary_key"`
+	PayoutBatchID int64        `json:"payoutBatchId"`
+	UserID        *int64       `json:"userId,omitempty"`
+	User          *UserShallow `json:"user,omitempty"`
+	CurrencyCode  string       `json:"currencyCode"`
+	Amount        float64      `json:"amount"`
+	BillingCount  int          `json:"billingCount"`
+	CreatedAt     time.Time    `json:"createdAt"`
+}
+
+type PayoutBatch struct {
+	ID           int64        `json:"id" sql:"primary_key"`
+	Cutoff       time.Time    `json:"cutoff"`
+	CreatedByID  *int64       `json:"createdById,omitempty"`
+	CreatedBy    *UserShallow `json:"createdBy,omitempty"`
+	PayoutCount  int          `json:"payoutCount"`
+	BillingCount int          `json:"billingCount"`
+	CreatedAt    time.Time    `json:"createdAt"`
+}
+
+type PayoutStatement struct {
+	Payout *Payout                `json:"payout"`
+	Items  []*PayoutStatementItem `json:"items"`
+	File   *DataExport            `json:"file"`
+}
+
+type PayoutStatementItem struct {
+	BillingID       int64     `json:"billingId"`
+	ProductID       int64     `json:"productId"`
+	ProductName     string    `json:"productName"`
+	ProductCode     string    `json:"productCode"`
+	BillingRateType string    `json:"billingRateType"`
+	Rate            float64   `json:"rate"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
 type Price struct {
-	ID            int64          `json:"id" sql:"primary_key"`
-	Amount        float64        `json:"amount"`
-	CurrencyCode  string         `json:"currencyCode"`
-	ProductID     int64          `json:"productId"`
-	StockID       int64          `json:"stockId"`
-	StoreID       int64          `json:"storeId"`
-	BranchID      int64          `json:"branchId"`
-	Sale          bool           `json:"sale"`
-	OriginalPrice *float64       `json:"originalPrice,omitempty"`
-	Condition     *string        `json:"condition,omitempty"`
-	UnitType      string         `json:"unitType"`
-	ImageID       *string        `json:"imageId,omitempty"`
-	ExpiresAt     *time.Time     `json:"expiresAt,omitempty"`
-	CreatedAt     time.Time      `json:"createdAt"`
-	CreatedByID   *int64         `json:"createdById,omitempty"`
-	CreatedBy     *CreatedByUser `json:"createdBy,omitempty"`
+	ID                    int64              `json:"id" sql:"primary_key"`
+	Amount                float64            `json:"amount"`
+	CurrencyCode          string             `json:"currencyCode"`
+	ProductID             int64              `json:"productId"`
+	StockID               int64              `json:"stockId"`
+	StoreID               int64              `json:"storeId"`
+	BranchID              int64              `json:"branchId"`
+	Sale                  bool               `json:"sale"`
+	OriginalPrice         *float64           `json:"originalPrice,omitempty"`
+	Condition             *string            `json:"condition,omitempty"`
+	UnitType              string             `json:"unitType"`
+	ImageID               *string            `json:"imageId,omitempty"`
+	ImageURL              *string            `json:"imageUrl,omitempty"`
+	ExpiresAt             *time.Time         `json:"expiresAt,omitempty"`
+	Official              bool               `json:"official"`
+	OcrAmount             *float64           `json:"ocrAmount,omitempty"`
+	ReviewStatus          *PriceReviewStatus `json:"reviewStatus,omitempty"`
+	ReviewReason          *string            `json:"reviewReason,omitempty"`
+	ReviewedByID          *int64             `json:"reviewedById,omitempty"`
+	ReviewedAt            *time.Time         `json:"reviewedAt,omitempty"`
+	DealType              *PriceDealType     `json:"dealType,omitempty"`
+	DealQuantity          *int               `json:"dealQuantity,omitempty"`
+	DealPrice             *float64           `json:"dealPrice,omitempty"`
+	DealFreeQuantity      *int               `json:"dealFreeQuantity,omitempty"`
+	DealDiscountPercent   *float64           `json:"dealDiscountPercent,omitempty"`
+	DealDiscountAmount    *float64           `json:"dealDiscountAmount,omitempty"`
+	RequiresMembership    bool               `json:"requiresMembership"`
+	PurchaseLimit         *int               `json:"purchaseLimit,omitempty"`
+	MinimumQuantity       *int               `json:"minimumQuantity,omitempty"`
+	MinimumPurchaseAmount *float64           `json:"minimumPurchaseAmount,omitempty"`
+	EffectivePrice        *float64           `json:"effectivePrice,omitempty"`
+	MemberOnly            bool               `json:"memberOnly"`
+	LoyaltyProgramID      *int64             `json:"loyaltyProgramId,omitempty"`
+	CreatedAt             time.Time          `json:"createdAt"`
+	CreatedByID           *int64             `json:"createdById,omitempty"`
+	CreatedBy             *CreatedByUser     `json:"createdBy,omitempty"`
+}
+
+type PriceDealInput struct {
+	Type                  *PriceDealType `json:"type,omitempty"`
+	Quantity              *int           `json:"quantity,omitempty"`
+	Price                 *float64       `json:"price,omitempty"`
+	FreeQuantity          *int           `json:"freeQuantity,omitempty"`
+	DiscountPercent       *float64       `json:"discountPercent,omitempty"`
+	DiscountAmount        *float64       `json:"discountAmount,omitempty"`
+	RequiresMembership    *bool          `json:"requiresMembership,omitempty"`
+	PurchaseLimit         *int           `json:"purchaseLimit,omitempty"`
+	MinimumQuantity       *int           `json:"minimumQuantity,omitempty"`
+	MinimumPurchaseAmount *float64       `json:"minimumPurchaseAmount,omitempty"`
+}
+
+type PriceDraft struct {
+	ProductID     int64      `json:"productId"`
+	BranchID      int64      `json:"branchId"`
+	Amount        float64    `json:"amount"`
+	CurrencyCode  *string    `json:"currencyCode,omitempty"`
+	Sale          bool       `json:"sale"`
+	OriginalPrice *float64   `json:"originalPrice,omitempty"`
+	Condition     *string    `json:"condition,omitempty"`
+	UnitType      string     `json:"unitType"`
+	ExpiresAt     *time.Time `json:"expiresAt,omitempty"`
+	MemberOnly    bool       `json:"memberOnly"`
 }
 
 type PriceHistoryFilter struct {
@@ -321,36 +659,81 @@ type PriceHistoryFilter struct {
 }
 
 type Product struct {
-	ID            int64          `json:"id" sql:"primary_key"`
-	Name          string         `json:"name"`
-	Image         string         `json:"image"`
-	Description   string         `json:"description"`
-	Brand         string         `json:"brand"`
-	Code          string         `json:"code"`
-	Model         *string        `json:"model,omitempty"`
-	CategoryID    int64          `json:"categoryId"`
-	Category      *Category      `json:"category,omitempty"`
-	Stock         *Stock         `json:"stock,omitempty"`
-	WeightValue   *float64       `json:"weightValue,omitempty"`
-	WeightType    *string        `json:"weightType,omitempty"`
-	QuantityValue int            `json:"quantityValue"`
-	QuantityType  string         `json:"quantityType"`
-	Views         int            `json:"views"`
-	CreatedAt     time.Time      `json:"createdAt"`
-	UpdatedAt     time.Time      `json:"updatedAt"`
-	ProductList   []*ProductList `json:"productList"`
+	ID            int64             `json:"id" sql:"primary_key"`
+	Name          string            `json:"name"`
+	Image         string            `json:"image"`
+	Description   string            `json:"description"`
+	Brand         string            `json:"brand"`
+	Code          string            `json:"code"`
+	Gtin          *string           `json:"gtin,omitempty"`
+	Model         *string           `json:"model,omitempty"`
+	CategoryID    int64             `json:"categoryId"`
+	Category      *Category         `json:"category,omitempty"`
+	Stock         *Stock            `json:"stock,omitempty"`
+	WeightValue   *float64          `json:"weightValue,omitempty"`
+	WeightType    *string           `json:"weightType,omitempty"`
+	QuantityValue int               `json:"quantityValue"`
+	QuantityType  string            `json:"quantityType"`
+	Views         int               `json:"views"`
+	CreatedAt     time.Time         `json:"createdAt"`
+	UpdatedAt     time.Time         `json:"updatedAt"`
+	ProductList   []*ProductList    `json:"productList"`
+	Images        []*ProductImage   `json:"images"`
+	Variants      []*ProductVariant `json:"variants"`
 }
 
 type ProductBilling struct {
-	ID              int64        `json:"id" sql:"primary_key"`
-	ProductID       int64        `json:"productId"`
-	Product         *Product     `json:"product,omitempty"`
-	UserID          int64        `json:"userId"`
-	User            *UserShallow `json:"user,omitempty"`
-	CreatedAt       time.Time    `json:"createdAt"`
-	Rate            float64      `json:"rate"`
-	BillingRateType string       `json:"billingRateType"`
-	PaidAt          *time.Time   `json:"paidAt,omitempty"`
+	ID              int64                `json:"id" sql:"primary_key"`
+	ProductID       int64                `json:"productId"`
+	Product         *Product             `json:"product,omitempty"`
+	UserID          int64                `json:"userId"`
+	User            *UserShallow         `json:"user,omitempty"`
+	CreatedAt       time.Time            `json:"createdAt"`
+	Rate            float64              `json:"rate"`
+	BillingRateType string               `json:"billingRateType"`
+	CurrencyCode    string               `json:"currencyCode"`
+	RateID          *int64               `json:"rateId,omitempty"`
+	BranchID        *int64               `json:"branchId,omitempty"`
+	Status          ProductBillingStatus `json:"status"`
+	StatusReason    *string              `json:"statusReason,omitempty"`
+	ReviewedByID    *int64               `json:"reviewedById,omitempty"`
+	ReviewedAt      *time.Time           `json:"reviewedAt,omitempty"`
+	PaidAt          *time.Time           `json:"paidAt,omitempty"`
+	PayoutID        *int64               `json:"payoutId,omitempty"`
+}
+
+type ProductBillingRate struct {
+	ID                     int64              `json:"id" sql:"primary_key"`
+	Type                   ProductBillingType `json:"type"`
+	Rate                   float64            `json:"rate"`
+	CurrencyCode           string             `json:"currencyCode"`
+	CategoryID             *int64             `json:"categoryId,omitempty"`
+	Category               *Category          `json:"category,omitempty"`
+	CountryCode            *string            `json:"countryCode,omitempty"`
+	AdministrativeDivision *string            `json:"administrativeDivision,omitempty"`
+	EffectiveFrom          time.Time          `json:"effectiveFrom"`
+	EffectiveTo            *time.Time         `json:"effectiveTo,omitempty"`
+	CreatedByID            *int64             `json:"createdById,omitempty"`
+	CreatedAt              time.Time          `json:"createdAt"`
+	UpdatedAt              time.Time          `json:"updatedAt"`
+}
+
+type ProductEditProposal struct {
+	ID             int64                     `json:"id" sql:"primary_key"`
+	ProductID      int64                     `json:"productId"`
+	Product        *Product                  `json:"product,omitempty"`
+	UserID         int64                     `json:"userId"`
+	User           *UserShallow              `json:"user,omitempty"`
+	Status         ProductEditProposalStatus `json:"status"`
+	Changes        string                    `json:"changes"`
+	OldData        string                    `json:"oldData"`
+	Image          *string                   `json:"image,omitempty"`
+	AppliedChanges *string                   `json:"appliedChanges,omitempty"`
+	ReviewNotes    *string                   `json:"reviewNotes,omitempty"`
+	ReviewedByID   *int64                    `json:"reviewedById,omitempty"`
+	ReviewedAt     *time.Time                `json:"reviewedAt,omitempty"`
+	CreatedAt      time.Time                 `json:"createdAt"`
+	UpdatedAt      time.Time                 `json:"updatedAt"`
 }
 
 type ProductExtractionFields struct {
@@ -370,6 +753,37 @@ type ProductExtractionResponse struct {
 	Category   *Category `json:"category,omitempty"`
 }
 
+type ProductFamily struct {
+	ID          int64             `json:"id" sql:"primary_key"`
+	Name        string            `json:"name"`
+	Brand       string            `json:"brand"`
+	CategoryID  *int64            `json:"categoryId,omitempty"`
+	Category    *Category         `json:"category,omitempty"`
+	CreatedByID *int64            `json:"createdById,omitempty"`
+	UpdatedByID *int64            `json:"updatedById,omitempty"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+	Variants    []*ProductVariant `json:"variants"`
+}
+
+type ProductImage struct {
+	ID           int64              `json:"id" sql:"primary_key"`
+	ProductID    int64              `json:"productId"`
+	Role         ProductImageRole   `json:"role"`
+	PublicID     string             `json:"publicId"`
+	URL          string             `json:"url"`
+	Position     int                `json:"position"`
+	IsPrimary    bool               `json:"isPrimary"`
+	Status       ProductImageStatus `json:"status"`
+	UploadedByID *int64             `json:"uploadedById,omitempty"`
+	UploadedBy   *UserShallow       `json:"uploadedBy,omitempty"`
+	ReviewNotes  *string            `json:"reviewNotes,omitempty"`
+	ReviewedByID *int64             `json:"reviewedById,omitempty"`
+	ReviewedAt   *time.Time         `json:"reviewedAt,omitempty"`
+	CreatedAt    time.Time          `json:"createdAt"`
+	UpdatedAt    time.Time          `json:"updatedAt"`
+}
+
 type ProductList struct {
 	ID        int64     `json:"id" sql:"primary_key"`
 	UserID    int64     `json:"userId"`
@@ -502,6 +916,20 @@ type ProductNutrition struct {
 	UpdatedAt              time.Time         `json:"updatedAt"`
 }
 
+type ProductRevision struct {
+	ID             int64                 `json:"id" sql:"primary_key"`
+	ProductID      int64                 `json:"productId"`
+	Revision       int                   `json:"revision"`
+	UserID         *int64                `json:"userId,omitempty"`
+	User           *UserShallow          `json:"user,omitempty"`
+	Action         ProductRevisionAction `json:"action"`
+	Data           string                `json:"data"`
+	Changes        string                `json:"changes"`
+	ImageVersion   *string               `json:"imageVersion,omitempty"`
+	RevertedFromID *int64                `json:"revertedFromId,omitempty"`
+	CreatedAt      time.Time             `json:"createdAt"`
+}
+
 type ProductSearch struct {
 	Query       *string        `json:"query,omitempty"`
 	Category    *string        `json:"category,omitempty"`
@@ -524,6 +952,7 @@ type ProductSimple struct {
 	Description   string       `json:"description"`
 	Brand         string       `json:"brand"`
 	Code          string       `json:"code"`
+	Gtin          *string      `json:"gtin,omitempty"`
 	Model         *string      `json:"model,omitempty"`
 	CategoryID    int64        `json:"categoryId"`
 	Category      *Category    `json:"category,omitempty"`
@@ -537,6 +966,26 @@ type ProductSimple struct {
 	UpdatedAt     time.Time    `json:"updatedAt"`
 }
 
+type ProductVariant struct {
+	ProductID       int64     `json:"productId" sql:"primary_key"`
+	ProductFamilyID int64     `json:"productFamilyId"`
+	Size            *string   `json:"size,omitempty"`
+	Flavor          *string   `json:"flavor,omitempty"`
+	PackCount       int       `json:"packCount"`
+	CreatedByID     *int64    `json:"createdById,omitempty"`
+	CreatedAt       time.Time `json:"createdAt"`
+	Product         *Product  `json:"product,omitempty"`
+	Stock           *Stock    `json:"stock,omitempty"`
+	UnitPrice       *float64  `json:"unitPrice,omitempty"`
+	UnitPriceType   *string   `json:"unitPriceType,omitempty"`
+}
+
+type ProductVariantInput struct {
+	Size      *string `json:"size,omitempty"`
+	Flavor    *string `json:"flavor,omitempty"`
+	PackCount *int    `json:"packCount,omitempty"`
+}
+
 type ProductWeightComponents struct {
 	WeightValue float64 `json:"weightValue" alias:"product.weight_value"`
 	WeightType  string  `json:"weightType" alias:"product.weight_type"`
@@ -545,6 +994,12 @@ type ProductWeightComponents struct {
 type Query struct {
 }
 
+type ReviewProductEditProposal struct {
+	Approve bool     `json:"approve"`
+	Fields  []string `json:"fields,omitempty"`
+	Notes   *string  `json:"notes,omitempty"`
+}
+
 type SaveExternalProductInput struct {
 	NumPagesToQuery int     `json:"numPagesToQuery"`
 	Search          string  `json:"search"`
@@ -566,22 +1021,61 @@ type SearchResult struct {
 	Failed int `json:"failed"`
 }
 
+type ShelfTagExtraction struct {
+	Fields  *ShelfTagFields `json:"fields"`
+	Product *Product        `json:"product,omitempty"`
+	Draft   *PriceDraft     `json:"draft,omitempty"`
+	Price   *Price          `json:"price,omitempty"`
+}
+
+type ShelfTagFields struct {
+	ProductName   *string  `json:"productName,omitempty"`
+	Brand         *string  `json:"brand,omitempty"`
+	Upc           *string  `json:"upc,omitempty"`
+	Price         *float64 `json:"price,omitempty"`
+	RegularPrice  *float64 `json:"regularPrice,omitempty"`
+	Sale          *bool    `json:"sale,omitempty"`
+	UnitPrice     *float64 `json:"unitPrice,omitempty"`
+	UnitPriceUnit *string  `json:"unitPriceUnit,omitempty"`
+	PriceUnit     *string  `json:"priceUnit,omitempty"`
+	MemberOnly    *bool    `json:"memberOnly,omitempty"`
+	MemberProgram *string  `json:"memberProgram,omitempty"`
+	Condition     *string  `json:"condition,omitempty"`
+	ExpiresAt     *string  `json:"expiresAt,omitempty"`
+}
+
 type Stock struct {
-	ID            int64          `json:"id" sql:"primary_key"`
-	ProductID     int64          `json:"productId"`
-	Product       *Product       `json:"product,omitempty"`
-	StoreID       int64          `json:"storeId"`
-	Store         *Store         `json:"store,omitempty"`
-	BranchID      int64          `json:"branchId"`
-	Branch        *BranchFlat    `json:"branch,omitempty"`
-	LatestPriceID int64          `json:"latestPriceId"`
-	LatestPrice   *Price         `json:"latestPrice,omitempty"`
-	CreatedAt     time.Time      `json:"createdAt"`
-	UpdatedAt     time.Time      `json:"updatedAt"`
-	CreatedByID   *int64         `json:"createdById,omitempty"`
-	CreatedBy     *CreatedByUser `json:"createdBy,omitempty"`
-	UpdatedByID   *int64         `json:"updatedById,omitempty"`
-	UpdatedBy     *UpdatedByUser `json:"updatedBy,omitempty"`
+	ID                  int64          `json:"id" sql:"primary_key"`
+	ProductID           int64          `json:"productId"`
+	Product             *Product       `json:"product,omitempty"`
+	StoreID             int64          `json:"storeId"`
+	Store               *Store         `json:"store,omitempty"`
+	BranchID            int64          `json:"branchId"`
+	Branch              *BranchFlat    `json:"branch,omitempty"`
+	LatestPriceID       *int64         `json:"latestPriceId,omitempty"`
+	LatestPrice         *Price         `json:"latestPrice,omitempty"`
+	LatestMemberPriceID *int64         `json:"latestMemberPriceId,omitempty"`
+	LatestMemberPrice   *Price         `json:"latestMemberPrice,omitempty" alias:"latest_member_price"`
+	NonMemberPrice      *Price         `json:"nonMemberPrice,omitempty" alias:"non_member_price"`
+	CouponPrice         *float64       `json:"couponPrice,omitempty"`
+	AppliedCoupons      []*Coupon      `json:"appliedCoupons,omitempty"`
+	CreatedAt           time.Time      `json:"createdAt"`
+	UpdatedAt           time.Time      `json:"updatedAt"`
+	CreatedByID         *int64         `json:"createdById,omitempty"`
+	CreatedBy           *CreatedByUser `json:"createdBy,omitempty"`
+	UpdatedByID         *int64         `json:"updatedById,omitempty"`
+	UpdatedBy           *UpdatedByUser `json:"updatedBy,omitempty"`
+}
+
+type StockPriceTransition struct {
+	ID          int64                      `json:"id" sql:"primary_key"`
+	StockID     int64                      `json:"stockId"`
+	FromPriceID *int64                     `json:"fromPriceId,omitempty"`
+	FromPrice   *Price                     `json:"fromPrice,omitempty" alias:"from_price"`
+	ToPriceID   *int64                     `json:"toPriceId,omitempty"`
+	ToPrice     *Price                     `json:"toPrice,omitempty" alias:"to_price"`
+	Reason      StockPriceTransitionReason `json:"reason"`
+	CreatedAt   time.Time                  `json:"createdAt"`
 }
 
 type StockSimple struct {
@@ -589,7 +1083,7 @@ type StockSimple struct {
 	ProductID     int64          `json:"productId"`
 	StoreID       int64          `json:"storeId"`
 	BranchID      int64          `json:"branchId"`
-	LatestPriceID int64          `json:"latestPriceId"`
+	LatestPriceID *int64         `json:"latestPriceId,omitempty"`
 	LatestPrice   *Price         `json:"latestPrice,omitempty"`
 	CreatedAt     time.Time      `json:"createdAt"`
 	UpdatedAt     time.Time      `json:"updatedAt"`
@@ -606,6 +1100,45 @@ type Store struct {
 	Website string `json:"website"`
 }
 
+type StoreMember struct {
+	ID          int64        `json:"id" sql:"primary_key"`
+	UserID      int64        `json:"userId"`
+	User        *UserShallow `json:"user,omitempty"`
+	StoreID     int64        `json:"storeId"`
+	Store       *Store       `json:"store,omitempty"`
+	Role        StoreRole    `json:"role"`
+	CreatedByID *int64       `json:"createdById,omitempty"`
+	CreatedAt   time.Time    `json:"createdAt"`
+	UpdatedAt   time.Time    `json:"updatedAt"`
+}
+
+type TwoFactorEnrollment struct {
+	Secret     string `json:"secret"`
+	OtpauthURL string `json:"otpauthUrl"`
+}
+
+type TwoFactorRecoveryCodes struct {
+	Codes []string `json:"codes"`
+}
+
+type UpdateBillingRate struct {
+	Rate          *float64   `json:"rate,omitempty" validate:"omitempty,gte=0,lt=100000000"`
+	CurrencyCode  *string    `json:"currencyCode,omitempty" validate:"omitempty,len=3"`
+	EffectiveFrom *time.Time `json:"effectiveFrom,omitempty"`
+	EffectiveTo   *time.Time `json:"effectiveTo,omitempty"`
+}
+
+type UpdateBranch struct {
+	Name        *string `json:"name,omitempty"`
+	FullAddress *string `json:"fullAddress,omitempty"`
+}
+
+type UpdateLoyaltyProgram struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	SignupURL   *string `json:"signupUrl,omitempty" validate:"omitempty,http_url"`
+}
+
 type UpdateProduct struct {
 	Name          *string         `json:"name,omitempty"`
 	Description   *string         `json:"description,omitempty"`
@@ -620,6 +1153,19 @@ type UpdateProduct struct {
 	ImageBase64   *string         `json:"imageBase64,omitempty"`
 }
 
+type UpdateProductFamily struct {
+	Name       *string `json:"name,omitempty"`
+	Brand      *string `json:"brand,omitempty"`
+	CategoryID *int64  `json:"categoryId,omitempty"`
+}
+
+type UpdateStore struct {
+	Name       *string         `json:"name,omitempty"`
+	LogoBase64 *string         `json:"logoBase64,omitempty"`
+	Website    *string         `json:"website,omitempty" validate:"omitempty,http_url"`
+	LogoFile   *graphql.Upload `json:"logoFile,omitempty"`
+}
+
 type UpdateUser struct {
 	Name         *string         `json:"name,omitempty"`
 	AvatarFile   *graphql.Upload `json:"avatarFile,omitempty"`
@@ -650,23 +1196,36 @@ type UpdatedByUser struct {
 }
 
 type User struct {
-	ID            int64             `json:"id" sql:"primary_key"`
-	CreatedAt     time.Time         `json:"createdAt"`
-	UpdatedAt     time.Time         `json:"updatedAt"`
-	Email         string            `json:"email"`
-	PhoneNumber   *string           `json:"phoneNumber,omitempty"`
-	Name          string            `json:"name"`
-	Avatar        *string           `json:"avatar,omitempty"`
-	BirthDate     *time.Time        `json:"birthDate,omitempty"`
-	Bio           *string           `json:"bio,omitempty"`
-	Active        bool              `json:"active"`
-	AuthPlatform  *AuthPlatformType `json:"authPlatform,omitempty" alias:"auth_state.platform"`
-	AuthDevice    *AuthDeviceType   `json:"authDevice,omitempty" alias:"auth_state.device_type"`
-	AuthStateID   *string           `json:"authStateId,omitempty" alias:"auth_state.id"`
-	ExpoPushToken *string           `json:"expoPushToken,omitempty" alias:"auth_state.expo_push_token"`
-	Role          UserRole          `json:"role"`
-	AddressID     *int64            `json:"addressId,omitempty"`
-	Address       *Address          `json:"address,omitempty"`
+	ID                      int64              `json:"id" sql:"primary_key"`
+	CreatedAt               time.Time          `json:"createdAt"`
+	UpdatedAt               time.Time          `json:"updatedAt"`
+	Email                   string             `json:"email"`
+	PhoneNumber             *string            `json:"phoneNumber,omitempty"`
+	Name                    string             `json:"name"`
+	Avatar                  *string            `json:"avatar,omitempty"`
+	BirthDate               *time.Time         `json:"birthDate,omitempty"`
+	Bio                     *string            `json:"bio,omitempty"`
+	Active                  bool               `json:"active"`
+	AuthPlatform            *AuthPlatformType  `json:"authPlatform,omitempty" alias:"auth_state.platform"`
+	AuthDevice              *AuthDeviceType    `json:"authDevice,omitempty" alias:"auth_state.device_type"`
+	AuthStateID             *string            `json:"authStateId,omitempty" alias:"auth_state.id"`
+	ExpoPushToken           *string            `json:"expoPushToken,omitempty" alias:"auth_state.expo_push_token"`
+	AuthTwoFactorVerifiedAt *time.Time         `json:"authTwoFactorVerifiedAt,omitempty" alias:"auth_state.two_factor_verified_at"`
+	Role                    UserRole           `json:"role"`
+	TwoFactorEnabled        bool               `json:"twoFactorEnabled"`
+	DeletionRequestedAt     *time.Time         `json:"deletionRequestedAt,omitempty"`
+	DeletionScheduledAt     *time.Time         `json:"deletionScheduledAt,omitempty"`
+	ReputationScore         float64            `json:"reputationScore"`
+	Achievements            []*UserAchievement `json:"achievements"`
+	AddressID               *int64             `json:"addressId,omitempty"`
+	Address                 *Address           `json:"address,omitempty"`
+}
+
+type UserAchievement struct {
+	ID          int64           `json:"id" sql:"primary_key"`
+	UserID      int64           `json:"userId"`
+	Achievement AchievementType `json:"achievement"`
+	AwardedAt   time.Time       `json:"awardedAt"`
 }
 
 type UserFilter struct {
@@ -676,6 +1235,26 @@ type UserFilter struct {
 	Role  *UserRole `json:"role,omitempty"`
 }
 
+type UserLoyaltyProgram struct {
+	ID               int64           `json:"id" sql:"primary_key"`
+	UserID           int64           `json:"userId"`
+	LoyaltyProgramID int64           `json:"loyaltyProgramId"`
+	LoyaltyProgram   *LoyaltyProgram `json:"loyaltyProgram,omitempty"`
+	CreatedAt        time.Time       `json:"createdAt"`
+}
+
+type UserReputation struct {
+	UserID          int64     `json:"userId"`
+	Score           float64   `json:"score"`
+	Trusted         bool      `json:"trusted"`
+	ConfirmedPrices int       `json:"confirmedPrices"`
+	DisputedPrices  int       `json:"disputedPrices"`
+	AcceptedEdits   int       `json:"acceptedEdits"`
+	RejectedEdits   int       `json:"rejectedEdits"`
+	AccountAgeDays  int       `json:"accountAgeDays"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
 type UserShallow struct {
 	ID     int64   `json:"id" sql:"primary_key" alias:"user.id"`
 	Name   string  `json:"name" alias:"user.name"`
@@ -688,6 +1267,55 @@ type ViewerTrailInput struct {
 	Origin  *string `json:"origin,omitempty"`
 }
 
+type AchievementType string
+
+const (
+	AchievementTypeFirstScan        AchievementType = "FIRST_SCAN"
+	AchievementTypeFirstPrice       AchievementType = "FIRST_PRICE"
+	AchievementTypeFirstProduct     AchievementType = "FIRST_PRODUCT"
+	AchievementTypeHundredPrices    AchievementType = "HUNDRED_PRICES"
+	AchievementTypeThousandPrices   AchievementType = "THOUSAND_PRICES"
+	AchievementTypeNewStoreCoverage AchievementType = "NEW_STORE_COVERAGE"
+)
+
+var AllAchievementType = []AchievementType{
+	AchievementTypeFirstScan,
+	AchievementTypeFirstPrice,
+	AchievementTypeFirstProduct,
+	AchievementTypeHundredPrices,
+	AchievementTypeThousandPrices,
+	AchievementTypeNewStoreCoverage,
+}
+
+func (e AchievementType) IsValid() bool {
+	switch e {
+	case AchievementTypeFirstScan, AchievementTypeFirstPrice, AchievementTypeFirstProduct, AchievementTypeHundredPrices, AchievementTypeThousandPrices, AchievementTypeNewStoreCoverage:
+		return true
+	}
+	return false
+}
+
+func (e AchievementType) String() string {
+	return string(e)
+}
+
+func (e *AchievementType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AchievementType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AchievementType", str)
+	}
+	return nil
+}
+
+func (e AchievementType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type AuthDeviceType string
 
 const (
@@ -778,87 +1406,777 @@ func (e AuthPlatformType) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-type ListType string
+type CouponScope string
 
 const (
-	ListTypeFavorites ListType = "FAVORITES"
-	ListTypeWatchList ListType = "WATCH_LIST"
-	ListTypePersonal  ListType = "PERSONAL"
+	CouponScopeProduct  CouponScope = "PRODUCT"
+	CouponScopeBrand    CouponScope = "BRAND"
+	CouponScopeCategory CouponScope = "CATEGORY"
 )
 
-var AllListType = []ListType{
-	ListTypeFavorites,
-	ListTypeWatchList,
-	ListTypePersonal,
+var AllCouponScope = []CouponScope{
+	CouponScopeProduct,
+	CouponScopeBrand,
+	CouponScopeCategory,
 }
 
-func (e ListType) IsValid() bool {
+func (e CouponScope) IsValid() bool {
 	switch e {
-	case ListTypeFavorites, ListTypeWatchList, ListTypePersonal:
+	case CouponScopeProduct, CouponScopeBrand, CouponScopeCategory:
 		return true
 	}
 	return false
 }
 
-func (e ListType) String() string {
+func (e CouponScope) String() string {
 	return string(e)
 }
 
-func (e *ListType) UnmarshalGQL(v interface{}) error {
+func (e *CouponScope) UnmarshalGQL(v interface{}) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = ListType(str)
+	*e = CouponScope(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid ListType", str)
+		return fmt.Errorf("%s is not a valid CouponScope", str)
 	}
 	return nil
 }
 
-func (e ListType) MarshalGQL(w io.Writer) {
+func (e CouponScope) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-type OrderByType string
+type CouponStatus string
 
 const (
-	OrderByTypeAsc  OrderByType = "ASC"
-	OrderByTypeDesc OrderByType = "DESC"
+	CouponStatusPending  CouponStatus = "PENDING"
+	CouponStatusApproved CouponStatus = "APPROVED"
+	CouponStatusRejected CouponStatus = "REJECTED"
 )
 
-var AllOrderByType = []OrderByType{
-	OrderByTypeAsc,
-	OrderByTypeDesc,
+var AllCouponStatus = []CouponStatus{
+	CouponStatusPending,
+	CouponStatusApproved,
+	CouponStatusRejected,
 }
 
-func (e OrderByType) IsValid() bool {
+func (e CouponStatus) IsValid() bool {
 	switch e {
-	case OrderByTypeAsc, OrderByTypeDesc:
+	case CouponStatusPending, CouponStatusApproved, CouponStatusRejected:
 		return true
 	}
 	return false
 }
 
-func (e OrderByType) String() string {
+func (e CouponStatus) String() string {
 	return string(e)
 }
 
-func (e *OrderByType) UnmarshalGQL(v interface{}) error {
+func (e *CouponStatus) UnmarshalGQL(v interface{}) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = OrderByType(str)
+	*e = CouponStatus(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid OrderByType", str)
+		return fmt.Errorf("%s is not a valid CouponStatus", str)
 	}
 	return nil
 }
 
-func (e OrderByType) MarshalGQL(w io.Writer) {
+func (e CouponStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type CouponType string
+
+const (
+	CouponTypeStore        CouponType = "STORE"
+	CouponTypeManufacturer CouponType = "MANUFACTURER"
+)
+
+var AllCouponType = []CouponType{
+	CouponTypeStore,
+	CouponTypeManufacturer,
+}
+
+func (e CouponType) IsValid() bool {
+	switch e {
+	case CouponTypeStore, CouponTypeManufacturer:
+		return true
+	}
+	return false
+}
+
+func (e CouponType) String() string {
+	return string(e)
+}
+
+func (e *CouponType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CouponType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CouponType", str)
+	}
+	return nil
+}
+
+func (e CouponType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type DataExportFormat string
+
+const (
+	DataExportFormatJSON DataExportFormat = "JSON"
+	DataExportFormatZip  DataExportFormat = "ZIP"
+)
+
+var AllDataExportFormat = []DataExportFormat{
+	DataExportFormatJSON,
+	DataExportFormatZip,
+}
+
+func (e DataExportFormat) IsValid() bool {
+	switch e {
+	case DataExportFormatJSON, DataExportFormatZip:
+		return true
+	}
+	return false
+}
+
+func (e DataExportFormat) String() string {
+	return string(e)
+}
+
+func (e *DataExportFormat) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DataExportFormat(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DataExportFormat", str)
+	}
+	return nil
+}
+
+func (e DataExportFormat) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "PENDING"
+	JobStatusRunning   JobStatus = "RUNNING"
+	JobStatusSucceeded JobStatus = "SUCCEEDED"
+	JobStatusFailed    JobStatus = "FAILED"
+)
+
+var AllJobStatus = []JobStatus{
+	JobStatusPending,
+	JobStatusRunning,
+	JobStatusSucceeded,
+	JobStatusFailed,
+}
+
+func (e JobStatus) IsValid() bool {
+	switch e {
+	case JobStatusPending, JobStatusRunning, JobStatusSucceeded, JobStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e JobStatus) String() string {
+	return string(e)
+}
+
+func (e *JobStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = JobStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid JobStatus", str)
+	}
+	return nil
+}
+
+func (e JobStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type LeaderboardPeriod string
+
+const (
+	LeaderboardPeriodWeekly  LeaderboardPeriod = "WEEKLY"
+	LeaderboardPeriodMonthly LeaderboardPeriod = "MONTHLY"
+	LeaderboardPeriodAllTime LeaderboardPeriod = "ALL_TIME"
+)
+
+var AllLeaderboardPeriod = []LeaderboardPeriod{
+	LeaderboardPeriodWeekly,
+	LeaderboardPeriodMonthly,
+	LeaderboardPeriodAllTime,
+}
+
+func (e LeaderboardPeriod) IsValid() bool {
+	switch e {
+	case LeaderboardPeriodWeekly, LeaderboardPeriodMonthly, LeaderboardPeriodAllTime:
+		return true
+	}
+	return false
+}
+
+func (e LeaderboardPeriod) String() string {
+	return string(e)
+}
+
+func (e *LeaderboardPeriod) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = LeaderboardPeriod(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid LeaderboardPeriod", str)
+	}
+	return nil
+}
+
+func (e LeaderboardPeriod) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ListType string
+
+const (
+	ListTypeFavorites ListType = "FAVORITES"
+	ListTypeWatchList ListType = "WATCH_LIST"
+	ListTypePersonal  ListType = "PERSONAL"
+)
+
+var AllListType = []ListType{
+	ListTypeFavorites,
+	ListTypeWatchList,
+	ListTypePersonal,
+}
+
+func (e ListType) IsValid() bool {
+	switch e {
+	case ListTypeFavorites, ListTypeWatchList, ListTypePersonal:
+		return true
+	}
+	return false
+}
+
+func (e ListType) String() string {
+	return string(e)
+}
+
+func (e *ListType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ListType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ListType", str)
+	}
+	return nil
+}
+
+func (e ListType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type OrderByType string
+
+const (
+	OrderByTypeAsc  OrderByType = "ASC"
+	OrderByTypeDesc OrderByType = "DESC"
+)
+
+var AllOrderByType = []OrderByType{
+	OrderByTypeAsc,
+	OrderByTypeDesc,
+}
+
+func (e OrderByType) IsValid() bool {
+	switch e {
+	case OrderByTypeAsc, OrderByTypeDesc:
+		return true
+	}
+	return false
+}
+
+func (e OrderByType) String() string {
+	return string(e)
+}
+
+func (e *OrderByType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OrderByType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OrderByType", str)
+	}
+	return nil
+}
+
+func (e OrderByType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type PriceDealType string
+
+const (
+	PriceDealTypeMultiBuy PriceDealType = "MULTI_BUY"
+	PriceDealTypeBuyXGetY PriceDealType = "BUY_X_GET_Y"
+	PriceDealTypeDiscount PriceDealType = "DISCOUNT"
+)
+
+var AllPriceDealType = []PriceDealType{
+	PriceDealTypeMultiBuy,
+	PriceDealTypeBuyXGetY,
+	PriceDealTypeDiscount,
+}
+
+func (e PriceDealType) IsValid() bool {
+	switch e {
+	case PriceDealTypeMultiBuy, PriceDealTypeBuyXGetY, PriceDealTypeDiscount:
+		return true
+	}
+	return false
+}
+
+func (e PriceDealType) String() string {
+	return string(e)
+}
+
+func (e *PriceDealType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PriceDealType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PriceDealType", str)
+	}
+	return nil
+}
+
+func (e PriceDealType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type PriceReviewStatus string
+
+const (
+	PriceReviewStatusFlagged  PriceReviewStatus = "FLAGGED"
+	PriceReviewStatusApproved PriceReviewStatus = "APPROVED"
+	PriceReviewStatusRejected PriceReviewStatus = "REJECTED"
+)
+
+var AllPriceReviewStatus = []PriceReviewStatus{
+	PriceReviewStatusFlagged,
+	PriceReviewStatusApproved,
+	PriceReviewStatusRejected,
+}
+
+func (e PriceReviewStatus) IsValid() bool {
+	switch e {
+	case PriceReviewStatusFlagged, PriceReviewStatusApproved, PriceReviewStatusRejected:
+		return true
+	}
+	return false
+}
+
+func (e PriceReviewStatus) String() string {
+	return string(e)
+}
+
+func (e *PriceReviewStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PriceReviewStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PriceReviewStatus", str)
+	}
+	return nil
+}
+
+func (e PriceReviewStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ProductBillingStatus string
+
+const (
+	ProductBillingStatusApproved ProductBillingStatus = "APPROVED"
+	ProductBillingStatusHeld     ProductBillingStatus = "HELD"
+	ProductBillingStatusRejected ProductBillingStatus = "REJECTED"
+)
+
+var AllProductBillingStatus = []ProductBillingStatus{
+	ProductBillingStatusApproved,
+	ProductBillingStatusHeld,
+	ProductBillingStatusRejected,
+}
+
+func (e ProductBillingStatus) IsValid() bool {
+	switch e {
+	case ProductBillingStatusApproved, ProductBillingStatusHeld, ProductBillingStatusRejected:
+		return true
+	}
+	return false
+}
+
+func (e ProductBillingStatus) String() string {
+	return string(e)
+}
+
+func (e *ProductBillingStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ProductBillingStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ProductBillingStatus", str)
+	}
+	return nil
+}
+
+func (e ProductBillingStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ProductBillingType string
+
+const (
+	ProductBillingTypeCreate ProductBillingType = "CREATE"
+	ProductBillingTypeUpdate ProductBillingType = "UPDATE"
+	ProductBillingTypeScan   ProductBillingType = "SCAN"
+	ProductBillingTypePrice  ProductBillingType = "PRICE"
+)
+
+var AllProductBillingType = []ProductBillingType{
+	ProductBillingTypeCreate,
+	ProductBillingTypeUpdate,
+	ProductBillingTypeScan,
+	ProductBillingTypePrice,
+}
+
+func (e ProductBillingType) IsValid() bool {
+	switch e {
+	case ProductBillingTypeCreate, ProductBillingTypeUpdate, ProductBillingTypeScan, ProductBillingTypePrice:
+		return true
+	}
+	return false
+}
+
+func (e ProductBillingType) String() string {
+	return string(e)
+}
+
+func (e *ProductBillingType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ProductBillingType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ProductBillingType", str)
+	}
+	return nil
+}
+
+func (e ProductBillingType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ProductEditProposalStatus string
+
+const (
+	ProductEditProposalStatusPending           ProductEditProposalStatus = "PENDING"
+	ProductEditProposalStatusApproved          ProductEditProposalStatus = "APPROVED"
+	ProductEditProposalStatusPartiallyApproved ProductEditProposalStatus = "PARTIALLY_APPROVED"
+	ProductEditProposalStatusRejected          ProductEditProposalStatus = "REJECTED"
+)
+
+var AllProductEditProposalStatus = []ProductEditProposalStatus{
+	ProductEditProposalStatusPending,
+	ProductEditProposalStatusApproved,
+	ProductEditProposalStatusPartiallyApproved,
+	ProductEditProposalStatusRejected,
+}
+
+func (e ProductEditProposalStatus) IsValid() bool {
+	switch e {
+	case ProductEditProposalStatusPending, ProductEditProposalStatusApproved, ProductEditProposalStatusPartiallyApproved, ProductEditProposalStatusRejected:
+		return true
+	}
+	return false
+}
+
+func (e ProductEditProposalStatus) String() string {
+	return string(e)
+}
+
+func (e *ProductEditProposalStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ProductEditProposalStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ProductEditProposalStatus", str)
+	}
+	return nil
+}
+
+func (e ProductEditProposalStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ProductImageRole string
+
+const (
+	ProductImageRoleFront       ProductImageRole = "FRONT"
+	ProductImageRoleBack        ProductImageRole = "BACK"
+	ProductImageRoleNutrition   ProductImageRole = "NUTRITION"
+	ProductImageRoleIngredients ProductImageRole = "INGREDIENTS"
+	ProductImageRoleShelfTag    ProductImageRole = "SHELF_TAG"
+)
+
+var AllProductImageRole = []ProductImageRole{
+	ProductImageRoleFront,
+	ProductImageRoleBack,
+	ProductImageRoleNutrition,
+	ProductImageRoleIngredients,
+	ProductImageRoleShelfTag,
+}
+
+func (e ProductImageRole) IsValid() bool {
+	switch e {
+	case ProductImageRoleFront, ProductImageRoleBack, ProductImageRoleNutrition, ProductImageRoleIngredients, ProductImageRoleShelfTag:
+		return true
+	}
+	return false
+}
+
+func (e ProductImageRole) String() string {
+	return string(e)
+}
+
+func (e *ProductImageRole) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ProductImageRole(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ProductImageRole", str)
+	}
+	return nil
+}
+
+func (e ProductImageRole) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ProductImageStatus string
+
+const (
+	ProductImageStatusPending  ProductImageStatus = "PENDING"
+	ProductImageStatusApproved ProductImageStatus = "APPROVED"
+	ProductImageStatusRejected ProductImageStatus = "REJECTED"
+)
+
+var AllProductImageStatus = []ProductImageStatus{
+	ProductImageStatusPending,
+	ProductImageStatusApproved,
+	ProductImageStatusRejected,
+}
+
+func (e ProductImageStatus) IsValid() bool {
+	switch e {
+	case ProductImageStatusPending, ProductImageStatusApproved, ProductImageStatusRejected:
+		return true
+	}
+	return false
+}
+
+func (e ProductImageStatus) String() string {
+	return string(e)
+}
+
+func (e *ProductImageStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ProductImageStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ProductImageStatus", str)
+	}
+	return nil
+}
+
+func (e ProductImageStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ProductRevisionAction string
+
+const (
+	ProductRevisionActionCreate ProductRevisionAction = "CREATE"
+	ProductRevisionActionUpdate ProductRevisionAction = "UPDATE"
+	ProductRevisionActionRevert ProductRevisionAction = "REVERT"
+)
+
+var AllProductRevisionAction = []ProductRevisionAction{
+	ProductRevisionActionCreate,
+	ProductRevisionActionUpdate,
+	ProductRevisionActionRevert,
+}
+
+func (e ProductRevisionAction) IsValid() bool {
+	switch e {
+	case ProductRevisionActionCreate, ProductRevisionActionUpdate, ProductRevisionActionRevert:
+		return true
+	}
+	return false
+}
+
+func (e ProductRevisionAction) String() string {
+	return string(e)
+}
+
+func (e *ProductRevisionAction) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ProductRevisionAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ProductRevisionAction", str)
+	}
+	return nil
+}
+
+func (e ProductRevisionAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type StockPriceTransitionReason string
+
+const (
+	StockPriceTransitionReasonSaleExpired StockPriceTransitionReason = "SALE_EXPIRED"
+)
+
+var AllStockPriceTransitionReason = []StockPriceTransitionReason{
+	StockPriceTransitionReasonSaleExpired,
+}
+
+func (e StockPriceTransitionReason) IsValid() bool {
+	switch e {
+	case StockPriceTransitionReasonSaleExpired:
+		return true
+	}
+	return false
+}
+
+func (e StockPriceTransitionReason) String() string {
+	return string(e)
+}
+
+func (e *StockPriceTransitionReason) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = StockPriceTransitionReason(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid StockPriceTransitionReason", str)
+	}
+	return nil
+}
+
+func (e StockPriceTransitionReason) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type StoreRole string
+
+const (
+	StoreRoleOwner   StoreRole = "OWNER"
+	StoreRoleManager StoreRole = "MANAGER"
+	StoreRoleStaff   StoreRole = "STAFF"
+)
+
+var AllStoreRole = []StoreRole{
+	StoreRoleOwner,
+	StoreRoleManager,
+	StoreRoleStaff,
+}
+
+func (e StoreRole) IsValid() bool {
+	switch e {
+	case StoreRoleOwner, StoreRoleManager, StoreRoleStaff:
+		return true
+	}
+	return false
+}
+
+func (e StoreRole) String() string {
+	return string(e)
+}
+
+func (e *StoreRole) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = StoreRole(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid StoreRole", str)
+	}
+	return nil
+}
+
+func (e StoreRole) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
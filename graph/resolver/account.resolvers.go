@@ -0,0 +1,55 @@
+package gresolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.44
+
+import (
+	"context"
+
+	"github.com/pricetra/api/graph"
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+// RequestAccountDeletion is the resolver for the requestAccountDeletion field.
+func (r *mutationResolver) RequestAccountDeletion(ctx context.Context) (*gmodel.User, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	updated_user, err := r.Service.RequestAccountDeletion(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &updated_user, nil
+}
+
+// CancelAccountDeletion is the resolver for the cancelAccountDeletion field.
+func (r *mutationResolver) CancelAccountDeletion(ctx context.Context) (*gmodel.User, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	updated_user, err := r.Service.CancelAccountDeletion(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &updated_user, nil
+}
+
+// ExportMyData is the resolver for the exportMyData field.
+func (r *queryResolver) ExportMyData(ctx context.Context, format *gmodel.DataExportFormat) (*gmodel.DataExport, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	export_format := gmodel.DataExportFormatJSON
+	if format != nil {
+		export_format = *format
+	}
+	export, err := r.Service.ExportUserData(ctx, user, export_format)
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// Mutation returns graph.MutationResolver implementation.
+func (r *Resolver) Mutation() graph.MutationResolver { return &mutationResolver{r} }
+
+// Query returns graph.QueryResolver implementation.
+func (r *Resolver) Query() graph.QueryResolver { return &queryResolver{r} }
+
+type mutationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
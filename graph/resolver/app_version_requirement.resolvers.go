@@ -11,7 +11,6 @@ import (
 	"github.com/go-jet/jet/v2/postgres"
 	"github.com/pricetra/api/database/jet/postgres/public/model"
 	"github.com/pricetra/api/database/jet/postgres/public/table"
-	"github.com/pricetra/api/graph"
 	"github.com/pricetra/api/graph/gmodel"
 	"golang.org/x/mod/semver"
 )
@@ -38,8 +37,3 @@ func (r *queryResolver) CheckAppVersion(ctx context.Context, platform gmodel.Aut
 	}
 	return semver.Compare(cur_version, "v"+ver.MinVersion) >= 0, nil
 }
-
-// Query returns graph.QueryResolver implementation.
-func (r *Resolver) Query() graph.QueryResolver { return &queryResolver{r} }
-
-type queryResolver struct{ *Resolver }
@@ -0,0 +1,20 @@
+package gresolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.44
+
+import (
+	"context"
+
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+// AuditLogs is the resolver for the auditLogs field.
+func (r *queryResolver) AuditLogs(ctx context.Context, paginator gmodel.PaginatorInput, filters *gmodel.AuditLogFilter) (*gmodel.PaginatedAuditLogs, error) {
+	res, err := r.Service.PaginatedAuditLogs(ctx, paginator, filters)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
@@ -7,10 +7,59 @@ package gresolver
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/pricetra/api/graph/gmodel"
 )
 
+// CreatePayoutBatch is the resolver for the createPayoutBatch field.
+func (r *mutationResolver) CreatePayoutBatch(ctx context.Context, cutoff time.Time, minimumAmount *float64) (*gmodel.PayoutBatch, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	batch, err := r.Service.CreatePayoutBatch(ctx, user, cutoff, minimumAmount)
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// CreateBillingRate is the resolver for the createBillingRate field.
+func (r *mutationResolver) CreateBillingRate(ctx context.Context, input gmodel.CreateBillingRate) (*gmodel.ProductBillingRate, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	rate, err := r.Service.CreateBillingRate(ctx, user, input)
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// UpdateBillingRate is the resolver for the updateBillingRate field.
+func (r *mutationResolver) UpdateBillingRate(ctx context.Context, id int64, input gmodel.UpdateBillingRate) (*gmodel.ProductBillingRate, error) {
+	rate, err := r.Service.UpdateBillingRate(ctx, id, input)
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// DeleteBillingRate is the resolver for the deleteBillingRate field.
+func (r *mutationResolver) DeleteBillingRate(ctx context.Context, id int64) (*gmodel.ProductBillingRate, error) {
+	rate, err := r.Service.DeleteBillingRate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// ReviewProductBilling is the resolver for the reviewProductBilling field.
+func (r *mutationResolver) ReviewProductBilling(ctx context.Context, id int64, status gmodel.ProductBillingStatus, reason *string) (*gmodel.ProductBilling, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	billing, err := r.Service.ReviewProductBilling(ctx, user, id, status, reason)
+	if err != nil {
+		return nil, err
+	}
+	return &billing, nil
+}
+
 // MyProductBillingData is the resolver for the myProductBillingData field.
 func (r *queryResolver) MyProductBillingData(ctx context.Context, paginator gmodel.PaginatorInput) (*gmodel.PaginatedProductBilling, error) {
 	user := r.Service.GetAuthUserFromContext(ctx)
@@ -33,3 +82,84 @@ func (r *queryResolver) ProductBillingDataByUserID(ctx context.Context, userID i
 	}
 	return &res, nil
 }
+
+// MyEarningsSummary is the resolver for the myEarningsSummary field.
+func (r *queryResolver) MyEarningsSummary(ctx context.Context) ([]*gmodel.EarningsSummary, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	summary, err := r.Service.EarningsSummary(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*gmodel.EarningsSummary, len(summary))
+	for i := range summary {
+		res[i] = &summary[i]
+	}
+	return res, nil
+}
+
+// MyPayouts is the resolver for the myPayouts field.
+func (r *queryResolver) MyPayouts(ctx context.Context, paginator gmodel.PaginatorInput) (*gmodel.PaginatedPayouts, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	res, err := r.Service.PaginatedPayoutsByUser(ctx, paginator, user)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// PayoutBatches is the resolver for the payoutBatches field.
+func (r *queryResolver) PayoutBatches(ctx context.Context, paginator gmodel.PaginatorInput) (*gmodel.PaginatedPayoutBatches, error) {
+	res, err := r.Service.PaginatedPayoutBatches(ctx, paginator)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Payouts is the resolver for the payouts field.
+func (r *queryResolver) Payouts(ctx context.Context, payoutBatchID int64) ([]*gmodel.Payout, error) {
+	payouts, err := r.Service.FindPayoutsByBatch(ctx, payoutBatchID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*gmodel.Payout, len(payouts))
+	for i := range payouts {
+		res[i] = &payouts[i]
+	}
+	return res, nil
+}
+
+// PayoutStatement is the resolver for the payoutStatement field.
+func (r *queryResolver) PayoutStatement(ctx context.Context, payoutID int64) (*gmodel.PayoutStatement, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	statement, err := r.Service.PayoutStatement(ctx, user, payoutID)
+	if err != nil {
+		return nil, err
+	}
+	return &statement, nil
+}
+
+// BillingRates is the resolver for the billingRates field.
+func (r *queryResolver) BillingRates(ctx context.Context, filters *gmodel.BillingRateFilter) ([]*gmodel.ProductBillingRate, error) {
+	rates, err := r.Service.FindBillingRates(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*gmodel.ProductBillingRate, len(rates))
+	for i := range rates {
+		res[i] = &rates[i]
+	}
+	return res, nil
+}
+
+// FlaggedProductBilling is the resolver for the flaggedProductBilling field.
+func (r *queryResolver) FlaggedProductBilling(ctx context.Context, paginator gmodel.PaginatorInput, status *gmodel.ProductBillingStatus) (*gmodel.PaginatedProductBilling, error) {
+	res, err := r.Service.FlaggedProductBilling(ctx, paginator, status)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
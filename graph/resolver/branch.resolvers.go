@@ -7,8 +7,8 @@ package gresolver
 import (
 	"context"
 
-	"github.com/pricetra/api/graph"
 	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/services"
 )
 
 // CreateBranchWithFullAddress is the resolver for the createBranchWithFullAddress field.
@@ -31,6 +31,16 @@ func (r *mutationResolver) CreateBranch(ctx context.Context, input gmodel.Create
 	return &branch, nil
 }
 
+// UpdateBranch is the resolver for the updateBranch field.
+func (r *mutationResolver) UpdateBranch(ctx context.Context, branchID int64, input gmodel.UpdateBranch) (*gmodel.Branch, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	branch, err := r.Service.UpdateBranch(ctx, user, branchID, input)
+	if err != nil {
+		return nil, err
+	}
+	return &branch, nil
+}
+
 // AllBranches is the resolver for the allBranches field.
 func (r *queryResolver) AllBranches(ctx context.Context, storeID int64, paginator gmodel.PaginatorInput, search *string, location *gmodel.LocationInput) (*gmodel.PaginatedBranches, error) {
 	branches, err := r.Service.FindBranchesByStoreId(ctx, storeID, paginator, search, location)
@@ -72,19 +82,11 @@ func (r *queryResolver) BranchesWithProducts(ctx context.Context, paginator gmod
 	}
 
 	if filters != nil && filters.Query != nil && len(*filters.Query) > 1 {
-		go func() {
-			var user_ptr *gmodel.User
-			if user != (gmodel.User{}) {
-				user_ptr = &user
-			}
-			ctx := context.Background()
-			r.Service.CreateSearchHistoryEntry(ctx, *filters.Query, user_ptr)
-		}()
+		search_history := services.SearchHistoryJob{SearchTerm: *filters.Query}
+		if user.ID != 0 {
+			search_history.UserID = &user.ID
+		}
+		r.Service.EnqueueJobOrLog(ctx, services.JOB_SEARCH_HISTORY, search_history, services.EnqueueJobOptions{})
 	}
 	return &res, nil
 }
-
-// Mutation returns graph.MutationResolver implementation.
-func (r *Resolver) Mutation() graph.MutationResolver { return &mutationResolver{r} }
-
-type mutationResolver struct{ *Resolver }
@@ -0,0 +1,49 @@
+package gresolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.44
+
+import (
+	"context"
+
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+// SubmitCoupon is the resolver for the submitCoupon field.
+func (r *mutationResolver) SubmitCoupon(ctx context.Context, input gmodel.CreateCoupon) (*gmodel.Coupon, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	coupon, err := r.Service.SubmitCoupon(ctx, user, input)
+	if err != nil {
+		return nil, err
+	}
+	return &coupon, nil
+}
+
+// ReviewCoupon is the resolver for the reviewCoupon field.
+func (r *mutationResolver) ReviewCoupon(ctx context.Context, id int64, approve bool, reason *string) (*gmodel.Coupon, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	coupon, err := r.Service.ReviewCoupon(ctx, user, id, approve, reason)
+	if err != nil {
+		return nil, err
+	}
+	return &coupon, nil
+}
+
+// Coupons is the resolver for the coupons field.
+func (r *queryResolver) Coupons(ctx context.Context, paginator gmodel.PaginatorInput, filters *gmodel.CouponFilter) (*gmodel.PaginatedCoupons, error) {
+	coupons, err := r.Service.PaginatedCoupons(ctx, paginator, filters)
+	if err != nil {
+		return nil, err
+	}
+	return &coupons, nil
+}
+
+// CouponReviewQueue is the resolver for the couponReviewQueue field.
+func (r *queryResolver) CouponReviewQueue(ctx context.Context, paginator gmodel.PaginatorInput) (*gmodel.PaginatedCoupons, error) {
+	coupons, err := r.Service.PaginatedCouponReviewQueue(ctx, paginator)
+	if err != nil {
+		return nil, err
+	}
+	return &coupons, nil
+}
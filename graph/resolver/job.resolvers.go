@@ -0,0 +1,52 @@
+package gresolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.44
+
+import (
+	"context"
+
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+// EnqueueJob is the resolver for the enqueueJob field.
+func (r *mutationResolver) EnqueueJob(ctx context.Context, input gmodel.EnqueueJob) (*gmodel.Job, error) {
+	job, err := r.Service.EnqueueJobFromInput(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// RetryJob is the resolver for the retryJob field.
+func (r *mutationResolver) RetryJob(ctx context.Context, id int64) (*gmodel.Job, error) {
+	job, err := r.Service.RetryJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Jobs is the resolver for the jobs field.
+func (r *queryResolver) Jobs(ctx context.Context, paginator gmodel.PaginatorInput, filters *gmodel.JobFilter) (*gmodel.PaginatedJobs, error) {
+	jobs, err := r.Service.PaginatedJobs(ctx, paginator, filters)
+	if err != nil {
+		return nil, err
+	}
+	return &jobs, nil
+}
+
+// JobSchedules is the resolver for the jobSchedules field.
+func (r *queryResolver) JobSchedules(ctx context.Context) ([]*gmodel.JobSchedule, error) {
+	schedules, err := r.Service.JobSchedules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*gmodel.JobSchedule, len(schedules))
+	for i := range schedules {
+		res[i] = &schedules[i]
+	}
+	return res, nil
+}
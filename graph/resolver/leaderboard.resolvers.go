@@ -0,0 +1,24 @@
+package gresolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.44
+
+import (
+	"context"
+
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+// Leaderboard is the resolver for the leaderboard field.
+func (r *queryResolver) Leaderboard(ctx context.Context, period gmodel.LeaderboardPeriod, countryCode *string, administrativeDivision *string, limit *int) ([]*gmodel.LeaderboardEntry, error) {
+	entries, err := r.Service.Leaderboard(ctx, period, countryCode, administrativeDivision, limit)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*gmodel.LeaderboardEntry, len(entries))
+	for i := range entries {
+		res[i] = &entries[i]
+	}
+	return res, nil
+}
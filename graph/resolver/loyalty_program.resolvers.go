@@ -0,0 +1,77 @@
+package gresolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.44
+
+import (
+	"context"
+
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+// CreateLoyaltyProgram is the resolver for the createLoyaltyProgram field.
+func (r *mutationResolver) CreateLoyaltyProgram(ctx context.Context, input gmodel.CreateLoyaltyProgram) (*gmodel.LoyaltyProgram, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	program, err := r.Service.CreateLoyaltyProgram(ctx, user, input)
+	if err != nil {
+		return nil, err
+	}
+	return &program, nil
+}
+
+// UpdateLoyaltyProgram is the resolver for the updateLoyaltyProgram field.
+func (r *mutationResolver) UpdateLoyaltyProgram(ctx context.Context, storeID int64, id int64, input gmodel.UpdateLoyaltyProgram) (*gmodel.LoyaltyProgram, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	program, err := r.Service.UpdateLoyaltyProgram(ctx, user, storeID, id, input)
+	if err != nil {
+		return nil, err
+	}
+	return &program, nil
+}
+
+// JoinLoyaltyProgram is the resolver for the joinLoyaltyProgram field.
+func (r *mutationResolver) JoinLoyaltyProgram(ctx context.Context, loyaltyProgramID int64) (*gmodel.UserLoyaltyProgram, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	membership, err := r.Service.JoinLoyaltyProgram(ctx, user, loyaltyProgramID)
+	if err != nil {
+		return nil, err
+	}
+	return &membership, nil
+}
+
+// LeaveLoyaltyProgram is the resolver for the leaveLoyaltyProgram field.
+func (r *mutationResolver) LeaveLoyaltyProgram(ctx context.Context, loyaltyProgramID int64) (bool, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	if err := r.Service.LeaveLoyaltyProgram(ctx, user, loyaltyProgramID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// LoyaltyPrograms is the resolver for the loyaltyPrograms field.
+func (r *queryResolver) LoyaltyPrograms(ctx context.Context, storeID int64) ([]*gmodel.LoyaltyProgram, error) {
+	programs, err := r.Service.LoyaltyPrograms(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*gmodel.LoyaltyProgram, len(programs))
+	for i := range programs {
+		res[i] = &programs[i]
+	}
+	return res, nil
+}
+
+// MyLoyaltyPrograms is the resolver for the myLoyaltyPrograms field.
+func (r *queryResolver) MyLoyaltyPrograms(ctx context.Context) ([]*gmodel.UserLoyaltyProgram, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	programs, err := r.Service.UserLoyaltyPrograms(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*gmodel.UserLoyaltyProgram, len(programs))
+	for i := range programs {
+		res[i] = &programs[i]
+	}
+	return res, nil
+}
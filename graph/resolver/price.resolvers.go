@@ -6,11 +6,11 @@ package gresolver
 
 import (
 	"context"
+	"log"
 
-	"github.com/go-jet/jet/v2/postgres"
 	"github.com/pricetra/api/database/jet/postgres/public/model"
-	"github.com/pricetra/api/database/jet/postgres/public/table"
 	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/services"
 )
 
 // CreatePrice is the resolver for the createPrice field.
@@ -26,49 +26,63 @@ func (r *mutationResolver) CreatePrice(ctx context.Context, input gmodel.CreateP
 	}
 
 	product, _ := r.Service.FindProductById(ctx, input.ProductID)
-	price_enum := model.ProductBillingType_Price
-	if old_price_err != nil {
-		r.Service.CreateProductBilling(ctx, user, price_enum, product, input, nil)
-	} else {
-		r.Service.CreateProductBilling(ctx, user, price_enum, product, input, old_price)
+	var old_data any
+	if old_price_err == nil {
+		old_data = old_price
+	}
+	if _, err := r.Service.EnqueueProductBilling(ctx, user, model.ProductBillingType_Price, product, &input.BranchID, input, old_data); err != nil {
+		log.Printf("could not enqueue billing of price %d. %s\n", price.ID, err.Error())
+	}
+
+	// the new report confirms or disputes the previous price
+	if old_price_err == nil && old_price.CreatedByID != nil && *old_price.CreatedByID != user.ID {
+		r.Service.EnqueueUserReputationRefresh(ctx, *old_price.CreatedByID)
 	}
 
 	// Send push notification to users
-	go func() {
-		ctx := context.Background()
-		qb := table.ProductList.
-			SELECT(table.User.AllColumns, table.AuthState.AllColumns).
-			FROM(
-				table.ProductList.
-					INNER_JOIN(table.List,
-						table.List.ID.EQ(table.ProductList.ListID).
-							AND(table.List.Type.EQ(
-								postgres.NewEnumValue(model.ListType_WatchList.String()),
-							)),
-					).
-					INNER_JOIN(table.Product, table.Product.ID.EQ(table.ProductList.ProductID)).
-					INNER_JOIN(table.Stock, table.Stock.ID.EQ(table.ProductList.StockID)).
-					INNER_JOIN(table.User, table.User.ID.EQ(table.ProductList.UserID)).
-					INNER_JOIN(table.AuthState,
-						table.AuthState.UserID.EQ(table.User.ID).
-							AND(table.AuthState.LoggedInAt.GT_EQ(
-								postgres.NOW().SUB(postgres.INTERVAL(30, postgres.DAY)),
-							)),
-					),
-			).
-			WHERE(
-				table.Product.ID.EQ(postgres.Int(input.ProductID)).
-					AND(table.Stock.ID.EQ(postgres.Int(price.StockID))).
-					AND(table.User.ID.NOT_EQ(postgres.Int(user.ID))).
-					AND(table.AuthState.ExpoPushToken.IS_NOT_NULL()),
-			).
-			ORDER_BY(table.ProductList.CreatedAt.ASC())
-		var users []gmodel.User
-		if qb.QueryContext(ctx, r.Service.DB, &users) != nil {
-			return
+	notification := services.PriceChangeNotificationJob{
+		PriceID:    price.ID,
+		ReporterID: user.ID,
+	}
+	if old_price_err == nil {
+		notification.OldPriceID = &old_price.ID
+	}
+	r.Service.EnqueueJobOrLog(ctx, services.JOB_PRICE_CHANGE_NOTIFICATION, notification, services.EnqueueJobOptions{})
+	return &price, nil
+}
+
+// ExtractPriceFromShelfTag is the resolver for the extractPriceFromShelfTag field.
+func (r *mutationResolver) ExtractPriceFromShelfTag(ctx context.Context, branchID int64, base64Image string, submit *bool) (*gmodel.ShelfTagExtraction, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	extraction, image, err := r.Service.ExtractPriceFromShelfTag(ctx, user, branchID, base64Image)
+	if err != nil {
+		return nil, err
+	}
+	if extraction.Draft == nil || (submit != nil && !*submit) {
+		return &extraction, nil
+	}
+
+	branch, err := r.Service.FindBranchById(ctx, branchID)
+	if err != nil {
+		return nil, err
+	}
+	if r.Service.CanSubmitShelfTagPrice(ctx, user, branch.StoreID) {
+		price, err := r.CreatePrice(ctx, services.ShelfTagCreatePrice(*extraction.Draft, image))
+		if err != nil {
+			return nil, err
 		}
-		r.Service.SendPriceChangePushNotifications(ctx, users, price, old_price)
-	}()
+		extraction.Price = price
+	}
+	return &extraction, nil
+}
+
+// ReviewPrice is the resolver for the reviewPrice field.
+func (r *mutationResolver) ReviewPrice(ctx context.Context, id int64, approve bool, reason *string) (*gmodel.Price, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	price, err := r.Service.ReviewPrice(ctx, user, id, approve, reason)
+	if err != nil {
+		return nil, err
+	}
 	return &price, nil
 }
 
@@ -80,3 +94,12 @@ func (r *queryResolver) PriceChangeHistory(ctx context.Context, productID int64,
 	}
 	return &res, nil
 }
+
+// PriceReviewQueue is the resolver for the priceReviewQueue field.
+func (r *queryResolver) PriceReviewQueue(ctx context.Context, paginator gmodel.PaginatorInput, status *gmodel.PriceReviewStatus) (*gmodel.PaginatedPriceHistory, error) {
+	res, err := r.Service.PaginatedPriceReviewQueue(ctx, paginator, status)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
@@ -10,8 +10,8 @@ import (
 	"log"
 	"strings"
 
-	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
 	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/graph"
 	"github.com/pricetra/api/graph/gmodel"
 	"github.com/pricetra/api/services"
 )
@@ -19,6 +19,10 @@ import (
 // CreateProduct is the resolver for the createProduct field.
 func (r *mutationResolver) CreateProduct(ctx context.Context, input gmodel.CreateProduct) (*gmodel.Product, error) {
 	user := r.Service.GetAuthUserFromContext(ctx)
+	image, err := r.Service.PrepareImage(input.ImageFile, input.ImageBase64, true)
+	if err != nil {
+		return nil, err
+	}
 	source := model.ProductSourceType_Pricetra
 	product, err := r.Service.CreateProduct(ctx, user, input, &source)
 	if err != nil {
@@ -26,53 +30,59 @@ func (r *mutationResolver) CreateProduct(ctx context.Context, input gmodel.Creat
 	}
 
 	// upload image file to CDN
-	upload_params := uploader.UploadParams{
-		PublicID: product.Code,
-		Tags:     []string{"PRODUCT"},
-	}
-	if input.ImageFile != nil {
-		r.Service.GraphImageUpload(ctx, *input.ImageFile, upload_params)
-	} else if input.ImageBase64 != nil {
-		r.Service.Base64ImageUpload(ctx, *input.ImageBase64, upload_params)
+	upload_params := services.ImageUploadParams{
+		PublicID:  product.Code,
+		Tags:      []string{"PRODUCT"},
+		Thumbnail: true,
+		HashScope: services.ProductImageHashScope(product.ID),
+	}
+	var upload_result *services.ImageUploadResult
+	if image != nil {
+		upload_result, _ = r.Service.ProcessedImageUpload(ctx, *image, upload_params)
 	} else if input.ImageURL != nil {
-		r.Service.ImageUrlUpload(ctx, *input.ImageURL, upload_params)
+		upload_result, _ = r.Service.ImageUrlUpload(ctx, *input.ImageURL, upload_params)
+	}
+	if upload_result != nil {
+		r.Service.RecordProductImageRevision(ctx, user, product, upload_result)
 	}
 
 	// handle billing
-	go func() {
-		ctx := context.Background()
-		r.Service.CreateProductBilling(ctx, user, model.ProductBillingType_Create, product, input, nil)
-	}()
+	if _, err := r.Service.EnqueueProductBilling(ctx, user, model.ProductBillingType_Create, product, nil, input, nil); err != nil {
+		log.Printf("could not enqueue billing of product %d. %s\n", product.ID, err.Error())
+	}
 	return &product, nil
 }
 
 // UpdateProduct is the resolver for the updateProduct field.
 func (r *mutationResolver) UpdateProduct(ctx context.Context, id int64, input gmodel.UpdateProduct) (*gmodel.Product, error) {
 	user := r.Service.GetAuthUserFromContext(ctx)
+	if r.Service.RequiresProductEditProposal(user) {
+		return nil, fmt.Errorf("product edits must be reviewed, use proposeProductEdit instead")
+	}
+
+	image, err := r.Service.PrepareImage(input.ImageFile, input.ImageBase64, true)
+	if err != nil {
+		return nil, err
+	}
 	product, old_product, err := r.Service.UpdateProductById(ctx, user, id, input)
 	if err != nil {
 		return nil, err
 	}
 
-	// upload image file to CDN
-	if input.ImageFile != nil || input.ImageBase64 != nil {
-		r.Service.DeleteImageUpload(ctx, product.Code)
-	}
-	upload_params := uploader.UploadParams{
-		PublicID: product.Code,
-		Tags:     []string{"PRODUCT"},
-	}
-	if input.ImageFile != nil {
-		r.Service.GraphImageUpload(ctx, *input.ImageFile, upload_params)
-	} else if input.ImageBase64 != nil {
-		r.Service.Base64ImageUpload(ctx, *input.ImageBase64, upload_params)
+	// upload image file to CDN as the new primary product image
+	if image != nil {
+		if public_id, err := r.Service.ReplacePrimaryProductImage(ctx, user, product, image, nil); err != nil {
+			log.Printf("could not upload image of product %d. %s\n", product.ID, err.Error())
+		} else {
+			r.Service.RecordPrimaryProductImageRevision(ctx, user, product, public_id)
+			product.Image = r.Service.ImageUrl(public_id)
+		}
 	}
 
 	// Handle billing
-	go func() {
-		ctx := context.Background()
-		r.Service.CreateProductBilling(ctx, user, model.ProductBillingType_Update, product, input, old_product)
-	}()
+	if _, err := r.Service.EnqueueProductBilling(ctx, user, model.ProductBillingType_Update, product, nil, input, old_product); err != nil {
+		log.Printf("could not enqueue billing of product %d. %s\n", product.ID, err.Error())
+	}
 	return &product, nil
 }
 
@@ -123,6 +133,33 @@ func (r *mutationResolver) ExtractAndCreateProduct(ctx context.Context, barcode
 	})
 }
 
+// Images is the resolver for the images field.
+func (r *productResolver) Images(ctx context.Context, obj *gmodel.Product) ([]*gmodel.ProductImage, error) {
+	approved := gmodel.ProductImageStatusApproved
+	images, err := r.Service.FindProductImages(ctx, obj.ID, &approved)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*gmodel.ProductImage, len(images))
+	for i := range images {
+		res[i] = &images[i]
+	}
+	return res, nil
+}
+
+// Variants is the resolver for the variants field.
+func (r *productResolver) Variants(ctx context.Context, obj *gmodel.Product, location *gmodel.LocationInput) ([]*gmodel.ProductVariant, error) {
+	variants, err := r.Service.ProductVariants(ctx, obj.ID, location)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*gmodel.ProductVariant, len(variants))
+	for i := range variants {
+		res[i] = &variants[i]
+	}
+	return res, nil
+}
+
 // BarcodeScan is the resolver for the barcodeScan field.
 func (r *queryResolver) BarcodeScan(ctx context.Context, barcode string, searchMode *bool) (*gmodel.Product, error) {
 	user := r.Service.GetAuthUserFromContext(ctx)
@@ -162,7 +199,7 @@ func (r *queryResolver) BarcodeScan(ctx context.Context, barcode string, searchM
 
 	// Upload image to CDN
 	if product_input.ImageURL != nil {
-		_, err := r.Service.ImageUrlUpload(ctx, *product_input.ImageURL, uploader.UploadParams{
+		_, err := r.Service.ImageUrlUpload(ctx, *product_input.ImageURL, services.ImageUploadParams{
 			PublicID: product.Code,
 			Tags:     []string{"PRODUCT"},
 		})
@@ -172,7 +209,9 @@ func (r *queryResolver) BarcodeScan(ctx context.Context, barcode string, searchM
 	}
 
 	// Handle billing
-	r.Service.CreateProductBilling(ctx, user, model.ProductBillingType_Scan, product, item, nil)
+	if _, err := r.Service.EnqueueProductBilling(ctx, user, model.ProductBillingType_Scan, product, nil, item, nil); err != nil {
+		log.Printf("could not enqueue billing of product %d. %s\n", product.ID, err.Error())
+	}
 	return &product, nil
 }
 
@@ -183,17 +222,21 @@ func (r *queryResolver) AllProducts(ctx context.Context, paginator gmodel.Pagina
 	if err != nil {
 		return nil, err
 	}
+	stocks := make([]*gmodel.Stock, len(paginated_products.Products))
+	for i, product := range paginated_products.Products {
+		stocks[i] = product.Stock
+	}
+	if err := r.Service.ApplyViewerPrices(ctx, user, stocks); err != nil {
+		return nil, err
+	}
 
 	if search != nil && search.Query != nil && len(*search.Query) > 1 {
 		// search term is provided so create log in search_history table
-		go func() {
-			var user_ptr *gmodel.User
-			if user != (gmodel.User{}) {
-				user_ptr = &user
-			}
-			ctx := context.Background()
-			r.Service.CreateSearchHistoryEntry(ctx, *search.Query, user_ptr)
-		}()
+		search_history := services.SearchHistoryJob{SearchTerm: *search.Query}
+		if user.ID != 0 {
+			search_history.UserID = &user.ID
+		}
+		r.Service.EnqueueJobOrLog(ctx, services.JOB_SEARCH_HISTORY, search_history, services.EnqueueJobOptions{})
 	}
 	return &paginated_products, nil
 }
@@ -223,7 +266,7 @@ func (r *queryResolver) Product(ctx context.Context, id int64, viewerTrail *gmod
 		return nil, err
 	}
 
-	if user == (gmodel.User{}) {
+	if user.ID == 0 {
 		// user is not authenticated so return basic product info
 		return &product, nil
 	}
@@ -238,21 +281,21 @@ func (r *queryResolver) Product(ctx context.Context, id int64, viewerTrail *gmod
 	}
 
 	// log visited product
-	go func() {
-		ctx := context.Background()
-		var platform *gmodel.AuthDeviceType
-		if user.AuthDevice != nil {
-			platform = user.AuthDevice
-		}
-		trail_input := services.ViewerTrailFull{
-			UserID:   &user.ID,
-			Platform: platform,
-		}
-		if viewerTrail != nil {
-			trail_input.ViewerTrailInput = *viewerTrail
-		}
-		r.Service.AddProductViewer(ctx, product.ID, trail_input)
-	}()
+	var platform *gmodel.AuthDeviceType
+	if user.AuthDevice != nil {
+		platform = user.AuthDevice
+	}
+	trail_input := services.ViewerTrailFull{
+		UserID:   &user.ID,
+		Platform: platform,
+	}
+	if viewerTrail != nil {
+		trail_input.ViewerTrailInput = *viewerTrail
+	}
+	r.Service.EnqueueJobOrLog(ctx, services.JOB_PRODUCT_VIEW, services.ProductViewJob{
+		ProductID:   product.ID,
+		ViewerTrail: trail_input,
+	}, services.EnqueueJobOptions{})
 	return &product, nil
 }
 
@@ -308,3 +351,8 @@ func (r *queryResolver) ProductSearch(ctx context.Context, paginator gmodel.Pagi
 func (r *queryResolver) WeightComponentsFromCategoryID(ctx context.Context, categoryID int64) ([]*gmodel.ProductWeightComponents, error) {
 	return r.Service.GetProductWeightComponents(ctx, categoryID)
 }
+
+// Product returns graph.ProductResolver implementation.
+func (r *Resolver) Product() graph.ProductResolver { return &productResolver{r} }
+
+type productResolver struct{ *Resolver }
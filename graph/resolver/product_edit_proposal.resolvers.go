@@ -0,0 +1,50 @@
+package gresolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.44
+
+import (
+	"context"
+
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+// ProposeProductEdit is the resolver for the proposeProductEdit field.
+func (r *mutationResolver) ProposeProductEdit(ctx context.Context, productID int64, input gmodel.UpdateProduct) (*gmodel.ProductEditProposal, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	proposal, err := r.Service.CreateProductEditProposal(ctx, user, productID, input)
+	if err != nil {
+		return nil, err
+	}
+	return &proposal, nil
+}
+
+// ReviewProductEditProposal is the resolver for the reviewProductEditProposal field.
+func (r *mutationResolver) ReviewProductEditProposal(ctx context.Context, id int64, input gmodel.ReviewProductEditProposal) (*gmodel.ProductEditProposal, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	proposal, err := r.Service.ReviewProductEditProposal(ctx, user, id, input)
+	if err != nil {
+		return nil, err
+	}
+	return &proposal, nil
+}
+
+// MyProductEditProposals is the resolver for the myProductEditProposals field.
+func (r *queryResolver) MyProductEditProposals(ctx context.Context, paginator gmodel.PaginatorInput) (*gmodel.PaginatedProductEditProposals, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	res, err := r.Service.PaginatedProductEditProposalsByUser(ctx, paginator, user)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ProductEditProposals is the resolver for the productEditProposals field.
+func (r *queryResolver) ProductEditProposals(ctx context.Context, paginator gmodel.PaginatorInput, status *gmodel.ProductEditProposalStatus, productID *int64) (*gmodel.PaginatedProductEditProposals, error) {
+	res, err := r.Service.PaginatedProductEditProposals(ctx, paginator, status, productID)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
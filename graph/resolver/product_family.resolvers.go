@@ -0,0 +1,93 @@
+package gresolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.44
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pricetra/api/graph"
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+// CreateProductFamily is the resolver for the createProductFamily field.
+func (r *mutationResolver) CreateProductFamily(ctx context.Context, input gmodel.CreateProductFamily) (*gmodel.ProductFamily, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	family, err := r.Service.CreateProductFamily(ctx, user, input)
+	if err != nil {
+		return nil, err
+	}
+	return &family, nil
+}
+
+// UpdateProductFamily is the resolver for the updateProductFamily field.
+func (r *mutationResolver) UpdateProductFamily(ctx context.Context, id int64, input gmodel.UpdateProductFamily) (*gmodel.ProductFamily, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	family, err := r.Service.UpdateProductFamily(ctx, user, id, input)
+	if err != nil {
+		return nil, err
+	}
+	return &family, nil
+}
+
+// DeleteProductFamily is the resolver for the deleteProductFamily field.
+func (r *mutationResolver) DeleteProductFamily(ctx context.Context, id int64) (*gmodel.ProductFamily, error) {
+	family, err := r.Service.DeleteProductFamily(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &family, nil
+}
+
+// AddProductToFamily is the resolver for the addProductToFamily field.
+func (r *mutationResolver) AddProductToFamily(ctx context.Context, productFamilyID int64, productID int64, input gmodel.ProductVariantInput) (*gmodel.ProductVariant, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	variant, err := r.Service.AddProductToFamily(ctx, user, productFamilyID, productID, input)
+	if err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// RemoveProductFromFamily is the resolver for the removeProductFromFamily field.
+func (r *mutationResolver) RemoveProductFromFamily(ctx context.Context, productID int64) (bool, error) {
+	return r.Service.RemoveProductFromFamily(ctx, productID)
+}
+
+// Variants is the resolver for the variants field.
+func (r *productFamilyResolver) Variants(ctx context.Context, obj *gmodel.ProductFamily, location *gmodel.LocationInput) ([]*gmodel.ProductVariant, error) {
+	variants, err := r.Service.ProductFamilyVariants(ctx, obj.ID, nil, location)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*gmodel.ProductVariant, len(variants))
+	for i := range variants {
+		res[i] = &variants[i]
+	}
+	return res, nil
+}
+
+// ProductFamily is the resolver for the productFamily field.
+func (r *queryResolver) ProductFamily(ctx context.Context, id int64) (*gmodel.ProductFamily, error) {
+	family, err := r.Service.FindProductFamilyById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("product family not found")
+	}
+	return &family, nil
+}
+
+// ProductFamilies is the resolver for the productFamilies field.
+func (r *queryResolver) ProductFamilies(ctx context.Context, paginator gmodel.PaginatorInput, search *string) (*gmodel.PaginatedProductFamilies, error) {
+	res, err := r.Service.PaginatedProductFamilies(ctx, paginator, search)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ProductFamily returns graph.ProductFamilyResolver implementation.
+func (r *Resolver) ProductFamily() graph.ProductFamilyResolver { return &productFamilyResolver{r} }
+
+type productFamilyResolver struct{ *Resolver }
@@ -0,0 +1,85 @@
+package gresolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.44
+
+import (
+	"context"
+
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+// AddProductImage is the resolver for the addProductImage field.
+func (r *mutationResolver) AddProductImage(ctx context.Context, productID int64, input gmodel.AddProductImage) (*gmodel.ProductImage, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	image, err := r.Service.AddProductImage(ctx, user, productID, input)
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+// RemoveProductImage is the resolver for the removeProductImage field.
+func (r *mutationResolver) RemoveProductImage(ctx context.Context, id int64) (*gmodel.ProductImage, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	image, err := r.Service.RemoveProductImage(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+// ReorderProductImages is the resolver for the reorderProductImages field.
+func (r *mutationResolver) ReorderProductImages(ctx context.Context, productID int64, imageIds []int64) ([]*gmodel.ProductImage, error) {
+	images, err := r.Service.ReorderProductImages(ctx, productID, imageIds)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*gmodel.ProductImage, len(images))
+	for i := range images {
+		res[i] = &images[i]
+	}
+	return res, nil
+}
+
+// SetPrimaryProductImage is the resolver for the setPrimaryProductImage field.
+func (r *mutationResolver) SetPrimaryProductImage(ctx context.Context, id int64) (*gmodel.ProductImage, error) {
+	image, err := r.Service.SetPrimaryProductImage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+// ReviewProductImage is the resolver for the reviewProductImage field.
+func (r *mutationResolver) ReviewProductImage(ctx context.Context, id int64, approve bool, notes *string) (*gmodel.ProductImage, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	image, err := r.Service.ReviewProductImage(ctx, user, id, approve, notes)
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+// ProductImages is the resolver for the productImages field.
+func (r *queryResolver) ProductImages(ctx context.Context, productID int64, status *gmodel.ProductImageStatus) ([]*gmodel.ProductImage, error) {
+	images, err := r.Service.FindProductImages(ctx, productID, status)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*gmodel.ProductImage, len(images))
+	for i := range images {
+		res[i] = &images[i]
+	}
+	return res, nil
+}
+
+// ProductImageQueue is the resolver for the productImageQueue field.
+func (r *queryResolver) ProductImageQueue(ctx context.Context, paginator gmodel.PaginatorInput, status *gmodel.ProductImageStatus) (*gmodel.PaginatedProductImages, error) {
+	res, err := r.Service.PaginatedProductImages(ctx, paginator, status)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
@@ -0,0 +1,34 @@
+package gresolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.44
+
+import (
+	"context"
+
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+// MergeProducts is the resolver for the mergeProducts field.
+func (r *mutationResolver) MergeProducts(ctx context.Context, sourceID int64, targetID int64) (*gmodel.Product, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	product, err := r.Service.MergeProducts(ctx, user, sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// DuplicateProductCandidates is the resolver for the duplicateProductCandidates field.
+func (r *queryResolver) DuplicateProductCandidates(ctx context.Context, productID int64, limit *int) ([]*gmodel.DuplicateProductCandidate, error) {
+	candidates, err := r.Service.DuplicateProductCandidates(ctx, productID, limit)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*gmodel.DuplicateProductCandidate, len(candidates))
+	for i := range candidates {
+		res[i] = &candidates[i]
+	}
+	return res, nil
+}
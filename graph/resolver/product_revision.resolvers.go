@@ -0,0 +1,30 @@
+package gresolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.44
+
+import (
+	"context"
+
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+// RevertProduct is the resolver for the revertProduct field.
+func (r *mutationResolver) RevertProduct(ctx context.Context, productID int64, revisionID int64) (*gmodel.Product, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	product, err := r.Service.RevertProduct(ctx, user, productID, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// ProductRevisions is the resolver for the productRevisions field.
+func (r *queryResolver) ProductRevisions(ctx context.Context, productID int64, paginator gmodel.PaginatorInput) (*gmodel.PaginatedProductRevisions, error) {
+	res, err := r.Service.PaginatedProductRevisions(ctx, paginator, productID)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
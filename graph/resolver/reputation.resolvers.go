@@ -0,0 +1,31 @@
+package gresolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.44
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+// MyReputation is the resolver for the myReputation field.
+func (r *queryResolver) MyReputation(ctx context.Context) (*gmodel.UserReputation, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	reputation, err := r.Service.RefreshUserReputation(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &reputation, nil
+}
+
+// UserReputation is the resolver for the userReputation field.
+func (r *queryResolver) UserReputation(ctx context.Context, userID int64) (*gmodel.UserReputation, error) {
+	reputation, err := r.Service.RefreshUserReputation(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("could not find user with id")
+	}
+	return &reputation, nil
+}
@@ -17,14 +17,36 @@ func (r *queryResolver) Stock(ctx context.Context, stockID int64) (*gmodel.Stock
 	if err != nil {
 		return nil, fmt.Errorf("could not fetch stock with the provided stockId")
 	}
+	user := r.Service.GetAuthUserFromContext(ctx)
+	if err := r.Service.ApplyViewerPrices(ctx, user, []*gmodel.Stock{&stock}); err != nil {
+		return nil, err
+	}
 	return &stock, nil
 }
 
+// StockPriceTransitions is the resolver for the stockPriceTransitions field.
+func (r *queryResolver) StockPriceTransitions(ctx context.Context, stockID int64) ([]*gmodel.StockPriceTransition, error) {
+	transitions, err := r.Service.StockPriceTransitions(ctx, stockID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*gmodel.StockPriceTransition, len(transitions))
+	for i := range transitions {
+		res[i] = &transitions[i]
+	}
+	return res, nil
+}
+
 // GetProductStocks is the resolver for the getProductStocks field.
 func (r *queryResolver) GetProductStocks(ctx context.Context, paginator gmodel.PaginatorInput, productID int64, location *gmodel.LocationInput) (*gmodel.PaginatedStocks, error) {
 	paginated_stocks, err := r.Service.PaginatedStocksForProduct(ctx, paginator, productID, location)
 	if err != nil {
 		return nil, err
 	}
+	user := r.Service.GetAuthUserFromContext(ctx)
+	if err := r.Service.ApplyViewerPrices(ctx, user, paginated_stocks.Stocks); err != nil {
+		return nil, err
+	}
 	return &paginated_stocks, nil
 }
@@ -6,9 +6,10 @@ package gresolver
 
 import (
 	"context"
+	"fmt"
 
-	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
 	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/services"
 )
 
 // CreateStore is the resolver for the createStore field.
@@ -20,7 +21,7 @@ func (r *mutationResolver) CreateStore(ctx context.Context, input gmodel.CreateS
 	}
 
 	// upload file
-	upload_params := uploader.UploadParams{
+	upload_params := services.ImageUploadParams{
 		PublicID: store.Logo,
 		Tags:     []string{"COMPANY_LOGO"},
 	}
@@ -32,6 +33,34 @@ func (r *mutationResolver) CreateStore(ctx context.Context, input gmodel.CreateS
 	return &store, nil
 }
 
+// UpdateStore is the resolver for the updateStore field.
+func (r *mutationResolver) UpdateStore(ctx context.Context, storeID int64, input gmodel.UpdateStore) (*gmodel.Store, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	old_store, err := r.Service.FindStore(ctx, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("store id is invalid")
+	}
+	store, err := r.Service.UpdateStore(ctx, user, storeID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	// upload file and delete old logo
+	if store.Logo != old_store.Logo {
+		upload_params := services.ImageUploadParams{
+			PublicID: store.Logo,
+			Tags:     []string{"COMPANY_LOGO"},
+		}
+		if input.LogoFile != nil {
+			r.Service.GraphImageUpload(ctx, *input.LogoFile, upload_params)
+		} else if input.LogoBase64 != nil {
+			r.Service.Base64ImageUpload(ctx, *input.LogoBase64, upload_params)
+		}
+		r.Service.DeleteImageUpload(ctx, old_store.Logo)
+	}
+	return &store, nil
+}
+
 // AllStores is the resolver for the allStores field.
 func (r *queryResolver) AllStores(ctx context.Context, paginator gmodel.PaginatorInput, search *string) (*gmodel.PaginatedStores, error) {
 	res, err := r.Service.PaginatedStores(ctx, paginator, search)
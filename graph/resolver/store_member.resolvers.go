@@ -0,0 +1,59 @@
+package gresolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.44
+
+import (
+	"context"
+
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+// AddStoreMember is the resolver for the addStoreMember field.
+func (r *mutationResolver) AddStoreMember(ctx context.Context, storeID int64, userID int64, role gmodel.StoreRole) (*gmodel.StoreMember, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	member, err := r.Service.AddStoreMember(ctx, user, storeID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// RemoveStoreMember is the resolver for the removeStoreMember field.
+func (r *mutationResolver) RemoveStoreMember(ctx context.Context, storeID int64, userID int64) (bool, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	if err := r.Service.RemoveStoreMember(ctx, user, storeID, userID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// StoreMembers is the resolver for the storeMembers field.
+func (r *queryResolver) StoreMembers(ctx context.Context, storeID int64) ([]*gmodel.StoreMember, error) {
+	members, err := r.Service.StoreMembers(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*gmodel.StoreMember, len(members))
+	for i := range members {
+		res[i] = &members[i]
+	}
+	return res, nil
+}
+
+// MyStoreMemberships is the resolver for the myStoreMemberships field.
+func (r *queryResolver) MyStoreMemberships(ctx context.Context) ([]*gmodel.StoreMember, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	members, err := r.Service.UserStoreMemberships(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*gmodel.StoreMember, len(members))
+	for i := range members {
+		res[i] = &members[i]
+	}
+	return res, nil
+}
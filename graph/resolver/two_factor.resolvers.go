@@ -0,0 +1,60 @@
+package gresolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.44
+
+import (
+	"context"
+
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+// VerifyTwoFactorLogin is the resolver for the verifyTwoFactorLogin field.
+func (r *mutationResolver) VerifyTwoFactorLogin(ctx context.Context, challengeID string, code string) (*gmodel.Auth, error) {
+	auth, err := r.Service.VerifyTwoFactorLogin(ctx, challengeID, code)
+	if err != nil {
+		return nil, err
+	}
+	return &auth, nil
+}
+
+// EnrollTwoFactor is the resolver for the enrollTwoFactor field.
+func (r *mutationResolver) EnrollTwoFactor(ctx context.Context) (*gmodel.TwoFactorEnrollment, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	enrollment, err := r.Service.EnrollTwoFactor(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &enrollment, nil
+}
+
+// ConfirmTwoFactor is the resolver for the confirmTwoFactor field.
+func (r *mutationResolver) ConfirmTwoFactor(ctx context.Context, code string) (*gmodel.TwoFactorRecoveryCodes, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	codes, err := r.Service.ConfirmTwoFactor(ctx, user, code)
+	if err != nil {
+		return nil, err
+	}
+	return &gmodel.TwoFactorRecoveryCodes{Codes: codes}, nil
+}
+
+// DisableTwoFactor is the resolver for the disableTwoFactor field.
+func (r *mutationResolver) DisableTwoFactor(ctx context.Context, code string) (*gmodel.User, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	updated_user, err := r.Service.DisableTwoFactor(ctx, user, code)
+	if err != nil {
+		return nil, err
+	}
+	return &updated_user, nil
+}
+
+// RegenerateTwoFactorRecoveryCodes is the resolver for the regenerateTwoFactorRecoveryCodes field.
+func (r *mutationResolver) RegenerateTwoFactorRecoveryCodes(ctx context.Context, code string) (*gmodel.TwoFactorRecoveryCodes, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	codes, err := r.Service.RegenerateTwoFactorRecoveryCodes(ctx, user, code)
+	if err != nil {
+		return nil, err
+	}
+	return &gmodel.TwoFactorRecoveryCodes{Codes: codes}, nil
+}
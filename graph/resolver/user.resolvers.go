@@ -9,9 +9,10 @@ import (
 	"fmt"
 	"net/http"
 
-	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
 	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/graph"
 	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/services"
 )
 
 // CreateAccount is the resolver for the createAccount field.
@@ -69,20 +70,22 @@ func (r *mutationResolver) ResendEmailVerificationCode(ctx context.Context, emai
 // UpdateProfile is the resolver for the updateProfile field.
 func (r *mutationResolver) UpdateProfile(ctx context.Context, input gmodel.UpdateUser) (*gmodel.User, error) {
 	user := r.Service.GetAuthUserFromContext(ctx)
+	avatar, err := r.Service.PrepareImage(input.AvatarFile, input.AvatarBase64, false)
+	if err != nil {
+		return nil, err
+	}
 	updated_user, err := r.Service.UpdateUser(ctx, user, input)
 	if err != nil {
 		return nil, err
 	}
 
 	if updated_user.Avatar != nil {
-		upload_params := uploader.UploadParams{
+		upload_params := services.ImageUploadParams{
 			PublicID: *updated_user.Avatar,
 			Tags:     []string{"USER_PROFILE"},
 		}
-		if input.AvatarFile != nil {
-			r.Service.GraphImageUpload(ctx, *input.AvatarFile, upload_params)
-		} else if input.AvatarBase64 != nil {
-			r.Service.Base64ImageUpload(ctx, *input.AvatarBase64, upload_params)
+		if avatar != nil {
+			r.Service.ProcessedImageUpload(ctx, *avatar, upload_params)
 		}
 		// Delete old avatar
 		if user.Avatar != nil && *updated_user.Avatar != *user.Avatar {
@@ -109,20 +112,22 @@ func (r *mutationResolver) UpdateUserByID(ctx context.Context, userID int64, inp
 		return nil, fmt.Errorf("user was not found")
 	}
 
+	avatar, err := r.Service.PrepareImage(input.AvatarFile, input.AvatarBase64, false)
+	if err != nil {
+		return nil, err
+	}
 	updated_user, err := r.Service.UpdateUserFull(ctx, user, input)
 	if err != nil {
 		return nil, err
 	}
 
 	if updated_user.Avatar != nil {
-		upload_params := uploader.UploadParams{
+		upload_params := services.ImageUploadParams{
 			PublicID: *updated_user.Avatar,
 			Tags:     []string{"USER_PROFILE"},
 		}
-		if input.AvatarFile != nil {
-			r.Service.GraphImageUpload(ctx, *input.AvatarFile, upload_params)
-		} else if input.AvatarBase64 != nil {
-			r.Service.Base64ImageUpload(ctx, *input.AvatarBase64, upload_params)
+		if avatar != nil {
+			r.Service.ProcessedImageUpload(ctx, *avatar, upload_params)
 		}
 		// Delete old avatar
 		if user.Avatar != nil && *updated_user.Avatar != *user.Avatar {
@@ -170,6 +175,41 @@ func (r *mutationResolver) RegisterExpoPushToken(ctx context.Context, expoPushTo
 	return &user, nil
 }
 
+// RequestEmailChange is the resolver for the requestEmailChange field.
+func (r *mutationResolver) RequestEmailChange(ctx context.Context, newEmail string) (bool, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	email_verification, err := r.Service.RequestEmailChange(ctx, user, newEmail)
+	if err != nil {
+		return false, err
+	}
+
+	new_email_user := user
+	new_email_user.Email = *email_verification.NewEmail
+	email_res, err := r.Service.SendEmailVerification(ctx, new_email_user, email_verification)
+	if err != nil {
+		return false, err
+	}
+	if email_res.StatusCode() == http.StatusBadRequest {
+		return false, fmt.Errorf("could not send email. %s", email_res.Body)
+	}
+	return true, nil
+}
+
+// ConfirmEmailChange is the resolver for the confirmEmailChange field.
+func (r *mutationResolver) ConfirmEmailChange(ctx context.Context, code string) (*gmodel.Auth, error) {
+	user := r.Service.GetAuthUserFromContext(ctx)
+	auth, old_email, err := r.Service.ConfirmEmailChange(ctx, user, code)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Service.EnqueueJobOrLog(ctx, services.JOB_EMAIL_CHANGE_NOTIFICATION, services.EmailChangeNotificationJob{
+		UserID:   auth.User.ID,
+		OldEmail: old_email,
+	}, services.EnqueueJobOptions{})
+	return &auth, nil
+}
+
 // Login is the resolver for the login field.
 func (r *queryResolver) Login(ctx context.Context, email string, password string, ipAddress *string, device *gmodel.AuthDeviceType) (*gmodel.Auth, error) {
 	var mapped_device model.AuthDeviceType
@@ -217,3 +257,21 @@ func (r *queryResolver) VerifyPasswordResetCode(ctx context.Context, email strin
 	}
 	return true, nil
 }
+
+// Achievements is the resolver for the achievements field.
+func (r *userResolver) Achievements(ctx context.Context, obj *gmodel.User) ([]*gmodel.UserAchievement, error) {
+	achievements, err := r.Service.FindUserAchievements(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*gmodel.UserAchievement, len(achievements))
+	for i := range achievements {
+		res[i] = &achievements[i]
+	}
+	return res, nil
+}
+
+// User returns graph.UserResolver implementation.
+func (r *Resolver) User() graph.UserResolver { return &userResolver{r} }
+
+type userResolver struct{ *Resolver }
@@ -14,6 +14,23 @@ import (
 	"strings"
 )
 
+// EmailChangeNotificationRequest defines model for EmailChangeNotificationRequest.
+type EmailChangeNotificationRequest struct {
+	Name           string `json:"name"`
+	NewEmail       string `json:"newEmail"`
+	RecipientEmail string `json:"recipientEmail"`
+}
+
+// EmailChangeNotificationResponse defines model for EmailChangeNotificationResponse.
+type EmailChangeNotificationResponse struct {
+	Content        string  `json:"content"`
+	Name           string  `json:"name"`
+	NewEmail       string  `json:"newEmail"`
+	RecipientEmail string  `json:"recipientEmail"`
+	Status         float64 `json:"status"`
+	Subject        string  `json:"subject"`
+}
+
 // EmailRequest defines model for EmailRequest.
 type EmailRequest struct {
 	RecipientEmail string `json:"recipientEmail"`
@@ -63,6 +80,9 @@ type PasswordResetResponse struct {
 	Subject        string  `json:"subject"`
 }
 
+// SendEmailChangeNotificationJSONRequestBody defines body for SendEmailChangeNotification for application/json ContentType.
+type SendEmailChangeNotificationJSONRequestBody = EmailChangeNotificationRequest
+
 // SendEmailVerificationCodeJSONRequestBody defines body for SendEmailVerificationCode for application/json ContentType.
 type SendEmailVerificationCodeJSONRequestBody = EmailVerificationRequest
 
@@ -142,6 +162,11 @@ func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
 
 // The interface specification for the client above.
 type ClientInterface interface {
+	// SendEmailChangeNotificationWithBody request with any body
+	SendEmailChangeNotificationWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	SendEmailChangeNotification(ctx context.Context, body SendEmailChangeNotificationJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// SendEmailVerificationCodeWithBody request with any body
 	SendEmailVerificationCodeWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -153,6 +178,30 @@ type ClientInterface interface {
 	SendPasswordResetCode(ctx context.Context, body SendPasswordResetCodeJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
 }
 
+func (c *Client) SendEmailChangeNotificationWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSendEmailChangeNotificationRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) SendEmailChangeNotification(ctx context.Context, body SendEmailChangeNotificationJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSendEmailChangeNotificationRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) SendEmailVerificationCodeWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewSendEmailVerificationCodeRequestWithBody(c.Server, contentType, body)
 	if err != nil {
@@ -201,6 +250,46 @@ func (c *Client) SendPasswordResetCode(ctx context.Context, body SendPasswordRes
 	return c.Client.Do(req)
 }
 
+// NewSendEmailChangeNotificationRequest calls the generic SendEmailChangeNotification builder with application/json body
+func NewSendEmailChangeNotificationRequest(server string, body SendEmailChangeNotificationJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewSendEmailChangeNotificationRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewSendEmailChangeNotificationRequestWithBody generates requests for SendEmailChangeNotification with any type of body
+func NewSendEmailChangeNotificationRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/email-change-notification")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
 // NewSendEmailVerificationCodeRequest calls the generic SendEmailVerificationCode builder with application/json body
 func NewSendEmailVerificationCodeRequest(server string, body SendEmailVerificationCodeJSONRequestBody) (*http.Request, error) {
 	var bodyReader io.Reader
@@ -324,6 +413,11 @@ func WithBaseURL(baseURL string) ClientOption {
 
 // ClientWithResponsesInterface is the interface specification for the client with responses above.
 type ClientWithResponsesInterface interface {
+	// SendEmailChangeNotificationWithBodyWithResponse request with any body
+	SendEmailChangeNotificationWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*SendEmailChangeNotificationResponse, error)
+
+	SendEmailChangeNotificationWithResponse(ctx context.Context, body SendEmailChangeNotificationJSONRequestBody, reqEditors ...RequestEditorFn) (*SendEmailChangeNotificationResponse, error)
+
 	// SendEmailVerificationCodeWithBodyWithResponse request with any body
 	SendEmailVerificationCodeWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*SendEmailVerificationCodeResponse, error)
 
@@ -335,6 +429,28 @@ type ClientWithResponsesInterface interface {
 	SendPasswordResetCodeWithResponse(ctx context.Context, body SendPasswordResetCodeJSONRequestBody, reqEditors ...RequestEditorFn) (*SendPasswordResetCodeResponse, error)
 }
 
+type SendEmailChangeNotificationResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *EmailChangeNotificationResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r SendEmailChangeNotificationResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SendEmailChangeNotificationResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
 type SendEmailVerificationCodeResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
@@ -379,6 +495,23 @@ func (r SendPasswordResetCodeResponse) StatusCode() int {
 	return 0
 }
 
+// SendEmailChangeNotificationWithBodyWithResponse request with arbitrary body returning *SendEmailChangeNotificationResponse
+func (c *ClientWithResponses) SendEmailChangeNotificationWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*SendEmailChangeNotificationResponse, error) {
+	rsp, err := c.SendEmailChangeNotificationWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSendEmailChangeNotificationResponse(rsp)
+}
+
+func (c *ClientWithResponses) SendEmailChangeNotificationWithResponse(ctx context.Context, body SendEmailChangeNotificationJSONRequestBody, reqEditors ...RequestEditorFn) (*SendEmailChangeNotificationResponse, error) {
+	rsp, err := c.SendEmailChangeNotification(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSendEmailChangeNotificationResponse(rsp)
+}
+
 // SendEmailVerificationCodeWithBodyWithResponse request with arbitrary body returning *SendEmailVerificationCodeResponse
 func (c *ClientWithResponses) SendEmailVerificationCodeWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*SendEmailVerificationCodeResponse, error) {
 	rsp, err := c.SendEmailVerificationCodeWithBody(ctx, contentType, body, reqEditors...)
@@ -413,6 +546,32 @@ func (c *ClientWithResponses) SendPasswordResetCodeWithResponse(ctx context.Cont
 	return ParseSendPasswordResetCodeResponse(rsp)
 }
 
+// ParseSendEmailChangeNotificationResponse parses an HTTP response from a SendEmailChangeNotificationWithResponse call
+func ParseSendEmailChangeNotificationResponse(rsp *http.Response) (*SendEmailChangeNotificationResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SendEmailChangeNotificationResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest EmailChangeNotificationResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
 // ParseSendEmailVerificationCodeResponse parses an HTTP response from a SendEmailVerificationCodeWithResponse call
 func ParseSendEmailVerificationCodeResponse(rsp *http.Response) (*SendEmailVerificationCodeResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
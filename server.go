@@ -1,10 +1,15 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/joho/godotenv"
@@ -32,8 +37,31 @@ func main() {
 	router := chi.NewRouter()
 	server := setup.NewServer(db, router)
 
-	log.Printf("🚀 Server running http://localhost:%s/playground\n", port)
-	if err := http.ListenAndServe(fmt.Sprintf(":%s", port), server.Router); err != nil {
-		log.Fatal("❌ Could not start server", err)
+	http_server := &http.Server{
+		Addr: fmt.Sprintf(":%s", port),
+		Handler: server.Router,
+	}
+	go func() {
+		log.Printf("🚀 Server running http://localhost:%s/playground\n", port)
+		if err := http_server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("❌ Could not start server", err)
+		}
+	}()
+
+	// Wait for a termination signal then finish in-flight requests and jobs
+	signal_ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-signal_ctx.Done()
+	log.Println("Shutting down...")
+
+	shutdown_ctx, cancel := context.WithTimeout(context.Background(), 30 * time.Second)
+	defer cancel()
+	if err := http_server.Shutdown(shutdown_ctx); err != nil {
+		log.Println("Could not shut down server gracefully.", err)
+	}
+	for _, hook := range server.ShutdownHooks {
+		if err := hook(shutdown_ctx); err != nil {
+			log.Println("Could not run shutdown hook.", err)
+		}
 	}
 }
@@ -0,0 +1,347 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+const ACCOUNT_DELETION_GRACE_PERIOD = 30 * 24 * time.Hour
+
+type ProductViewExport struct {
+	ID int64 `json:"id"`
+	ProductID int64 `json:"productId"`
+	StockID *int64 `json:"stockId,omitempty"`
+	Origin *string `json:"origin,omitempty"`
+	Platform string `json:"platform"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type UserDataExport struct {
+	ExportedAt time.Time `json:"exportedAt"`
+	Profile gmodel.User `json:"profile"`
+	Lists []gmodel.List `json:"lists"`
+	ProductLists []gmodel.ProductList `json:"productLists"`
+	BranchLists []gmodel.BranchList `json:"branchLists"`
+	GroceryLists []gmodel.GroceryList `json:"groceryLists"`
+	GroceryListItems []gmodel.GroceryListItem `json:"groceryListItems"`
+	SearchHistory []gmodel.SearchHistory `json:"searchHistory"`
+	ProductViews []ProductViewExport `json:"productViews"`
+	Prices []gmodel.Price `json:"prices"`
+	ProductBilling []gmodel.ProductBilling `json:"productBilling"`
+}
+
+// Schedules the user account for deletion after `ACCOUNT_DELETION_GRACE_PERIOD`.
+// The account remains usable until then and the request can be cancelled with `CancelAccountDeletion`
+func (s Service) RequestAccountDeletion(ctx context.Context, user gmodel.User) (gmodel.User, error) {
+	if user.DeletionScheduledAt != nil {
+		return gmodel.User{}, fmt.Errorf("account deletion has already been requested")
+	}
+	if s.IsRoleAuthorized(gmodel.UserRoleSuperAdmin, user.Role) {
+		return gmodel.User{}, fmt.Errorf("super admin accounts cannot be deleted")
+	}
+
+	now := time.Now()
+	scheduled_at := now.Add(ACCOUNT_DELETION_GRACE_PERIOD)
+	qb := table.User.
+		UPDATE(
+			table.User.DeletionRequestedAt,
+			table.User.DeletionScheduledAt,
+			table.User.UpdatedAt,
+		).
+		MODEL(model.User{
+			DeletionRequestedAt: &now,
+			DeletionScheduledAt: &scheduled_at,
+			UpdatedAt: now,
+		}).
+		WHERE(table.User.ID.EQ(postgres.Int(user.ID)))
+	if _, err := qb.ExecContext(ctx, s.DbOrTxExecutable()); err != nil {
+		return gmodel.User{}, fmt.Errorf("could not schedule account deletion")
+	}
+	return s.findAuthUserOrUser(ctx, user)
+}
+
+func (s Service) CancelAccountDeletion(ctx context.Context, user gmodel.User) (gmodel.User, error) {
+	if user.DeletionScheduledAt == nil {
+		return gmodel.User{}, fmt.Errorf("account deletion has not been requested")
+	}
+	qb := table.User.
+		UPDATE(table.User.DeletionRequestedAt, table.User.DeletionScheduledAt, table.User.UpdatedAt).
+		SET(postgres.NULL, postgres.NULL, postgres.TimestampzT(time.Now())).
+		WHERE(table.User.ID.EQ(postgres.Int(user.ID)))
+	if _, err := qb.ExecContext(ctx, s.DbOrTxExecutable()); err != nil {
+		return gmodel.User{}, fmt.Errorf("could not cancel account deletion")
+	}
+	return s.findAuthUserOrUser(ctx, user)
+}
+
+func (s Service) findAuthUserOrUser(ctx context.Context, user gmodel.User) (gmodel.User, error) {
+	if user.AuthStateID == nil {
+		return s.FindUserById(ctx, user.ID)
+	}
+	return s.FindAuthUserById(ctx, user.ID, *user.AuthStateID)
+}
+
+// Permanently deletes the user along with their address and avatar.
+// Rows referencing the user are either cascaded or set to null by the schema
+func (s Service) DeleteUserAccount(ctx context.Context, user_id int64) (err error) {
+	user, err := s.FindUserById(ctx, user_id)
+	if err != nil {
+		return err
+	}
+
+	if s.TX, err = s.DB.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer s.TX.Rollback()
+
+	_, err = table.User.
+		DELETE().
+		WHERE(table.User.ID.EQ(postgres.Int(user.ID))).
+		ExecContext(ctx, s.TX)
+	if err != nil {
+		return fmt.Errorf("could not delete user. %s", err.Error())
+	}
+	if user.AddressID != nil {
+		_, err = table.Address.
+			DELETE().
+			WHERE(table.Address.ID.EQ(postgres.Int(*user.AddressID))).
+			ExecContext(ctx, s.TX)
+		if err != nil {
+			return fmt.Errorf("could not delete user address. %s", err.Error())
+		}
+	}
+	if err = s.TX.Commit(); err != nil {
+		return fmt.Errorf("could not commit changes")
+	}
+
+	if user.Avatar != nil {
+		s.DeleteImageUpload(ctx, *user.Avatar)
+	}
+	return nil
+}
+
+// Deletes all accounts whose grace period has elapsed. Returns the number of deleted accounts
+func (s Service) DeleteScheduledAccounts(ctx context.Context) (deleted int, err error) {
+	qb := table.User.
+		SELECT(table.User.ID.AS("id")).
+		FROM(table.User).
+		WHERE(table.User.DeletionScheduledAt.LT_EQ(postgres.TimestampzT(time.Now())))
+	var user_ids []int64
+	if err := qb.QueryContext(ctx, s.DB, &user_ids); err != nil {
+		return 0, err
+	}
+
+	for _, user_id := range user_ids {
+		if err := s.DeleteUserAccount(ctx, user_id); err != nil {
+			log.Printf("could not delete account %d. %s\n", user_id, err.Error())
+			continue
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// Collects all personal data tied to the user
+func (s Service) CollectUserData(ctx context.Context, user gmodel.User) (data UserDataExport, err error) {
+	db := s.DbOrTxQueryable()
+	user_id := postgres.Int(user.ID)
+	data.ExportedAt = time.Now()
+
+	profile_qb := table.User.
+		SELECT(
+			table.User.AllColumns,
+			table.Address.AllColumns,
+			table.Country.Name,
+		).
+		FROM(
+			table.User.
+				LEFT_JOIN(table.Address, table.Address.ID.EQ(table.User.AddressID)).
+				LEFT_JOIN(table.Country, table.Country.Code.EQ(table.Address.CountryCode)),
+		).
+		WHERE(table.User.ID.EQ(user_id)).
+		LIMIT(1)
+	if err = profile_qb.QueryContext(ctx, db, &data.Profile); err != nil {
+		return UserDataExport{}, fmt.Errorf("could not export profile")
+	}
+
+	lists_qb := table.List.
+		SELECT(table.List.AllColumns).
+		FROM(table.List).
+		WHERE(table.List.UserID.EQ(user_id)).
+		ORDER_BY(table.List.ID.ASC())
+	if err = lists_qb.QueryContext(ctx, db, &data.Lists); err != nil {
+		return UserDataExport{}, fmt.Errorf("could not export lists")
+	}
+
+	product_lists_qb := table.ProductList.
+		SELECT(table.ProductList.AllColumns).
+		FROM(table.ProductList).
+		WHERE(table.ProductList.UserID.EQ(user_id)).
+		ORDER_BY(table.ProductList.ID.ASC())
+	if err = product_lists_qb.QueryContext(ctx, db, &data.ProductLists); err != nil {
+		return UserDataExport{}, fmt.Errorf("could not export product lists")
+	}
+
+	branch_lists_qb := table.BranchList.
+		SELECT(table.BranchList.AllColumns).
+		FROM(table.BranchList).
+		WHERE(table.BranchList.UserID.EQ(user_id)).
+		ORDER_BY(table.BranchList.ID.ASC())
+	if err = branch_lists_qb.QueryContext(ctx, db, &data.BranchLists); err != nil {
+		return UserDataExport{}, fmt.Errorf("could not export branch lists")
+	}
+
+	grocery_lists_qb := table.GroceryList.
+		SELECT(table.GroceryList.AllColumns).
+		FROM(table.GroceryList).
+		WHERE(table.GroceryList.UserID.EQ(user_id)).
+		ORDER_BY(table.GroceryList.ID.ASC())
+	if err = grocery_lists_qb.QueryContext(ctx, db, &data.GroceryLists); err != nil {
+		return UserDataExport{}, fmt.Errorf("could not export grocery lists")
+	}
+
+	grocery_list_items_qb := table.GroceryListItem.
+		SELECT(table.GroceryListItem.AllColumns).
+		FROM(table.GroceryListItem).
+		WHERE(table.GroceryListItem.UserID.EQ(user_id)).
+		ORDER_BY(table.GroceryListItem.ID.ASC())
+	if err = grocery_list_items_qb.QueryContext(ctx, db, &data.GroceryListItems); err != nil {
+		return UserDataExport{}, fmt.Errorf("could not export grocery list items")
+	}
+
+	search_history_qb := table.SearchHistory.
+		SELECT(table.SearchHistory.AllColumns).
+		FROM(table.SearchHistory).
+		WHERE(table.SearchHistory.UserID.EQ(user_id)).
+		ORDER_BY(table.SearchHistory.CreatedAt.ASC())
+	if err = search_history_qb.QueryContext(ctx, db, &data.SearchHistory); err != nil {
+		return UserDataExport{}, fmt.Errorf("could not export search history")
+	}
+
+	product_views_qb := table.ProductView.
+		SELECT(table.ProductView.AllColumns).
+		FROM(table.ProductView).
+		WHERE(table.ProductView.UserID.EQ(user_id)).
+		ORDER_BY(table.ProductView.CreatedAt.ASC())
+	var product_views []model.ProductView
+	if err = product_views_qb.QueryContext(ctx, db, &product_views); err != nil {
+		return UserDataExport{}, fmt.Errorf("could not export product views")
+	}
+	data.ProductViews = make([]ProductViewExport, len(product_views))
+	for i, view := range product_views {
+		data.ProductViews[i] = ProductViewExport{
+			ID: view.ID,
+			ProductID: view.ProductID,
+			StockID: view.StockID,
+			Origin: view.Origin,
+			Platform: view.Platform.String(),
+			CreatedAt: view.CreatedAt,
+		}
+	}
+
+	prices_qb := table.Price.
+		SELECT(table.Price.AllColumns).
+		FROM(table.Price).
+		WHERE(table.Price.CreatedByID.EQ(user_id)).
+		ORDER_BY(table.Price.CreatedAt.ASC())
+	if err = prices_qb.QueryContext(ctx, db, &data.Prices); err != nil {
+		return UserDataExport{}, fmt.Errorf("could not export prices")
+	}
+
+	billing_qb := table.ProductBilling.
+		SELECT(table.ProductBilling.AllColumns).
+		FROM(table.ProductBilling).
+		WHERE(table.ProductBilling.UserID.EQ(user_id)).
+		ORDER_BY(table.ProductBilling.CreatedAt.ASC())
+	if err = billing_qb.QueryContext(ctx, db, &data.ProductBilling); err != nil {
+		return UserDataExport{}, fmt.Errorf("could not export billing records")
+	}
+	return data, nil
+}
+
+// Builds a downloadable archive of the user's personal data.
+// JSON exports contain a single document while ZIP exports contain one file per section
+func (s Service) ExportUserData(ctx context.Context, user gmodel.User, format gmodel.DataExportFormat) (gmodel.DataExport, error) {
+	data, err := s.CollectUserData(ctx, user)
+	if err != nil {
+		return gmodel.DataExport{}, err
+	}
+
+	filename_base := fmt.Sprintf("pricetra-data-export-%d-%s", user.ID, data.ExportedAt.Format("20060102150405"))
+	var export gmodel.DataExport
+	switch format {
+	case gmodel.DataExportFormatZip:
+		archive, err := data.Zip()
+		if err != nil {
+			return gmodel.DataExport{}, fmt.Errorf("could not create zip archive")
+		}
+		export = gmodel.DataExport{
+			Filename: filename_base + ".zip",
+			ContentType: "application/zip",
+			Data: base64.StdEncoding.EncodeToString(archive),
+		}
+	default:
+		json_data, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return gmodel.DataExport{}, fmt.Errorf("could not encode data")
+		}
+		export = gmodel.DataExport{
+			Filename: filename_base + ".json",
+			ContentType: "application/json",
+			Data: base64.StdEncoding.EncodeToString(json_data),
+		}
+	}
+	export.CreatedAt = data.ExportedAt
+	return export, nil
+}
+
+func (data UserDataExport) Zip() ([]byte, error) {
+	sections := []struct {
+		name string
+		value any
+	}{
+		{ "profile.json", data.Profile },
+		{ "lists.json", data.Lists },
+		{ "product_lists.json", data.ProductLists },
+		{ "branch_lists.json", data.BranchLists },
+		{ "grocery_lists.json", data.GroceryLists },
+		{ "grocery_list_items.json", data.GroceryListItems },
+		{ "search_history.json", data.SearchHistory },
+		{ "product_views.json", data.ProductViews },
+		{ "prices.json", data.Prices },
+		{ "product_billing.json", data.ProductBilling },
+	}
+
+	buf := new(bytes.Buffer)
+	writer := zip.NewWriter(buf)
+	for _, section := range sections {
+		f, err := writer.CreateHeader(&zip.FileHeader{
+			Name: section.name,
+			Method: zip.Deflate,
+			Modified: data.ExportedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoder := json.NewEncoder(f)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(section.value); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+// Price count milestones
+const HUNDRED_PRICES_MILESTONE = 100
+const THOUSAND_PRICES_MILESTONE = 1000
+
+func (s Service) FindUserAchievements(ctx context.Context, user_id int64) (achievements []gmodel.UserAchievement, err error) {
+	qb := table.UserAchievement.
+		SELECT(table.UserAchievement.AllColumns).
+		FROM(table.UserAchievement).
+		WHERE(table.UserAchievement.UserID.EQ(postgres.Int(user_id))).
+		ORDER_BY(table.UserAchievement.AwardedAt.ASC())
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &achievements); err != nil {
+		return nil, err
+	}
+	if achievements == nil {
+		achievements = []gmodel.UserAchievement{}
+	}
+	return achievements, nil
+}
+
+// Awards the achievement to the user. Returns false if the user already had it
+func (s Service) AwardAchievement(ctx context.Context, user_id int64, achievement model.AchievementType) (bool, error) {
+	qb := table.UserAchievement.
+		INSERT(table.UserAchievement.UserID, table.UserAchievement.Achievement).
+		MODEL(model.UserAchievement{
+			UserID: user_id,
+			Achievement: achievement,
+		}).
+		ON_CONFLICT(table.UserAchievement.UserID, table.UserAchievement.Achievement).
+		DO_NOTHING()
+	res, err := qb.ExecContext(ctx, s.DbOrTxExecutable())
+	if err != nil {
+		return false, err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// Checks which achievements the user unlocked with the contribution and awards them.
+// Returns the newly awarded achievements
+func (s Service) EvaluateAchievements(
+	ctx context.Context,
+	user_id int64,
+	billing_type model.ProductBillingType,
+	branch_id *int64,
+) (awarded []model.AchievementType, err error) {
+	unlocked := []model.AchievementType{}
+	switch billing_type {
+	case model.ProductBillingType_Scan:
+		unlocked = append(unlocked, model.AchievementType_FirstScan)
+	case model.ProductBillingType_Create:
+		unlocked = append(unlocked, model.AchievementType_FirstProduct)
+	case model.ProductBillingType_Price:
+		unlocked = append(unlocked, model.AchievementType_FirstPrice)
+		price_count, err := s.UserPriceCount(ctx, user_id)
+		if err != nil {
+			return nil, err
+		}
+		if price_count >= HUNDRED_PRICES_MILESTONE {
+			unlocked = append(unlocked, model.AchievementType_HundredPrices)
+		}
+		if price_count >= THOUSAND_PRICES_MILESTONE {
+			unlocked = append(unlocked, model.AchievementType_ThousandPrices)
+		}
+		if branch_id != nil {
+			first_contributor, err := s.FirstBranchPriceContributor(ctx, *branch_id)
+			if err != nil {
+				return nil, err
+			}
+			if first_contributor != nil && *first_contributor == user_id {
+				unlocked = append(unlocked, model.AchievementType_NewStoreCoverage)
+			}
+		}
+	}
+
+	for _, achievement := range unlocked {
+		ok, err := s.AwardAchievement(ctx, user_id, achievement)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			awarded = append(awarded, achievement)
+		}
+	}
+	return awarded, nil
+}
+
+func (s Service) UserPriceCount(ctx context.Context, user_id int64) (int, error) {
+	qb := table.Price.
+		SELECT(postgres.COUNT(table.Price.ID).AS("count")).
+		FROM(table.Price).
+		WHERE(table.Price.CreatedByID.EQ(postgres.Int(user_id)))
+	var res struct{ Count int }
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &res); err != nil {
+		return 0, err
+	}
+	return res.Count, nil
+}
+
+// Returns the user that added the first price at the branch
+func (s Service) FirstBranchPriceContributor(ctx context.Context, branch_id int64) (*int64, error) {
+	qb := table.Price.
+		SELECT(table.Price.CreatedByID).
+		FROM(table.Price).
+		WHERE(table.Price.BranchID.EQ(postgres.Int(branch_id))).
+		ORDER_BY(table.Price.CreatedAt.ASC(), table.Price.ID.ASC()).
+		LIMIT(1)
+	var prices []model.Price
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &prices); err != nil {
+		return nil, err
+	}
+	if len(prices) == 0 {
+		return nil, nil
+	}
+	return prices[0].CreatedByID, nil
+}
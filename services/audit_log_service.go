@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/types"
+)
+
+const AUDIT_LOG_REDACTED = "[REDACTED]"
+
+// Argument keys containing any of these values are redacted before being stored
+var AUDIT_LOG_REDACTED_KEYS = []string{"password", "base64", "secret", "token", "file"}
+
+// Records the mutation along with the actor, arguments, entity snapshots and request metadata.
+// Failing to write the audit entry does not fail the mutation
+func (s Service) AuditedDirective(
+	ctx context.Context,
+	obj any,
+	next graphql.Resolver,
+	action *string,
+	entity *string,
+	id_arg *string,
+) (res any, err error) {
+	entry := model.AuditLog{
+		Entity: entity,
+		Success: true,
+	}
+	field_ctx := graphql.GetFieldContext(ctx)
+	if action != nil {
+		entry.Action = *action
+	} else if field_ctx != nil && field_ctx.Field.Field != nil {
+		entry.Action = field_ctx.Field.Name
+	}
+
+	user := s.GetAuthUserFromContext(ctx)
+	if user.ID != 0 {
+		entry.ActorID = &user.ID
+		entry.ActorEmail = &user.Email
+		var role model.UserRoleType
+		if role.Scan(user.Role.String()) == nil {
+			entry.ActorRole = &role
+		}
+		if user.AuthStateID != nil {
+			if auth_state_id, err := uuid.Parse(*user.AuthStateID); err == nil {
+				entry.AuthStateID = &auth_state_id
+			}
+		}
+	}
+	if metadata, ok := ctx.Value(types.RequestMetadataKey).(types.RequestMetadata); ok {
+		entry.IPAddress = &metadata.IpAddress
+		entry.UserAgent = &metadata.UserAgent
+	}
+
+	if field_ctx != nil {
+		entry.Arguments = s.AuditJson(RedactAuditArguments(field_ctx.Args))
+		if entity != nil && id_arg != nil {
+			if entity_id, ok := field_ctx.Args[*id_arg].(int64); ok {
+				entry.EntityID = &entity_id
+				if before, err := s.AuditEntitySnapshot(ctx, *entity, entity_id); err == nil {
+					entry.Before = s.AuditJson(before)
+				}
+			}
+		}
+	}
+
+	res, err = next(ctx)
+	if err != nil {
+		entry.Success = false
+		err_msg := err.Error()
+		entry.Error = &err_msg
+	} else {
+		entry.After = s.AuditJson(RedactAuditArguments(res))
+		if entry.EntityID == nil {
+			entry.EntityID = auditEntityId(res)
+		}
+	}
+
+	if _, audit_err := s.CreateAuditLog(ctx, entry); audit_err != nil {
+		log.Printf("could not create audit log entry for %s. %s\n", entry.Action, audit_err.Error())
+	}
+	return res, err
+}
+
+func (s Service) CreateAuditLog(ctx context.Context, entry model.AuditLog) (audit_log gmodel.AuditLog, err error) {
+	qb := table.AuditLog.
+		INSERT(table.AuditLog.MutableColumns.Except(table.AuditLog.CreatedAt)).
+		MODEL(entry).
+		RETURNING(table.AuditLog.AllColumns)
+	err = qb.QueryContext(ctx, s.DB, &audit_log)
+	return audit_log, err
+}
+
+// Returns the current state of an audited entity
+func (s Service) AuditEntitySnapshot(ctx context.Context, entity string, id int64) (any, error) {
+	switch entity {
+	case "user":
+		return s.FindUserById(ctx, id)
+	case "store":
+		return s.FindStore(ctx, id)
+	case "branch":
+		return s.FindBranchById(ctx, id)
+	case "category":
+		return s.FindCategoryById(ctx, id)
+	case "product":
+		return s.FindProductById(ctx, id)
+	case "product_billing":
+		return s.FindProductBillingById(ctx, id)
+	case "product_billing_rate":
+		return s.FindBillingRateById(ctx, id)
+	case "product_edit_proposal":
+		return s.FindProductEditProposalById(ctx, id)
+	case "product_image":
+		return s.FindProductImageById(ctx, id)
+	case "product_family":
+		return s.FindProductFamilyById(ctx, id)
+	case "price":
+		return s.FindPriceById(ctx, id)
+	case "loyalty_program":
+		return s.FindLoyaltyProgramById(ctx, id)
+	case "coupon":
+		return s.FindCouponById(ctx, id)
+	case "job":
+		return s.FindJobById(ctx, id)
+	default:
+		return nil, fmt.Errorf("unsupported audit entity %s", entity)
+	}
+}
+
+func (Service) AuditJson(v any) *string {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	str := string(data)
+	return &str
+}
+
+// Converts the value into generic JSON values and replaces sensitive fields with `AUDIT_LOG_REDACTED`
+func RedactAuditArguments(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil
+	}
+	return redactAuditValue(generic)
+}
+
+func redactAuditValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for key, child := range val {
+			if isRedactedAuditKey(key) && child != nil {
+				val[key] = AUDIT_LOG_REDACTED
+				continue
+			}
+			val[key] = redactAuditValue(child)
+		}
+		return val
+	case []any:
+		for i := range val {
+			val[i] = redactAuditValue(val[i])
+		}
+		return val
+	default:
+		return val
+	}
+}
+
+func isRedactedAuditKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, redacted := range AUDIT_LOG_REDACTED_KEYS {
+		if strings.Contains(key, redacted) {
+			return true
+		}
+	}
+	return false
+}
+
+func auditEntityId(res any) *int64 {
+	v := reflect.Indirect(reflect.ValueOf(res))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	id := v.FieldByName("ID")
+	if !id.IsValid() || id.Kind() != reflect.Int64 {
+		return nil
+	}
+	val := id.Int()
+	return &val
+}
+
+func (s Service) PaginatedAuditLogs(
+	ctx context.Context,
+	paginator_input gmodel.PaginatorInput,
+	filters *gmodel.AuditLogFilter,
+) (result gmodel.PaginatedAuditLogs, err error) {
+	where_clause := postgres.Bool(true)
+	if filters != nil {
+		if filters.ActorID != nil {
+			where_clause = where_clause.AND(table.AuditLog.ActorID.EQ(postgres.Int(*filters.ActorID)))
+		}
+		if filters.Action != nil {
+			where_clause = where_clause.AND(table.AuditLog.Action.EQ(postgres.String(*filters.Action)))
+		}
+		if filters.Entity != nil {
+			where_clause = where_clause.AND(table.AuditLog.Entity.EQ(postgres.String(*filters.Entity)))
+		}
+		if filters.EntityID != nil {
+			where_clause = where_clause.AND(table.AuditLog.EntityID.EQ(postgres.Int(*filters.EntityID)))
+		}
+		if filters.Success != nil {
+			where_clause = where_clause.AND(table.AuditLog.Success.EQ(postgres.Bool(*filters.Success)))
+		}
+		if filters.From != nil {
+			where_clause = where_clause.AND(table.AuditLog.CreatedAt.GT_EQ(postgres.TimestampzT(*filters.From)))
+		}
+		if filters.To != nil {
+			where_clause = where_clause.AND(table.AuditLog.CreatedAt.LT_EQ(postgres.TimestampzT(*filters.To)))
+		}
+	}
+
+	paginator, err := s.Paginate(ctx, paginator_input, table.AuditLog, table.AuditLog.ID, where_clause)
+	if err != nil {
+		return gmodel.PaginatedAuditLogs{
+			AuditLogs: []*gmodel.AuditLog{},
+			Paginator: &gmodel.Paginator{},
+		}, nil
+	}
+	qb := table.AuditLog.
+		SELECT(table.AuditLog.AllColumns).
+		FROM(table.AuditLog).
+		WHERE(where_clause).
+		ORDER_BY(table.AuditLog.ID.DESC()).
+		LIMIT(int64(paginator.Limit)).
+		OFFSET(int64(paginator.Offset))
+	if err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &result.AuditLogs); err != nil {
+		return gmodel.PaginatedAuditLogs{}, err
+	}
+	result.Paginator = &paginator.Paginator
+	return result, nil
+}
@@ -0,0 +1,519 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/utils"
+)
+
+// Scope used to find the applicable billing rate.
+// Nil fields only match rates without that scope
+type BillingRateScope struct {
+	CategoryPath []int
+	CountryCode *string
+	AdministrativeDivision *string
+}
+
+func (s Service) FindBillingRateById(ctx context.Context, id int64) (rate gmodel.ProductBillingRate, err error) {
+	qb := table.ProductBillingRate.
+		SELECT(table.ProductBillingRate.AllColumns, table.Category.AllColumns).
+		FROM(table.ProductBillingRate.LEFT_JOIN(table.Category, table.Category.ID.EQ(table.ProductBillingRate.CategoryID))).
+		WHERE(table.ProductBillingRate.ID.EQ(postgres.Int(id))).
+		LIMIT(1)
+	err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &rate)
+	return rate, err
+}
+
+func (s Service) FindBillingRates(ctx context.Context, filters *gmodel.BillingRateFilter) (rates []gmodel.ProductBillingRate, err error) {
+	where_clause := postgres.Bool(true)
+	if filters != nil {
+		if filters.Type != nil {
+			where_clause = where_clause.AND(table.ProductBillingRate.Type.EQ(postgres.NewEnumValue(filters.Type.String())))
+		}
+		if filters.CategoryID != nil {
+			where_clause = where_clause.AND(table.ProductBillingRate.CategoryID.EQ(postgres.Int(*filters.CategoryID)))
+		}
+		if filters.CountryCode != nil {
+			where_clause = where_clause.AND(table.ProductBillingRate.CountryCode.EQ(postgres.NewEnumValue(*filters.CountryCode)))
+		}
+		if filters.AdministrativeDivision != nil {
+			where_clause = where_clause.AND(table.ProductBillingRate.AdministrativeDivision.EQ(postgres.String(*filters.AdministrativeDivision)))
+		}
+		if filters.EffectiveAt != nil {
+			where_clause = where_clause.AND(billingRateEffectiveClause(*filters.EffectiveAt))
+		}
+	}
+
+	qb := table.ProductBillingRate.
+		SELECT(table.ProductBillingRate.AllColumns, table.Category.AllColumns).
+		FROM(table.ProductBillingRate.LEFT_JOIN(table.Category, table.Category.ID.EQ(table.ProductBillingRate.CategoryID))).
+		WHERE(where_clause).
+		ORDER_BY(
+			table.ProductBillingRate.Type.ASC(),
+			table.ProductBillingRate.EffectiveFrom.DESC(),
+			table.ProductBillingRate.ID.DESC(),
+		)
+	if err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &rates); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+func billingRateEffectiveClause(at time.Time) postgres.BoolExpression {
+	return postgres.AND(
+		table.ProductBillingRate.EffectiveFrom.LT_EQ(postgres.TimestampzT(at)),
+		postgres.OR(
+			table.ProductBillingRate.EffectiveTo.IS_NULL(),
+			table.ProductBillingRate.EffectiveTo.GT(postgres.TimestampzT(at)),
+		),
+	)
+}
+
+// Matches rate versions with exactly the same type, category and region
+func billingRateSameScopeClause(rate model.ProductBillingRate) postgres.BoolExpression {
+	where_clause := table.ProductBillingRate.Type.EQ(postgres.NewEnumValue(rate.Type.String()))
+	if rate.CategoryID != nil {
+		where_clause = where_clause.AND(table.ProductBillingRate.CategoryID.EQ(postgres.Int(*rate.CategoryID)))
+	} else {
+		where_clause = where_clause.AND(table.ProductBillingRate.CategoryID.IS_NULL())
+	}
+	if rate.CountryCode != nil {
+		where_clause = where_clause.AND(table.ProductBillingRate.CountryCode.EQ(postgres.NewEnumValue(rate.CountryCode.String())))
+	} else {
+		where_clause = where_clause.AND(table.ProductBillingRate.CountryCode.IS_NULL())
+	}
+	if rate.AdministrativeDivision != nil {
+		where_clause = where_clause.AND(table.ProductBillingRate.AdministrativeDivision.EQ(postgres.String(*rate.AdministrativeDivision)))
+	} else {
+		where_clause = where_clause.AND(table.ProductBillingRate.AdministrativeDivision.IS_NULL())
+	}
+	return where_clause
+}
+
+// Matches rate versions whose effective range overlaps with the range of `rate`
+func billingRateOverlapClause(rate model.ProductBillingRate) postgres.BoolExpression {
+	where_clause := postgres.OR(
+		table.ProductBillingRate.EffectiveTo.IS_NULL(),
+		table.ProductBillingRate.EffectiveTo.GT(postgres.TimestampzT(rate.EffectiveFrom)),
+	)
+	if rate.EffectiveTo != nil {
+		where_clause = where_clause.AND(table.ProductBillingRate.EffectiveFrom.LT(postgres.TimestampzT(*rate.EffectiveTo)))
+	}
+	return billingRateSameScopeClause(rate).AND(where_clause)
+}
+
+func (s Service) billingRateOverlaps(ctx context.Context, rate model.ProductBillingRate) (bool, error) {
+	qb := table.ProductBillingRate.
+		SELECT(postgres.COUNT(table.ProductBillingRate.ID).AS("count")).
+		FROM(table.ProductBillingRate).
+		WHERE(billingRateOverlapClause(rate).AND(table.ProductBillingRate.ID.NOT_EQ(postgres.Int(rate.ID))))
+	var res struct{ Count int }
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &res); err != nil {
+		return false, err
+	}
+	return res.Count > 0, nil
+}
+
+func (s Service) billingRateUsage(ctx context.Context, rate_id int64) (count int, last_used_at *time.Time, err error) {
+	qb := table.ProductBilling.
+		SELECT(
+			postgres.COUNT(table.ProductBilling.ID).AS("count"),
+			postgres.MAX(table.ProductBilling.CreatedAt).AS("last_used_at"),
+		).
+		FROM(table.ProductBilling).
+		WHERE(table.ProductBilling.RateID.EQ(postgres.Int(rate_id)))
+	var usage struct{
+		Count int
+		LastUsedAt *time.Time
+	}
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &usage); err != nil {
+		return 0, nil, err
+	}
+	return usage.Count, usage.LastUsedAt, nil
+}
+
+func (Service) billingRateCountryCode(country_code *string) (*model.CountryCodeAlpha2, error) {
+	if country_code == nil {
+		return nil, nil
+	}
+	var code model.CountryCodeAlpha2
+	if err := code.Scan(*country_code); err != nil {
+		return nil, fmt.Errorf("invalid country code")
+	}
+	return &code, nil
+}
+
+func (s Service) CreateBillingRate(ctx context.Context, user gmodel.User, input gmodel.CreateBillingRate) (gmodel.ProductBillingRate, error) {
+	if err := s.StructValidator.StructCtx(ctx, input); err != nil {
+		return gmodel.ProductBillingRate{}, err
+	}
+	var billing_type model.ProductBillingType
+	if err := billing_type.Scan(input.Type.String()); err != nil {
+		return gmodel.ProductBillingRate{}, fmt.Errorf("invalid billing type")
+	}
+	country_code, err := s.billingRateCountryCode(input.CountryCode)
+	if err != nil {
+		return gmodel.ProductBillingRate{}, err
+	}
+	if input.CategoryID != nil {
+		if _, err := s.FindCategoryById(ctx, *input.CategoryID); err != nil {
+			return gmodel.ProductBillingRate{}, fmt.Errorf("invalid category id")
+		}
+	}
+
+	rate := model.ProductBillingRate{
+		Type: billing_type,
+		Rate: input.Rate,
+		CurrencyCode: input.CurrencyCode,
+		CategoryID: input.CategoryID,
+		CountryCode: country_code,
+		AdministrativeDivision: input.AdministrativeDivision,
+		EffectiveFrom: time.Now(),
+		EffectiveTo: input.EffectiveTo,
+		CreatedByID: &user.ID,
+	}
+	if input.EffectiveFrom != nil {
+		// versions can't start in the past since billing rows were already created with the previous rates
+		if input.EffectiveFrom.Before(rate.EffectiveFrom) {
+			return gmodel.ProductBillingRate{}, fmt.Errorf("effectiveFrom cannot be in the past")
+		}
+		rate.EffectiveFrom = *input.EffectiveFrom
+	}
+	if rate.EffectiveTo != nil && !rate.EffectiveTo.After(rate.EffectiveFrom) {
+		return gmodel.ProductBillingRate{}, fmt.Errorf("effectiveTo must be after effectiveFrom")
+	}
+
+	s.TX, err = s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return gmodel.ProductBillingRate{}, err
+	}
+	defer s.TX.Rollback()
+
+	// close the version that is open at the start of the new one
+	var open_rates []model.ProductBillingRate
+	open_qb := table.ProductBillingRate.
+		SELECT(table.ProductBillingRate.AllColumns).
+		FROM(table.ProductBillingRate).
+		WHERE(postgres.AND(
+			billingRateSameScopeClause(rate),
+			table.ProductBillingRate.EffectiveFrom.LT(postgres.TimestampzT(rate.EffectiveFrom)),
+			postgres.OR(
+				table.ProductBillingRate.EffectiveTo.IS_NULL(),
+				table.ProductBillingRate.EffectiveTo.GT(postgres.TimestampzT(rate.EffectiveFrom)),
+			),
+		)).
+		FOR(postgres.UPDATE())
+	if err := open_qb.QueryContext(ctx, s.TX, &open_rates); err != nil {
+		return gmodel.ProductBillingRate{}, err
+	}
+	for _, open_rate := range open_rates {
+		close_qb := table.ProductBillingRate.
+			UPDATE(table.ProductBillingRate.EffectiveTo, table.ProductBillingRate.UpdatedAt).
+			SET(postgres.TimestampzT(rate.EffectiveFrom), postgres.TimestampzT(time.Now())).
+			WHERE(table.ProductBillingRate.ID.EQ(postgres.Int(open_rate.ID)))
+		if _, err := close_qb.ExecContext(ctx, s.TX); err != nil {
+			return gmodel.ProductBillingRate{}, err
+		}
+
+		// a bounded version inside of the open one is followed by a copy of the open version
+		// for the rest of its range. Otherwise nothing would apply after the new version ends
+		if rate.EffectiveTo == nil || (open_rate.EffectiveTo != nil && !open_rate.EffectiveTo.After(*rate.EffectiveTo)) {
+			continue
+		}
+		reopen_qb := table.ProductBillingRate.
+			INSERT(
+				table.ProductBillingRate.Type,
+				table.ProductBillingRate.Rate,
+				table.ProductBillingRate.CurrencyCode,
+				table.ProductBillingRate.CategoryID,
+				table.ProductBillingRate.CountryCode,
+				table.ProductBillingRate.AdministrativeDivision,
+				table.ProductBillingRate.EffectiveFrom,
+				table.ProductBillingRate.EffectiveTo,
+				table.ProductBillingRate.CreatedByID,
+			).
+			MODEL(model.ProductBillingRate{
+				Type: open_rate.Type,
+				Rate: open_rate.Rate,
+				CurrencyCode: open_rate.CurrencyCode,
+				CategoryID: open_rate.CategoryID,
+				CountryCode: open_rate.CountryCode,
+				AdministrativeDivision: open_rate.AdministrativeDivision,
+				EffectiveFrom: *rate.EffectiveTo,
+				EffectiveTo: open_rate.EffectiveTo,
+				CreatedByID: &user.ID,
+			})
+		if _, err := reopen_qb.ExecContext(ctx, s.TX); err != nil {
+			return gmodel.ProductBillingRate{}, err
+		}
+	}
+	overlaps, err := s.billingRateOverlaps(ctx, rate)
+	if err != nil {
+		return gmodel.ProductBillingRate{}, err
+	}
+	if overlaps {
+		return gmodel.ProductBillingRate{}, fmt.Errorf("rate overlaps with an existing rate version")
+	}
+
+	var created model.ProductBillingRate
+	qb := table.ProductBillingRate.
+		INSERT(
+			table.ProductBillingRate.Type,
+			table.ProductBillingRate.Rate,
+			table.ProductBillingRate.CurrencyCode,
+			table.ProductBillingRate.CategoryID,
+			table.ProductBillingRate.CountryCode,
+			table.ProductBillingRate.AdministrativeDivision,
+			table.ProductBillingRate.EffectiveFrom,
+			table.ProductBillingRate.EffectiveTo,
+			table.ProductBillingRate.CreatedByID,
+		).
+		MODEL(rate).
+		RETURNING(table.ProductBillingRate.ID)
+	if err := qb.QueryContext(ctx, s.TX, &created); err != nil {
+		return gmodel.ProductBillingRate{}, err
+	}
+	if err := s.TX.Commit(); err != nil {
+		return gmodel.ProductBillingRate{}, err
+	}
+	s.TX = nil
+	return s.FindBillingRateById(ctx, created.ID)
+}
+
+func (s Service) UpdateBillingRate(ctx context.Context, id int64, input gmodel.UpdateBillingRate) (gmodel.ProductBillingRate, error) {
+	if err := s.StructValidator.StructCtx(ctx, input); err != nil {
+		return gmodel.ProductBillingRate{}, err
+	}
+	var rate model.ProductBillingRate
+	find_qb := table.ProductBillingRate.
+		SELECT(table.ProductBillingRate.AllColumns).
+		FROM(table.ProductBillingRate).
+		WHERE(table.ProductBillingRate.ID.EQ(postgres.Int(id))).
+		LIMIT(1)
+	if err := find_qb.QueryContext(ctx, s.DbOrTxQueryable(), &rate); err != nil {
+		return gmodel.ProductBillingRate{}, fmt.Errorf("rate not found")
+	}
+
+	usage_count, last_used_at, err := s.billingRateUsage(ctx, rate.ID)
+	if err != nil {
+		return gmodel.ProductBillingRate{}, err
+	}
+	if usage_count > 0 && (input.Rate != nil || input.CurrencyCode != nil || input.EffectiveFrom != nil) {
+		return gmodel.ProductBillingRate{}, fmt.Errorf("rate has already been applied to billing rows. create a new rate version instead")
+	}
+
+	columns := postgres.ColumnList{table.ProductBillingRate.UpdatedAt}
+	rate.UpdatedAt = time.Now()
+	if input.Rate != nil {
+		columns = append(columns, table.ProductBillingRate.Rate)
+		rate.Rate = *input.Rate
+	}
+	if input.CurrencyCode != nil {
+		columns = append(columns, table.ProductBillingRate.CurrencyCode)
+		rate.CurrencyCode = *input.CurrencyCode
+	}
+	if input.EffectiveFrom != nil {
+		if input.EffectiveFrom.Before(rate.UpdatedAt) {
+			return gmodel.ProductBillingRate{}, fmt.Errorf("effectiveFrom cannot be in the past")
+		}
+		columns = append(columns, table.ProductBillingRate.EffectiveFrom)
+		rate.EffectiveFrom = *input.EffectiveFrom
+	}
+	if input.EffectiveTo != nil {
+		if last_used_at != nil && !input.EffectiveTo.After(*last_used_at) {
+			return gmodel.ProductBillingRate{}, fmt.Errorf("effectiveTo cannot be before the rate was last applied")
+		}
+		columns = append(columns, table.ProductBillingRate.EffectiveTo)
+		rate.EffectiveTo = input.EffectiveTo
+	}
+	if rate.EffectiveTo != nil && !rate.EffectiveTo.After(rate.EffectiveFrom) {
+		return gmodel.ProductBillingRate{}, fmt.Errorf("effectiveTo must be after effectiveFrom")
+	}
+	overlaps, err := s.billingRateOverlaps(ctx, rate)
+	if err != nil {
+		return gmodel.ProductBillingRate{}, err
+	}
+	if overlaps {
+		return gmodel.ProductBillingRate{}, fmt.Errorf("rate overlaps with an existing rate version")
+	}
+
+	qb := table.ProductBillingRate.
+		UPDATE(columns).
+		MODEL(rate).
+		WHERE(table.ProductBillingRate.ID.EQ(postgres.Int(rate.ID)))
+	if _, err := qb.ExecContext(ctx, s.DbOrTxExecutable()); err != nil {
+		return gmodel.ProductBillingRate{}, err
+	}
+	return s.FindBillingRateById(ctx, rate.ID)
+}
+
+// Deletes a rate version that has not been applied to any billing rows.
+// The version that was closed when this one was created is extended to cover its range
+func (s Service) DeleteBillingRate(ctx context.Context, id int64) (gmodel.ProductBillingRate, error) {
+	rate, err := s.FindBillingRateById(ctx, id)
+	if err != nil {
+		return gmodel.ProductBillingRate{}, fmt.Errorf("rate not found")
+	}
+	usage_count, _, err := s.billingRateUsage(ctx, rate.ID)
+	if err != nil {
+		return gmodel.ProductBillingRate{}, err
+	}
+	if usage_count > 0 {
+		return gmodel.ProductBillingRate{}, fmt.Errorf("rate has already been applied to billing rows")
+	}
+	scope_rate := model.ProductBillingRate{
+		CategoryID: rate.CategoryID,
+		AdministrativeDivision: rate.AdministrativeDivision,
+	}
+	scope_rate.Type.Scan(rate.Type.String())
+	if scope_rate.CountryCode, err = s.billingRateCountryCode(rate.CountryCode); err != nil {
+		return gmodel.ProductBillingRate{}, err
+	}
+
+	s.TX, err = s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return gmodel.ProductBillingRate{}, err
+	}
+	defer s.TX.Rollback()
+
+	qb := table.ProductBillingRate.
+		DELETE().
+		WHERE(table.ProductBillingRate.ID.EQ(postgres.Int(rate.ID)))
+	if _, err := qb.ExecContext(ctx, s.TX); err != nil {
+		return gmodel.ProductBillingRate{}, err
+	}
+
+	effective_to := postgres.TimestampzExp(postgres.NULL)
+	if rate.EffectiveTo != nil {
+		effective_to = postgres.TimestampzT(*rate.EffectiveTo)
+	}
+	reopen_qb := table.ProductBillingRate.
+		UPDATE(table.ProductBillingRate.EffectiveTo, table.ProductBillingRate.UpdatedAt).
+		SET(effective_to, postgres.TimestampzT(time.Now())).
+		WHERE(postgres.AND(
+			billingRateSameScopeClause(scope_rate),
+			table.ProductBillingRate.EffectiveTo.EQ(postgres.TimestampzT(rate.EffectiveFrom)),
+		))
+	if _, err := reopen_qb.ExecContext(ctx, s.TX); err != nil {
+		return gmodel.ProductBillingRate{}, err
+	}
+	if err := s.TX.Commit(); err != nil {
+		return gmodel.ProductBillingRate{}, err
+	}
+	s.TX = nil
+	return rate, nil
+}
+
+// Returns the most specific rate version effective at the given time.
+// Deeper category matches win over shallower ones, followed by region matches
+// and finally the most recent version
+func (s Service) FindApplicableBillingRate(
+	ctx context.Context,
+	billing_type model.ProductBillingType,
+	scope BillingRateScope,
+	at time.Time,
+) (rate model.ProductBillingRate, err error) {
+	category_clause := table.ProductBillingRate.CategoryID.IS_NULL()
+	if len(scope.CategoryPath) > 0 {
+		category_ids := make([]postgres.Expression, len(scope.CategoryPath))
+		for i, id := range scope.CategoryPath {
+			category_ids[i] = postgres.Int(int64(id))
+		}
+		category_clause = category_clause.OR(table.ProductBillingRate.CategoryID.IN(category_ids...))
+	}
+	country_clause := table.ProductBillingRate.CountryCode.IS_NULL()
+	if scope.CountryCode != nil {
+		country_clause = country_clause.OR(table.ProductBillingRate.CountryCode.EQ(postgres.NewEnumValue(*scope.CountryCode)))
+	}
+	division_clause := table.ProductBillingRate.AdministrativeDivision.IS_NULL()
+	if scope.AdministrativeDivision != nil {
+		division_clause = division_clause.OR(table.ProductBillingRate.AdministrativeDivision.EQ(postgres.String(*scope.AdministrativeDivision)))
+	}
+
+	qb := table.ProductBillingRate.
+		SELECT(table.ProductBillingRate.AllColumns).
+		FROM(table.ProductBillingRate).
+		WHERE(postgres.AND(
+			table.ProductBillingRate.Type.EQ(postgres.NewEnumValue(billing_type.String())),
+			billingRateEffectiveClause(at),
+			category_clause,
+			country_clause,
+			division_clause,
+		))
+	var candidates []model.ProductBillingRate
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &candidates); err != nil {
+		return model.ProductBillingRate{}, err
+	}
+	if len(candidates) == 0 {
+		return model.ProductBillingRate{}, fmt.Errorf("no billing rate found for %s", billing_type.String())
+	}
+
+	best := -1
+	var best_score []int64
+	for i, candidate := range candidates {
+		score := billingRateSpecificity(candidate, scope.CategoryPath)
+		if best == -1 || compareScores(score, best_score) > 0 {
+			best = i
+			best_score = score
+		}
+	}
+	return candidates[best], nil
+}
+
+func billingRateSpecificity(rate model.ProductBillingRate, category_path []int) []int64 {
+	var category_depth int64
+	if rate.CategoryID != nil {
+		for i, id := range category_path {
+			if int64(id) == *rate.CategoryID {
+				category_depth = int64(i + 1)
+			}
+		}
+	}
+	var region int64
+	if rate.CountryCode != nil {
+		region++
+	}
+	if rate.AdministrativeDivision != nil {
+		region++
+	}
+	return []int64{category_depth, region, rate.EffectiveFrom.UnixNano(), rate.ID}
+}
+
+func compareScores(a []int64, b []int64) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] > b[i] {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
+
+// Builds the billing rate scope for a product and an optional branch
+func (s Service) BillingRateScopeFor(ctx context.Context, product gmodel.Product, branch_id *int64) BillingRateScope {
+	scope := BillingRateScope{}
+	category := product.Category
+	if category == nil {
+		if c, err := s.FindCategoryById(ctx, product.CategoryID); err == nil {
+			category = &c
+		}
+	}
+	if category != nil && category.Path != "" {
+		scope.CategoryPath = utils.PostgresArrayToIntArray(category.Path)
+	}
+	if branch_id != nil {
+		if branch, err := s.FindBranchById(ctx, *branch_id); err == nil && branch.Address != nil {
+			scope.CountryCode = &branch.Address.CountryCode
+			scope.AdministrativeDivision = &branch.Address.AdministrativeDivision
+		}
+	}
+	return scope
+}
@@ -0,0 +1,279 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+// Repeated submissions for the same product (and branch) within this window are held
+const BILLING_REPEAT_WINDOW = 6 * time.Hour
+
+// Window used to look for users confirming each other's prices
+const BILLING_RING_WINDOW = 7 * 24 * time.Hour
+
+// A price counts as a confirmation when it matches the previous price within this window
+const BILLING_CONFIRMATION_WINDOW = 24 * time.Hour
+
+// Minimum number of confirmations in both directions to flag a confirmation ring
+const BILLING_RING_MIN_CONFIRMATIONS = 2
+
+// Price changes above this ratio of the previous price are held as outliers
+const PRICE_OUTLIER_RATIO = 0.5
+
+type BillingReview struct {
+	Status model.ProductBillingStatus
+	Reasons []string
+}
+
+func (review *BillingReview) Flag(status model.ProductBillingStatus, reason string) {
+	if billingStatusSeverity(status) > billingStatusSeverity(review.Status) {
+		review.Status = status
+	}
+	review.Reasons = append(review.Reasons, reason)
+}
+
+func (review BillingReview) Reason() *string {
+	if len(review.Reasons) == 0 {
+		return nil
+	}
+	reason := strings.Join(review.Reasons, "; ")
+	return &reason
+}
+
+func billingStatusSeverity(status model.ProductBillingStatus) int {
+	switch status {
+		case model.ProductBillingStatus_Rejected: return 2
+		case model.ProductBillingStatus_Held: return 1
+		default: return 0
+	}
+}
+
+// Runs the fraud and abuse rules against a billing submission before it's stored.
+// Rule failures never block the underlying contribution, they only affect the billing status
+func (s Service) ReviewBillingSubmission(
+	ctx context.Context,
+	user gmodel.User,
+	billing_type model.ProductBillingType,
+	product_id int64,
+	branch_id *int64,
+	new_data *string,
+	old_data *string,
+) BillingReview {
+	review := BillingReview{ Status: model.ProductBillingStatus_Approved }
+
+	if new_data != nil && old_data != nil {
+		var new_values, old_values map[string]any
+		json.Unmarshal([]byte(*new_data), &new_values)
+		json.Unmarshal([]byte(*old_data), &old_values)
+		changed, trivial := billingDataChanges(new_values, old_values)
+
+		// a different user submitting the same price is a confirmation, not a no-op
+		confirmation_of := billingConfirmationOf(billing_type, user, old_values)
+		if changed == 0 && confirmation_of == nil {
+			if trivial > 0 {
+				review.Flag(model.ProductBillingStatus_Rejected, "trivial update: only whitespace or letter case changed")
+			} else {
+				review.Flag(model.ProductBillingStatus_Rejected, "no-op update: no fields changed")
+			}
+		}
+
+		new_amount, new_ok := new_values["amount"].(float64)
+		old_amount, old_ok := old_values["amount"].(float64)
+		if billing_type == model.ProductBillingType_Price && new_ok && old_ok && old_amount > 0 {
+			if change := math.Abs(new_amount - old_amount) / old_amount; change > PRICE_OUTLIER_RATIO {
+				review.Flag(
+					model.ProductBillingStatus_Held,
+					fmt.Sprintf("price outlier: %.0f%% change from previous price", change * 100),
+				)
+			}
+		}
+
+		if changed == 0 && confirmation_of != nil {
+			since := time.Now().Add(-BILLING_RING_WINDOW)
+			given, err_given := s.PriceConfirmationCount(ctx, user.ID, *confirmation_of, since)
+			received, err_received := s.PriceConfirmationCount(ctx, *confirmation_of, user.ID, since)
+			if err_given == nil && err_received == nil &&
+				given >= BILLING_RING_MIN_CONFIRMATIONS &&
+				received >= BILLING_RING_MIN_CONFIRMATIONS {
+				review.Flag(
+					model.ProductBillingStatus_Held,
+					fmt.Sprintf("possible confirmation ring with user %d", *confirmation_of),
+				)
+			}
+		}
+	}
+
+	recent, err := s.RecentBillingCount(ctx, user.ID, billing_type, product_id, branch_id, time.Now().Add(-BILLING_REPEAT_WINDOW))
+	if err == nil && recent > 0 {
+		review.Flag(
+			model.ProductBillingStatus_Held,
+			fmt.Sprintf("repeated submission within %s", BILLING_REPEAT_WINDOW.String()),
+		)
+	}
+	if s.IsLowReputation(user) {
+		review.Flag(model.ProductBillingStatus_Held, "low reputation score")
+	}
+
+	// trusted contributors skip moderation. rejected no-op submissions still apply
+	if review.Status == model.ProductBillingStatus_Held && s.IsTrustedContributor(user) {
+		review.Status = model.ProductBillingStatus_Approved
+		review.Reasons = append([]string{"auto-approved trusted contributor"}, review.Reasons...)
+	}
+	return review
+}
+
+// Compares submitted values with the previous values.
+// Returns the number of substantive changes and the number of changes
+// that only differ by whitespace or letter case
+func billingDataChanges(new_values map[string]any, old_values map[string]any) (changed int, trivial int) {
+	for key, new_val := range new_values {
+		if new_val == nil {
+			continue
+		}
+		old_val, ok := old_values[key]
+		if !ok {
+			changed++
+			continue
+		}
+		if reflect.DeepEqual(new_val, old_val) {
+			continue
+		}
+		new_str, new_is_str := new_val.(string)
+		old_str, old_is_str := old_val.(string)
+		if new_is_str && old_is_str && normalizeBillingString(new_str) == normalizeBillingString(old_str) {
+			trivial++
+			continue
+		}
+		changed++
+	}
+	return changed, trivial
+}
+
+func normalizeBillingString(v string) string {
+	return strings.ToLower(strings.Join(strings.Fields(v), " "))
+}
+
+// Returns the creator of the previous price when the submission is a price by a different user
+func billingConfirmationOf(billing_type model.ProductBillingType, user gmodel.User, old_values map[string]any) *int64 {
+	if billing_type != model.ProductBillingType_Price {
+		return nil
+	}
+	created_by, ok := old_values["createdById"].(float64)
+	if !ok || int64(created_by) == user.ID {
+		return nil
+	}
+	creator_id := int64(created_by)
+	return &creator_id
+}
+
+// Number of non-rejected billing rows created by the user for the same product, type and branch since the given time
+func (s Service) RecentBillingCount(
+	ctx context.Context,
+	user_id int64,
+	billing_type model.ProductBillingType,
+	product_id int64,
+	branch_id *int64,
+	since time.Time,
+) (int, error) {
+	where_clause := postgres.AND(
+		table.ProductBilling.UserID.EQ(postgres.Int(user_id)),
+		table.ProductBilling.ProductID.EQ(postgres.Int(product_id)),
+		table.ProductBilling.BillingRateType.EQ(postgres.NewEnumValue(billing_type.String())),
+		table.ProductBilling.CreatedAt.GT(postgres.TimestampzT(since)),
+		table.ProductBilling.Status.NOT_EQ(postgres.NewEnumValue(model.ProductBillingStatus_Rejected.String())),
+	)
+	if branch_id != nil {
+		where_clause = where_clause.AND(table.ProductBilling.BranchID.EQ(postgres.Int(*branch_id)))
+	}
+	qb := table.ProductBilling.
+		SELECT(postgres.COUNT(table.ProductBilling.ID).AS("count")).
+		FROM(table.ProductBilling).
+		WHERE(where_clause)
+	var res struct{ Count int }
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &res); err != nil {
+		return 0, err
+	}
+	return res.Count, nil
+}
+
+// Number of prices created by `confirmer_id` that repeat a price created by `creator_id`
+// on the same stock shortly before
+func (s Service) PriceConfirmationCount(ctx context.Context, confirmer_id int64, creator_id int64, since time.Time) (int, error) {
+	confirmed := table.Price.AS("confirmed_price")
+	qb := table.Price.
+		SELECT(postgres.COUNT(table.Price.ID).AS("count")).
+		FROM(table.Price.INNER_JOIN(confirmed, postgres.AND(
+			confirmed.StockID.EQ(table.Price.StockID),
+			confirmed.Amount.EQ(table.Price.Amount),
+			confirmed.CreatedAt.LT(table.Price.CreatedAt),
+			confirmed.CreatedAt.GT(table.Price.CreatedAt.SUB(postgres.INTERVALd(BILLING_CONFIRMATION_WINDOW))),
+		))).
+		WHERE(postgres.AND(
+			table.Price.CreatedByID.EQ(postgres.Int(confirmer_id)),
+			confirmed.CreatedByID.EQ(postgres.Int(creator_id)),
+			table.Price.CreatedAt.GT(postgres.TimestampzT(since)),
+		))
+	var res struct{ Count int }
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &res); err != nil {
+		return 0, err
+	}
+	return res.Count, nil
+}
+
+// Lets an admin approve, hold or reject a billing row. Paid rows cannot be changed
+func (s Service) ReviewProductBilling(
+	ctx context.Context,
+	user gmodel.User,
+	id int64,
+	status gmodel.ProductBillingStatus,
+	reason *string,
+) (gmodel.ProductBilling, error) {
+	billing, err := s.FindProductBillingById(ctx, id)
+	if err != nil {
+		return gmodel.ProductBilling{}, fmt.Errorf("billing row not found")
+	}
+	if billing.PaidAt != nil {
+		return gmodel.ProductBilling{}, fmt.Errorf("billing row has already been paid")
+	}
+	var billing_status model.ProductBillingStatus
+	if err := billing_status.Scan(status.String()); err != nil {
+		return gmodel.ProductBilling{}, fmt.Errorf("invalid status")
+	}
+
+	now := time.Now()
+	qb := table.ProductBilling.
+		UPDATE(
+			table.ProductBilling.Status,
+			table.ProductBilling.StatusReason,
+			table.ProductBilling.ReviewedByID,
+			table.ProductBilling.ReviewedAt,
+		).
+		MODEL(model.ProductBilling{
+			Status: billing_status,
+			StatusReason: reason,
+			ReviewedByID: &user.ID,
+			ReviewedAt: &now,
+		}).
+		WHERE(postgres.AND(
+			table.ProductBilling.ID.EQ(postgres.Int(billing.ID)),
+			table.ProductBilling.PaidAt.IS_NULL(),
+		))
+	if _, err := qb.ExecContext(ctx, s.DbOrTxExecutable()); err != nil {
+		return gmodel.ProductBilling{}, err
+	}
+	if _, err := s.RefreshUserReputation(ctx, billing.UserID); err != nil {
+		log.Printf("could not refresh reputation for user %d. %s\n", billing.UserID, err.Error())
+	}
+	return s.FindProductBillingById(ctx, billing.ID)
+}
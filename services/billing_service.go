@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"log"
+	"time"
 
 	"github.com/go-jet/jet/v2/postgres"
 	"github.com/pricetra/api/database/jet/postgres/public/model"
@@ -22,22 +24,21 @@ func toJsonString(v any) (*string, error) {
 	return &x, nil
 }
 
+// Creates a billing row using the rate version that applies to the product category
+// and, when a branch is provided, the branch region
 func (s Service) CreateProductBilling(
 	ctx context.Context,
 	user gmodel.User,
 	billing_type model.ProductBillingType,
 	product gmodel.Product,
+	branch_id *int64,
 	new_data any,
 	old_data any,
 ) (res model.ProductBilling, err error) {
 	db := s.DbOrTxQueryable()
-	cur_rate := model.ProductBillingRate{}
-	rate_qb := table.ProductBillingRate.
-		SELECT(table.ProductBillingRate.AllColumns).
-		FROM(table.ProductBillingRate).
-		WHERE(table.ProductBillingRate.Type.EQ(postgres.NewEnumValue(billing_type.String()))).
-		LIMIT(1)
-	if err := rate_qb.QueryContext(ctx, db, &cur_rate); err != nil {
+	scope := s.BillingRateScopeFor(ctx, product, branch_id)
+	cur_rate, err := s.FindApplicableBillingRate(ctx, billing_type, scope, time.Now())
+	if err != nil {
 		return model.ProductBilling{}, err
 	}
 
@@ -46,6 +47,11 @@ func (s Service) CreateProductBilling(
 		table.ProductBilling.ProductID,
 		table.ProductBilling.Rate,
 		table.ProductBilling.BillingRateType,
+		table.ProductBilling.CurrencyCode,
+		table.ProductBilling.RateID,
+		table.ProductBilling.BranchID,
+		table.ProductBilling.Status,
+		table.ProductBilling.StatusReason,
 		table.ProductBilling.NewData,
 	}
 	new_data_json, err := toJsonString(new_data)
@@ -59,6 +65,7 @@ func (s Service) CreateProductBilling(
 	if old_data_json != nil {
 		insert_cols = append(insert_cols, table.ProductBilling.OldData)
 	}
+	review := s.ReviewBillingSubmission(ctx, user, billing_type, product.ID, branch_id, new_data_json, old_data_json)
 
 	qb := table.ProductBilling.
 		INSERT(insert_cols).
@@ -67,22 +74,93 @@ func (s Service) CreateProductBilling(
 			ProductID: product.ID,
 			Rate: cur_rate.Rate,
 			BillingRateType: billing_type,
+			CurrencyCode: cur_rate.CurrencyCode,
+			RateID: &cur_rate.ID,
+			BranchID: branch_id,
+			Status: review.Status,
+			StatusReason: review.Reason(),
 			NewData: new_data_json,
 			OldData: old_data_json,
 		}).RETURNING(table.ProductBilling.AllColumns)
 	if err := qb.QueryContext(ctx, db, &res); err != nil {
 		return model.ProductBilling{}, err
 	}
+	if res.Status != model.ProductBillingStatus_Rejected {
+		if _, err := s.EvaluateAchievements(ctx, user.ID, billing_type, branch_id); err != nil {
+			log.Printf("could not evaluate achievements for user %d. %s\n", user.ID, err.Error())
+		}
+	}
 	return res, nil
 }
 
-func (s Service) FindProductBillingByUser(ctx context.Context, paginator_input gmodel.PaginatorInput, user gmodel.User) (res gmodel.PaginatedProductBilling, err error) {
-	where_clause := table.ProductBilling.UserID.EQ(postgres.Int(user.ID))
-	my_table := table.ProductBilling.
+func productBillingTable() postgres.ReadableTable {
+	return table.ProductBilling.
 		INNER_JOIN(table.User, table.User.ID.EQ(table.ProductBilling.UserID)).
 		INNER_JOIN(table.Product, table.Product.ID.EQ(table.ProductBilling.ProductID)).
 		INNER_JOIN(table.Category, table.Category.ID.EQ(table.Product.CategoryID))
+}
+
+func productBillingColumns() postgres.ProjectionList {
+	return postgres.ProjectionList{
+		table.ProductBilling.ID,
+		table.ProductBilling.ProductID,
+		table.ProductBilling.UserID,
+		table.ProductBilling.CreatedAt,
+		table.ProductBilling.Rate,
+		table.ProductBilling.PaidAt,
+		table.ProductBilling.BillingRateType,
+		table.ProductBilling.CurrencyCode,
+		table.ProductBilling.PayoutID,
+		table.ProductBilling.RateID,
+		table.ProductBilling.BranchID,
+		table.ProductBilling.Status,
+		table.ProductBilling.StatusReason,
+		table.ProductBilling.ReviewedByID,
+		table.ProductBilling.ReviewedAt,
+		table.User.ID,
+		table.User.Name,
+		table.User.Avatar,
+		table.User.Active,
+		table.Product.AllColumns,
+		table.Category.AllColumns,
+	}
+}
 
+func (s Service) FindProductBillingById(ctx context.Context, id int64) (billing gmodel.ProductBilling, err error) {
+	qb := table.ProductBilling.
+		SELECT(productBillingColumns()).
+		FROM(productBillingTable()).
+		WHERE(table.ProductBilling.ID.EQ(postgres.Int(id))).
+		LIMIT(1)
+	err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &billing)
+	return billing, err
+}
+
+func (s Service) FindProductBillingByUser(ctx context.Context, paginator_input gmodel.PaginatorInput, user gmodel.User) (res gmodel.PaginatedProductBilling, err error) {
+	where_clause := table.ProductBilling.UserID.EQ(postgres.Int(user.ID))
+	return s.paginatedProductBilling(ctx, paginator_input, where_clause)
+}
+
+// Returns billing rows flagged by the fraud rules with the given status (defaults to HELD)
+func (s Service) FlaggedProductBilling(
+	ctx context.Context,
+	paginator_input gmodel.PaginatorInput,
+	status *gmodel.ProductBillingStatus,
+) (res gmodel.PaginatedProductBilling, err error) {
+	billing_status := gmodel.ProductBillingStatusHeld
+	if status != nil {
+		billing_status = *status
+	}
+	where_clause := table.ProductBilling.Status.EQ(postgres.NewEnumValue(billing_status.String()))
+	return s.paginatedProductBilling(ctx, paginator_input, where_clause)
+}
+
+func (s Service) paginatedProductBilling(
+	ctx context.Context,
+	paginator_input gmodel.PaginatorInput,
+	where_clause postgres.BoolExpression,
+) (res gmodel.PaginatedProductBilling, err error) {
+	my_table := productBillingTable()
 	paginator, err := s.Paginate(ctx, paginator_input, my_table, table.ProductBilling.ID, where_clause)
 	if err != nil {
 		return gmodel.PaginatedProductBilling{
@@ -92,21 +170,7 @@ func (s Service) FindProductBillingByUser(ctx context.Context, paginator_input g
 	}
 
 	qb := table.ProductBilling.
-		SELECT(
-			table.ProductBilling.ID,
-			table.ProductBilling.ProductID,
-			table.ProductBilling.UserID,
-			table.ProductBilling.CreatedAt,
-			table.ProductBilling.Rate,
-			table.ProductBilling.PaidAt,
-			table.ProductBilling.BillingRateType,
-			table.User.ID,
-			table.User.Name,
-			table.User.Avatar,
-			table.User.Active,
-			table.Product.AllColumns,
-			table.Category.AllColumns,
-		).
+		SELECT(productBillingColumns()).
 		FROM(my_table).
 		WHERE(where_clause).
 		ORDER_BY(table.ProductBilling.CreatedAt.DESC()).
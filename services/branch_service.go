@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/Goldziher/go-utils/sliceutils"
 	"github.com/go-jet/jet/v2/postgres"
@@ -100,6 +101,65 @@ func (s Service) FindBranchById(ctx context.Context, id int64) (branch gmodel.Br
 	return branch, err
 }
 
+func (s Service) UpdateBranch(ctx context.Context, user gmodel.User, branch_id int64, input gmodel.UpdateBranch) (updated_branch gmodel.Branch, err error) {
+	branch, err := s.FindBranchById(ctx, branch_id)
+	if err != nil {
+		return gmodel.Branch{}, fmt.Errorf("branch id is invalid")
+	}
+
+	s.TX, err = s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return gmodel.Branch{}, err
+	}
+	defer s.TX.Rollback()
+
+	b := model.Branch{}
+	columns := postgres.ColumnList{}
+	if input.Name != nil {
+		if strings.TrimSpace(*input.Name) == "" {
+			return gmodel.Branch{}, fmt.Errorf("branch name cannot be empty")
+		}
+		columns = append(columns, table.Branch.Name)
+		b.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.FullAddress != nil {
+		address_input, err := s.FullAddressToCreateAddress(ctx, *input.FullAddress)
+		if err != nil {
+			return gmodel.Branch{}, err
+		}
+		address, err := s.FindOrCreateAddress(ctx, &user, address_input)
+		if err != nil {
+			return gmodel.Branch{}, err
+		}
+		if address.ID != branch.AddressID {
+			if _, err := s.FindBranchByStoreIdAndAddressId(ctx, branch.StoreID, address.ID); err == nil {
+				return gmodel.Branch{}, fmt.Errorf("branch with this store and address already exists")
+			}
+		}
+		columns = append(columns, table.Branch.AddressID)
+		b.AddressID = address.ID
+	}
+	if len(columns) == 0 {
+		return branch, nil
+	}
+	columns = append(columns, table.Branch.UpdatedByID, table.Branch.UpdatedAt)
+	b.UpdatedByID = &user.ID
+	b.UpdatedAt = time.Now()
+
+	qb := table.Branch.
+		UPDATE(columns).
+		MODEL(b).
+		WHERE(table.Branch.ID.EQ(postgres.Int(branch.ID)))
+	if _, err := qb.ExecContext(ctx, s.TX); err != nil {
+		return gmodel.Branch{}, err
+	}
+	if err := s.TX.Commit(); err != nil {
+		return gmodel.Branch{}, err
+	}
+	s.TX = nil
+	return s.FindBranchById(ctx, branch.ID)
+}
+
 func (s Service) FindBranchesByStoreId(
 	ctx context.Context,
 	store_id int64,
@@ -344,7 +404,7 @@ func (s Service) AllFavoriteBranchProductPrices(ctx context.Context, user gmodel
 						table.Stock.BranchID.EQ(table.Branch.ID).
 							AND(table.Stock.ProductID.EQ(postgres.Int(product_id))),
 					).
-					INNER_JOIN(table.Price, table.Price.ID.EQ(table.Stock.LatestPriceID)),
+					LEFT_JOIN(table.Price, table.Price.ID.EQ(table.Stock.LatestPriceID)),
 			).
 			WHERE(
 				table.Branch.StoreID.EQ(postgres.Int(bl.Branch.StoreID)).
@@ -395,8 +455,8 @@ func (s Service) BranchesWithProducts(
 				INNER_JOIN(table.Store, table.Store.ID.EQ(table.Branch.StoreID)).
 				INNER_JOIN(table.Address, table.Address.ID.EQ(table.Branch.AddressID)).
 				INNER_JOIN(table.Stock, table.Stock.BranchID.EQ(table.Branch.ID)).
-				INNER_JOIN(table.Price, table.Price.ID.EQ(table.Stock.LatestPriceID)).
-				INNER_JOIN(table.Product, table.Product.ID.EQ(table.Price.ProductID)).
+				LEFT_JOIN(table.Price, table.Price.ID.EQ(table.Stock.LatestPriceID)).
+				INNER_JOIN(table.Product, table.Product.ID.EQ(table.Stock.ProductID)).
 				INNER_JOIN(table.Category, table.Category.ID.EQ(table.Product.CategoryID)),
 		).
 		WHERE(branch_where_clause).
@@ -447,7 +507,7 @@ func (s Service) BranchesWithProducts(
 	}
 	order_by = append(
 		order_by,
-		table.Price.CreatedAt.DESC(),
+		table.Price.CreatedAt.DESC().NULLS_LAST(),
 		table.Product.Views.DESC(),
 	)
 	cols = append(cols, filter_cols...)
@@ -484,7 +544,7 @@ func (s Service) BranchesWithProducts(
 					INNER_JOIN(table.Branch, table.Branch.ID.EQ(table.Branch.ID.From(paginated_branch_ids_table))).
 					INNER_JOIN(table.Address, table.Address.ID.EQ(table.Branch.AddressID)).
 					INNER_JOIN(table.Stock, table.Stock.BranchID.EQ(table.Branch.ID)).
-					INNER_JOIN(table.Price, table.Price.ID.EQ(table.Stock.LatestPriceID)).
+					LEFT_JOIN(table.Price, table.Price.ID.EQ(table.Stock.LatestPriceID)).
 					INNER_JOIN(table.Product, table.Product.ID.EQ(table.Stock.ProductID)).
 					INNER_JOIN(table.Category, table.Category.ID.EQ(table.Product.CategoryID)),
 			).
@@ -505,7 +565,7 @@ func (s Service) BranchesWithProducts(
 						INNER_JOIN(table.Category, table.Category.ID.EQ(table.Product.CategoryID)).
 						INNER_JOIN(table.Store, table.Store.ID.EQ(table.Stock.StoreID)).
 						INNER_JOIN(table.Branch, table.Branch.ID.EQ(table.Stock.BranchID)).
-						INNER_JOIN(table.Price, table.Price.ID.EQ(table.Stock.LatestPriceID)).
+						LEFT_JOIN(table.Price, table.Price.ID.EQ(table.Stock.LatestPriceID)).
 						INNER_JOIN(table.Address, table.Address.ID.EQ(table.Branch.AddressID)).
 						LEFT_JOIN(created_user_table, created_user_table.ID.EQ(table.Price.CreatedByID)).
 						LEFT_JOIN(updated_user_table, updated_user_table.ID.EQ(table.Price.UpdatedByID)),
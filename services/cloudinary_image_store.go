@@ -0,0 +1,81 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/cloudinary/cloudinary-go/v2"
+	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+)
+
+type CloudinaryImageStore struct {
+	Client *cloudinary.Cloudinary
+	BaseUrl string
+}
+
+func NewCloudinaryImageStore(cloud_name string, api_key string, api_secret string) (*CloudinaryImageStore, error) {
+	client, err := cloudinary.NewFromParams(cloud_name, api_key, api_secret)
+	if err != nil {
+		return nil, err
+	}
+	return &CloudinaryImageStore{
+		Client: client,
+		BaseUrl: CLOUDINARY_UPLOAD_BASE,
+	}, nil
+}
+
+// Uploads a file, URL or base64 data URI
+func (c CloudinaryImageStore) upload(ctx context.Context, file any, params ImageUploadParams) (*ImageUploadResult, error) {
+	res, err := c.Client.Upload.Upload(ctx, file, uploader.UploadParams{
+		PublicID: params.PublicID,
+		Tags: params.Tags,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.Error.Message != "" {
+		return nil, fmt.Errorf("cloudinary upload failed: %s", res.Error.Message)
+	}
+	return &ImageUploadResult{
+		PublicID: res.PublicID,
+		Version: res.Version,
+		URL: res.SecureURL,
+	}, nil
+}
+
+func (c CloudinaryImageStore) UploadUrl(ctx context.Context, image_url string, params ImageUploadParams) (*ImageUploadResult, error) {
+	return c.upload(ctx, image_url, params)
+}
+
+func (c CloudinaryImageStore) UploadBytes(ctx context.Context, data []byte, params ImageUploadParams) (*ImageUploadResult, error) {
+	return c.upload(ctx, bytes.NewReader(data), params)
+}
+
+func (c CloudinaryImageStore) UploadStream(ctx context.Context, stream io.Reader, params ImageUploadParams) (*ImageUploadResult, error) {
+	return c.upload(ctx, stream, params)
+}
+
+func (c CloudinaryImageStore) Delete(ctx context.Context, public_id string) error {
+	invalidate := true
+	res, err := c.Client.Upload.Destroy(ctx, uploader.DestroyParams{
+		PublicID: public_id,
+		Invalidate: &invalidate,
+	})
+	if err != nil {
+		return err
+	}
+	if res.Error.Message != "" {
+		return fmt.Errorf("cloudinary delete failed: %s", res.Error.Message)
+	}
+	return nil
+}
+
+func (c CloudinaryImageStore) Url(public_id string) string {
+	return fmt.Sprintf("%s/%s", c.BaseUrl, public_id)
+}
+
+func (c CloudinaryImageStore) VersionUrl(public_id string, version string) string {
+	return fmt.Sprintf("%s/v%s/%s", c.BaseUrl, version, public_id)
+}
@@ -0,0 +1,486 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/utils"
+)
+
+func (s Service) FindCouponById(ctx context.Context, id int64) (coupon gmodel.Coupon, err error) {
+	created_by_user, _, _ := s.CreatedAndUpdatedUserTable()
+	qb := table.Coupon.
+		SELECT(
+			table.Coupon.AllColumns,
+			created_by_user.ID,
+			created_by_user.Name,
+			created_by_user.Avatar,
+		).
+		FROM(table.Coupon.
+			LEFT_JOIN(created_by_user, created_by_user.ID.EQ(table.Coupon.CreatedByID)),
+		).
+		WHERE(table.Coupon.ID.EQ(postgres.Int(id))).
+		LIMIT(1)
+	err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &coupon)
+	return coupon, err
+}
+
+// Approved coupons within their validity window
+func activeCouponClause() postgres.BoolExpression {
+	now := postgres.TimestampzT(time.Now())
+	return postgres.AND(
+		table.Coupon.Status.EQ(postgres.NewEnumValue(model.CouponStatus_Approved.String())),
+		postgres.OR(table.Coupon.StartsAt.IS_NULL(), table.Coupon.StartsAt.LT_EQ(now)),
+		postgres.OR(table.Coupon.ExpiresAt.IS_NULL(), table.Coupon.ExpiresAt.GT(now)),
+	)
+}
+
+// Coupons scoped to any of the products, brands or categories
+func couponScopeClause(product_ids []int64, brands []string, category_ids []int64) postgres.BoolExpression {
+	clauses := []postgres.BoolExpression{}
+	if len(product_ids) > 0 {
+		ids := make([]postgres.Expression, len(product_ids))
+		for i, id := range product_ids {
+			ids[i] = postgres.Int(id)
+		}
+		clauses = append(clauses, table.Coupon.ProductID.IN(ids...))
+	}
+	if len(brands) > 0 {
+		values := make([]postgres.Expression, len(brands))
+		for i, brand := range brands {
+			values[i] = postgres.String(strings.ToLower(brand))
+		}
+		clauses = append(clauses, postgres.LOWER(table.Coupon.Brand).IN(values...))
+	}
+	if len(category_ids) > 0 {
+		ids := make([]postgres.Expression, len(category_ids))
+		for i, id := range category_ids {
+			ids[i] = postgres.Int(id)
+		}
+		clauses = append(clauses, table.Coupon.CategoryID.IN(ids...))
+	}
+	if len(clauses) == 0 {
+		return postgres.Bool(false)
+	}
+	return postgres.OR(clauses...)
+}
+
+// Checks the scope fields of the coupon input
+func (s Service) validateCouponInput(ctx context.Context, input gmodel.CreateCoupon) error {
+	if (input.DiscountAmount == nil) == (input.DiscountPercent == nil) {
+		return fmt.Errorf("coupon needs either a discount amount or percent")
+	}
+	if input.Type == gmodel.CouponTypeStore && input.StoreID == nil {
+		return fmt.Errorf("store coupons need a store")
+	}
+	if input.StoreID != nil && !s.StoreExists(ctx, *input.StoreID) {
+		return fmt.Errorf("store id is invalid")
+	}
+	switch input.Scope {
+	case gmodel.CouponScopeProduct:
+		if input.ProductID == nil || !s.ProductExists(ctx, *input.ProductID) {
+			return fmt.Errorf("product id is invalid")
+		}
+	case gmodel.CouponScopeBrand:
+		if input.Brand == nil || strings.TrimSpace(*input.Brand) == "" {
+			return fmt.Errorf("brand coupons need a brand")
+		}
+	case gmodel.CouponScopeCategory:
+		if input.CategoryID == nil {
+			return fmt.Errorf("category coupons need a category")
+		}
+		if _, err := s.FindCategoryById(ctx, *input.CategoryID); err != nil {
+			return fmt.Errorf("category id is invalid")
+		}
+	}
+	if input.ExpiresAt != nil {
+		if input.ExpiresAt.Before(time.Now()) {
+			return fmt.Errorf("expiration date cannot be in the past")
+		}
+		if input.StartsAt != nil && !input.StartsAt.Before(*input.ExpiresAt) {
+			return fmt.Errorf("coupon must start before it expires")
+		}
+	}
+	return nil
+}
+
+// Coupons submitted by trusted contributors, admins and staff of the coupon's store are approved right away,
+// all others are reviewed first
+func (s Service) SubmitCoupon(ctx context.Context, user gmodel.User, input gmodel.CreateCoupon) (gmodel.Coupon, error) {
+	if err := s.StructValidator.StructCtx(ctx, input); err != nil {
+		return gmodel.Coupon{}, fmt.Errorf("invalid input: %w", err)
+	}
+	if err := s.validateCouponInput(ctx, input); err != nil {
+		return gmodel.Coupon{}, err
+	}
+
+	var coupon_type model.CouponType
+	if err := coupon_type.Scan(input.Type.String()); err != nil {
+		return gmodel.Coupon{}, err
+	}
+	var scope model.CouponScope
+	if err := scope.Scan(input.Scope.String()); err != nil {
+		return gmodel.Coupon{}, err
+	}
+	status := model.CouponStatus_Pending
+	if !s.RequiresProductEditProposal(user) ||
+		(input.StoreID != nil && s.HasStoreRole(ctx, user, *input.StoreID, gmodel.StoreRoleStaff)) {
+		status = model.CouponStatus_Approved
+	}
+
+	// only the field of the coupon's scope is kept
+	coupon := model.Coupon{
+		Type: coupon_type,
+		StoreID: input.StoreID,
+		Scope: scope,
+		Title: strings.TrimSpace(input.Title),
+		Description: input.Description,
+		Code: input.Code,
+		DiscountAmount: input.DiscountAmount,
+		DiscountPercent: input.DiscountPercent,
+		MinimumQuantity: 1,
+		StartsAt: input.StartsAt,
+		ExpiresAt: input.ExpiresAt,
+		Status: status,
+		CreatedByID: &user.ID,
+		UpdatedByID: &user.ID,
+	}
+	switch input.Scope {
+	case gmodel.CouponScopeProduct:
+		coupon.ProductID = input.ProductID
+	case gmodel.CouponScopeBrand:
+		brand := strings.TrimSpace(*input.Brand)
+		coupon.Brand = &brand
+	case gmodel.CouponScopeCategory:
+		coupon.CategoryID = input.CategoryID
+	}
+	if input.MinimumQuantity != nil {
+		coupon.MinimumQuantity = int32(*input.MinimumQuantity)
+	}
+	if input.Stackable != nil {
+		coupon.Stackable = *input.Stackable
+	}
+
+	qb := table.Coupon.
+		INSERT(
+			table.Coupon.Type,
+			table.Coupon.StoreID,
+			table.Coupon.Scope,
+			table.Coupon.ProductID,
+			table.Coupon.Brand,
+			table.Coupon.CategoryID,
+			table.Coupon.Title,
+			table.Coupon.Description,
+			table.Coupon.Code,
+			table.Coupon.DiscountAmount,
+			table.Coupon.DiscountPercent,
+			table.Coupon.MinimumQuantity,
+			table.Coupon.StartsAt,
+			table.Coupon.ExpiresAt,
+			table.Coupon.Stackable,
+			table.Coupon.Status,
+			table.Coupon.CreatedByID,
+			table.Coupon.UpdatedByID,
+		).
+		MODEL(coupon).
+		RETURNING(table.Coupon.AllColumns)
+	var created gmodel.Coupon
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &created); err != nil {
+		return gmodel.Coupon{}, err
+	}
+	return created, nil
+}
+
+func (s Service) paginatedCoupons(
+	ctx context.Context,
+	paginator_input gmodel.PaginatorInput,
+	where_clause postgres.BoolExpression,
+	order_by postgres.OrderByClause,
+) (res gmodel.PaginatedCoupons, err error) {
+	created_by_user, _, _ := s.CreatedAndUpdatedUserTable()
+	tables := table.Coupon.
+		LEFT_JOIN(created_by_user, created_by_user.ID.EQ(table.Coupon.CreatedByID))
+	sql_paginator, err := s.Paginate(ctx, paginator_input, tables, table.Coupon.ID, where_clause)
+	if err != nil {
+		return gmodel.PaginatedCoupons{
+			Coupons: []*gmodel.Coupon{},
+			Paginator: &gmodel.Paginator{},
+		}, nil
+	}
+
+	qb := table.Coupon.
+		SELECT(
+			table.Coupon.AllColumns,
+			created_by_user.ID,
+			created_by_user.Name,
+			created_by_user.Avatar,
+		).
+		FROM(tables).
+		WHERE(where_clause).
+		ORDER_BY(order_by).
+		LIMIT(int64(sql_paginator.Limit)).
+		OFFSET(int64(sql_paginator.Offset))
+	if err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &res.Coupons); err != nil {
+		return gmodel.PaginatedCoupons{}, err
+	}
+	res.Paginator = &sql_paginator.Paginator
+	return res, nil
+}
+
+// Active coupons. Coupons of a product include its brand and category coupons
+func (s Service) PaginatedCoupons(
+	ctx context.Context,
+	paginator_input gmodel.PaginatorInput,
+	filters *gmodel.CouponFilter,
+) (gmodel.PaginatedCoupons, error) {
+	where_clause := activeCouponClause()
+	if filters != nil && filters.StoreID != nil {
+		where_clause = where_clause.AND(postgres.OR(
+			table.Coupon.StoreID.IS_NULL(),
+			table.Coupon.StoreID.EQ(postgres.Int(*filters.StoreID)),
+		))
+	}
+	if filters != nil && filters.ProductID != nil {
+		products, err := s.couponProducts(ctx, []int64{*filters.ProductID})
+		if err != nil || len(products) == 0 {
+			return gmodel.PaginatedCoupons{}, fmt.Errorf("invalid product id")
+		}
+		product := products[0]
+		where_clause = where_clause.AND(couponScopeClause(
+			[]int64{product.ID},
+			[]string{product.Brand},
+			couponCategoryIds(product),
+		))
+	}
+	return s.paginatedCoupons(ctx, paginator_input, where_clause, table.Coupon.CreatedAt.DESC())
+}
+
+func (s Service) PaginatedCouponReviewQueue(ctx context.Context, paginator_input gmodel.PaginatorInput) (gmodel.PaginatedCoupons, error) {
+	where_clause := table.Coupon.Status.EQ(postgres.NewEnumValue(model.CouponStatus_Pending.String()))
+	return s.paginatedCoupons(ctx, paginator_input, where_clause, table.Coupon.CreatedAt.ASC())
+}
+
+func (s Service) ReviewCoupon(
+	ctx context.Context,
+	user gmodel.User,
+	id int64,
+	approve bool,
+	reason *string,
+) (gmodel.Coupon, error) {
+	if _, err := s.FindCouponById(ctx, id); err != nil {
+		return gmodel.Coupon{}, fmt.Errorf("coupon not found")
+	}
+	status := model.CouponStatus_Approved
+	if !approve {
+		status = model.CouponStatus_Rejected
+	}
+	now := time.Now()
+	qb := table.Coupon.
+		UPDATE(
+			table.Coupon.Status,
+			table.Coupon.ReviewReason,
+			table.Coupon.ReviewedByID,
+			table.Coupon.ReviewedAt,
+			table.Coupon.UpdatedByID,
+			table.Coupon.UpdatedAt,
+		).
+		MODEL(model.Coupon{
+			Status: status,
+			ReviewReason: reason,
+			ReviewedByID: &user.ID,
+			ReviewedAt: &now,
+			UpdatedByID: &user.ID,
+			UpdatedAt: now,
+		}).
+		WHERE(table.Coupon.ID.EQ(postgres.Int(id)))
+	if _, err := qb.ExecContext(ctx, s.DbOrTxExecutable()); err != nil {
+		return gmodel.Coupon{}, err
+	}
+	return s.FindCouponById(ctx, id)
+}
+
+// Products with the fields needed to match coupons
+func (s Service) couponProducts(ctx context.Context, product_ids []int64) (products []gmodel.Product, err error) {
+	ids := make([]postgres.Expression, len(product_ids))
+	for i, id := range product_ids {
+		ids[i] = postgres.Int(id)
+	}
+	qb := table.Product.
+		SELECT(
+			table.Product.ID,
+			table.Product.Brand,
+			table.Product.CategoryID,
+			table.Category.ID,
+			table.Category.Path,
+		).
+		FROM(table.Product.
+			INNER_JOIN(table.Category, table.Category.ID.EQ(table.Product.CategoryID)),
+		).
+		WHERE(table.Product.ID.IN(ids...))
+	err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &products)
+	return products, err
+}
+
+// The product's category along with its parent categories
+func couponCategoryIds(product gmodel.Product) []int64 {
+	ids := []int64{product.CategoryID}
+	if product.Category == nil {
+		return ids
+	}
+	for _, id := range utils.PostgresArrayToIntArray(product.Category.Path) {
+		if id > 0 && !slices.Contains(ids, int64(id)) {
+			ids = append(ids, int64(id))
+		}
+	}
+	return ids
+}
+
+// Returns `true` if the coupon can be used on the product at the store
+func IsCouponApplicable(coupon gmodel.Coupon, product gmodel.Product, store_id int64) bool {
+	if coupon.StoreID != nil && *coupon.StoreID != store_id {
+		return false
+	}
+	switch coupon.Scope {
+	case gmodel.CouponScopeProduct:
+		return coupon.ProductID != nil && *coupon.ProductID == product.ID
+	case gmodel.CouponScopeBrand:
+		return coupon.Brand != nil && strings.EqualFold(*coupon.Brand, product.Brand)
+	case gmodel.CouponScopeCategory:
+		return coupon.CategoryID != nil && slices.Contains(couponCategoryIds(product), *coupon.CategoryID)
+	}
+	return false
+}
+
+// Discount of a single unit. Amounts off are spread across the coupon's minimum quantity
+func CouponUnitDiscount(unit_price float64, coupon gmodel.Coupon) float64 {
+	if coupon.DiscountPercent != nil {
+		return unit_price * *coupon.DiscountPercent / 100
+	}
+	if coupon.DiscountAmount != nil {
+		return *coupon.DiscountAmount / float64(max(coupon.MinimumQuantity, 1))
+	}
+	return 0
+}
+
+// Picks the coupons with the largest discount. A non-stackable coupon is used on its own,
+// stackable coupons combine one store coupon with one manufacturer coupon
+func BestCouponCombination(unit_price float64, coupons []gmodel.Coupon) (float64, []*gmodel.Coupon) {
+	var best []*gmodel.Coupon
+	best_discount := 0.0
+	var store_coupon, manufacturer_coupon *gmodel.Coupon
+	for i := range coupons {
+		coupon := &coupons[i]
+		discount := CouponUnitDiscount(unit_price, *coupon)
+		if discount > best_discount {
+			best_discount = discount
+			best = []*gmodel.Coupon{coupon}
+		}
+		if !coupon.Stackable {
+			continue
+		}
+		switch coupon.Type {
+		case gmodel.CouponTypeStore:
+			if store_coupon == nil || discount > CouponUnitDiscount(unit_price, *store_coupon) {
+				store_coupon = coupon
+			}
+		case gmodel.CouponTypeManufacturer:
+			if manufacturer_coupon == nil || discount > CouponUnitDiscount(unit_price, *manufacturer_coupon) {
+				manufacturer_coupon = coupon
+			}
+		}
+	}
+	if store_coupon != nil && manufacturer_coupon != nil {
+		discount := CouponUnitDiscount(unit_price, *store_coupon) + CouponUnitDiscount(unit_price, *manufacturer_coupon)
+		if discount > best_discount {
+			best_discount = discount
+			best = []*gmodel.Coupon{store_coupon, manufacturer_coupon}
+		}
+	}
+	price := math.Round(math.Max(unit_price - best_discount, 0) * 100) / 100
+	return price, best
+}
+
+// Sets the coupon price of the stocks from the best combination of active coupons
+func (s Service) ApplyCouponPrices(ctx context.Context, stocks []*gmodel.Stock) error {
+	product_ids := []int64{}
+	store_ids := []postgres.Expression{postgres.Int(0)}
+	for _, stock := range stocks {
+		if stock == nil || stock.LatestPrice == nil {
+			continue
+		}
+		if !slices.Contains(product_ids, stock.ProductID) {
+			product_ids = append(product_ids, stock.ProductID)
+		}
+		store_ids = append(store_ids, postgres.Int(stock.StoreID))
+	}
+	if len(product_ids) == 0 {
+		return nil
+	}
+
+	products, err := s.couponProducts(ctx, product_ids)
+	if err != nil {
+		return err
+	}
+	brands := []string{}
+	category_ids := []int64{}
+	for _, product := range products {
+		brands = append(brands, product.Brand)
+		category_ids = append(category_ids, couponCategoryIds(product)...)
+	}
+	qb := table.Coupon.
+		SELECT(table.Coupon.AllColumns).
+		FROM(table.Coupon).
+		WHERE(postgres.AND(
+			activeCouponClause(),
+			postgres.OR(table.Coupon.StoreID.IS_NULL(), table.Coupon.StoreID.IN(store_ids...)),
+			couponScopeClause(product_ids, brands, category_ids),
+		))
+	var coupons []gmodel.Coupon
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &coupons); err != nil {
+		return err
+	}
+	if len(coupons) == 0 {
+		return nil
+	}
+
+	for _, stock := range stocks {
+		if stock == nil || stock.LatestPrice == nil {
+			continue
+		}
+		i := slices.IndexFunc(products, func(p gmodel.Product) bool {
+			return p.ID == stock.ProductID
+		})
+		if i < 0 {
+			continue
+		}
+		applicable := []gmodel.Coupon{}
+		for _, coupon := range coupons {
+			if IsCouponApplicable(coupon, products[i], stock.StoreID) {
+				applicable = append(applicable, coupon)
+			}
+		}
+		price, applied := BestCouponCombination(PriceEffectiveAmount(*stock.LatestPrice), applicable)
+		if len(applied) == 0 {
+			continue
+		}
+		stock.CouponPrice = &price
+		stock.AppliedCoupons = applied
+	}
+	return nil
+}
+
+// Prices the viewer would pay. Member prices are applied before coupons
+func (s Service) ApplyViewerPrices(ctx context.Context, user gmodel.User, stocks []*gmodel.Stock) error {
+	if err := s.ApplyMemberPrices(ctx, user, stocks); err != nil {
+		return err
+	}
+	return s.ApplyCouponPrices(ctx, stocks)
+}
@@ -8,14 +8,39 @@ import (
 	"github.com/pricetra/api/graph/gmodel"
 )
 
-func (s Service) IsAuthenticatedDirective(ctx context.Context, obj any, next graphql.Resolver, role *gmodel.UserRole) (res any, err error) {
+func (s Service) IsAuthenticatedDirective(
+	ctx context.Context,
+	obj any,
+	next graphql.Resolver,
+	role *gmodel.UserRole,
+	store_role *gmodel.StoreRole,
+) (res any, err error) {
 	user := s.GetAuthUserFromContext(ctx)
-	if user == (gmodel.User{}) {
+	if user.ID == 0 {
 		return nil, fmt.Errorf("unauthorized")
 	}
 
 	if role != nil && !s.IsRoleAuthorized(*role, user.Role) {
-		return nil, fmt.Errorf("insufficient permissions")
+		if store_role == nil {
+			return nil, fmt.Errorf("insufficient permissions")
+		}
+		// Fallback to store scoped membership
+		store_id, err := s.StoreIdFromFieldArgs(ctx)
+		if err != nil || !s.HasStoreRole(ctx, user, store_id, *store_role) {
+			return nil, fmt.Errorf("insufficient permissions")
+		}
+		return next(ctx)
+	}
+	if role == nil && store_role != nil {
+		store_id, err := s.StoreIdFromFieldArgs(ctx)
+		if err != nil || !s.HasStoreRole(ctx, user, store_id, *store_role) {
+			return nil, fmt.Errorf("insufficient permissions")
+		}
+	}
+	if role != nil && s.IsTwoFactorRequired(user) {
+		if !user.TwoFactorEnabled || user.AuthTwoFactorVerifiedAt == nil {
+			return nil, fmt.Errorf("two-factor authentication is required for this role")
+		}
 	}
 	return next(ctx)
 }
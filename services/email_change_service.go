@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/thanhpk/randstr"
+)
+
+const EMAIL_CHANGE_CODE_TTL = 10 * time.Minute
+const EMAIL_CHANGE_MAX_TRIES = 5
+
+// Creates a pending email change entry for the user. Any previous pending change is discarded.
+// The returned verification code should be sent to the new email address
+func (s Service) RequestEmailChange(ctx context.Context, user gmodel.User, new_email string) (email_verification model.EmailVerification, err error) {
+	new_email = strings.ToLower(strings.TrimSpace(new_email))
+	if s.StructValidator != nil {
+		if err := s.StructValidator.Var(new_email, "required,email"); err != nil {
+			return model.EmailVerification{}, fmt.Errorf("invalid email address")
+		}
+	}
+	if strings.EqualFold(new_email, user.Email) {
+		return model.EmailVerification{}, fmt.Errorf("new email must be different from the current email")
+	}
+	if s.UserEmailExists(ctx, new_email) {
+		return model.EmailVerification{}, fmt.Errorf("email already exists")
+	}
+
+	if s.TX, err = s.DB.BeginTx(ctx, nil); err != nil {
+		return model.EmailVerification{}, err
+	}
+	defer s.TX.Rollback()
+
+	_, err = table.EmailVerification.
+		DELETE().
+		WHERE(postgres.AND(
+			table.EmailVerification.UserID.EQ(postgres.Int(user.ID)),
+			table.EmailVerification.NewEmail.IS_NOT_NULL(),
+		)).
+		ExecContext(ctx, s.TX)
+	if err != nil {
+		return model.EmailVerification{}, fmt.Errorf("could not remove pending email change")
+	}
+
+	qb := table.EmailVerification.
+		INSERT(
+			table.EmailVerification.UserID,
+			table.EmailVerification.Code,
+			table.EmailVerification.NewEmail,
+		).
+		MODEL(model.EmailVerification{
+			UserID: user.ID,
+			Code: randstr.Dec(EMAIL_VERIFICATION_CODE_LEN),
+			NewEmail: &new_email,
+		}).
+		RETURNING(table.EmailVerification.AllColumns)
+	if err = qb.QueryContext(ctx, s.TX, &email_verification); err != nil {
+		return model.EmailVerification{}, fmt.Errorf("could not create email change entry")
+	}
+	if err = s.TX.Commit(); err != nil {
+		return model.EmailVerification{}, fmt.Errorf("could not commit changes")
+	}
+	return email_verification, nil
+}
+
+// Applies a pending email change. All other sessions are logged out and a new
+// token is issued for the current session since the JWT email claim is no longer valid
+func (s Service) ConfirmEmailChange(ctx context.Context, user gmodel.User, code string) (auth gmodel.Auth, old_email string, err error) {
+	if user.AuthStateID == nil {
+		return gmodel.Auth{}, "", fmt.Errorf("invalid auth state")
+	}
+	auth_state_uuid, err := uuid.Parse(*user.AuthStateID)
+	if err != nil {
+		return gmodel.Auth{}, "", fmt.Errorf("invalid auth state")
+	}
+
+	// Every confirmation attempt counts towards the tries limit, including the successful one
+	var email_verification model.EmailVerification
+	pending_change := postgres.AND(
+		table.EmailVerification.UserID.EQ(postgres.Int(user.ID)),
+		table.EmailVerification.NewEmail.IS_NOT_NULL(),
+	)
+	tries_qb := table.EmailVerification.
+		UPDATE(table.EmailVerification.Tries).
+		SET(table.EmailVerification.Tries.SET(table.EmailVerification.Tries.ADD(postgres.Int(1)))).
+		WHERE(postgres.AND(
+			pending_change,
+			table.EmailVerification.Tries.LT(postgres.Int(EMAIL_CHANGE_MAX_TRIES)),
+		)).
+		RETURNING(table.EmailVerification.AllColumns)
+	if err = tries_qb.QueryContext(ctx, s.DB, &email_verification); err != nil {
+		res, err := table.EmailVerification.
+			DELETE().
+			WHERE(pending_change).
+			ExecContext(ctx, s.DB)
+		if err != nil {
+			return gmodel.Auth{}, "", fmt.Errorf("something went wrong during delete action")
+		}
+		if deleted, _ := res.RowsAffected(); deleted == 0 {
+			return gmodel.Auth{}, "", fmt.Errorf("invalid email verification code")
+		}
+		return gmodel.Auth{}, "", fmt.Errorf("maximum number of tries reached for verification")
+	}
+	if email_verification.Code != strings.TrimSpace(code) {
+		return gmodel.Auth{}, "", fmt.Errorf("invalid email verification code")
+	}
+
+	delete_qb := table.EmailVerification.
+		DELETE().
+		WHERE(table.EmailVerification.ID.EQ(postgres.Int(email_verification.ID)))
+	if time.Since(email_verification.CreatedAt) > EMAIL_CHANGE_CODE_TTL {
+		delete_qb.ExecContext(ctx, s.DB)
+		return gmodel.Auth{}, "", fmt.Errorf("verification code has expired")
+	}
+	new_email := *email_verification.NewEmail
+	if s.UserEmailExists(ctx, new_email) {
+		delete_qb.ExecContext(ctx, s.DB)
+		return gmodel.Auth{}, "", fmt.Errorf("email already exists")
+	}
+
+	if s.TX, err = s.DB.BeginTx(ctx, nil); err != nil {
+		return gmodel.Auth{}, "", err
+	}
+	defer s.TX.Rollback()
+
+	// Changing the email also verifies it
+	update_qb := table.User.
+		UPDATE(table.User.Email, table.User.Active, table.User.UpdatedAt).
+		MODEL(model.User{
+			Email: new_email,
+			Active: true,
+			UpdatedAt: time.Now(),
+		}).
+		WHERE(table.User.ID.EQ(postgres.Int(user.ID)))
+	if _, err = update_qb.ExecContext(ctx, s.TX); err != nil {
+		return gmodel.Auth{}, "", fmt.Errorf("could not update email")
+	}
+	if _, err = delete_qb.ExecContext(ctx, s.TX); err != nil {
+		return gmodel.Auth{}, "", fmt.Errorf("could not delete email verification entry")
+	}
+	if err = s.LogoutOtherSessions(ctx, user.ID, auth_state_uuid); err != nil {
+		return gmodel.Auth{}, "", fmt.Errorf("could not logout other sessions")
+	}
+	if err = s.TX.Commit(); err != nil {
+		return gmodel.Auth{}, "", fmt.Errorf("could not commit changes")
+	}
+	s.TX = nil
+
+	updated_user, err := s.FindAuthUserById(ctx, user.ID, *user.AuthStateID)
+	if err != nil {
+		return gmodel.Auth{}, "", fmt.Errorf("internal error")
+	}
+	jwt, err := s.GenerateJWT(s.Tokens.JwtKey, &updated_user)
+	if err != nil {
+		return gmodel.Auth{}, "", fmt.Errorf("could not generate JWT")
+	}
+	return gmodel.Auth{
+		Token: jwt,
+		User: &updated_user,
+	}, user.Email, nil
+}
+
+// Deletes all auth_state entries for the user except for `auth_state_id`
+func (s Service) LogoutOtherSessions(ctx context.Context, user_id int64, auth_state_id uuid.UUID) error {
+	qb := table.AuthState.
+		DELETE().
+		WHERE(postgres.AND(
+			table.AuthState.UserID.EQ(postgres.Int(user_id)),
+			table.AuthState.ID.NOT_EQ(postgres.UUID(auth_state_id)),
+		))
+	_, err := qb.ExecContext(ctx, s.DbOrTxExecutable())
+	return err
+}
@@ -74,10 +74,10 @@ func (s Service) SendPasswordResetCode(
 	}
 	var avatar_url string
 	if user.Avatar != nil {
-		avatar_url = CLOUDINARY_UPLOAD_BASE + "/" + *user.Avatar
+		avatar_url = s.ImageUrl(*user.Avatar)
 	} else {
 		no_profile_id := "f89a1553-b74e-426c-a82a-359787168a53"
-		avatar_url = CLOUDINARY_UPLOAD_BASE + "/" + no_profile_id
+		avatar_url = s.ImageUrl(no_profile_id)
 	}
 	res, err := client.SendPasswordResetCodeWithResponse(ctx, oapi.PasswordResetRequest{
 		RecipientEmail: user.Email,
@@ -87,3 +87,21 @@ func (s Service) SendPasswordResetCode(
 	})
 	return res, err
 }
+
+// Notifies the previous email address that the account email has changed
+func (s Service) SendEmailChangeNotification(
+	ctx context.Context,
+	user gmodel.User,
+	old_email string,
+) (*oapi.SendEmailChangeNotificationResponse, error) {
+	client, err := s.NewEmailClient()
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.SendEmailChangeNotificationWithResponse(ctx, oapi.EmailChangeNotificationRequest{
+		RecipientEmail: old_email,
+		Name: user.Name,
+		NewEmail: user.Email,
+	})
+	return res, err
+}
@@ -2,54 +2,274 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
 
 	"github.com/99designs/gqlgen/graphql"
-	"github.com/cloudinary/cloudinary-go/v2/api/admin"
-	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/utils"
 )
 
 const CLOUDINARY_UPLOAD_BASE string = "https://res.cloudinary.com/pricetra-cdn/image/upload"
 
+// Max size of remote images downloaded by stores that can't fetch URLs themselves
+const MAX_REMOTE_IMAGE_BYTES = 20 << 20
+
+// Max hamming distance between perceptual hashes of two images considered duplicates
+const IMAGE_DUPLICATE_MAX_DISTANCE = 4
+
+type ImageUploadParams struct {
+	PublicID string
+	Tags []string
+	// Also uploads a scaled down copy under `ImageThumbnailId(PublicID)`.
+	// Only applies to uploaded files and base64 images
+	Thumbnail bool
+	// Records the perceptual hash of the image under this scope
+	HashScope string
+	// Rejects images perceptually identical to another image of `HashScope`
+	RejectDuplicates bool
+}
+
+type ImageUploadResult struct {
+	PublicID string
+	// Changes with every upload of the same public id. Used by `ImageStore.VersionUrl`
+	Version int
+	URL string
+}
+
+// Storage backend for uploaded images. Images are addressed by their public id
+// and uploading to an existing public id replaces the image
+type ImageStore interface {
+	UploadUrl(ctx context.Context, image_url string, params ImageUploadParams) (*ImageUploadResult, error)
+	UploadBytes(ctx context.Context, data []byte, params ImageUploadParams) (*ImageUploadResult, error)
+	UploadStream(ctx context.Context, stream io.Reader, params ImageUploadParams) (*ImageUploadResult, error)
+	Delete(ctx context.Context, public_id string) error
+	// Public URL of the latest version of the image
+	Url(public_id string) string
+	// Public URL of a previous version of the image
+	VersionUrl(public_id string, version string) string
+}
+
+// Downloads a remote image
+func fetchImageUrl(ctx context.Context, image_url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, image_url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not download image. status %d", res.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(res.Body, MAX_REMOTE_IMAGE_BYTES + 1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MAX_REMOTE_IMAGE_BYTES {
+		return nil, fmt.Errorf("image is too large")
+	}
+	return data, nil
+}
+
+func ImageThumbnailId(public_id string) string {
+	return public_id + "_thumbnail"
+}
+
+// Reads an uploaded file from the start. Uploads are seekable so they can be read more than once
+func ReadImageUpload(image graphql.Upload) ([]byte, error) {
+	if _, err := image.File.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(image.File, utils.MAX_IMAGE_BYTES + 1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > utils.MAX_IMAGE_BYTES {
+		return nil, fmt.Errorf("image must be smaller than %d MB", utils.MAX_IMAGE_BYTES >> 20)
+	}
+	return data, nil
+}
+
+func (s Service) ImageUrl(public_id string) string {
+	return s.ImageStore.Url(public_id)
+}
+
+func (s Service) ImageThumbnailUrl(public_id string) string {
+	return s.ImageStore.Url(ImageThumbnailId(public_id))
+}
+
+// Validates, strips and resizes an uploaded file or base64 image.
+// Returns nil if neither is provided
+func (s Service) PrepareImage(
+	image_file *graphql.Upload,
+	base64_image *string,
+	thumbnail bool,
+) (*utils.ProcessedImage, error) {
+	var data []byte
+	var err error
+	if image_file != nil {
+		data, err = ReadImageUpload(*image_file)
+	} else if base64_image != nil {
+		data, err = utils.DecodeBase64Image(*base64_image)
+	} else {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	image, err := utils.ProcessImage(data, thumbnail)
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+// Finds an image of the scope perceptually identical to `hash`. Images with `exclude_public_id` are ignored
+func (s Service) FindDuplicateImage(
+	ctx context.Context,
+	scope string,
+	hash string,
+	exclude_public_id string,
+) (*model.ImageHash, error) {
+	qb := table.ImageHash.
+		SELECT(table.ImageHash.AllColumns).
+		FROM(table.ImageHash).
+		WHERE(postgres.AND(
+			table.ImageHash.Scope.EQ(postgres.String(scope)),
+			table.ImageHash.PublicID.NOT_EQ(postgres.String(exclude_public_id)),
+		))
+	var hashes []model.ImageHash
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &hashes); err != nil {
+		return nil, err
+	}
+	for _, h := range hashes {
+		distance, err := utils.PerceptualHashDistance(h.Hash, hash)
+		if err != nil {
+			continue
+		}
+		if distance <= IMAGE_DUPLICATE_MAX_DISTANCE {
+			return &h, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s Service) saveImageHash(ctx context.Context, image utils.ProcessedImage, params ImageUploadParams) error {
+	qb := table.ImageHash.
+		INSERT(
+			table.ImageHash.PublicID,
+			table.ImageHash.Scope,
+			table.ImageHash.Hash,
+			table.ImageHash.Width,
+			table.ImageHash.Height,
+		).
+		MODEL(model.ImageHash{
+			PublicID: params.PublicID,
+			Scope: params.HashScope,
+			Hash: image.Hash,
+			Width: int32(image.Width),
+			Height: int32(image.Height),
+		}).
+		ON_CONFLICT(table.ImageHash.PublicID).
+		DO_UPDATE(postgres.SET(
+			table.ImageHash.Scope.SET(table.ImageHash.EXCLUDED.Scope),
+			table.ImageHash.Hash.SET(table.ImageHash.EXCLUDED.Hash),
+			table.ImageHash.Width.SET(table.ImageHash.EXCLUDED.Width),
+			table.ImageHash.Height.SET(table.ImageHash.EXCLUDED.Height),
+			table.ImageHash.CreatedAt.SET(postgres.NOW()),
+		))
+	_, err := qb.ExecContext(ctx, s.DbOrTxExecutable())
+	return err
+}
+
+// Uploads an image processed by `PrepareImage` along with its thumbnail
+func (s Service) ProcessedImageUpload(
+	ctx context.Context,
+	image utils.ProcessedImage,
+	params ImageUploadParams,
+) (*ImageUploadResult, error) {
+	if params.RejectDuplicates && params.HashScope != "" {
+		duplicate, err := s.FindDuplicateImage(ctx, params.HashScope, image.Hash, params.PublicID)
+		if err != nil {
+			return nil, err
+		}
+		if duplicate != nil {
+			return nil, fmt.Errorf("image is a duplicate of an existing image")
+		}
+	}
+
+	result, err := s.ImageStore.UploadBytes(ctx, image.Data, params)
+	if err != nil {
+		return nil, err
+	}
+	if params.Thumbnail && len(image.Thumbnail) > 0 {
+		thumbnail_params := ImageUploadParams{
+			PublicID: ImageThumbnailId(params.PublicID),
+			Tags: append(slices.Clone(params.Tags), "THUMBNAIL"),
+		}
+		if _, err := s.ImageStore.UploadBytes(ctx, image.Thumbnail, thumbnail_params); err != nil {
+			return nil, err
+		}
+	}
+	if params.HashScope != "" {
+		if err := s.saveImageHash(ctx, image, params); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
 func (s Service) ImageUrlUpload(
 	ctx context.Context,
 	image_url string,
-	params uploader.UploadParams,
-) (*uploader.UploadResult, error) {
-	return s.Cloudinary.Upload.Upload(ctx, image_url, params)
+	params ImageUploadParams,
+) (*ImageUploadResult, error) {
+	return s.ImageStore.UploadUrl(ctx, image_url, params)
 }
 
 func (s Service) Base64ImageUpload(
 	ctx context.Context,
 	base64_image string,
-	params uploader.UploadParams,
-) (*uploader.UploadResult, error) {
-	return s.Cloudinary.Upload.Upload(ctx, base64_image, params)
+	params ImageUploadParams,
+) (*ImageUploadResult, error) {
+	image, err := s.PrepareImage(nil, &base64_image, params.Thumbnail)
+	if err != nil {
+		return nil, err
+	}
+	return s.ProcessedImageUpload(ctx, *image, params)
 }
 
 func (s Service) GraphImageUpload(
 	ctx context.Context,
 	image graphql.Upload,
-	params uploader.UploadParams,
-) (*uploader.UploadResult, error) {
-	return s.Cloudinary.Upload.Upload(ctx, image.File, params)
+	params ImageUploadParams,
+) (*ImageUploadResult, error) {
+	processed, err := s.PrepareImage(&image, nil, params.Thumbnail)
+	if err != nil {
+		return nil, err
+	}
+	return s.ProcessedImageUpload(ctx, *processed, params)
 }
 
 func (s Service) DeleteImageUpload(
 	ctx context.Context,
 	upload_id string,
-) (*uploader.DestroyResult, error) {
-	invalidate := true
-	return s.Cloudinary.Upload.Destroy(ctx, uploader.DestroyParams{
-		PublicID: upload_id,
-		Invalidate: &invalidate,
-	})
-}
-
-func (s Service) GetImageUpload(
-	ctx context.Context,
-	upload_id string,
-) (*admin.AssetResult, error) {
-	return s.Cloudinary.Admin.AssetByAssetID(ctx, admin.AssetByAssetIDParams{
-		AssetID: upload_id,
-	})
+) error {
+	if err := s.ImageStore.Delete(ctx, upload_id); err != nil {
+		return err
+	}
+	// thumbnails are optional so a missing one isn't an error
+	s.ImageStore.Delete(ctx, ImageThumbnailId(upload_id))
+	_, err := table.ImageHash.
+		DELETE().
+		WHERE(table.ImageHash.PublicID.EQ(postgres.String(upload_id))).
+		ExecContext(ctx, s.DbOrTxExecutable())
+	return err
 }
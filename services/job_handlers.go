@@ -0,0 +1,321 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+const (
+	JOB_PRODUCT_BILLING = "product_billing"
+	JOB_SEARCH_HISTORY = "search_history"
+	JOB_PRODUCT_VIEW = "product_view"
+	JOB_PRICE_CHANGE_NOTIFICATION = "price_change_notification"
+	JOB_PRICE_EVIDENCE_CHECK = "price_evidence_check"
+	JOB_EMAIL_CHANGE_NOTIFICATION = "email_change_notification"
+	JOB_AI_RESPONSE_ENTRY = "ai_response_entry"
+	JOB_REFRESH_USER_REPUTATION = "refresh_user_reputation"
+	JOB_REFRESH_ALL_USER_REPUTATIONS = "refresh_all_user_reputations"
+	JOB_DELETE_SCHEDULED_ACCOUNTS = "delete_scheduled_accounts"
+	JOB_EXPIRE_SALE_PRICES = "expire_sale_prices"
+	JOB_BACKFILL_PRICE_DEALS = "backfill_price_deals"
+	JOB_MIGRATE_PRODUCT_IMAGES_TO_CDN = "migrate_product_images_to_cdn"
+)
+
+var job_handlers = map[string]JobHandler{
+	JOB_PRODUCT_BILLING: Service.runProductBillingJob,
+	JOB_SEARCH_HISTORY: Service.runSearchHistoryJob,
+	JOB_PRODUCT_VIEW: Service.runProductViewJob,
+	JOB_PRICE_CHANGE_NOTIFICATION: Service.runPriceChangeNotificationJob,
+	JOB_PRICE_EVIDENCE_CHECK: Service.runPriceEvidenceCheckJob,
+	JOB_EMAIL_CHANGE_NOTIFICATION: Service.runEmailChangeNotificationJob,
+	JOB_AI_RESPONSE_ENTRY: Service.runAiResponseEntryJob,
+	JOB_REFRESH_USER_REPUTATION: Service.runRefreshUserReputationJob,
+	JOB_REFRESH_ALL_USER_REPUTATIONS: Service.runRefreshAllUserReputationsJob,
+	JOB_DELETE_SCHEDULED_ACCOUNTS: Service.runDeleteScheduledAccountsJob,
+	JOB_EXPIRE_SALE_PRICES: Service.runExpireSalePricesJob,
+	JOB_BACKFILL_PRICE_DEALS: Service.runBackfillPriceDealsJob,
+	JOB_MIGRATE_PRODUCT_IMAGES_TO_CDN: Service.runMigrateProductImagesToCdnJob,
+}
+
+type ProductBillingJob struct {
+	UserID int64 `json:"userId"`
+	BillingType model.ProductBillingType `json:"billingType"`
+	ProductID int64 `json:"productId"`
+	BranchID *int64 `json:"branchId,omitempty"`
+	NewData json.RawMessage `json:"newData,omitempty"`
+	OldData json.RawMessage `json:"oldData,omitempty"`
+}
+
+type SearchHistoryJob struct {
+	SearchTerm string `json:"searchTerm"`
+	UserID *int64 `json:"userId,omitempty"`
+}
+
+type ProductViewJob struct {
+	ProductID int64 `json:"productId"`
+	ViewerTrail ViewerTrailFull `json:"viewerTrail"`
+}
+
+type PriceChangeNotificationJob struct {
+	PriceID int64 `json:"priceId"`
+	OldPriceID *int64 `json:"oldPriceId,omitempty"`
+	// The reporter isn't notified of their own price
+	ReporterID int64 `json:"reporterId"`
+}
+
+type PriceEvidenceCheckJob struct {
+	PriceID int64 `json:"priceId"`
+}
+
+type EmailChangeNotificationJob struct {
+	UserID int64 `json:"userId"`
+	OldEmail string `json:"oldEmail"`
+}
+
+type AiResponseEntryJob struct {
+	UserID int64 `json:"userId"`
+	PromptType model.AiPromptType `json:"promptType"`
+	Request ChatCompletionRequest `json:"request"`
+	Response ChatCompletionResponse `json:"response"`
+}
+
+type RefreshUserReputationJob struct {
+	UserID int64 `json:"userId"`
+}
+
+// Queues the billing of a product contribution. `new_data` and `old_data` are stored as JSON
+func (s Service) EnqueueProductBilling(
+	ctx context.Context,
+	user gmodel.User,
+	billing_type model.ProductBillingType,
+	product gmodel.Product,
+	branch_id *int64,
+	new_data any,
+	old_data any,
+) (gmodel.Job, error) {
+	payload := ProductBillingJob{
+		UserID: user.ID,
+		BillingType: billing_type,
+		ProductID: product.ID,
+		BranchID: branch_id,
+	}
+	var err error
+	if payload.NewData, err = json.Marshal(new_data); err != nil {
+		return gmodel.Job{}, fmt.Errorf("could not encode billing data: %w", err)
+	}
+	if old_data != nil {
+		if payload.OldData, err = json.Marshal(old_data); err != nil {
+			return gmodel.Job{}, fmt.Errorf("could not encode billing data: %w", err)
+		}
+	}
+	return s.EnqueueJob(ctx, JOB_PRODUCT_BILLING, payload, EnqueueJobOptions{})
+}
+
+func (s Service) runProductBillingJob(ctx context.Context, job gmodel.Job) error {
+	payload, err := JobPayload[ProductBillingJob](job)
+	if err != nil {
+		return err
+	}
+	user, err := s.FindUserById(ctx, payload.UserID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+	product, err := s.FindProductById(ctx, payload.ProductID)
+	if err != nil {
+		return fmt.Errorf("product not found: %w", err)
+	}
+	var old_data any
+	if len(payload.OldData) > 0 {
+		old_data = payload.OldData
+	}
+	_, err = s.CreateProductBilling(ctx, user, payload.BillingType, product, payload.BranchID, payload.NewData, old_data)
+	return err
+}
+
+func (s Service) runSearchHistoryJob(ctx context.Context, job gmodel.Job) error {
+	payload, err := JobPayload[SearchHistoryJob](job)
+	if err != nil {
+		return err
+	}
+	var user *gmodel.User
+	if payload.UserID != nil {
+		user = &gmodel.User{ ID: *payload.UserID }
+	}
+	_, err = s.CreateSearchHistoryEntry(ctx, payload.SearchTerm, user)
+	return err
+}
+
+func (s Service) runProductViewJob(ctx context.Context, job gmodel.Job) error {
+	payload, err := JobPayload[ProductViewJob](job)
+	if err != nil {
+		return err
+	}
+	_, err = s.AddProductViewer(ctx, payload.ProductID, payload.ViewerTrail)
+	return err
+}
+
+func (s Service) runPriceChangeNotificationJob(ctx context.Context, job gmodel.Job) error {
+	payload, err := JobPayload[PriceChangeNotificationJob](job)
+	if err != nil {
+		return err
+	}
+	if s.ExpoPushClient == nil {
+		return nil
+	}
+	price, err := s.FindPriceById(ctx, payload.PriceID)
+	if err != nil {
+		return fmt.Errorf("price not found: %w", err)
+	}
+	var old_price gmodel.Price
+	if payload.OldPriceID != nil {
+		if old_price, err = s.FindPriceById(ctx, *payload.OldPriceID); err != nil {
+			return fmt.Errorf("old price not found: %w", err)
+		}
+	}
+	users, err := s.StockWatchers(ctx, price.ProductID, price.StockID, payload.ReporterID)
+	if err != nil {
+		return err
+	}
+	_, err = s.SendPriceChangePushNotifications(ctx, users, price, old_price)
+	return err
+}
+
+func (s Service) runPriceEvidenceCheckJob(ctx context.Context, job gmodel.Job) error {
+	payload, err := JobPayload[PriceEvidenceCheckJob](job)
+	if err != nil {
+		return err
+	}
+	price, err := s.FindPriceById(ctx, payload.PriceID)
+	if err != nil {
+		return fmt.Errorf("price not found: %w", err)
+	}
+	if price.ImageURL == nil {
+		return nil
+	}
+	image, err := fetchImageUrl(ctx, *price.ImageURL)
+	if err != nil {
+		return err
+	}
+	return s.CheckPriceEvidence(ctx, price, image)
+}
+
+func (s Service) runEmailChangeNotificationJob(ctx context.Context, job gmodel.Job) error {
+	payload, err := JobPayload[EmailChangeNotificationJob](job)
+	if err != nil {
+		return err
+	}
+	user, err := s.FindUserById(ctx, payload.UserID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+	res, err := s.SendEmailChangeNotification(ctx, user, payload.OldEmail)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode() >= 300 {
+		return fmt.Errorf("email server responded with status %d", res.StatusCode())
+	}
+	return nil
+}
+
+func (s Service) runAiResponseEntryJob(ctx context.Context, job gmodel.Job) error {
+	payload, err := JobPayload[AiResponseEntryJob](job)
+	if err != nil {
+		return err
+	}
+	user, err := s.FindUserById(ctx, payload.UserID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+	_, err = s.CreateAiResponseEntry(ctx, user, payload.Request, payload.Response, payload.PromptType)
+	return err
+}
+
+// Queues a reputation refresh. Refreshes of the same user are merged while one is queued
+func (s Service) EnqueueUserReputationRefresh(ctx context.Context, user_id int64) {
+	unique_key := fmt.Sprintf("%s:%d", JOB_REFRESH_USER_REPUTATION, user_id)
+	s.EnqueueJobOrLog(ctx, JOB_REFRESH_USER_REPUTATION, RefreshUserReputationJob{ UserID: user_id }, EnqueueJobOptions{
+		UniqueKey: &unique_key,
+	})
+}
+
+func (s Service) runRefreshUserReputationJob(ctx context.Context, job gmodel.Job) error {
+	payload, err := JobPayload[RefreshUserReputationJob](job)
+	if err != nil {
+		return err
+	}
+	_, err = s.RefreshUserReputation(ctx, payload.UserID)
+	return err
+}
+
+func (s Service) runRefreshAllUserReputationsJob(ctx context.Context, job gmodel.Job) error {
+	refreshed, err := s.RefreshAllUserReputations(ctx)
+	if err != nil {
+		return err
+	}
+	log.Printf("refreshed reputation for %d user(s)\n", refreshed)
+	return nil
+}
+
+func (s Service) runDeleteScheduledAccountsJob(ctx context.Context, job gmodel.Job) error {
+	deleted, err := s.DeleteScheduledAccounts(ctx)
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		log.Printf("deleted %d scheduled account(s)\n", deleted)
+	}
+	return nil
+}
+
+func (s Service) runExpireSalePricesJob(ctx context.Context, job gmodel.Job) error {
+	transitions, err := s.ExpireSalePrices(ctx)
+	if len(transitions) > 0 {
+		log.Printf("expired sale prices of %d stock(s)\n", len(transitions))
+	}
+	return err
+}
+
+func (s Service) runBackfillPriceDealsJob(ctx context.Context, job gmodel.Job) error {
+	updated, err := s.BackfillPriceDeals(ctx)
+	if updated > 0 {
+		log.Printf("backfilled deals of %d price(s)\n", updated)
+	}
+	return err
+}
+
+// Uploads remote product images to the image store. Failed uploads are skipped
+func (s Service) runMigrateProductImagesToCdnJob(ctx context.Context, job gmodel.Job) error {
+	qb := table.Product.
+		SELECT(table.Product.Image.AS("image"), table.Product.Code.AS("code")).
+		FROM(table.Product).
+		WHERE(table.Product.Image.NOT_EQ(postgres.String("")))
+	var res []struct{
+		Image string
+		Code string
+	}
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &res); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, ob := range res {
+		_, err := s.ImageUrlUpload(ctx, ob.Image, ImageUploadParams{
+			PublicID: ob.Code,
+			Tags: []string{"PRODUCT"},
+		})
+		if err != nil {
+			log.Printf("could not migrate image of product %s. %s\n", ob.Code, err.Error())
+			failed++
+		}
+	}
+	log.Printf("migrated %d product image(s)\n", len(res) - failed)
+	return nil
+}
@@ -0,0 +1,529 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"runtime/debug"
+	"time"
+
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/lib/pq"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/utils"
+)
+
+const (
+	JOB_DEFAULT_MAX_ATTEMPTS = 5
+	// Running jobs locked for longer than this are assumed to be lost (e.g. the server was killed)
+	JOB_LOCK_TIMEOUT = 30 * time.Minute
+	JOB_RETRY_BASE_DELAY = 30 * time.Second
+	JOB_RETRY_MAX_DELAY = 6 * time.Hour
+	PG_UNIQUE_VIOLATION = "23505"
+)
+
+// Runs a job. Returned errors are retried until the job runs out of attempts
+type JobHandler func(s Service, ctx context.Context, job gmodel.Job) error
+
+type EnqueueJobOptions struct {
+	RunAt *time.Time
+	// Jobs with a key are not queued if a pending or running job has the same key
+	UniqueKey *string
+	MaxAttempts int
+	ScheduleID *int64
+}
+
+func RegisterJobHandler(job_type string, handler JobHandler) {
+	job_handlers[job_type] = handler
+}
+
+func IsJobTypeRegistered(job_type string) bool {
+	_, ok := job_handlers[job_type]
+	return ok
+}
+
+// Decodes the job payload into `T`
+func JobPayload[T any](job gmodel.Job) (payload T, err error) {
+	if err = json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+		return payload, fmt.Errorf("invalid job payload: %w", err)
+	}
+	return payload, nil
+}
+
+func (s Service) FindJobById(ctx context.Context, id int64) (job gmodel.Job, err error) {
+	qb := table.Job.
+		SELECT(table.Job.AllColumns).
+		FROM(table.Job).
+		WHERE(table.Job.ID.EQ(postgres.Int(id))).
+		LIMIT(1)
+	err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &job)
+	return job, err
+}
+
+func (s Service) findActiveJobByUniqueKey(ctx context.Context, unique_key string) (job gmodel.Job, err error) {
+	qb := table.Job.
+		SELECT(table.Job.AllColumns).
+		FROM(table.Job).
+		WHERE(postgres.AND(
+			table.Job.UniqueKey.EQ(postgres.String(unique_key)),
+			jobActiveStatus(),
+		)).
+		LIMIT(1)
+	err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &job)
+	return job, err
+}
+
+// Must match the predicate of the `job_unique_key_idx` index
+func jobActiveStatus() postgres.BoolExpression {
+	return table.Job.Status.IN(
+		postgres.NewEnumValue(model.JobStatus_Pending.String()),
+		postgres.NewEnumValue(model.JobStatus_Running.String()),
+	)
+}
+
+// Queues a job with a JSON encoded payload. If a pending or running job has the
+// same unique key the existing job is returned instead
+func (s Service) EnqueueJob(ctx context.Context, job_type string, payload any, opts EnqueueJobOptions) (job gmodel.Job, err error) {
+	if !IsJobTypeRegistered(job_type) {
+		return gmodel.Job{}, fmt.Errorf("unknown job type %s", job_type)
+	}
+	payload_json := "{}"
+	switch p := payload.(type) {
+	case nil:
+	case string:
+		payload_json = p
+	default:
+		data, err := json.Marshal(p)
+		if err != nil {
+			return gmodel.Job{}, fmt.Errorf("could not encode job payload: %w", err)
+		}
+		payload_json = string(data)
+	}
+	if !json.Valid([]byte(payload_json)) {
+		return gmodel.Job{}, fmt.Errorf("job payload must be valid json")
+	}
+	run_at := time.Now()
+	if opts.RunAt != nil {
+		run_at = *opts.RunAt
+	}
+	max_attempts := opts.MaxAttempts
+	if max_attempts <= 0 {
+		max_attempts = JOB_DEFAULT_MAX_ATTEMPTS
+	}
+
+	qb := table.Job.
+		INSERT(
+			table.Job.Type,
+			table.Job.Payload,
+			table.Job.UniqueKey,
+			table.Job.ScheduleID,
+			table.Job.MaxAttempts,
+			table.Job.RunAt,
+		).
+		MODEL(model.Job{
+			Type: job_type,
+			Payload: payload_json,
+			UniqueKey: opts.UniqueKey,
+			ScheduleID: opts.ScheduleID,
+			MaxAttempts: int32(max_attempts),
+			RunAt: run_at,
+		}).
+		ON_CONFLICT(table.Job.UniqueKey).
+		WHERE(jobActiveStatus()).
+		DO_NOTHING().
+		RETURNING(table.Job.AllColumns)
+	err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &job)
+	if errors.Is(err, qrm.ErrNoRows) && opts.UniqueKey != nil {
+		return s.findActiveJobByUniqueKey(ctx, *opts.UniqueKey)
+	}
+	if err != nil {
+		return gmodel.Job{}, err
+	}
+	return job, nil
+}
+
+func (s Service) EnqueueJobFromInput(ctx context.Context, input gmodel.EnqueueJob) (gmodel.Job, error) {
+	if err := s.StructValidator.StructCtx(ctx, input); err != nil {
+		return gmodel.Job{}, fmt.Errorf("invalid input: %w", err)
+	}
+	var payload any
+	if input.Payload != nil {
+		payload = *input.Payload
+	}
+	opts := EnqueueJobOptions{
+		RunAt: input.RunAt,
+		UniqueKey: input.UniqueKey,
+	}
+	if input.MaxAttempts != nil {
+		opts.MaxAttempts = *input.MaxAttempts
+	}
+	return s.EnqueueJob(ctx, input.Type, payload, opts)
+}
+
+// Enqueues the job and logs failures. Used for side effects of requests that
+// shouldn't fail the request itself
+func (s Service) EnqueueJobOrLog(ctx context.Context, job_type string, payload any, opts EnqueueJobOptions) {
+	if _, err := s.EnqueueJob(ctx, job_type, payload, opts); err != nil {
+		log.Printf("could not enqueue %s job. %s\n", job_type, err.Error())
+	}
+}
+
+// Locks up to `limit` due jobs so other workers skip them
+func (s Service) ClaimJobs(ctx context.Context, limit int) (jobs []gmodel.Job, err error) {
+	due_jobs := table.Job.
+		SELECT(table.Job.ID).
+		FROM(table.Job).
+		WHERE(postgres.AND(
+			table.Job.Status.EQ(postgres.NewEnumValue(model.JobStatus_Pending.String())),
+			table.Job.RunAt.LT_EQ(postgres.TimestampzT(time.Now())),
+		)).
+		ORDER_BY(table.Job.RunAt.ASC()).
+		LIMIT(int64(limit)).
+		FOR(postgres.UPDATE().SKIP_LOCKED())
+	qb := table.Job.
+		UPDATE(
+			table.Job.Status,
+			table.Job.Attempts,
+			table.Job.LockedAt,
+			table.Job.UpdatedAt,
+		).
+		SET(
+			postgres.NewEnumValue(model.JobStatus_Running.String()),
+			table.Job.Attempts.ADD(postgres.Int(1)),
+			postgres.TimestampzT(time.Now()),
+			postgres.TimestampzT(time.Now()),
+		).
+		WHERE(table.Job.ID.IN(due_jobs)).
+		RETURNING(table.Job.AllColumns)
+	if err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// Delay before the next attempt. Doubles with every failed attempt
+func JobRetryDelay(attempts int) time.Duration {
+	delay := float64(JOB_RETRY_BASE_DELAY) * math.Pow(2, float64(attempts - 1))
+	if delay > float64(JOB_RETRY_MAX_DELAY) {
+		return JOB_RETRY_MAX_DELAY
+	}
+	return time.Duration(delay)
+}
+
+// Runs a claimed job and stores the outcome. Failed jobs are rescheduled
+// until they run out of attempts
+func (s Service) RunJob(ctx context.Context, job gmodel.Job) (updated_job gmodel.Job, err error) {
+	job_err := s.runJobHandler(ctx, job)
+
+	now := time.Now()
+	update := model.Job{
+		Status: model.JobStatus_Succeeded,
+		RunAt: job.RunAt,
+		CompletedAt: &now,
+		UpdatedAt: now,
+	}
+	if job_err != nil {
+		error_message := job_err.Error()
+		update.LastError = &error_message
+		update.CompletedAt = nil
+		if job.Attempts < job.MaxAttempts {
+			update.Status = model.JobStatus_Pending
+			update.RunAt = now.Add(JobRetryDelay(job.Attempts))
+		} else {
+			update.Status = model.JobStatus_Failed
+		}
+	}
+	qb := table.Job.
+		UPDATE(
+			table.Job.Status,
+			table.Job.RunAt,
+			table.Job.LockedAt,
+			table.Job.LastError,
+			table.Job.CompletedAt,
+			table.Job.UpdatedAt,
+		).
+		MODEL(update).
+		WHERE(table.Job.ID.EQ(postgres.Int(job.ID))).
+		RETURNING(table.Job.AllColumns)
+	if err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &updated_job); err != nil {
+		return gmodel.Job{}, err
+	}
+	if job_err != nil {
+		log.Printf("job %d (%s) failed on attempt %d/%d. %s\n", job.ID, job.Type, job.Attempts, job.MaxAttempts, job_err.Error())
+	}
+	return updated_job, nil
+}
+
+func (s Service) runJobHandler(ctx context.Context, job gmodel.Job) (err error) {
+	handler, ok := job_handlers[job.Type]
+	if !ok {
+		return fmt.Errorf("unknown job type %s", job.Type)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v\n%s", r, debug.Stack())
+		}
+	}()
+	// handlers get their own copy of the service so they can't share a transaction
+	s.TX = nil
+	return handler(s, ctx, job)
+}
+
+// Claims and runs due jobs one at a time until none are left
+// or `ctx` is cancelled. Returns the number of jobs that were run
+func (s Service) RunPendingJobs(ctx context.Context, batch_size int) (ran int, err error) {
+	for ctx.Err() == nil {
+		jobs, err := s.ClaimJobs(ctx, batch_size)
+		if err != nil {
+			return ran, err
+		}
+		if len(jobs) == 0 {
+			return ran, nil
+		}
+		for _, job := range jobs {
+			// finish claimed jobs even if the worker is stopping so they aren't left running
+			if _, err := s.RunJob(context.WithoutCancel(ctx), job); err != nil {
+				return ran, err
+			}
+			ran++
+		}
+	}
+	return ran, nil
+}
+
+// Puts jobs that have been running for longer than `JOB_LOCK_TIMEOUT` back in the queue
+func (s Service) RecoverStaleJobs(ctx context.Context) (recovered int64, err error) {
+	qb := table.Job.
+		UPDATE(table.Job.Status, table.Job.LockedAt, table.Job.UpdatedAt).
+		SET(
+			postgres.NewEnumValue(model.JobStatus_Pending.String()),
+			postgres.NULL,
+			postgres.TimestampzT(time.Now()),
+		).
+		WHERE(postgres.AND(
+			table.Job.Status.EQ(postgres.NewEnumValue(model.JobStatus_Running.String())),
+			table.Job.LockedAt.LT(postgres.TimestampzT(time.Now().Add(-JOB_LOCK_TIMEOUT))),
+		))
+	res, err := qb.ExecContext(ctx, s.DbOrTxExecutable())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// Queues a failed job again with a fresh set of attempts
+func (s Service) RetryJob(ctx context.Context, id int64) (job gmodel.Job, err error) {
+	job, err = s.FindJobById(ctx, id)
+	if err != nil {
+		return gmodel.Job{}, fmt.Errorf("job not found")
+	}
+	if job.Status != gmodel.JobStatusFailed {
+		return gmodel.Job{}, fmt.Errorf("only failed jobs can be retried")
+	}
+	qb := table.Job.
+		UPDATE(
+			table.Job.Status,
+			table.Job.Attempts,
+			table.Job.RunAt,
+			table.Job.LockedAt,
+			table.Job.CompletedAt,
+			table.Job.UpdatedAt,
+		).
+		MODEL(model.Job{
+			Status: model.JobStatus_Pending,
+			Attempts: 0,
+			RunAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}).
+		WHERE(table.Job.ID.EQ(postgres.Int(id))).
+		RETURNING(table.Job.AllColumns)
+	if err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &job); err != nil {
+		var pq_err *pq.Error
+		if errors.As(err, &pq_err) && pq_err.Code == PG_UNIQUE_VIOLATION {
+			return gmodel.Job{}, fmt.Errorf("a job with the same unique key is already queued")
+		}
+		return gmodel.Job{}, err
+	}
+	return job, nil
+}
+
+func (s Service) PaginatedJobs(
+	ctx context.Context,
+	paginator_input gmodel.PaginatorInput,
+	filters *gmodel.JobFilter,
+) (res gmodel.PaginatedJobs, err error) {
+	where_clause := postgres.Bool(true)
+	if filters != nil {
+		if filters.Status != nil {
+			where_clause = where_clause.AND(table.Job.Status.EQ(postgres.NewEnumValue(filters.Status.String())))
+		}
+		if filters.Type != nil {
+			where_clause = where_clause.AND(table.Job.Type.EQ(postgres.String(*filters.Type)))
+		}
+	}
+	sql_paginator, err := s.Paginate(ctx, paginator_input, table.Job, table.Job.ID, where_clause)
+	if err != nil {
+		return gmodel.PaginatedJobs{
+			Jobs: []*gmodel.Job{},
+			Paginator: &gmodel.Paginator{},
+		}, nil
+	}
+
+	qb := table.Job.
+		SELECT(table.Job.AllColumns).
+		FROM(table.Job).
+		WHERE(where_clause).
+		ORDER_BY(table.Job.CreatedAt.DESC(), table.Job.ID.DESC()).
+		LIMIT(int64(sql_paginator.Limit)).
+		OFFSET(int64(sql_paginator.Offset))
+	if err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &res.Jobs); err != nil {
+		return gmodel.PaginatedJobs{}, err
+	}
+	res.Paginator = &sql_paginator.Paginator
+	return res, nil
+}
+
+func (s Service) JobSchedules(ctx context.Context) (schedules []gmodel.JobSchedule, err error) {
+	qb := table.JobSchedule.
+		SELECT(table.JobSchedule.AllColumns).
+		FROM(table.JobSchedule).
+		ORDER_BY(table.JobSchedule.Name.ASC())
+	if err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &schedules); err != nil {
+		return nil, err
+	}
+	if schedules == nil {
+		schedules = []gmodel.JobSchedule{}
+	}
+	return schedules, nil
+}
+
+// Creates or updates a named schedule. The next run is only moved if the cron expression changed
+func (s Service) UpsertJobSchedule(
+	ctx context.Context,
+	name string,
+	job_type string,
+	cron_expression string,
+	payload any,
+) (schedule gmodel.JobSchedule, err error) {
+	if !IsJobTypeRegistered(job_type) {
+		return gmodel.JobSchedule{}, fmt.Errorf("unknown job type %s", job_type)
+	}
+	cron, err := utils.ParseCron(cron_expression)
+	if err != nil {
+		return gmodel.JobSchedule{}, err
+	}
+	next_run_at := cron.Next(time.Now())
+	if next_run_at.IsZero() {
+		return gmodel.JobSchedule{}, fmt.Errorf("cron expression never runs")
+	}
+	payload_json, err := toJsonString(payload)
+	if err != nil {
+		return gmodel.JobSchedule{}, err
+	}
+	if payload_json == nil {
+		empty := "{}"
+		payload_json = &empty
+	}
+
+	qb := table.JobSchedule.
+		INSERT(
+			table.JobSchedule.Name,
+			table.JobSchedule.Type,
+			table.JobSchedule.CronExpression,
+			table.JobSchedule.Payload,
+			table.JobSchedule.NextRunAt,
+		).
+		MODEL(model.JobSchedule{
+			Name: name,
+			Type: job_type,
+			CronExpression: cron_expression,
+			Payload: *payload_json,
+			NextRunAt: next_run_at,
+		}).
+		ON_CONFLICT(table.JobSchedule.Name).
+		DO_UPDATE(postgres.SET(
+			table.JobSchedule.Type.SET(table.JobSchedule.EXCLUDED.Type),
+			table.JobSchedule.Payload.SET(table.JobSchedule.EXCLUDED.Payload),
+			table.JobSchedule.NextRunAt.SET(postgres.TimestampzExp(postgres.Raw(
+				`case when "job_schedule"."cron_expression" = excluded."cron_expression" then "job_schedule"."next_run_at" else excluded."next_run_at" end`,
+			))),
+			table.JobSchedule.CronExpression.SET(table.JobSchedule.EXCLUDED.CronExpression),
+			table.JobSchedule.UpdatedAt.SET(postgres.TimestampzT(time.Now())),
+		)).
+		RETURNING(table.JobSchedule.AllColumns)
+	err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &schedule)
+	return schedule, err
+}
+
+// Queues a job for every enabled schedule that is due and moves the schedule to its next run.
+// Runs missed while the server was down are collapsed into a single job
+func (s Service) EnqueueDueScheduledJobs(ctx context.Context) (enqueued int, err error) {
+	if s.TX, err = s.DB.BeginTx(ctx, nil); err != nil {
+		return 0, err
+	}
+	defer s.TX.Rollback()
+
+	qb := table.JobSchedule.
+		SELECT(table.JobSchedule.AllColumns).
+		FROM(table.JobSchedule).
+		WHERE(postgres.AND(
+			table.JobSchedule.Enabled.IS_TRUE(),
+			table.JobSchedule.NextRunAt.LT_EQ(postgres.TimestampzT(time.Now())),
+		)).
+		FOR(postgres.UPDATE().SKIP_LOCKED())
+	var schedules []model.JobSchedule
+	if err = qb.QueryContext(ctx, s.TX, &schedules); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	for _, schedule := range schedules {
+		cron, err := utils.ParseCron(schedule.CronExpression)
+		if err != nil {
+			log.Printf("invalid cron expression of job schedule %s. %s\n", schedule.Name, err.Error())
+			continue
+		}
+		unique_key := fmt.Sprintf("schedule:%s", schedule.Name)
+		if _, err := s.EnqueueJob(ctx, schedule.Type, schedule.Payload, EnqueueJobOptions{
+			UniqueKey: &unique_key,
+			ScheduleID: &schedule.ID,
+		}); err != nil {
+			return enqueued, err
+		}
+		enqueued++
+
+		next_run_at := cron.Next(now)
+		enabled := !next_run_at.IsZero()
+		if !enabled {
+			next_run_at = schedule.NextRunAt
+		}
+		update_qb := table.JobSchedule.
+			UPDATE(
+				table.JobSchedule.NextRunAt,
+				table.JobSchedule.LastRunAt,
+				table.JobSchedule.Enabled,
+				table.JobSchedule.UpdatedAt,
+			).
+			MODEL(model.JobSchedule{
+				NextRunAt: next_run_at,
+				LastRunAt: &now,
+				Enabled: enabled,
+				UpdatedAt: now,
+			}).
+			WHERE(table.JobSchedule.ID.EQ(postgres.Int(schedule.ID)))
+		if _, err := update_qb.ExecContext(ctx, s.TX); err != nil {
+			return enqueued, err
+		}
+	}
+	if err = s.TX.Commit(); err != nil {
+		return 0, err
+	}
+	s.TX = nil
+	return enqueued, nil
+}
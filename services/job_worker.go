@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Polls the job queue and runs due jobs in the background
+type JobWorker struct {
+	Service Service
+	PollInterval time.Duration
+	BatchSize int
+	cancel context.CancelFunc
+	done chan struct{}
+}
+
+func NewJobWorker(service Service, poll_interval time.Duration, batch_size int) *JobWorker {
+	return &JobWorker{
+		Service: service,
+		PollInterval: poll_interval,
+		BatchSize: batch_size,
+	}
+}
+
+func (w *JobWorker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.done = make(chan struct{})
+	go w.run(ctx)
+}
+
+func (w *JobWorker) run(ctx context.Context) {
+	defer close(w.done)
+	ticker := time.NewTicker(w.PollInterval)
+	defer ticker.Stop()
+	for {
+		w.poll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *JobWorker) poll(ctx context.Context) {
+	if recovered, err := w.Service.RecoverStaleJobs(ctx); err != nil {
+		log.Println("could not recover stale jobs.", err.Error())
+	} else if recovered > 0 {
+		log.Printf("requeued %d stale job(s)\n", recovered)
+	}
+	if _, err := w.Service.EnqueueDueScheduledJobs(ctx); err != nil {
+		log.Println("could not enqueue scheduled jobs.", err.Error())
+	}
+	if _, err := w.Service.RunPendingJobs(ctx, w.BatchSize); err != nil && ctx.Err() == nil {
+		log.Println("could not run jobs.", err.Error())
+	}
+}
+
+// Stops polling and waits for running jobs to finish. Jobs still running when `ctx`
+// is done are picked up again by the next worker after `JOB_LOCK_TIMEOUT`
+func (w *JobWorker) Shutdown(ctx context.Context) error {
+	if w.cancel == nil {
+		return nil
+	}
+	w.cancel()
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
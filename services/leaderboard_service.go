@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+const LEADERBOARD_DEFAULT_LIMIT = 25
+const LEADERBOARD_MAX_LIMIT = 100
+
+// Points awarded per contribution type
+const (
+	LEADERBOARD_PRICE_POINTS = 1
+	LEADERBOARD_PRODUCT_POINTS = 5
+	LEADERBOARD_CONFIRMATION_POINTS = 2
+)
+
+type leaderboardRow struct {
+	UserID int64
+	Prices int
+	Products int
+	Confirmations int
+}
+
+// Returns the start of the leaderboard period in UTC. Weeks start on Monday
+func LeaderboardPeriodStart(period gmodel.LeaderboardPeriod, now time.Time) *time.Time {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	var start time.Time
+	switch period {
+	case gmodel.LeaderboardPeriodWeekly:
+		days_since_monday := (int(today.Weekday()) + 6) % 7
+		start = today.AddDate(0, 0, -days_since_monday)
+	case gmodel.LeaderboardPeriodMonthly:
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return nil
+	}
+	return &start
+}
+
+func leaderboardRegionClause(country_code *string, administrative_division *string) postgres.BoolExpression {
+	where_clause := postgres.Bool(true)
+	if country_code != nil {
+		where_clause = where_clause.AND(table.Address.CountryCode.EQ(postgres.NewEnumValue(*country_code)))
+	}
+	if administrative_division != nil {
+		where_clause = where_clause.AND(table.Address.AdministrativeDivision.EQ(postgres.String(*administrative_division)))
+	}
+	return where_clause
+}
+
+func (s Service) Leaderboard(
+	ctx context.Context,
+	period gmodel.LeaderboardPeriod,
+	country_code *string,
+	administrative_division *string,
+	limit *int,
+) ([]gmodel.LeaderboardEntry, error) {
+	max_entries := LEADERBOARD_DEFAULT_LIMIT
+	if limit != nil && *limit > 0 {
+		max_entries = min(*limit, LEADERBOARD_MAX_LIMIT)
+	}
+	start := LeaderboardPeriodStart(period, time.Now())
+	db := s.DbOrTxQueryable()
+
+	billing_where := postgres.AND(
+		table.ProductBilling.Status.EQ(postgres.NewEnumValue(model.ProductBillingStatus_Approved.String())),
+		leaderboardRegionClause(country_code, administrative_division),
+	)
+	if start != nil {
+		billing_where = billing_where.AND(table.ProductBilling.CreatedAt.GT_EQ(postgres.TimestampzT(*start)))
+	}
+	count_type := func(billing_type model.ProductBillingType) postgres.Expression {
+		return postgres.SUMi(postgres.IntExp(
+			postgres.CASE().
+				WHEN(table.ProductBilling.BillingRateType.EQ(postgres.NewEnumValue(billing_type.String()))).
+				THEN(postgres.Int(1)).
+				ELSE(postgres.Int(0)),
+		))
+	}
+	billing_qb := table.ProductBilling.
+		SELECT(
+			table.ProductBilling.UserID.AS("leaderboard_row.user_id"),
+			count_type(model.ProductBillingType_Price).AS("leaderboard_row.prices"),
+			count_type(model.ProductBillingType_Create).AS("leaderboard_row.products"),
+		).
+		FROM(table.ProductBilling.
+			INNER_JOIN(table.User, table.User.ID.EQ(table.ProductBilling.UserID)).
+			LEFT_JOIN(table.Address, table.Address.ID.EQ(table.User.AddressID)),
+		).
+		WHERE(billing_where).
+		GROUP_BY(table.ProductBilling.UserID)
+	var billing_rows []leaderboardRow
+	if err := billing_qb.QueryContext(ctx, db, &billing_rows); err != nil {
+		return nil, err
+	}
+
+	follow_up := table.Price.AS("follow_up_price")
+	confirmation_where := postgres.AND(
+		table.Price.CreatedByID.IS_NOT_NULL(),
+		leaderboardRegionClause(country_code, administrative_division),
+	)
+	if start != nil {
+		confirmation_where = confirmation_where.AND(table.Price.CreatedAt.GT_EQ(postgres.TimestampzT(*start)))
+	}
+	confirmation_qb := table.Price.
+		SELECT(
+			table.Price.CreatedByID.AS("leaderboard_row.user_id"),
+			postgres.COUNT(postgres.DISTINCT(table.Price.ID)).AS("leaderboard_row.confirmations"),
+		).
+		FROM(table.Price.
+			INNER_JOIN(follow_up, postgres.AND(
+				follow_up.StockID.EQ(table.Price.StockID),
+				follow_up.Amount.EQ(table.Price.Amount),
+				follow_up.CreatedAt.GT(table.Price.CreatedAt),
+				follow_up.CreatedAt.LT(table.Price.CreatedAt.ADD(postgres.INTERVALd(BILLING_CONFIRMATION_WINDOW))),
+				follow_up.CreatedByID.NOT_EQ(table.Price.CreatedByID),
+			)).
+			INNER_JOIN(table.User, table.User.ID.EQ(table.Price.CreatedByID)).
+			LEFT_JOIN(table.Address, table.Address.ID.EQ(table.User.AddressID)),
+		).
+		WHERE(confirmation_where).
+		GROUP_BY(table.Price.CreatedByID)
+	var confirmation_rows []leaderboardRow
+	if err := confirmation_qb.QueryContext(ctx, db, &confirmation_rows); err != nil {
+		return nil, err
+	}
+
+	rows_by_user := map[int64]*leaderboardRow{}
+	for i := range billing_rows {
+		rows_by_user[billing_rows[i].UserID] = &billing_rows[i]
+	}
+	for _, row := range confirmation_rows {
+		if existing, ok := rows_by_user[row.UserID]; ok {
+			existing.Confirmations = row.Confirmations
+			continue
+		}
+		row := row
+		rows_by_user[row.UserID] = &row
+	}
+
+	entries := []gmodel.LeaderboardEntry{}
+	for _, row := range rows_by_user {
+		entries = append(entries, gmodel.LeaderboardEntry{
+			User: &gmodel.UserShallow{ ID: row.UserID },
+			Points: row.Prices * LEADERBOARD_PRICE_POINTS +
+				row.Products * LEADERBOARD_PRODUCT_POINTS +
+				row.Confirmations * LEADERBOARD_CONFIRMATION_POINTS,
+			Prices: row.Prices,
+			Products: row.Products,
+			Confirmations: row.Confirmations,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Points != entries[j].Points {
+			return entries[i].Points > entries[j].Points
+		}
+		return entries[i].User.ID < entries[j].User.ID
+	})
+	if len(entries) > max_entries {
+		entries = entries[:max_entries]
+	}
+	if len(entries) == 0 {
+		return entries, nil
+	}
+
+	user_ids := make([]postgres.Expression, len(entries))
+	for i := range entries {
+		user_ids[i] = postgres.Int(entries[i].User.ID)
+	}
+	users_qb := table.User.
+		SELECT(
+			table.User.ID,
+			table.User.Name,
+			table.User.Avatar,
+			table.User.Active,
+		).
+		FROM(table.User).
+		WHERE(table.User.ID.IN(user_ids...))
+	var users []gmodel.UserShallow
+	if err := users_qb.QueryContext(ctx, db, &users); err != nil {
+		return nil, err
+	}
+	users_by_id := map[int64]gmodel.UserShallow{}
+	for _, user := range users {
+		users_by_id[user.ID] = user
+	}
+
+	// competition ranking. ties share the same rank
+	for i := range entries {
+		user := users_by_id[entries[i].User.ID]
+		entries[i].User = &user
+		if i > 0 && entries[i].Points == entries[i - 1].Points {
+			entries[i].Rank = entries[i - 1].Rank
+		} else {
+			entries[i].Rank = i + 1
+		}
+	}
+	return entries, nil
+}
@@ -411,12 +411,17 @@ func (s Service) GetProductListsWithListId(
 	if err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &product_lists); err != nil {
 		return nil, err
 	}
+	stocks := []*gmodel.Stock{}
 	for i := range product_lists {
 		if product_lists[i].StockID != nil {
+			stocks = append(stocks, product_lists[i].Stock)
 			continue
 		}
 		product_lists[i].Stock = nil
 	}
+	if err = s.ApplyViewerPrices(ctx, user, stocks); err != nil {
+		return nil, err
+	}
 	return product_lists, nil
 }
 
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Returns the cleaned object key of a public id. Rejects keys escaping the store root
+func imageStoreKey(public_id string) (string, error) {
+	key := path.Clean("/" + public_id)[1:]
+	if key == "" || key != public_id {
+		return "", fmt.Errorf("invalid image public id %s", public_id)
+	}
+	return key, nil
+}
+
+func imageVersionKey(key string, version string) string {
+	return fmt.Sprintf("v%s/%s", version, key)
+}
+
+// Stores images on the local filesystem. Every upload also keeps a copy
+// under `v<version>/<public_id>` so previous versions can be restored.
+// Intended for development and tests, images are served by `ServeHTTP`
+type LocalImageStore struct {
+	Directory string
+	// URL the images are served from, e.g. http://localhost:8080/images
+	BaseUrl string
+}
+
+func (l LocalImageStore) write(key string, data []byte) error {
+	file_path := filepath.Join(l.Directory, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(file_path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(file_path, data, 0644)
+}
+
+func (l LocalImageStore) UploadBytes(ctx context.Context, data []byte, params ImageUploadParams) (*ImageUploadResult, error) {
+	key, err := imageStoreKey(params.PublicID)
+	if err != nil {
+		return nil, err
+	}
+	version := int(time.Now().UnixMilli())
+	if err := l.write(imageVersionKey(key, strconv.Itoa(version)), data); err != nil {
+		return nil, err
+	}
+	if err := l.write(key, data); err != nil {
+		return nil, err
+	}
+	return &ImageUploadResult{
+		PublicID: key,
+		Version: version,
+		URL: l.Url(key),
+	}, nil
+}
+
+func (l LocalImageStore) UploadStream(ctx context.Context, stream io.Reader, params ImageUploadParams) (*ImageUploadResult, error) {
+	data, err := io.ReadAll(stream)
+	if err != nil {
+		return nil, err
+	}
+	return l.UploadBytes(ctx, data, params)
+}
+
+// Images of this store are copied from disk, other URLs are downloaded
+func (l LocalImageStore) UploadUrl(ctx context.Context, image_url string, params ImageUploadParams) (*ImageUploadResult, error) {
+	var data []byte
+	var err error
+	if local_key, ok := strings.CutPrefix(image_url, l.BaseUrl + "/"); ok {
+		if local_key, err = imageStoreKey(local_key); err != nil {
+			return nil, err
+		}
+		data, err = os.ReadFile(filepath.Join(l.Directory, filepath.FromSlash(local_key)))
+	} else {
+		data, err = fetchImageUrl(ctx, image_url)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return l.UploadBytes(ctx, data, params)
+}
+
+// Deletes the latest version. Previous versions are kept
+func (l LocalImageStore) Delete(ctx context.Context, public_id string) error {
+	key, err := imageStoreKey(public_id)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(filepath.Join(l.Directory, filepath.FromSlash(key)))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (l LocalImageStore) Url(public_id string) string {
+	return fmt.Sprintf("%s/%s", l.BaseUrl, public_id)
+}
+
+func (l LocalImageStore) VersionUrl(public_id string, version string) string {
+	return l.Url(imageVersionKey(public_id, version))
+}
+
+// Serves stored images. Mount with the base URL path stripped, e.g.
+// `router.Handle("/images/*", http.StripPrefix("/images", store))`
+func (l LocalImageStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	key, err := imageStoreKey(strings.TrimPrefix(r.URL.Path, "/"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	file_path := filepath.Join(l.Directory, filepath.FromSlash(key))
+	info, err := os.Stat(file_path)
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+	file, err := os.Open(file_path)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(file, head)
+	w.Header().Set("Content-Type", http.DetectContentType(head[:n]))
+	w.Header().Set("Cache-Control", "no-cache")
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.ServeContent(w, r, "", info.ModTime(), file)
+}
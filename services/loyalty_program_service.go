@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+func (s Service) FindLoyaltyProgramById(ctx context.Context, id int64) (program gmodel.LoyaltyProgram, err error) {
+	qb := table.LoyaltyProgram.
+		SELECT(table.LoyaltyProgram.AllColumns).
+		FROM(table.LoyaltyProgram).
+		WHERE(table.LoyaltyProgram.ID.EQ(postgres.Int(id))).
+		LIMIT(1)
+	err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &program)
+	return program, err
+}
+
+func (s Service) LoyaltyPrograms(ctx context.Context, store_id int64) (programs []gmodel.LoyaltyProgram, err error) {
+	qb := table.LoyaltyProgram.
+		SELECT(table.LoyaltyProgram.AllColumns).
+		FROM(table.LoyaltyProgram).
+		WHERE(table.LoyaltyProgram.StoreID.EQ(postgres.Int(store_id))).
+		ORDER_BY(table.LoyaltyProgram.Name.ASC())
+	if err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &programs); err != nil {
+		return nil, err
+	}
+	if programs == nil {
+		programs = []gmodel.LoyaltyProgram{}
+	}
+	return programs, nil
+}
+
+func (s Service) CreateLoyaltyProgram(
+	ctx context.Context,
+	user gmodel.User,
+	input gmodel.CreateLoyaltyProgram,
+) (program gmodel.LoyaltyProgram, err error) {
+	if err = s.StructValidator.StructCtx(ctx, input); err != nil {
+		return gmodel.LoyaltyProgram{}, fmt.Errorf("invalid input: %w", err)
+	}
+	if !s.StoreExists(ctx, input.StoreID) {
+		return gmodel.LoyaltyProgram{}, fmt.Errorf("store id is invalid")
+	}
+	qb := table.LoyaltyProgram.
+		INSERT(
+			table.LoyaltyProgram.StoreID,
+			table.LoyaltyProgram.Name,
+			table.LoyaltyProgram.Description,
+			table.LoyaltyProgram.SignupURL,
+			table.LoyaltyProgram.CreatedByID,
+			table.LoyaltyProgram.UpdatedByID,
+		).
+		MODEL(model.LoyaltyProgram{
+			StoreID: input.StoreID,
+			Name: strings.TrimSpace(input.Name),
+			Description: input.Description,
+			SignupURL: input.SignupURL,
+			CreatedByID: &user.ID,
+			UpdatedByID: &user.ID,
+		}).
+		RETURNING(table.LoyaltyProgram.AllColumns)
+	if err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &program); err != nil {
+		return gmodel.LoyaltyProgram{}, fmt.Errorf("store already has a loyalty program with this name")
+	}
+	return program, nil
+}
+
+func (s Service) UpdateLoyaltyProgram(
+	ctx context.Context,
+	user gmodel.User,
+	store_id int64,
+	id int64,
+	input gmodel.UpdateLoyaltyProgram,
+) (program gmodel.LoyaltyProgram, err error) {
+	if err = s.StructValidator.StructCtx(ctx, input); err != nil {
+		return gmodel.LoyaltyProgram{}, fmt.Errorf("invalid input: %w", err)
+	}
+	program, err = s.FindLoyaltyProgramById(ctx, id)
+	if err != nil || program.StoreID != store_id {
+		return gmodel.LoyaltyProgram{}, fmt.Errorf("loyalty program not found")
+	}
+
+	cols := postgres.ColumnList{table.LoyaltyProgram.UpdatedByID, table.LoyaltyProgram.UpdatedAt}
+	update := model.LoyaltyProgram{
+		Name: program.Name,
+		UpdatedByID: &user.ID,
+		UpdatedAt: time.Now(),
+	}
+	if input.Name != nil && strings.TrimSpace(*input.Name) != "" {
+		cols = append(cols, table.LoyaltyProgram.Name)
+		update.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Description != nil {
+		cols = append(cols, table.LoyaltyProgram.Description)
+		update.Description = input.Description
+	}
+	if input.SignupURL != nil {
+		cols = append(cols, table.LoyaltyProgram.SignupURL)
+		update.SignupURL = input.SignupURL
+	}
+	qb := table.LoyaltyProgram.
+		UPDATE(cols).
+		MODEL(update).
+		WHERE(table.LoyaltyProgram.ID.EQ(postgres.Int(id))).
+		RETURNING(table.LoyaltyProgram.AllColumns)
+	if err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &program); err != nil {
+		return gmodel.LoyaltyProgram{}, fmt.Errorf("store already has a loyalty program with this name")
+	}
+	return program, nil
+}
+
+func (s Service) UserLoyaltyPrograms(ctx context.Context, user gmodel.User) (programs []gmodel.UserLoyaltyProgram, err error) {
+	qb := table.UserLoyaltyProgram.
+		SELECT(
+			table.UserLoyaltyProgram.AllColumns,
+			table.LoyaltyProgram.AllColumns,
+		).
+		FROM(table.UserLoyaltyProgram.
+			INNER_JOIN(table.LoyaltyProgram, table.LoyaltyProgram.ID.EQ(table.UserLoyaltyProgram.LoyaltyProgramID)),
+		).
+		WHERE(table.UserLoyaltyProgram.UserID.EQ(postgres.Int(user.ID))).
+		ORDER_BY(table.UserLoyaltyProgram.CreatedAt.ASC())
+	if err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &programs); err != nil {
+		return nil, err
+	}
+	if programs == nil {
+		programs = []gmodel.UserLoyaltyProgram{}
+	}
+	return programs, nil
+}
+
+func (s Service) JoinLoyaltyProgram(ctx context.Context, user gmodel.User, program_id int64) (gmodel.UserLoyaltyProgram, error) {
+	program, err := s.FindLoyaltyProgramById(ctx, program_id)
+	if err != nil {
+		return gmodel.UserLoyaltyProgram{}, fmt.Errorf("loyalty program not found")
+	}
+	qb := table.UserLoyaltyProgram.
+		INSERT(
+			table.UserLoyaltyProgram.UserID,
+			table.UserLoyaltyProgram.LoyaltyProgramID,
+		).
+		MODEL(model.UserLoyaltyProgram{
+			UserID: user.ID,
+			LoyaltyProgramID: program.ID,
+		}).
+		ON_CONFLICT(table.UserLoyaltyProgram.UserID, table.UserLoyaltyProgram.LoyaltyProgramID).
+		DO_UPDATE(postgres.SET(
+			table.UserLoyaltyProgram.LoyaltyProgramID.SET(table.UserLoyaltyProgram.EXCLUDED.LoyaltyProgramID),
+		)).
+		RETURNING(table.UserLoyaltyProgram.AllColumns)
+	var membership gmodel.UserLoyaltyProgram
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &membership); err != nil {
+		return gmodel.UserLoyaltyProgram{}, err
+	}
+	membership.LoyaltyProgram = &program
+	return membership, nil
+}
+
+func (s Service) LeaveLoyaltyProgram(ctx context.Context, user gmodel.User, program_id int64) error {
+	qb := table.UserLoyaltyProgram.
+		DELETE().
+		WHERE(postgres.AND(
+			table.UserLoyaltyProgram.UserID.EQ(postgres.Int(user.ID)),
+			table.UserLoyaltyProgram.LoyaltyProgramID.EQ(postgres.Int(program_id)),
+		))
+	res, err := qb.ExecContext(ctx, s.DbOrTxExecutable())
+	if err != nil {
+		return err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return fmt.Errorf("not a member of this loyalty program")
+	}
+	return nil
+}
+
+// Returns `true` if the user can get the member price. Prices without a program
+// apply to members of any program of the price's store
+func IsMemberPriceApplicable(price gmodel.Price, memberships []gmodel.UserLoyaltyProgram) bool {
+	for _, membership := range memberships {
+		if price.LoyaltyProgramID != nil {
+			if membership.LoyaltyProgramID == *price.LoyaltyProgramID {
+				return true
+			}
+			continue
+		}
+		if membership.LoyaltyProgram != nil && membership.LoyaltyProgram.StoreID == price.StoreID {
+			return true
+		}
+	}
+	return false
+}
+
+// Amount per unit after deals
+func PriceEffectiveAmount(price gmodel.Price) float64 {
+	if price.EffectivePrice != nil {
+		return *price.EffectivePrice
+	}
+	return price.Amount
+}
+
+// Loads the latest member price of the stocks and uses it as the latest price
+// if the user is a member and it's lower than the regular price.
+// The regular price is kept as `NonMemberPrice`
+func (s Service) ApplyMemberPrices(ctx context.Context, user gmodel.User, stocks []*gmodel.Stock) error {
+	price_ids := []postgres.Expression{}
+	for _, stock := range stocks {
+		if stock != nil && stock.LatestMemberPriceID != nil {
+			price_ids = append(price_ids, postgres.Int(*stock.LatestMemberPriceID))
+		}
+	}
+	if len(price_ids) == 0 {
+		return nil
+	}
+
+	qb := table.Price.
+		SELECT(table.Price.AllColumns).
+		FROM(table.Price).
+		WHERE(postgres.AND(
+			table.Price.ID.IN(price_ids...),
+			table.Price.ReviewStatus.IS_DISTINCT_FROM(postgres.NewEnumValue(model.PriceReviewStatus_Rejected.String())),
+			postgres.OR(
+				table.Price.ExpiresAt.IS_NULL(),
+				table.Price.ExpiresAt.GT(postgres.TimestampzT(time.Now())),
+			),
+		))
+	var member_prices []gmodel.Price
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &member_prices); err != nil {
+		return err
+	}
+	memberships := []gmodel.UserLoyaltyProgram{}
+	if user.ID != 0 {
+		var err error
+		if memberships, err = s.UserLoyaltyPrograms(ctx, user); err != nil {
+			return err
+		}
+	}
+
+	for _, stock := range stocks {
+		if stock == nil || stock.LatestMemberPriceID == nil {
+			continue
+		}
+		i := slices.IndexFunc(member_prices, func(p gmodel.Price) bool {
+			return p.ID == *stock.LatestMemberPriceID
+		})
+		if i < 0 {
+			continue
+		}
+		member_price := member_prices[i]
+		stock.LatestMemberPrice = &member_price
+		if !IsMemberPriceApplicable(member_price, memberships) {
+			continue
+		}
+		if stock.LatestPrice != nil {
+			if stock.LatestPrice.ID == member_price.ID || PriceEffectiveAmount(member_price) >= PriceEffectiveAmount(*stock.LatestPrice) {
+				continue
+			}
+			if !stock.LatestPrice.MemberOnly {
+				stock.NonMemberPrice = stock.LatestPrice
+			}
+		}
+		stock.LatestPrice = &member_price
+		stock.LatestPriceID = &member_price.ID
+	}
+	return nil
+}
@@ -2,9 +2,12 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"net/http"
+	"net/netip"
+	"strings"
 
-	"github.com/pricetra/api/graph/gmodel"
 	"github.com/pricetra/api/types"
 )
 
@@ -19,9 +22,17 @@ func (s Service) AuthorizationMiddleware(next http.Handler) http.Handler {
 			types.AuthorizationKey, 
 			bearer_token,
 		))
+		r = r.WithContext(context.WithValue(
+			r.Context(),
+			types.RequestMetadataKey,
+			types.RequestMetadata{
+				IpAddress: s.RequestIpAddress(r),
+				UserAgent: r.UserAgent(),
+			},
+		))
 
 		// If valid JWT token, store the user info in context with key `types.AuthUserKey`
-		if user, err := s.VerifyJwt(r.Context(), bearer_token); err == nil && user != (gmodel.User{}) {
+		if user, err := s.VerifyJwt(r.Context(), bearer_token); err == nil && user.ID != 0 {
 			r = r.WithContext(context.WithValue(
 				r.Context(), 
 				types.AuthUserKey, 
@@ -32,3 +43,65 @@ func (s Service) AuthorizationMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// Parses a comma separated list of proxy IP addresses or CIDR ranges
+func ParseTrustedProxies(value string) (proxies []netip.Prefix, err error) {
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			addr, err := netip.ParseAddr(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %s", entry)
+			}
+			proxies = append(proxies, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %s", entry)
+		}
+		proxies = append(proxies, prefix.Masked())
+	}
+	return proxies, nil
+}
+
+func (s Service) isTrustedProxy(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, proxy := range s.TrustedProxies {
+		if proxy.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the client IP address. "X-Forwarded-For" is only used when the request
+// comes from a trusted proxy, in which case the last entry not added by a trusted proxy is returned
+func (s Service) RequestIpAddress(r *http.Request) string {
+	remote_ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remote_ip = r.RemoteAddr
+	}
+	if !s.isTrustedProxy(remote_ip) {
+		return remote_ip
+	}
+	forwarded_for := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(forwarded_for) - 1; i >= 0; i-- {
+		ip := strings.TrimSpace(forwarded_for[i])
+		if ip == "" {
+			continue
+		}
+		if !s.isTrustedProxy(ip) {
+			return ip
+		}
+		remote_ip = ip
+	}
+	return remote_ip
+}
@@ -0,0 +1,338 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+type payoutTotal struct {
+	UserID int64
+	CurrencyCode string
+	Amount float64
+	BillingCount int32
+}
+
+func unpaidBillingClause(cutoff time.Time) postgres.BoolExpression {
+	return postgres.AND(
+		table.ProductBilling.PaidAt.IS_NULL(),
+		table.ProductBilling.Status.EQ(postgres.NewEnumValue(model.ProductBillingStatus_Approved.String())),
+		table.ProductBilling.CreatedAt.LT_EQ(postgres.TimestampzT(cutoff)),
+	)
+}
+
+// Groups unpaid billing rows created on or before the cutoff into one payout per user and currency.
+// All included rows are marked as paid within the same transaction
+func (s Service) CreatePayoutBatch(
+	ctx context.Context,
+	user gmodel.User,
+	cutoff time.Time,
+	minimum_amount *float64,
+) (batch gmodel.PayoutBatch, err error) {
+	if cutoff.After(time.Now()) {
+		return gmodel.PayoutBatch{}, fmt.Errorf("cutoff cannot be in the future")
+	}
+
+	s.TX, err = s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return gmodel.PayoutBatch{}, err
+	}
+	defer s.TX.Rollback()
+
+	// lock unpaid rows so concurrent batches cannot pay the same rows twice
+	lock_qb := table.ProductBilling.
+		SELECT(table.ProductBilling.ID).
+		FROM(table.ProductBilling).
+		WHERE(unpaidBillingClause(cutoff)).
+		FOR(postgres.UPDATE())
+	if _, err := lock_qb.ExecContext(ctx, s.TX); err != nil {
+		return gmodel.PayoutBatch{}, err
+	}
+
+	amount_col := postgres.SUMf(table.ProductBilling.Rate)
+	totals_qb := table.ProductBilling.
+		SELECT(
+			table.ProductBilling.UserID.AS("payout_total.user_id"),
+			table.ProductBilling.CurrencyCode.AS("payout_total.currency_code"),
+			amount_col.AS("payout_total.amount"),
+			postgres.COUNT(table.ProductBilling.ID).AS("payout_total.billing_count"),
+		).
+		FROM(table.ProductBilling).
+		WHERE(unpaidBillingClause(cutoff)).
+		GROUP_BY(table.ProductBilling.UserID, table.ProductBilling.CurrencyCode).
+		ORDER_BY(table.ProductBilling.UserID.ASC())
+	if minimum_amount != nil {
+		totals_qb = totals_qb.HAVING(amount_col.GT_EQ(postgres.Float(*minimum_amount)))
+	}
+	var totals []payoutTotal
+	if err := totals_qb.QueryContext(ctx, s.TX, &totals); err != nil {
+		return gmodel.PayoutBatch{}, err
+	}
+	if len(totals) == 0 {
+		return gmodel.PayoutBatch{}, fmt.Errorf("no unpaid billing rows found before cutoff")
+	}
+
+	var billing_count int32
+	for _, total := range totals {
+		billing_count += total.BillingCount
+	}
+	batch_qb := table.PayoutBatch.
+		INSERT(
+			table.PayoutBatch.Cutoff,
+			table.PayoutBatch.CreatedByID,
+			table.PayoutBatch.PayoutCount,
+			table.PayoutBatch.BillingCount,
+		).
+		MODEL(model.PayoutBatch{
+			Cutoff: cutoff,
+			CreatedByID: &user.ID,
+			PayoutCount: int32(len(totals)),
+			BillingCount: billing_count,
+		}).
+		RETURNING(table.PayoutBatch.AllColumns)
+	if err := batch_qb.QueryContext(ctx, s.TX, &batch); err != nil {
+		return gmodel.PayoutBatch{}, err
+	}
+
+	for _, total := range totals {
+		var payout model.Payout
+		payout_qb := table.Payout.
+			INSERT(table.Payout.MutableColumns.Except(table.Payout.CreatedAt)).
+			MODEL(model.Payout{
+				PayoutBatchID: batch.ID,
+				UserID: &total.UserID,
+				CurrencyCode: total.CurrencyCode,
+				Amount: total.Amount,
+				BillingCount: total.BillingCount,
+			}).
+			RETURNING(table.Payout.AllColumns)
+		if err := payout_qb.QueryContext(ctx, s.TX, &payout); err != nil {
+			return gmodel.PayoutBatch{}, err
+		}
+
+		update_qb := table.ProductBilling.
+			UPDATE(table.ProductBilling.PaidAt, table.ProductBilling.PayoutID).
+			SET(postgres.TimestampzT(batch.CreatedAt), postgres.Int(payout.ID)).
+			WHERE(postgres.AND(
+				unpaidBillingClause(cutoff),
+				table.ProductBilling.UserID.EQ(postgres.Int(total.UserID)),
+				table.ProductBilling.CurrencyCode.EQ(postgres.String(total.CurrencyCode)),
+			))
+		if _, err := update_qb.ExecContext(ctx, s.TX); err != nil {
+			return gmodel.PayoutBatch{}, err
+		}
+	}
+
+	if err := s.TX.Commit(); err != nil {
+		return gmodel.PayoutBatch{}, err
+	}
+	s.TX = nil
+	return batch, nil
+}
+
+func (s Service) PaginatedPayoutBatches(ctx context.Context, paginator_input gmodel.PaginatorInput) (result gmodel.PaginatedPayoutBatches, err error) {
+	where_clause := postgres.Bool(true)
+	paginator, err := s.Paginate(ctx, paginator_input, table.PayoutBatch, table.PayoutBatch.ID, where_clause)
+	if err != nil {
+		return gmodel.PaginatedPayoutBatches{
+			PayoutBatches: []*gmodel.PayoutBatch{},
+			Paginator: &gmodel.Paginator{},
+		}, nil
+	}
+
+	qb := table.PayoutBatch.
+		SELECT(
+			table.PayoutBatch.AllColumns,
+			table.User.ID,
+			table.User.Name,
+			table.User.Avatar,
+			table.User.Active,
+		).
+		FROM(table.PayoutBatch.LEFT_JOIN(table.User, table.User.ID.EQ(table.PayoutBatch.CreatedByID))).
+		WHERE(where_clause).
+		ORDER_BY(table.PayoutBatch.ID.DESC()).
+		LIMIT(int64(paginator.Limit)).
+		OFFSET(int64(paginator.Offset))
+	if err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &result.PayoutBatches); err != nil {
+		return gmodel.PaginatedPayoutBatches{}, err
+	}
+	result.Paginator = &paginator.Paginator
+	return result, nil
+}
+
+func payoutColumns() postgres.ProjectionList {
+	return postgres.ProjectionList{
+		table.Payout.AllColumns,
+		table.User.ID,
+		table.User.Name,
+		table.User.Avatar,
+		table.User.Active,
+	}
+}
+
+func (s Service) FindPayoutsByBatch(ctx context.Context, payout_batch_id int64) (payouts []gmodel.Payout, err error) {
+	qb := table.Payout.
+		SELECT(payoutColumns()).
+		FROM(table.Payout.LEFT_JOIN(table.User, table.User.ID.EQ(table.Payout.UserID))).
+		WHERE(table.Payout.PayoutBatchID.EQ(postgres.Int(payout_batch_id))).
+		ORDER_BY(table.Payout.ID.ASC())
+	if err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &payouts); err != nil {
+		return nil, err
+	}
+	return payouts, nil
+}
+
+func (s Service) FindPayoutById(ctx context.Context, id int64) (payout gmodel.Payout, err error) {
+	qb := table.Payout.
+		SELECT(payoutColumns()).
+		FROM(table.Payout.LEFT_JOIN(table.User, table.User.ID.EQ(table.Payout.UserID))).
+		WHERE(table.Payout.ID.EQ(postgres.Int(id))).
+		LIMIT(1)
+	err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &payout)
+	return payout, err
+}
+
+func (s Service) PaginatedPayoutsByUser(ctx context.Context, paginator_input gmodel.PaginatorInput, user gmodel.User) (result gmodel.PaginatedPayouts, err error) {
+	where_clause := table.Payout.UserID.EQ(postgres.Int(user.ID))
+	paginator, err := s.Paginate(ctx, paginator_input, table.Payout, table.Payout.ID, where_clause)
+	if err != nil {
+		return gmodel.PaginatedPayouts{
+			Payouts: []*gmodel.Payout{},
+			Paginator: &gmodel.Paginator{},
+		}, nil
+	}
+
+	qb := table.Payout.
+		SELECT(payoutColumns()).
+		FROM(table.Payout.LEFT_JOIN(table.User, table.User.ID.EQ(table.Payout.UserID))).
+		WHERE(where_clause).
+		ORDER_BY(table.Payout.ID.DESC()).
+		LIMIT(int64(paginator.Limit)).
+		OFFSET(int64(paginator.Offset))
+	if err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &result.Payouts); err != nil {
+		return gmodel.PaginatedPayouts{}, err
+	}
+	result.Paginator = &paginator.Paginator
+	return result, nil
+}
+
+// Returns the line items of a payout along with a CSV version of the statement.
+// Only the payee or an admin can view a statement
+func (s Service) PayoutStatement(ctx context.Context, user gmodel.User, payout_id int64) (statement gmodel.PayoutStatement, err error) {
+	payout, err := s.FindPayoutById(ctx, payout_id)
+	if err != nil {
+		return gmodel.PayoutStatement{}, fmt.Errorf("payout not found")
+	}
+	if (payout.UserID == nil || *payout.UserID != user.ID) && !s.IsRoleAuthorized(gmodel.UserRoleAdmin, user.Role) {
+		return gmodel.PayoutStatement{}, fmt.Errorf("unauthorized")
+	}
+
+	qb := table.ProductBilling.
+		SELECT(
+			table.ProductBilling.ID.AS("payout_statement_item.billing_id"),
+			table.ProductBilling.ProductID.AS("payout_statement_item.product_id"),
+			table.Product.Name.AS("payout_statement_item.product_name"),
+			table.Product.Code.AS("payout_statement_item.product_code"),
+			table.ProductBilling.BillingRateType.AS("payout_statement_item.billing_rate_type"),
+			table.ProductBilling.Rate.AS("payout_statement_item.rate"),
+			table.ProductBilling.CreatedAt.AS("payout_statement_item.created_at"),
+		).
+		FROM(table.ProductBilling.INNER_JOIN(table.Product, table.Product.ID.EQ(table.ProductBilling.ProductID))).
+		WHERE(table.ProductBilling.PayoutID.EQ(postgres.Int(payout.ID))).
+		ORDER_BY(table.ProductBilling.CreatedAt.ASC())
+	var items []gmodel.PayoutStatementItem
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &items); err != nil {
+		return gmodel.PayoutStatement{}, err
+	}
+
+	csv_data, err := payoutStatementCsv(payout, items)
+	if err != nil {
+		return gmodel.PayoutStatement{}, fmt.Errorf("could not create statement")
+	}
+	statement.Payout = &payout
+	statement.Items = make([]*gmodel.PayoutStatementItem, len(items))
+	for i := range items {
+		statement.Items[i] = &items[i]
+	}
+	statement.File = &gmodel.DataExport{
+		Filename: fmt.Sprintf("pricetra-payout-%d-%d.csv", payout.PayoutBatchID, payout.ID),
+		ContentType: "text/csv",
+		Data: base64.StdEncoding.EncodeToString(csv_data),
+		CreatedAt: time.Now(),
+	}
+	return statement, nil
+}
+
+func payoutStatementCsv(payout gmodel.Payout, items []gmodel.PayoutStatementItem) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	writer := csv.NewWriter(buf)
+	writer.Write([]string{"billing_id", "created_at", "product_id", "product_code", "product_name", "type", "rate", "currency_code"})
+	for _, item := range items {
+		writer.Write([]string{
+			strconv.FormatInt(item.BillingID, 10),
+			item.CreatedAt.UTC().Format(time.RFC3339),
+			strconv.FormatInt(item.ProductID, 10),
+			item.ProductCode,
+			item.ProductName,
+			item.BillingRateType,
+			strconv.FormatFloat(item.Rate, 'f', 2, 64),
+			payout.CurrencyCode,
+		})
+	}
+	writer.Write([]string{"total", "", "", "", "", "", strconv.FormatFloat(payout.Amount, 'f', 2, 64), payout.CurrencyCode})
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+// Returns pending, held and paid totals for the user grouped by currency.
+// Rejected billing rows are excluded
+func (s Service) EarningsSummary(ctx context.Context, user gmodel.User) (summary []gmodel.EarningsSummary, err error) {
+	amount_when := func(condition postgres.BoolExpression) postgres.Expression {
+		return postgres.COALESCE(postgres.SUMf(postgres.FloatExp(
+			postgres.CASE().WHEN(condition).THEN(table.ProductBilling.Rate).ELSE(postgres.Float(0)),
+		)), postgres.Float(0))
+	}
+	count_when := func(condition postgres.BoolExpression) postgres.IntegerExpression {
+		return postgres.SUMi(postgres.IntExp(
+			postgres.CASE().WHEN(condition).THEN(postgres.Int(1)).ELSE(postgres.Int(0)),
+		))
+	}
+	pending := postgres.AND(
+		table.ProductBilling.PaidAt.IS_NULL(),
+		table.ProductBilling.Status.EQ(postgres.NewEnumValue(model.ProductBillingStatus_Approved.String())),
+	)
+	held := table.ProductBilling.Status.EQ(postgres.NewEnumValue(model.ProductBillingStatus_Held.String()))
+	paid := table.ProductBilling.PaidAt.IS_NOT_NULL()
+
+	qb := table.ProductBilling.
+		SELECT(
+			table.ProductBilling.CurrencyCode.AS("earnings_summary.currency_code"),
+			amount_when(pending).AS("earnings_summary.pending_amount"),
+			count_when(pending).AS("earnings_summary.pending_count"),
+			amount_when(held).AS("earnings_summary.held_amount"),
+			count_when(held).AS("earnings_summary.held_count"),
+			amount_when(paid).AS("earnings_summary.paid_amount"),
+			count_when(paid).AS("earnings_summary.paid_count"),
+			postgres.MAX(table.ProductBilling.PaidAt).AS("earnings_summary.last_paid_at"),
+		).
+		FROM(table.ProductBilling).
+		WHERE(postgres.AND(
+			table.ProductBilling.UserID.EQ(postgres.Int(user.ID)),
+			table.ProductBilling.Status.NOT_EQ(postgres.NewEnumValue(model.ProductBillingStatus_Rejected.String())),
+		)).
+		GROUP_BY(table.ProductBilling.CurrencyCode).
+		ORDER_BY(table.ProductBilling.CurrencyCode.ASC())
+	if err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &summary); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/utils"
+)
+
+// Legacy prices parsed per batch by `BackfillPriceDeals`
+const PRICE_DEAL_BACKFILL_BATCH_SIZE = 500
+
+func int32Ptr(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	res := int32(*v)
+	return &res
+}
+
+// Validates the deal of a `CreatePrice` input
+func PriceDealFromInput(input gmodel.PriceDealInput) (utils.PriceDeal, error) {
+	deal := utils.PriceDeal{
+		Quantity: input.Quantity,
+		Price: input.Price,
+		FreeQuantity: input.FreeQuantity,
+		DiscountPercent: input.DiscountPercent,
+		DiscountAmount: input.DiscountAmount,
+		PurchaseLimit: input.PurchaseLimit,
+		MinimumQuantity: input.MinimumQuantity,
+		MinimumPurchaseAmount: input.MinimumPurchaseAmount,
+	}
+	if input.RequiresMembership != nil {
+		deal.RequiresMembership = *input.RequiresMembership
+	}
+	if input.Type != nil {
+		deal.Type = input.Type.String()
+	}
+
+	switch deal.Type {
+	case utils.PRICE_DEAL_MULTI_BUY:
+		if deal.Quantity == nil || *deal.Quantity < 2 || deal.Price == nil || *deal.Price <= 0 {
+			return utils.PriceDeal{}, fmt.Errorf("multi-buy deals need a quantity of at least 2 and a price")
+		}
+	case utils.PRICE_DEAL_BUY_X_GET_Y:
+		if deal.Quantity == nil || *deal.Quantity < 1 || deal.FreeQuantity == nil || *deal.FreeQuantity < 1 {
+			return utils.PriceDeal{}, fmt.Errorf("buy x get y deals need a quantity and a free quantity")
+		}
+		if deal.DiscountPercent == nil {
+			free := 100.0
+			deal.DiscountPercent = &free
+		}
+	case utils.PRICE_DEAL_DISCOUNT:
+		if (deal.DiscountAmount == nil) == (deal.DiscountPercent == nil) {
+			return utils.PriceDeal{}, fmt.Errorf("discount deals need either a discount amount or percent")
+		}
+		if deal.DiscountAmount != nil && *deal.DiscountAmount <= 0 {
+			return utils.PriceDeal{}, fmt.Errorf("discount amount must be greater than 0")
+		}
+	}
+	if deal.DiscountPercent != nil && (*deal.DiscountPercent <= 0 || *deal.DiscountPercent > 100) {
+		return utils.PriceDeal{}, fmt.Errorf("discount percent must be between 0 and 100")
+	}
+	for _, v := range []*int{deal.PurchaseLimit, deal.MinimumQuantity} {
+		if v != nil && *v < 1 {
+			return utils.PriceDeal{}, fmt.Errorf("purchase limit and minimum quantity must be at least 1")
+		}
+	}
+	if deal.PurchaseLimit != nil && deal.MinimumQuantity != nil && *deal.MinimumQuantity > *deal.PurchaseLimit {
+		return utils.PriceDeal{}, fmt.Errorf("minimum quantity cannot be greater than the purchase limit")
+	}
+	return deal, nil
+}
+
+func PriceDealFromPrice(price gmodel.Price) utils.PriceDeal {
+	deal := utils.PriceDeal{
+		Quantity: price.DealQuantity,
+		Price: price.DealPrice,
+		FreeQuantity: price.DealFreeQuantity,
+		DiscountPercent: price.DealDiscountPercent,
+		DiscountAmount: price.DealDiscountAmount,
+		RequiresMembership: price.RequiresMembership || price.MemberOnly,
+		PurchaseLimit: price.PurchaseLimit,
+		MinimumQuantity: price.MinimumQuantity,
+		MinimumPurchaseAmount: price.MinimumPurchaseAmount,
+	}
+	if price.DealType != nil {
+		deal.Type = price.DealType.String()
+	}
+	return deal
+}
+
+// Deal columns of the price table along with the effective price
+func priceDealColumns() postgres.ColumnList {
+	return postgres.ColumnList{
+		table.Price.DealType,
+		table.Price.DealQuantity,
+		table.Price.DealPrice,
+		table.Price.DealFreeQuantity,
+		table.Price.DealDiscountPercent,
+		table.Price.DealDiscountAmount,
+		table.Price.RequiresMembership,
+		table.Price.PurchaseLimit,
+		table.Price.MinimumQuantity,
+		table.Price.MinimumPurchaseAmount,
+		table.Price.EffectivePrice,
+	}
+}
+
+// Sets the deal columns of `price` and computes its effective price
+func applyPriceDeal(price *model.Price, deal utils.PriceDeal) {
+	price.DealType = nil
+	if deal.Type != "" {
+		var deal_type model.PriceDealType
+		if err := deal_type.Scan(deal.Type); err == nil {
+			price.DealType = &deal_type
+		}
+	}
+	price.DealQuantity = int32Ptr(deal.Quantity)
+	price.DealPrice = deal.Price
+	price.DealFreeQuantity = int32Ptr(deal.FreeQuantity)
+	price.DealDiscountPercent = deal.DiscountPercent
+	price.DealDiscountAmount = deal.DiscountAmount
+	price.RequiresMembership = deal.RequiresMembership
+	price.PurchaseLimit = int32Ptr(deal.PurchaseLimit)
+	price.MinimumQuantity = int32Ptr(deal.MinimumQuantity)
+	price.MinimumPurchaseAmount = deal.MinimumPurchaseAmount
+	effective_price := deal.EffectiveUnitPrice(price.Amount)
+	price.EffectivePrice = &effective_price
+}
+
+// Parses the conditions of prices created before deals were structured.
+// Only prices without an effective price are updated, so it's safe to run more than once
+func (s Service) BackfillPriceDeals(ctx context.Context) (updated int, err error) {
+	for {
+		qb := table.Price.
+			SELECT(table.Price.ID, table.Price.Amount, table.Price.Condition).
+			FROM(table.Price).
+			WHERE(table.Price.EffectivePrice.IS_NULL()).
+			ORDER_BY(table.Price.ID.ASC()).
+			LIMIT(PRICE_DEAL_BACKFILL_BATCH_SIZE)
+		var prices []model.Price
+		if err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &prices); err != nil {
+			return updated, err
+		}
+		if len(prices) == 0 {
+			return updated, nil
+		}
+		for _, price := range prices {
+			deal := utils.PriceDeal{}
+			if price.Condition != nil {
+				deal = utils.ParsePriceDeal(*price.Condition)
+			}
+			applyPriceDeal(&price, deal)
+			update_qb := table.Price.
+				UPDATE(priceDealColumns()).
+				MODEL(price).
+				WHERE(table.Price.ID.EQ(postgres.Int(price.ID)))
+			if _, err = update_qb.ExecContext(ctx, s.DbOrTxExecutable()); err != nil {
+				return updated, err
+			}
+			updated++
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/utils"
+)
+
+// Max difference between the submitted amount and an amount on the evidence photo
+const PRICE_EVIDENCE_TOLERANCE = 0.01
+
+func priceEvidencePublicId(price_id int64) string {
+	return fmt.Sprintf("price_%d", price_id)
+}
+
+// Evidence photos are compared with all photos of the same stock,
+// so an old photo can't be reused to back a new price
+func PriceEvidenceHashScope(stock_id int64) string {
+	return fmt.Sprintf("price_stock:%d", stock_id)
+}
+
+func (s Service) FindPriceById(ctx context.Context, id int64) (gmodel.Price, error) {
+	created_by_user, updated_by_user, user_cols := s.CreatedAndUpdatedUserTable()
+	qb := table.Price.
+		SELECT(table.Price.AllColumns, user_cols...).
+		FROM(table.Price.
+			LEFT_JOIN(created_by_user, created_by_user.ID.EQ(table.Price.CreatedByID)).
+			LEFT_JOIN(updated_by_user, updated_by_user.ID.EQ(table.Price.UpdatedByID)),
+		).
+		WHERE(table.Price.ID.EQ(postgres.Int(id)))
+	var price gmodel.Price
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &price); err != nil {
+		return gmodel.Price{}, err
+	}
+	return price, nil
+}
+
+// Uploads the evidence photo and links it to the price
+func (s Service) savePriceEvidence(ctx context.Context, price gmodel.Price, image utils.ProcessedImage) (gmodel.Price, error) {
+	upload_params := ImageUploadParams{
+		PublicID: priceEvidencePublicId(price.ID),
+		Tags: []string{"PRICE"},
+		HashScope: PriceEvidenceHashScope(price.StockID),
+		RejectDuplicates: true,
+	}
+	if _, err := s.ProcessedImageUpload(ctx, image, upload_params); err != nil {
+		return gmodel.Price{}, fmt.Errorf("could not upload price image: %w", err)
+	}
+	image_url := s.ImageUrl(upload_params.PublicID)
+	qb := table.Price.
+		UPDATE(table.Price.ImageID, table.Price.ImageURL).
+		MODEL(model.Price{
+			ImageID: &upload_params.PublicID,
+			ImageURL: &image_url,
+		}).
+		WHERE(table.Price.ID.EQ(postgres.Int(price.ID))).
+		RETURNING(table.Price.AllColumns)
+	var updated_price gmodel.Price
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &updated_price); err != nil {
+		return gmodel.Price{}, err
+	}
+	return updated_price, nil
+}
+
+// Reads the amounts on the evidence photo and flags the price for review
+// if none of them match the submitted amount. Photos without any readable amount are not flagged
+func (s Service) CheckPriceEvidence(ctx context.Context, price gmodel.Price, image []byte) error {
+	ocr_data, err := s.GoogleVisionOcrData(ctx, image)
+	if err != nil {
+		return fmt.Errorf("ocr error: %w", err)
+	}
+	amounts := utils.ExtractPriceAmounts(ocr_data)
+	if len(amounts) == 0 {
+		return nil
+	}
+
+	closest := amounts[0]
+	for _, amount := range amounts[1:] {
+		if math.Abs(amount - price.Amount) < math.Abs(closest - price.Amount) {
+			closest = amount
+		}
+	}
+	columns := postgres.ColumnList{table.Price.OcrAmount}
+	update := model.Price{ OcrAmount: &closest }
+	if math.Abs(closest - price.Amount) > PRICE_EVIDENCE_TOLERANCE {
+		status := model.PriceReviewStatus_Flagged
+		reason := fmt.Sprintf("evidence photo shows %.2f but %.2f was submitted", closest, price.Amount)
+		columns = append(columns, table.Price.ReviewStatus, table.Price.ReviewReason)
+		update.ReviewStatus = &status
+		update.ReviewReason = &reason
+	}
+	qb := table.Price.
+		UPDATE(columns).
+		MODEL(update).
+		WHERE(table.Price.ID.EQ(postgres.Int(price.ID)))
+	_, err = qb.ExecContext(ctx, s.DbOrTxExecutable())
+	return err
+}
+
+func (s Service) PaginatedPriceReviewQueue(
+	ctx context.Context,
+	paginator_input gmodel.PaginatorInput,
+	status *gmodel.PriceReviewStatus,
+) (res gmodel.PaginatedPriceHistory, err error) {
+	review_status := gmodel.PriceReviewStatusFlagged
+	if status != nil {
+		review_status = *status
+	}
+	created_by_user, updated_by_user, user_cols := s.CreatedAndUpdatedUserTable()
+	tables := table.Price.
+		LEFT_JOIN(created_by_user, created_by_user.ID.EQ(table.Price.CreatedByID)).
+		LEFT_JOIN(updated_by_user, updated_by_user.ID.EQ(table.Price.UpdatedByID))
+	where_clause := table.Price.ReviewStatus.EQ(postgres.NewEnumValue(review_status.String()))
+	sql_paginator, err := s.Paginate(ctx, paginator_input, tables, table.Price.ID, where_clause)
+	if err != nil {
+		return gmodel.PaginatedPriceHistory{
+			Prices: []*gmodel.Price{},
+			Paginator: &gmodel.Paginator{},
+		}, nil
+	}
+
+	qb := table.Price.
+		SELECT(table.Price.AllColumns, user_cols...).
+		FROM(tables).
+		WHERE(where_clause).
+		ORDER_BY(table.Price.CreatedAt.DESC()).
+		LIMIT(int64(sql_paginator.Limit)).
+		OFFSET(int64(sql_paginator.Offset))
+	if err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &res.Prices); err != nil {
+		return gmodel.PaginatedPriceHistory{}, err
+	}
+	res.Paginator = &sql_paginator.Paginator
+	return res, nil
+}
+
+// Approves or rejects a price. A rejected price is replaced by the fallback price of the stock
+// (see `FallbackStockPrice`) if it's the latest price or latest member price of the stock.
+// The stock has no price if there is none
+func (s Service) ReviewPrice(
+	ctx context.Context,
+	user gmodel.User,
+	id int64,
+	approve bool,
+	reason *string,
+) (price gmodel.Price, err error) {
+	price, err = s.FindPriceById(ctx, id)
+	if err != nil {
+		return gmodel.Price{}, fmt.Errorf("price not found")
+	}
+	status := model.PriceReviewStatus_Approved
+	if !approve {
+		status = model.PriceReviewStatus_Rejected
+	}
+
+	if s.TX, err = s.DB.BeginTx(ctx, nil); err != nil {
+		return gmodel.Price{}, err
+	}
+	defer s.TX.Rollback()
+
+	now := time.Now()
+	qb := table.Price.
+		UPDATE(
+			table.Price.ReviewStatus,
+			table.Price.ReviewReason,
+			table.Price.ReviewedByID,
+			table.Price.ReviewedAt,
+		).
+		MODEL(model.Price{
+			ReviewStatus: &status,
+			ReviewReason: reason,
+			ReviewedByID: &user.ID,
+			ReviewedAt: &now,
+		}).
+		WHERE(table.Price.ID.EQ(postgres.Int(price.ID)))
+	if _, err = qb.ExecContext(ctx, s.TX); err != nil {
+		return gmodel.Price{}, err
+	}
+
+	if status == model.PriceReviewStatus_Rejected {
+		// member prices can also be the latest price of stocks without a regular price
+		if _, _, err = s.replaceStockPrice(ctx, price.StockID, price.ID, false, false, &user.ID); err != nil {
+			return gmodel.Price{}, err
+		}
+		if price.MemberOnly {
+			if _, _, err = s.replaceStockPrice(ctx, price.StockID, price.ID, true, false, &user.ID); err != nil {
+				return gmodel.Price{}, err
+			}
+		}
+	}
+	if err = s.TX.Commit(); err != nil {
+		return gmodel.Price{}, err
+	}
+	s.TX = nil
+	return s.FindPriceById(ctx, price.ID)
+}
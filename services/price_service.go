@@ -3,12 +3,14 @@ package services
 import (
 	"context"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/go-jet/jet/v2/postgres"
 	"github.com/pricetra/api/database/jet/postgres/public/model"
 	"github.com/pricetra/api/database/jet/postgres/public/table"
 	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/utils"
 
 	expo "github.com/oliveroneill/exponent-server-sdk-golang/sdk"
 )
@@ -17,6 +19,22 @@ func (s Service) CreatePrice(ctx context.Context, user gmodel.User, input gmodel
 	if err = s.StructValidator.StructCtx(ctx, input); err != nil {
 		return gmodel.Price{}, fmt.Errorf("invalid input: %w", err)
 	}
+	deal := utils.PriceDeal{}
+	if input.Deal != nil {
+		if deal, err = PriceDealFromInput(*input.Deal); err != nil {
+			return gmodel.Price{}, err
+		}
+		if input.Condition == nil && !deal.IsEmpty() {
+			condition := deal.Description()
+			input.Condition = &condition
+		}
+	} else if input.Condition != nil {
+		deal = utils.ParsePriceDeal(*input.Condition)
+	}
+	evidence, err := s.PrepareImage(input.ImageFile, nil, false)
+	if err != nil {
+		return gmodel.Price{}, fmt.Errorf("invalid price image: %w", err)
+	}
 
 	if s.TX, err = s.DB.BeginTx(ctx, nil); err != nil {
 		return gmodel.Price{}, fmt.Errorf("could not begin transaction")
@@ -81,10 +99,48 @@ func (s Service) CreatePrice(ctx context.Context, user gmodel.User, input gmodel
 	}
 
 
+	member_only := deal.RequiresMembership || (input.MemberOnly != nil && *input.MemberOnly)
+	if input.LoyaltyProgramID != nil {
+		program, err := s.FindLoyaltyProgramById(ctx, *input.LoyaltyProgramID)
+		if err != nil || program.StoreID != branch.StoreID {
+			return gmodel.Price{}, fmt.Errorf("loyalty program does not belong to the store")
+		}
+		member_only = true
+	}
+
 	currency_code := "USD"
 	if input.CurrencyCode != nil {
 		currency_code = *input.CurrencyCode
 	}
+	// images uploaded by the client are referenced by their public id
+	var image_url *string
+	if input.ImageID != nil {
+		url := s.ImageUrl(*input.ImageID)
+		image_url = &url
+	}
+	price_model := model.Price{
+		Amount: input.Amount,
+		CurrencyCode: currency_code,
+		ProductID: product.ID,
+		StoreID: branch.StoreID,
+		BranchID: branch.ID,
+		StockID: stock.ID,
+		Sale: input.Sale,
+		OriginalPrice: input.OriginalPrice,
+		Condition: input.Condition,
+		UnitType: input.UnitType,
+		ImageID: input.ImageID,
+		ImageURL: image_url,
+		ExpiresAt: input.ExpiresAt,
+		Official: s.HasStoreRole(ctx, user, branch.StoreID, gmodel.StoreRoleStaff),
+		MemberOnly: member_only,
+		LoyaltyProgramID: input.LoyaltyProgramID,
+		CreatedByID: &user.ID,
+		UpdatedByID: &user.ID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	applyPriceDeal(&price_model, deal)
 	qb := table.Price.INSERT(
 		table.Price.Amount,
 		table.Price.CurrencyCode,
@@ -97,42 +153,70 @@ func (s Service) CreatePrice(ctx context.Context, user gmodel.User, input gmodel
 		table.Price.Condition,
 		table.Price.UnitType,
 		table.Price.ImageID,
+		table.Price.ImageURL,
 		table.Price.ExpiresAt,
+		table.Price.Official,
 		table.Price.CreatedByID,
 		table.Price.UpdatedByID,
 		table.Price.CreatedAt,
 		table.Price.UpdatedAt,
-	).MODEL(model.Price{
-		Amount: input.Amount,
-		CurrencyCode: currency_code,
-		ProductID: product.ID,
-		StoreID: branch.StoreID,
-		BranchID: branch.ID,
-		StockID: stock.ID,
-		Sale: input.Sale,
-		OriginalPrice: input.OriginalPrice,
-		Condition: input.Condition,
-		UnitType: input.UnitType,
-		ImageID: input.ImageID,
-		ExpiresAt: input.ExpiresAt,
-		CreatedByID: &user.ID,
-		UpdatedByID: &user.ID,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}).RETURNING(table.Price.AllColumns)
+		table.Price.MemberOnly,
+		table.Price.LoyaltyProgramID,
+		priceDealColumns(),
+	).MODEL(price_model).RETURNING(table.Price.AllColumns)
 	if err = qb.QueryContext(ctx, s.TX, &price); err != nil {
 		return gmodel.Price{}, err
 	}
+	if evidence != nil {
+		if price, err = s.savePriceEvidence(ctx, price, *evidence); err != nil {
+			return gmodel.Price{}, err
+		}
+		// the uploaded photo would be orphaned if the transaction doesn't commit
+		defer func(public_id string) {
+			if s.TX == nil {
+				return
+			}
+			cleanup := s
+			cleanup.TX = nil
+			if err := cleanup.DeleteImageUpload(ctx, public_id); err != nil {
+				log.Printf("could not delete evidence photo %s. %s\n", public_id, err.Error())
+			}
+		}(*price.ImageID)
+	}
 
-	if _, err := s.UpdateStockWithLatestPrice(ctx, user, stock.ID, price.ID); err != nil {
-		return gmodel.Price{}, fmt.Errorf("could not update stock with latest price")
+	if price.MemberOnly {
+		var latest_member_price *gmodel.Price
+		if stock.LatestMemberPriceID != nil {
+			if p, err := s.FindPriceById(ctx, *stock.LatestMemberPriceID); err == nil {
+				latest_member_price = &p
+			}
+		}
+		if s.ShouldReplaceLatestPrice(ctx, user, price, latest_member_price) {
+			if _, err := s.UpdateStockWithLatestMemberPrice(ctx, user, stock.ID, price.ID); err != nil {
+				return gmodel.Price{}, fmt.Errorf("could not update stock with latest member price")
+			}
+		}
+	}
+	// member prices are only used as the latest price of stocks without a regular price
+	if !price.MemberOnly || stock.LatestPrice == nil {
+		if s.ShouldReplaceLatestPrice(ctx, user, price, stock.LatestPrice) {
+			if _, err := s.UpdateStockWithLatestPrice(ctx, user, stock.ID, price.ID); err != nil {
+				return gmodel.Price{}, fmt.Errorf("could not update stock with latest price")
+			}
+		}
 	}
 	if err = s.TX.Commit(); err != nil {
 		return gmodel.Price{}, fmt.Errorf("could not commit transaction")
 	}
+	s.TX = nil
+
+	if evidence != nil && s.GoogleVisionApiClient != nil {
+		s.EnqueueJobOrLog(ctx, JOB_PRICE_EVIDENCE_CHECK, PriceEvidenceCheckJob{ PriceID: price.ID }, EnqueueJobOptions{})
+	}
 	return price, nil
 }
 
+// Latest regular price of the product. Member prices are left out
 func (s Service) LatestPriceForProduct(ctx context.Context, product_id int64, branch_id int64) (price gmodel.Price, err error) {
 	qb := table.Price.
 		SELECT(table.Price.AllColumns).
@@ -141,6 +225,7 @@ func (s Service) LatestPriceForProduct(ctx context.Context, product_id int64, br
 			postgres.AND(
 				table.Price.ProductID.EQ(postgres.Int(product_id)),
 				table.Price.BranchID.EQ(postgres.Int(branch_id)),
+				table.Price.MemberOnly.IS_FALSE(),
 			),
 		).
 		ORDER_BY(table.Price.ID.DESC()).
@@ -193,7 +278,17 @@ func (s Service) SendPriceChangePushNotifications(ctx context.Context, users []g
 		"priceSale": fmt.Sprint(new_price.Sale),
 	}
 	var title, body string
-	if new_price.Sale {
+	if new_price.MemberOnly {
+		title = "Member price reported on your watched product"
+		body = fmt.Sprintf(
+			"%s is reported to be $%.2f for loyalty members",
+			product.Name,
+			new_price.Amount,
+		)
+		if deal := PriceDealFromPrice(new_price); deal.Type != "" {
+			body += fmt.Sprintf(" (%s)", deal.Description())
+		}
+	} else if new_price.Sale {
 		title = "Sale reported on your watched product"
 		body = fmt.Sprintf(
 			"%s is reported to be on sale $%.2f",
@@ -203,7 +298,9 @@ func (s Service) SendPriceChangePushNotifications(ctx context.Context, users []g
 		if new_price.ExpiresAt != nil {
 			body += fmt.Sprintf(". Valid until %s", new_price.ExpiresAt.Format("January 1"))
 		}
-		if new_price.Condition != nil {
+		if deal := PriceDealFromPrice(new_price); !deal.IsEmpty() {
+			body += fmt.Sprintf(" (%s)", deal.Description())
+		} else if new_price.Condition != nil {
 			body += fmt.Sprint("*", *new_price.Condition)
 		}
 	} else if new_price.Amount > old_price.Amount {
@@ -227,6 +324,17 @@ func (s Service) SendPriceChangePushNotifications(ctx context.Context, users []g
 		return []expo.PushResponse{}, nil
 	}
 
+	return s.publishPushNotifications(ctx, users, title, body, data)
+}
+
+// Sends the same notification to all users with a push token
+func (s Service) publishPushNotifications(
+	ctx context.Context,
+	users []gmodel.User,
+	title string,
+	body string,
+	data map[string]string,
+) (res []expo.PushResponse, err error) {
 	notifications := []expo.PushMessage{}
 	for _, user := range users {
 		if user.ExpoPushToken == nil {
@@ -240,6 +348,9 @@ func (s Service) SendPriceChangePushNotifications(ctx context.Context, users []g
 			Data: data,
 		})
 	}
+	if len(notifications) == 0 {
+		return []expo.PushResponse{}, nil
+	}
 	res, err = s.ExpoPushClient.PublishMultiple(notifications)
 	if err != nil {
 		s.CreatePushNotificationEntry(ctx, notifications, expo.PushResponse{
@@ -0,0 +1,431 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/utils"
+)
+
+// Field name used to approve the proposed image
+const PRODUCT_EDIT_PROPOSAL_IMAGE_FIELD = "image"
+
+// Edits from users that aren't admins or trusted contributors must be moderated
+func (s Service) RequiresProductEditProposal(user gmodel.User) bool {
+	return !s.IsRoleAuthorized(gmodel.UserRoleAdmin, user.Role) && !s.IsTrustedContributor(user)
+}
+
+func productEditProposalImageId(proposal_id int64) string {
+	return fmt.Sprintf("product_edit_proposal_%d", proposal_id)
+}
+
+func productWeightString(product gmodel.Product) *string {
+	if product.WeightValue == nil || product.WeightType == nil {
+		return nil
+	}
+	weight := fmt.Sprintf("%s %s", strconv.FormatFloat(*product.WeightValue, 'f', -1, 64), *product.WeightType)
+	return &weight
+}
+
+// Returns the fields of `input` that differ from the product along with the current values of those fields
+func productEditChanges(product gmodel.Product, input gmodel.UpdateProduct) (changes gmodel.UpdateProduct, old_data gmodel.UpdateProduct) {
+	if input.Name != nil && *input.Name != product.Name {
+		changes.Name, old_data.Name = input.Name, &product.Name
+	}
+	if input.Description != nil && *input.Description != product.Description {
+		changes.Description, old_data.Description = input.Description, &product.Description
+	}
+	if input.Brand != nil && *input.Brand != product.Brand {
+		changes.Brand, old_data.Brand = input.Brand, &product.Brand
+	}
+	if input.Code != nil && *input.Code != product.Code {
+		changes.Code, old_data.Code = input.Code, &product.Code
+	}
+	if input.CategoryID != nil && *input.CategoryID != product.CategoryID {
+		changes.CategoryID, old_data.CategoryID = input.CategoryID, &product.CategoryID
+	}
+	if input.Weight != nil {
+		weight, err := utils.ParseWeightIntoStruct(*input.Weight)
+		if err != nil ||
+			product.WeightValue == nil ||
+			product.WeightType == nil ||
+			weight.Weight != *product.WeightValue ||
+			weight.WeightType != *product.WeightType {
+			changes.Weight, old_data.Weight = input.Weight, productWeightString(product)
+		}
+	}
+	if input.QuantityValue != nil && *input.QuantityValue != product.QuantityValue {
+		changes.QuantityValue, old_data.QuantityValue = input.QuantityValue, &product.QuantityValue
+	}
+	if input.QuantityType != nil && *input.QuantityType != product.QuantityType {
+		changes.QuantityType, old_data.QuantityType = input.QuantityType, &product.QuantityType
+	}
+	return changes, old_data
+}
+
+// Keeps only the selected fields of the proposed changes
+func filterProductEditChanges(changes gmodel.UpdateProduct, fields []string) (filtered gmodel.UpdateProduct, err error) {
+	var values map[string]any
+	raw, err := json.Marshal(changes)
+	if err != nil {
+		return gmodel.UpdateProduct{}, err
+	}
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return gmodel.UpdateProduct{}, err
+	}
+	for key := range values {
+		if !slices.Contains(fields, key) {
+			delete(values, key)
+		}
+	}
+	raw, err = json.Marshal(values)
+	if err != nil {
+		return gmodel.UpdateProduct{}, err
+	}
+	err = json.Unmarshal(raw, &filtered)
+	return filtered, err
+}
+
+// Field names with proposed values
+func productEditProposalFields(proposal gmodel.ProductEditProposal) ([]string, error) {
+	var values map[string]any
+	if err := json.Unmarshal([]byte(proposal.Changes), &values); err != nil {
+		return nil, err
+	}
+	fields := []string{}
+	for key := range values {
+		fields = append(fields, key)
+	}
+	if proposal.Image != nil {
+		fields = append(fields, PRODUCT_EDIT_PROPOSAL_IMAGE_FIELD)
+	}
+	return fields, nil
+}
+
+func (s Service) CreateProductEditProposal(
+	ctx context.Context,
+	user gmodel.User,
+	product_id int64,
+	input gmodel.UpdateProduct,
+) (gmodel.ProductEditProposal, error) {
+	if err := s.StructValidator.StructCtx(ctx, input); err != nil {
+		return gmodel.ProductEditProposal{}, err
+	}
+	product, err := s.FindProductById(ctx, product_id)
+	if err != nil {
+		return gmodel.ProductEditProposal{}, fmt.Errorf("product with id does not exist")
+	}
+
+	changes, old_data := productEditChanges(product, input)
+	has_image := input.ImageFile != nil || input.ImageBase64 != nil
+	if changes == (gmodel.UpdateProduct{}) && !has_image {
+		return gmodel.ProductEditProposal{}, fmt.Errorf("no changes proposed")
+	}
+	if changes.Code != nil && s.BarcodeUsedByOtherProduct(ctx, *changes.Code, product.ID) {
+		return gmodel.ProductEditProposal{}, fmt.Errorf("new barcode is already in use")
+	}
+	if changes.Weight != nil {
+		if _, err := utils.ParseWeightIntoStruct(*changes.Weight); err != nil {
+			return gmodel.ProductEditProposal{}, fmt.Errorf("invalid weight format: %w", err)
+		}
+	}
+	processed_image, err := s.PrepareImage(input.ImageFile, input.ImageBase64, false)
+	if err != nil {
+		return gmodel.ProductEditProposal{}, err
+	}
+	changes_json, err := toJsonString(changes)
+	if err != nil {
+		return gmodel.ProductEditProposal{}, err
+	}
+	old_data_json, err := toJsonString(old_data)
+	if err != nil {
+		return gmodel.ProductEditProposal{}, err
+	}
+
+	qb := table.ProductEditProposal.
+		INSERT(
+			table.ProductEditProposal.ProductID,
+			table.ProductEditProposal.UserID,
+			table.ProductEditProposal.Changes,
+			table.ProductEditProposal.OldData,
+		).
+		MODEL(model.ProductEditProposal{
+			ProductID: product.ID,
+			UserID: user.ID,
+			Changes: *changes_json,
+			OldData: *old_data_json,
+		}).
+		RETURNING(table.ProductEditProposal.ID)
+	var proposal model.ProductEditProposal
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &proposal); err != nil {
+		return gmodel.ProductEditProposal{}, err
+	}
+
+	if processed_image != nil {
+		upload_params := ImageUploadParams{
+			PublicID: productEditProposalImageId(proposal.ID),
+			Tags: []string{"PRODUCT_EDIT_PROPOSAL"},
+		}
+		if _, upload_err := s.ProcessedImageUpload(ctx, *processed_image, upload_params); upload_err != nil {
+			log.Printf("could not upload image for product edit proposal %d. %s\n", proposal.ID, upload_err.Error())
+		} else {
+			image := s.ImageUrl(upload_params.PublicID)
+			update_qb := table.ProductEditProposal.
+				UPDATE(table.ProductEditProposal.Image).
+				SET(postgres.String(image)).
+				WHERE(table.ProductEditProposal.ID.EQ(postgres.Int(proposal.ID)))
+			if _, err := update_qb.ExecContext(ctx, s.DbOrTxExecutable()); err != nil {
+				return gmodel.ProductEditProposal{}, err
+			}
+		}
+	}
+	return s.FindProductEditProposalById(ctx, proposal.ID)
+}
+
+func productEditProposalTable() postgres.ReadableTable {
+	return table.ProductEditProposal.
+		INNER_JOIN(table.User, table.User.ID.EQ(table.ProductEditProposal.UserID)).
+		INNER_JOIN(table.Product, table.Product.ID.EQ(table.ProductEditProposal.ProductID)).
+		INNER_JOIN(table.Category, table.Category.ID.EQ(table.Product.CategoryID))
+}
+
+func productEditProposalColumns() postgres.ProjectionList {
+	return postgres.ProjectionList{
+		table.ProductEditProposal.AllColumns,
+		table.User.ID,
+		table.User.Name,
+		table.User.Avatar,
+		table.User.Active,
+		table.Product.AllColumns,
+		table.Category.AllColumns,
+	}
+}
+
+func (s Service) FindProductEditProposalById(ctx context.Context, id int64) (proposal gmodel.ProductEditProposal, err error) {
+	qb := table.ProductEditProposal.
+		SELECT(productEditProposalColumns()).
+		FROM(productEditProposalTable()).
+		WHERE(table.ProductEditProposal.ID.EQ(postgres.Int(id))).
+		LIMIT(1)
+	err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &proposal)
+	return proposal, err
+}
+
+func (s Service) PaginatedProductEditProposalsByUser(
+	ctx context.Context,
+	paginator_input gmodel.PaginatorInput,
+	user gmodel.User,
+) (gmodel.PaginatedProductEditProposals, error) {
+	where_clause := table.ProductEditProposal.UserID.EQ(postgres.Int(user.ID))
+	return s.paginatedProductEditProposals(ctx, paginator_input, where_clause)
+}
+
+// Returns proposals with the given status (defaults to PENDING)
+func (s Service) PaginatedProductEditProposals(
+	ctx context.Context,
+	paginator_input gmodel.PaginatorInput,
+	status *gmodel.ProductEditProposalStatus,
+	product_id *int64,
+) (gmodel.PaginatedProductEditProposals, error) {
+	proposal_status := gmodel.ProductEditProposalStatusPending
+	if status != nil {
+		proposal_status = *status
+	}
+	where_clause := table.ProductEditProposal.Status.EQ(postgres.NewEnumValue(proposal_status.String()))
+	if product_id != nil {
+		where_clause = where_clause.AND(table.ProductEditProposal.ProductID.EQ(postgres.Int(*product_id)))
+	}
+	return s.paginatedProductEditProposals(ctx, paginator_input, where_clause)
+}
+
+func (s Service) paginatedProductEditProposals(
+	ctx context.Context,
+	paginator_input gmodel.PaginatorInput,
+	where_clause postgres.BoolExpression,
+) (res gmodel.PaginatedProductEditProposals, err error) {
+	my_table := productEditProposalTable()
+	paginator, err := s.Paginate(ctx, paginator_input, my_table, table.ProductEditProposal.ID, where_clause)
+	if err != nil {
+		return gmodel.PaginatedProductEditProposals{
+			Data: []*gmodel.ProductEditProposal{},
+			Paginator: &gmodel.Paginator{},
+		}, nil
+	}
+
+	qb := table.ProductEditProposal.
+		SELECT(productEditProposalColumns()).
+		FROM(my_table).
+		WHERE(where_clause).
+		ORDER_BY(table.ProductEditProposal.CreatedAt.ASC()).
+		LIMIT(int64(paginator.Limit)).
+		OFFSET(int64(paginator.Offset))
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &res.Data); err != nil {
+		return gmodel.PaginatedProductEditProposals{}, err
+	}
+	res.Paginator = &paginator.Paginator
+	return res, nil
+}
+
+// Approves (fully or partially) or rejects a pending proposal.
+// Approved fields are applied with `UpdateProductById` on behalf of the proposer and billed to them.
+// The review fails without applying anything if the proposal was reviewed in the meantime
+func (s Service) ReviewProductEditProposal(
+	ctx context.Context,
+	user gmodel.User,
+	id int64,
+	input gmodel.ReviewProductEditProposal,
+) (gmodel.ProductEditProposal, error) {
+	var err error
+	if s.TX, err = s.DB.BeginTx(ctx, nil); err != nil {
+		return gmodel.ProductEditProposal{}, err
+	}
+	defer s.TX.Rollback()
+
+	proposal, err := s.FindProductEditProposalById(ctx, id)
+	if err != nil {
+		return gmodel.ProductEditProposal{}, fmt.Errorf("proposal not found")
+	}
+	if proposal.Status != gmodel.ProductEditProposalStatusPending {
+		return gmodel.ProductEditProposal{}, fmt.Errorf("proposal has already been reviewed")
+	}
+
+	status := model.ProductEditProposalStatus_Rejected
+	var applied_changes *string
+	if input.Approve {
+		proposed_fields, err := productEditProposalFields(proposal)
+		if err != nil {
+			return gmodel.ProductEditProposal{}, err
+		}
+		fields := proposed_fields
+		if input.Fields != nil {
+			fields = input.Fields
+		}
+		if len(fields) == 0 {
+			return gmodel.ProductEditProposal{}, fmt.Errorf("no fields selected")
+		}
+		for _, field := range fields {
+			if !slices.Contains(proposed_fields, field) {
+				return gmodel.ProductEditProposal{}, fmt.Errorf("field %s was not proposed", field)
+			}
+		}
+		status = model.ProductEditProposalStatus_Approved
+		if len(fields) < len(proposed_fields) {
+			status = model.ProductEditProposalStatus_PartiallyApproved
+		}
+
+		if applied_changes, err = s.applyProductEditProposal(ctx, user, proposal, fields); err != nil {
+			return gmodel.ProductEditProposal{}, err
+		}
+	}
+
+	now := time.Now()
+	qb := table.ProductEditProposal.
+		UPDATE(
+			table.ProductEditProposal.Status,
+			table.ProductEditProposal.AppliedChanges,
+			table.ProductEditProposal.ReviewNotes,
+			table.ProductEditProposal.ReviewedByID,
+			table.ProductEditProposal.ReviewedAt,
+			table.ProductEditProposal.UpdatedAt,
+		).
+		MODEL(model.ProductEditProposal{
+			Status: status,
+			AppliedChanges: applied_changes,
+			ReviewNotes: input.Notes,
+			ReviewedByID: &user.ID,
+			ReviewedAt: &now,
+			UpdatedAt: now,
+		}).
+		WHERE(postgres.AND(
+			table.ProductEditProposal.ID.EQ(postgres.Int(proposal.ID)),
+			table.ProductEditProposal.Status.EQ(postgres.NewEnumValue(model.ProductEditProposalStatus_Pending.String())),
+		))
+	res, err := qb.ExecContext(ctx, s.TX)
+	if err != nil {
+		return gmodel.ProductEditProposal{}, err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return gmodel.ProductEditProposal{}, fmt.Errorf("proposal has already been reviewed")
+	}
+	if err = s.TX.Commit(); err != nil {
+		return gmodel.ProductEditProposal{}, err
+	}
+	s.TX = nil
+
+	if proposal.Image != nil {
+		s.DeleteImageUpload(ctx, productEditProposalImageId(proposal.ID))
+	}
+	if status == model.ProductEditProposalStatus_Rejected {
+		if _, err := s.RefreshUserReputation(ctx, proposal.UserID); err != nil {
+			log.Printf("could not refresh reputation for user %d. %s\n", proposal.UserID, err.Error())
+		}
+	}
+	return s.FindProductEditProposalById(ctx, proposal.ID)
+}
+
+// Applies the selected proposal fields to the product and creates the billing row for the proposer.
+// Returns the applied changes as JSON
+func (s Service) applyProductEditProposal(
+	ctx context.Context,
+	reviewer gmodel.User,
+	proposal gmodel.ProductEditProposal,
+	fields []string,
+) (*string, error) {
+	proposer, err := s.FindUserById(ctx, proposal.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("proposer not found")
+	}
+	var changes gmodel.UpdateProduct
+	if err := json.Unmarshal([]byte(proposal.Changes), &changes); err != nil {
+		return nil, err
+	}
+	applied, err := filterProductEditChanges(changes, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	product, old_product, err := s.UpdateProductById(ctx, proposer, proposal.ProductID, applied)
+	if err != nil {
+		return nil, err
+	}
+	if proposal.Image != nil && slices.Contains(fields, PRODUCT_EDIT_PROPOSAL_IMAGE_FIELD) {
+		public_id, err := s.ReplacePrimaryProductImage(ctx, proposer, product, nil, proposal.Image)
+		if err != nil {
+			return nil, fmt.Errorf("could not apply proposed image: %w", err)
+		}
+		s.RecordPrimaryProductImageRevision(ctx, proposer, product, public_id)
+	}
+
+	applied_values := map[string]any{}
+	raw, err := json.Marshal(applied)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &applied_values); err != nil {
+		return nil, err
+	}
+	if slices.Contains(fields, PRODUCT_EDIT_PROPOSAL_IMAGE_FIELD) {
+		applied_values[PRODUCT_EDIT_PROPOSAL_IMAGE_FIELD] = *proposal.Image
+	}
+
+	billing, err := s.CreateProductBilling(ctx, proposer, model.ProductBillingType_Update, product, nil, applied_values, old_product)
+	if err != nil {
+		log.Printf("could not create billing for product edit proposal %d. %s\n", proposal.ID, err.Error())
+	} else if billing.Status == model.ProductBillingStatus_Held {
+		// the edit was already moderated
+		reason := fmt.Sprintf("approved product edit proposal %d", proposal.ID)
+		if _, err := s.ReviewProductBilling(ctx, reviewer, billing.ID, gmodel.ProductBillingStatusApproved, &reason); err != nil {
+			log.Printf("could not approve billing for product edit proposal %d. %s\n", proposal.ID, err.Error())
+		}
+	}
+	return toJsonString(applied_values)
+}
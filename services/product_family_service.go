@@ -0,0 +1,345 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/utils"
+)
+
+func (s Service) CreateProductFamily(ctx context.Context, user gmodel.User, input gmodel.CreateProductFamily) (family gmodel.ProductFamily, err error) {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Brand = strings.TrimSpace(input.Brand)
+	if input.Name == "" {
+		return gmodel.ProductFamily{}, fmt.Errorf("name is required")
+	}
+	if input.CategoryID != nil && !s.CategoryExists(ctx, *input.CategoryID) {
+		return gmodel.ProductFamily{}, fmt.Errorf("invalid category id")
+	}
+	for _, product_id := range input.ProductIds {
+		if !s.ProductExists(ctx, product_id) {
+			return gmodel.ProductFamily{}, fmt.Errorf("product with id %d does not exist", product_id)
+		}
+	}
+
+	s.TX, err = s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return gmodel.ProductFamily{}, err
+	}
+	defer s.TX.Rollback()
+
+	qb := table.ProductFamily.
+		INSERT(
+			table.ProductFamily.Name,
+			table.ProductFamily.Brand,
+			table.ProductFamily.CategoryID,
+			table.ProductFamily.CreatedByID,
+			table.ProductFamily.UpdatedByID,
+		).
+		MODEL(model.ProductFamily{
+			Name: input.Name,
+			Brand: input.Brand,
+			CategoryID: input.CategoryID,
+			CreatedByID: &user.ID,
+			UpdatedByID: &user.ID,
+		}).
+		RETURNING(table.ProductFamily.AllColumns)
+	if err = qb.QueryContext(ctx, s.TX, &family); err != nil {
+		return gmodel.ProductFamily{}, err
+	}
+	for _, product_id := range input.ProductIds {
+		if _, err = s.AddProductToFamily(ctx, user, family.ID, product_id, gmodel.ProductVariantInput{}); err != nil {
+			return gmodel.ProductFamily{}, err
+		}
+	}
+
+	if err = s.TX.Commit(); err != nil {
+		return gmodel.ProductFamily{}, err
+	}
+	s.TX = nil
+	return family, nil
+}
+
+func (s Service) FindProductFamilyById(ctx context.Context, id int64) (family gmodel.ProductFamily, err error) {
+	qb := table.ProductFamily.
+		SELECT(
+			table.ProductFamily.AllColumns,
+			table.Category.AllColumns,
+		).
+		FROM(table.ProductFamily.
+			LEFT_JOIN(table.Category, table.Category.ID.EQ(table.ProductFamily.CategoryID)),
+		).
+		WHERE(table.ProductFamily.ID.EQ(postgres.Int(id))).
+		LIMIT(1)
+	err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &family)
+	return family, err
+}
+
+func (s Service) PaginatedProductFamilies(
+	ctx context.Context,
+	paginator_input gmodel.PaginatorInput,
+	search *string,
+) (res gmodel.PaginatedProductFamilies, err error) {
+	my_table := table.ProductFamily.
+		LEFT_JOIN(table.Category, table.Category.ID.EQ(table.ProductFamily.CategoryID))
+	where_clause := postgres.Bool(true)
+	if search != nil && strings.TrimSpace(*search) != "" {
+		where_clause = postgres.RawBool(
+			fmt.Sprintf(
+				"(%s || ' ' || %s) ILIKE $search",
+				utils.BuildFullTableName(table.ProductFamily.Brand),
+				utils.BuildFullTableName(table.ProductFamily.Name),
+			),
+			map[string]any{
+				"$search": fmt.Sprintf("%%%s%%", strings.TrimSpace(*search)),
+			},
+		)
+	}
+	paginator, err := s.Paginate(ctx, paginator_input, my_table, table.ProductFamily.ID, where_clause)
+	if err != nil {
+		return gmodel.PaginatedProductFamilies{
+			Data: []*gmodel.ProductFamily{},
+			Paginator: &gmodel.Paginator{},
+		}, nil
+	}
+
+	qb := table.ProductFamily.
+		SELECT(
+			table.ProductFamily.AllColumns,
+			table.Category.AllColumns,
+		).
+		FROM(my_table).
+		WHERE(where_clause).
+		ORDER_BY(table.ProductFamily.Brand.ASC(), table.ProductFamily.Name.ASC()).
+		LIMIT(int64(paginator.Limit)).
+		OFFSET(int64(paginator.Offset))
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &res.Data); err != nil {
+		return gmodel.PaginatedProductFamilies{}, err
+	}
+	res.Paginator = &paginator.Paginator
+	return res, nil
+}
+
+func (s Service) UpdateProductFamily(ctx context.Context, user gmodel.User, id int64, input gmodel.UpdateProductFamily) (gmodel.ProductFamily, error) {
+	family, err := s.FindProductFamilyById(ctx, id)
+	if err != nil {
+		return gmodel.ProductFamily{}, fmt.Errorf("product family not found")
+	}
+	if input.Name != nil {
+		if strings.TrimSpace(*input.Name) == "" {
+			return gmodel.ProductFamily{}, fmt.Errorf("name is required")
+		}
+		family.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Brand != nil {
+		family.Brand = strings.TrimSpace(*input.Brand)
+	}
+	if input.CategoryID != nil {
+		if !s.CategoryExists(ctx, *input.CategoryID) {
+			return gmodel.ProductFamily{}, fmt.Errorf("invalid category id")
+		}
+		family.CategoryID = input.CategoryID
+	}
+
+	qb := table.ProductFamily.
+		UPDATE(
+			table.ProductFamily.Name,
+			table.ProductFamily.Brand,
+			table.ProductFamily.CategoryID,
+			table.ProductFamily.UpdatedByID,
+			table.ProductFamily.UpdatedAt,
+		).
+		MODEL(model.ProductFamily{
+			Name: family.Name,
+			Brand: family.Brand,
+			CategoryID: family.CategoryID,
+			UpdatedByID: &user.ID,
+			UpdatedAt: time.Now(),
+		}).
+		WHERE(table.ProductFamily.ID.EQ(postgres.Int(id)))
+	if _, err := qb.ExecContext(ctx, s.DbOrTxExecutable()); err != nil {
+		return gmodel.ProductFamily{}, err
+	}
+	return s.FindProductFamilyById(ctx, id)
+}
+
+func (s Service) DeleteProductFamily(ctx context.Context, id int64) (gmodel.ProductFamily, error) {
+	family, err := s.FindProductFamilyById(ctx, id)
+	if err != nil {
+		return gmodel.ProductFamily{}, fmt.Errorf("product family not found")
+	}
+	qb := table.ProductFamily.
+		DELETE().
+		WHERE(table.ProductFamily.ID.EQ(postgres.Int(id)))
+	if _, err := qb.ExecContext(ctx, s.DbOrTxExecutable()); err != nil {
+		return gmodel.ProductFamily{}, err
+	}
+	return family, nil
+}
+
+// Adds the product to the family. If the product already belongs to a family
+// it is moved and its variant attributes are replaced
+func (s Service) AddProductToFamily(
+	ctx context.Context,
+	user gmodel.User,
+	family_id int64,
+	product_id int64,
+	input gmodel.ProductVariantInput,
+) (variant gmodel.ProductVariant, err error) {
+	if _, err := s.FindProductFamilyById(ctx, family_id); err != nil {
+		return gmodel.ProductVariant{}, fmt.Errorf("product family not found")
+	}
+	if !s.ProductExists(ctx, product_id) {
+		return gmodel.ProductVariant{}, fmt.Errorf("product with id does not exist")
+	}
+	pack_count := 1
+	if input.PackCount != nil {
+		if *input.PackCount < 1 {
+			return gmodel.ProductVariant{}, fmt.Errorf("pack count must be at least 1")
+		}
+		pack_count = *input.PackCount
+	}
+
+	qb := table.ProductVariant.
+		INSERT(
+			table.ProductVariant.ProductID,
+			table.ProductVariant.ProductFamilyID,
+			table.ProductVariant.Size,
+			table.ProductVariant.Flavor,
+			table.ProductVariant.PackCount,
+			table.ProductVariant.CreatedByID,
+		).
+		MODEL(model.ProductVariant{
+			ProductID: product_id,
+			ProductFamilyID: family_id,
+			Size: input.Size,
+			Flavor: input.Flavor,
+			PackCount: int32(pack_count),
+			CreatedByID: &user.ID,
+		}).
+		ON_CONFLICT(table.ProductVariant.ProductID).
+		DO_UPDATE(postgres.SET(
+			table.ProductVariant.ProductFamilyID.SET(table.ProductVariant.EXCLUDED.ProductFamilyID),
+			table.ProductVariant.Size.SET(table.ProductVariant.EXCLUDED.Size),
+			table.ProductVariant.Flavor.SET(table.ProductVariant.EXCLUDED.Flavor),
+			table.ProductVariant.PackCount.SET(table.ProductVariant.EXCLUDED.PackCount),
+		)).
+		RETURNING(table.ProductVariant.AllColumns)
+	if err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &variant); err != nil {
+		return gmodel.ProductVariant{}, err
+	}
+	return variant, nil
+}
+
+func (s Service) RemoveProductFromFamily(ctx context.Context, product_id int64) (bool, error) {
+	qb := table.ProductVariant.
+		DELETE().
+		WHERE(table.ProductVariant.ProductID.EQ(postgres.Int(product_id)))
+	res, err := qb.ExecContext(ctx, s.DbOrTxExecutable())
+	if err != nil {
+		return false, err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return false, fmt.Errorf("product does not belong to a family")
+	}
+	return true, nil
+}
+
+// Sets the unit price of the variant from the effective latest price of its stock.
+// Uses the product weight when available, otherwise the product quantity
+func setVariantUnitPrice(variant *gmodel.ProductVariant) {
+	if variant.Stock == nil || variant.Stock.LatestPrice == nil || variant.Product == nil {
+		return
+	}
+	product := variant.Product
+	amount := variant.Stock.LatestPrice.Amount
+	if variant.Stock.LatestPrice.EffectivePrice != nil {
+		amount = *variant.Stock.LatestPrice.EffectivePrice
+	}
+	var unit_price float64
+	var unit string
+	var err error
+	if product.WeightValue != nil && product.WeightType != nil {
+		unit_price, unit, err = utils.UnitPrice(amount, *product.WeightValue, *product.WeightType, variant.PackCount)
+	} else {
+		unit_price, unit, err = utils.UnitPrice(amount, float64(product.QuantityValue), product.QuantityType, variant.PackCount)
+	}
+	if err != nil {
+		return
+	}
+	variant.UnitPrice = &unit_price
+	variant.UnitPriceType = &unit
+}
+
+// Variants of the family with their latest price. When a location is given
+// the price of the nearest branch within the radius is used
+func (s Service) ProductFamilyVariants(
+	ctx context.Context,
+	family_id int64,
+	exclude_product_id *int64,
+	location *gmodel.LocationInput,
+) ([]gmodel.ProductVariant, error) {
+	where_clause := table.ProductVariant.ProductFamilyID.EQ(postgres.Int(family_id))
+	if exclude_product_id != nil {
+		where_clause = where_clause.AND(table.ProductVariant.ProductID.NOT_EQ(postgres.Int(*exclude_product_id)))
+	}
+	qb := table.ProductVariant.
+		SELECT(
+			table.ProductVariant.AllColumns,
+			table.Product.AllColumns,
+			table.Category.AllColumns,
+		).
+		FROM(table.ProductVariant.
+			INNER_JOIN(table.Product, table.Product.ID.EQ(table.ProductVariant.ProductID)).
+			INNER_JOIN(table.Category, table.Category.ID.EQ(table.Product.CategoryID)),
+		).
+		WHERE(where_clause).
+		ORDER_BY(
+			table.ProductVariant.PackCount.ASC(),
+			table.Product.WeightValue.ASC().NULLS_LAST(),
+			table.Product.ID.ASC(),
+		)
+	var variants []gmodel.ProductVariant
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &variants); err != nil {
+		return nil, err
+	}
+
+	user := s.GetAuthUserFromContext(ctx)
+	for i := range variants {
+		stocks, err := s.GetStocksForProduct(ctx, variants[i].ProductID, location)
+		if err != nil {
+			return nil, err
+		}
+		if len(stocks) > 0 {
+			variants[i].Stock = &stocks[0]
+			if err := s.ApplyViewerPrices(ctx, user, []*gmodel.Stock{variants[i].Stock}); err != nil {
+				return nil, err
+			}
+		}
+		setVariantUnitPrice(&variants[i])
+	}
+	return variants, nil
+}
+
+// Other variants of the product's family. Empty if the product has no family
+func (s Service) ProductVariants(ctx context.Context, product_id int64, location *gmodel.LocationInput) ([]gmodel.ProductVariant, error) {
+	qb := table.ProductVariant.
+		SELECT(table.ProductVariant.AllColumns).
+		FROM(table.ProductVariant).
+		WHERE(table.ProductVariant.ProductID.EQ(postgres.Int(product_id))).
+		LIMIT(1)
+	var variant []model.ProductVariant
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &variant); err != nil {
+		return nil, err
+	}
+	if len(variant) == 0 {
+		return []gmodel.ProductVariant{}, nil
+	}
+	return s.ProductFamilyVariants(ctx, variant[0].ProductFamilyID, &product_id, location)
+}
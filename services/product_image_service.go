@@ -0,0 +1,551 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/utils"
+)
+
+func newProductImagePublicId() string {
+	return fmt.Sprintf("product_image_%s", uuid.NewString())
+}
+
+// Hash scope of all images of a product, including the barcode image
+func ProductImageHashScope(product_id int64) string {
+	return fmt.Sprintf("product:%d", product_id)
+}
+
+func productImageTable() postgres.ReadableTable {
+	return table.ProductImage.
+		LEFT_JOIN(table.User, table.User.ID.EQ(table.ProductImage.UploadedByID))
+}
+
+func productImageColumns() postgres.ProjectionList {
+	return postgres.ProjectionList{
+		table.ProductImage.AllColumns,
+		table.User.ID,
+		table.User.Name,
+		table.User.Avatar,
+		table.User.Active,
+	}
+}
+
+func (s Service) FindProductImageById(ctx context.Context, id int64) (image gmodel.ProductImage, err error) {
+	qb := table.ProductImage.
+		SELECT(productImageColumns()).
+		FROM(productImageTable()).
+		WHERE(table.ProductImage.ID.EQ(postgres.Int(id))).
+		LIMIT(1)
+	err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &image)
+	return image, err
+}
+
+func (s Service) FindProductImageByPublicId(ctx context.Context, product_id int64, public_id string) (image gmodel.ProductImage, err error) {
+	qb := table.ProductImage.
+		SELECT(productImageColumns()).
+		FROM(productImageTable()).
+		WHERE(postgres.AND(
+			table.ProductImage.ProductID.EQ(postgres.Int(product_id)),
+			table.ProductImage.PublicID.EQ(postgres.String(public_id)),
+		)).
+		LIMIT(1)
+	err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &image)
+	return image, err
+}
+
+// Returns the images of the product in display order. The primary image is always first
+func (s Service) FindProductImages(ctx context.Context, product_id int64, status *gmodel.ProductImageStatus) ([]gmodel.ProductImage, error) {
+	where_clause := table.ProductImage.ProductID.EQ(postgres.Int(product_id))
+	if status != nil {
+		where_clause = where_clause.AND(table.ProductImage.Status.EQ(postgres.NewEnumValue(status.String())))
+	}
+	qb := table.ProductImage.
+		SELECT(productImageColumns()).
+		FROM(productImageTable()).
+		WHERE(where_clause).
+		ORDER_BY(
+			table.ProductImage.IsPrimary.DESC(),
+			table.ProductImage.Position.ASC(),
+			table.ProductImage.ID.ASC(),
+		)
+	images := []gmodel.ProductImage{}
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &images); err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// Returns images with the given status (defaults to PENDING), oldest first
+func (s Service) PaginatedProductImages(
+	ctx context.Context,
+	paginator_input gmodel.PaginatorInput,
+	status *gmodel.ProductImageStatus,
+) (res gmodel.PaginatedProductImages, err error) {
+	image_status := gmodel.ProductImageStatusPending
+	if status != nil {
+		image_status = *status
+	}
+	where_clause := table.ProductImage.Status.EQ(postgres.NewEnumValue(image_status.String()))
+	paginator, err := s.Paginate(ctx, paginator_input, productImageTable(), table.ProductImage.ID, where_clause)
+	if err != nil {
+		return gmodel.PaginatedProductImages{
+			Data: []*gmodel.ProductImage{},
+			Paginator: &gmodel.Paginator{},
+		}, nil
+	}
+
+	qb := table.ProductImage.
+		SELECT(productImageColumns()).
+		FROM(productImageTable()).
+		WHERE(where_clause).
+		ORDER_BY(table.ProductImage.CreatedAt.ASC()).
+		LIMIT(int64(paginator.Limit)).
+		OFFSET(int64(paginator.Offset))
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &res.Data); err != nil {
+		return gmodel.PaginatedProductImages{}, err
+	}
+	res.Paginator = &paginator.Paginator
+	return res, nil
+}
+
+func (s Service) AddProductImage(
+	ctx context.Context,
+	user gmodel.User,
+	product_id int64,
+	input gmodel.AddProductImage,
+) (gmodel.ProductImage, error) {
+	if (input.ImageFile == nil) == (input.ImageBase64 == nil) {
+		return gmodel.ProductImage{}, fmt.Errorf("either imageFile or imageBase64 is required")
+	}
+	if input.ImageBase64 != nil && !utils.IsValidBase64Image(*input.ImageBase64) {
+		return gmodel.ProductImage{}, fmt.Errorf("invalid base64 image")
+	}
+	if !s.ProductExists(ctx, product_id) {
+		return gmodel.ProductImage{}, fmt.Errorf("product with id does not exist")
+	}
+	processed_image, err := s.PrepareImage(input.ImageFile, input.ImageBase64, true)
+	if err != nil {
+		return gmodel.ProductImage{}, err
+	}
+	duplicate, err := s.FindDuplicateImage(ctx, ProductImageHashScope(product_id), processed_image.Hash, "")
+	if err != nil {
+		return gmodel.ProductImage{}, err
+	}
+	if duplicate != nil {
+		return gmodel.ProductImage{}, fmt.Errorf("product already has this image")
+	}
+
+	status := model.ProductImageStatus_Approved
+	if s.RequiresProductEditProposal(user) {
+		status = model.ProductImageStatus_Pending
+	}
+	var role model.ProductImageRole
+	if err := role.Scan(input.Role.String()); err != nil {
+		return gmodel.ProductImage{}, err
+	}
+	image, _, err := s.uploadProductImage(ctx, user, product_id, processed_image, nil, role, status)
+	if err != nil {
+		return gmodel.ProductImage{}, err
+	}
+	if status == model.ProductImageStatus_Approved {
+		if err := s.ensurePrimaryProductImage(ctx, product_id); err != nil {
+			return gmodel.ProductImage{}, err
+		}
+	}
+	return s.FindProductImageById(ctx, image.ID)
+}
+
+// Uploads the image (either `image` or `image_url`) under a new public id and adds it as the last image of the product.
+// The product image is only inserted once the upload succeeded
+func (s Service) uploadProductImage(
+	ctx context.Context,
+	user gmodel.User,
+	product_id int64,
+	image *utils.ProcessedImage,
+	image_url *string,
+	role model.ProductImageRole,
+	status model.ProductImageStatus,
+) (model.ProductImage, *ImageUploadResult, error) {
+	position_qb := table.ProductImage.
+		SELECT(postgres.MAXi(table.ProductImage.Position).AS("max_position")).
+		FROM(table.ProductImage).
+		WHERE(table.ProductImage.ProductID.EQ(postgres.Int(product_id)))
+	var max_position struct{
+		MaxPosition *int32
+	}
+	if err := position_qb.QueryContext(ctx, s.DbOrTxQueryable(), &max_position); err != nil {
+		return model.ProductImage{}, nil, err
+	}
+	var position int32
+	if max_position.MaxPosition != nil {
+		position = *max_position.MaxPosition + 1
+	}
+
+	upload_params := ImageUploadParams{
+		PublicID: newProductImagePublicId(),
+		Tags: []string{"PRODUCT", "PRODUCT_IMAGE"},
+		Thumbnail: true,
+		HashScope: ProductImageHashScope(product_id),
+	}
+	var upload_result *ImageUploadResult
+	var err error
+	if image != nil {
+		upload_result, err = s.ProcessedImageUpload(ctx, *image, upload_params)
+	} else if image_url != nil {
+		upload_result, err = s.ImageUrlUpload(ctx, *image_url, upload_params)
+	} else {
+		err = fmt.Errorf("image is required")
+	}
+	if err != nil {
+		return model.ProductImage{}, nil, fmt.Errorf("could not upload image: %w", err)
+	}
+
+	qb := table.ProductImage.
+		INSERT(
+			table.ProductImage.ProductID,
+			table.ProductImage.Role,
+			table.ProductImage.PublicID,
+			table.ProductImage.URL,
+			table.ProductImage.Position,
+			table.ProductImage.Status,
+			table.ProductImage.UploadedByID,
+		).
+		MODEL(model.ProductImage{
+			ProductID: product_id,
+			Role: role,
+			PublicID: upload_params.PublicID,
+			URL: s.ImageUrl(upload_params.PublicID),
+			Position: position,
+			Status: status,
+			UploadedByID: &user.ID,
+		}).
+		RETURNING(table.ProductImage.AllColumns)
+	var product_image model.ProductImage
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &product_image); err != nil {
+		if err := s.DeleteImageUpload(ctx, upload_params.PublicID); err != nil {
+			log.Printf("could not delete orphaned product image %s. %s\n", upload_params.PublicID, err.Error())
+		}
+		return model.ProductImage{}, nil, err
+	}
+	return product_image, upload_result, nil
+}
+
+// Uploads a new front image and makes it the primary image of the product.
+// Returns the public id of the new image
+func (s Service) ReplacePrimaryProductImage(
+	ctx context.Context,
+	user gmodel.User,
+	product gmodel.Product,
+	image *utils.ProcessedImage,
+	image_url *string,
+) (string, error) {
+	product_image, _, err := s.uploadProductImage(
+		ctx,
+		user,
+		product.ID,
+		image,
+		image_url,
+		model.ProductImageRole_Front,
+		model.ProductImageStatus_Approved,
+	)
+	if err != nil {
+		return "", err
+	}
+	err = s.setPrimaryProductImage(ctx, gmodel.ProductImage{
+		ID: product_image.ID,
+		ProductID: product_image.ProductID,
+		URL: product_image.URL,
+	})
+	if err != nil {
+		return "", err
+	}
+	return product_image.PublicID, nil
+}
+
+// Marks the image as primary and points `product.image` to it
+func (s Service) setPrimaryProductImage(ctx context.Context, image gmodel.ProductImage) error {
+	db := s.DbOrTxExecutable()
+	unset_qb := table.ProductImage.
+		UPDATE(table.ProductImage.IsPrimary).
+		SET(postgres.Bool(false)).
+		WHERE(postgres.AND(
+			table.ProductImage.ProductID.EQ(postgres.Int(image.ProductID)),
+			table.ProductImage.IsPrimary.IS_TRUE(),
+		))
+	if _, err := unset_qb.ExecContext(ctx, db); err != nil {
+		return err
+	}
+	set_qb := table.ProductImage.
+		UPDATE(table.ProductImage.IsPrimary, table.ProductImage.UpdatedAt).
+		SET(postgres.Bool(true), postgres.TimestampzT(time.Now())).
+		WHERE(table.ProductImage.ID.EQ(postgres.Int(image.ID)))
+	if _, err := set_qb.ExecContext(ctx, db); err != nil {
+		return err
+	}
+	product_qb := table.Product.
+		UPDATE(table.Product.Image).
+		SET(postgres.String(image.URL)).
+		WHERE(table.Product.ID.EQ(postgres.Int(image.ProductID)))
+	_, err := product_qb.ExecContext(ctx, db)
+	return err
+}
+
+// Promotes the first approved image (front images first) to primary if the product has no primary image.
+// Clears `product.image` if the product has no approved images left
+func (s Service) ensurePrimaryProductImage(ctx context.Context, product_id int64) error {
+	approved := gmodel.ProductImageStatusApproved
+	images, err := s.FindProductImages(ctx, product_id, &approved)
+	if err != nil {
+		return err
+	}
+	if len(images) == 0 {
+		qb := table.Product.
+			UPDATE(table.Product.Image).
+			SET(postgres.String("")).
+			WHERE(table.Product.ID.EQ(postgres.Int(product_id)))
+		_, err := qb.ExecContext(ctx, s.DbOrTxExecutable())
+		return err
+	}
+	if images[0].IsPrimary {
+		return nil
+	}
+	primary := images[0]
+	for _, image := range images {
+		if image.Role == gmodel.ProductImageRoleFront {
+			primary = image
+			break
+		}
+	}
+	return s.setPrimaryProductImage(ctx, primary)
+}
+
+func (s Service) SetPrimaryProductImage(ctx context.Context, id int64) (gmodel.ProductImage, error) {
+	image, err := s.FindProductImageById(ctx, id)
+	if err != nil {
+		return gmodel.ProductImage{}, fmt.Errorf("product image not found")
+	}
+	if image.Status != gmodel.ProductImageStatusApproved {
+		return gmodel.ProductImage{}, fmt.Errorf("only approved images can be the primary image")
+	}
+
+	s.TX, err = s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return gmodel.ProductImage{}, err
+	}
+	defer s.TX.Rollback()
+	if err := s.setPrimaryProductImage(ctx, image); err != nil {
+		return gmodel.ProductImage{}, err
+	}
+	if err := s.TX.Commit(); err != nil {
+		return gmodel.ProductImage{}, err
+	}
+	s.TX = nil
+	return s.FindProductImageById(ctx, id)
+}
+
+// Makes the image stored under the product code (the image uploaded with
+// `createProduct` and `updateProduct`) the primary image of the product
+func (s Service) SetBarcodeProductImagePrimary(ctx context.Context, user gmodel.User, product gmodel.Product) (gmodel.ProductImage, error) {
+	qb := table.ProductImage.
+		SELECT(table.ProductImage.ID).
+		FROM(table.ProductImage).
+		WHERE(postgres.AND(
+			table.ProductImage.ProductID.EQ(postgres.Int(product.ID)),
+			table.ProductImage.PublicID.EQ(postgres.String(product.Code)),
+		)).
+		LIMIT(1)
+	var existing []model.ProductImage
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &existing); err != nil {
+		return gmodel.ProductImage{}, err
+	}
+
+	image_url := s.ImageUrl(product.Code)
+	var image_id int64
+	if len(existing) > 0 {
+		image_id = existing[0].ID
+		update_qb := table.ProductImage.
+			UPDATE(table.ProductImage.URL, table.ProductImage.Status, table.ProductImage.UpdatedAt).
+			SET(
+				postgres.String(image_url),
+				postgres.NewEnumValue(model.ProductImageStatus_Approved.String()),
+				postgres.TimestampzT(time.Now()),
+			).
+			WHERE(table.ProductImage.ID.EQ(postgres.Int(image_id)))
+		if _, err := update_qb.ExecContext(ctx, s.DbOrTxExecutable()); err != nil {
+			return gmodel.ProductImage{}, err
+		}
+	} else {
+		insert_qb := table.ProductImage.
+			INSERT(
+				table.ProductImage.ProductID,
+				table.ProductImage.Role,
+				table.ProductImage.PublicID,
+				table.ProductImage.URL,
+				table.ProductImage.Status,
+				table.ProductImage.UploadedByID,
+			).
+			MODEL(model.ProductImage{
+				ProductID: product.ID,
+				Role: model.ProductImageRole_Front,
+				PublicID: product.Code,
+				URL: image_url,
+				Status: model.ProductImageStatus_Approved,
+				UploadedByID: &user.ID,
+			}).
+			RETURNING(table.ProductImage.ID)
+		var image model.ProductImage
+		if err := insert_qb.QueryContext(ctx, s.DbOrTxQueryable(), &image); err != nil {
+			return gmodel.ProductImage{}, err
+		}
+		image_id = image.ID
+	}
+
+	image, err := s.FindProductImageById(ctx, image_id)
+	if err != nil {
+		return gmodel.ProductImage{}, err
+	}
+	if err := s.setPrimaryProductImage(ctx, image); err != nil {
+		return gmodel.ProductImage{}, err
+	}
+	image.IsPrimary = true
+	return image, nil
+}
+
+func (s Service) ReviewProductImage(
+	ctx context.Context,
+	user gmodel.User,
+	id int64,
+	approve bool,
+	notes *string,
+) (gmodel.ProductImage, error) {
+	image, err := s.FindProductImageById(ctx, id)
+	if err != nil {
+		return gmodel.ProductImage{}, fmt.Errorf("product image not found")
+	}
+	if image.Status != gmodel.ProductImageStatusPending {
+		return gmodel.ProductImage{}, fmt.Errorf("product image has already been reviewed")
+	}
+
+	status := model.ProductImageStatus_Rejected
+	if approve {
+		status = model.ProductImageStatus_Approved
+	}
+	now := time.Now()
+	qb := table.ProductImage.
+		UPDATE(
+			table.ProductImage.Status,
+			table.ProductImage.ReviewNotes,
+			table.ProductImage.ReviewedByID,
+			table.ProductImage.ReviewedAt,
+			table.ProductImage.UpdatedAt,
+		).
+		MODEL(model.ProductImage{
+			Status: status,
+			ReviewNotes: notes,
+			ReviewedByID: &user.ID,
+			ReviewedAt: &now,
+			UpdatedAt: now,
+		}).
+		WHERE(table.ProductImage.ID.EQ(postgres.Int(id)))
+	if _, err := qb.ExecContext(ctx, s.DbOrTxExecutable()); err != nil {
+		return gmodel.ProductImage{}, err
+	}
+	if approve {
+		if err := s.ensurePrimaryProductImage(ctx, image.ProductID); err != nil {
+			return gmodel.ProductImage{}, err
+		}
+	} else if err := s.DeleteImageUpload(ctx, image.PublicID); err != nil {
+		log.Printf("could not delete rejected product image %d. %s\n", image.ID, err.Error())
+	}
+	return s.FindProductImageById(ctx, id)
+}
+
+// Admins can remove any image. Uploaders can only remove their own pending images
+func (s Service) RemoveProductImage(ctx context.Context, user gmodel.User, id int64) (gmodel.ProductImage, error) {
+	image, err := s.FindProductImageById(ctx, id)
+	if err != nil {
+		return gmodel.ProductImage{}, fmt.Errorf("product image not found")
+	}
+	is_uploader := image.UploadedByID != nil && *image.UploadedByID == user.ID
+	if !s.IsRoleAuthorized(gmodel.UserRoleAdmin, user.Role) &&
+		(!is_uploader || image.Status != gmodel.ProductImageStatusPending) {
+		return gmodel.ProductImage{}, fmt.Errorf("you are not allowed to remove this image")
+	}
+
+	qb := table.ProductImage.
+		DELETE().
+		WHERE(table.ProductImage.ID.EQ(postgres.Int(id)))
+	if _, err := qb.ExecContext(ctx, s.DbOrTxExecutable()); err != nil {
+		return gmodel.ProductImage{}, err
+	}
+	if image.IsPrimary {
+		if err := s.ensurePrimaryProductImage(ctx, image.ProductID); err != nil {
+			return gmodel.ProductImage{}, err
+		}
+	}
+	if err := s.DeleteImageUpload(ctx, image.PublicID); err != nil {
+		log.Printf("could not delete product image %d. %s\n", image.ID, err.Error())
+	}
+	return image, nil
+}
+
+func (s Service) ReorderProductImages(ctx context.Context, product_id int64, image_ids []int64) (images []gmodel.ProductImage, err error) {
+	images, err = s.FindProductImages(ctx, product_id, nil)
+	if err != nil {
+		return nil, err
+	}
+	product_image_ids := map[int64]bool{}
+	for _, image := range images {
+		product_image_ids[image.ID] = true
+	}
+	listed := map[int64]bool{}
+	for _, id := range image_ids {
+		if !product_image_ids[id] {
+			return nil, fmt.Errorf("image %d does not belong to the product", id)
+		}
+		if listed[id] {
+			return nil, fmt.Errorf("image %d is listed more than once", id)
+		}
+		listed[id] = true
+	}
+
+	// images are returned primary first, unlisted images keep their position order
+	sort.SliceStable(images, func(i, j int) bool {
+		return images[i].Position < images[j].Position
+	})
+	ordered_ids := append([]int64{}, image_ids...)
+	for _, image := range images {
+		if !listed[image.ID] {
+			ordered_ids = append(ordered_ids, image.ID)
+		}
+	}
+
+	s.TX, err = s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer s.TX.Rollback()
+	for position, id := range ordered_ids {
+		qb := table.ProductImage.
+			UPDATE(table.ProductImage.Position).
+			SET(postgres.Int(int64(position))).
+			WHERE(table.ProductImage.ID.EQ(postgres.Int(id)))
+		if _, err := qb.ExecContext(ctx, s.TX); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.TX.Commit(); err != nil {
+		return nil, err
+	}
+	s.TX = nil
+	return s.FindProductImages(ctx, product_id, nil)
+}
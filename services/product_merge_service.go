@@ -0,0 +1,363 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+// Minimum brand + name trigram similarity for a product to be a duplicate candidate
+const DUPLICATE_NAME_SIMILARITY_THRESHOLD = 0.6
+
+const DUPLICATE_CANDIDATES_DEFAULT_LIMIT = 10
+
+var non_digit_regex = regexp.MustCompile(`[^0-9]`)
+
+// Digits of the barcode without leading zeros. UPC-A and EAN-13 codes
+// of the same item share the same normalized barcode
+func NormalizedBarcode(code string) string {
+	return strings.TrimLeft(non_digit_regex.ReplaceAllString(code, ""), "0")
+}
+
+func normalizedBarcodeExpression() postgres.StringExpression {
+	return postgres.RawString("ltrim(regexp_replace(product.code, '[^0-9]', '', 'g'), '0')")
+}
+
+func brandNameSimilarityExpression(brand string, name string) postgres.FloatExpression {
+	return postgres.RawFloat(
+		"similarity(lower(product.brand || ' ' || product.name), lower(#brand_name))",
+		postgres.RawArgs{ "#brand_name": fmt.Sprintf("%s %s", brand, name) },
+	)
+}
+
+type duplicateRow struct {
+	NameSimilarity float64
+	BarcodeMatch bool
+}
+
+func (s Service) DuplicateProductCandidates(ctx context.Context, product_id int64, limit *int) ([]gmodel.DuplicateProductCandidate, error) {
+	product, err := s.FindProductById(ctx, product_id)
+	if err != nil {
+		return nil, fmt.Errorf("product with id does not exist")
+	}
+	max_candidates := DUPLICATE_CANDIDATES_DEFAULT_LIMIT
+	if limit != nil && *limit > 0 {
+		max_candidates = *limit
+	}
+
+	normalized_code := NormalizedBarcode(product.Code)
+	barcode_match := postgres.Bool(false)
+	if normalized_code != "" {
+		barcode_match = normalizedBarcodeExpression().EQ(postgres.String(normalized_code))
+	}
+	if product.Gtin != nil {
+		barcode_match = barcode_match.OR(table.Product.Gtin.EQ(postgres.String(*product.Gtin)))
+	}
+	similarity := brandNameSimilarityExpression(product.Brand, product.Name)
+	qb := table.Product.
+		SELECT(
+			table.Product.AllColumns,
+			table.Category.AllColumns,
+			similarity.AS("duplicate_row.name_similarity"),
+			barcode_match.AS("duplicate_row.barcode_match"),
+		).
+		FROM(table.Product.
+			INNER_JOIN(table.Category, table.Category.ID.EQ(table.Product.CategoryID)),
+		).
+		WHERE(postgres.AND(
+			table.Product.ID.NOT_EQ(postgres.Int(product.ID)),
+			postgres.OR(
+				barcode_match,
+				similarity.GT_EQ(postgres.Float(DUPLICATE_NAME_SIMILARITY_THRESHOLD)),
+			),
+		)).
+		ORDER_BY(similarity.DESC()).
+		LIMIT(int64(max_candidates) * 2)
+	var rows []struct{
+		Product gmodel.Product
+		DuplicateRow duplicateRow
+	}
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &rows); err != nil {
+		return nil, err
+	}
+
+	candidates := []gmodel.DuplicateProductCandidate{}
+	for _, row := range rows {
+		candidate := gmodel.DuplicateProductCandidate{
+			Product: &row.Product,
+			Score: row.DuplicateRow.NameSimilarity * 0.5,
+			Reasons: []string{},
+		}
+		if row.DuplicateRow.BarcodeMatch {
+			candidate.Score += 0.4
+			candidate.Reasons = append(candidate.Reasons, "same normalized barcode")
+		}
+		if row.DuplicateRow.NameSimilarity >= DUPLICATE_NAME_SIMILARITY_THRESHOLD {
+			candidate.Reasons = append(candidate.Reasons, fmt.Sprintf("%.0f%% brand and name similarity", row.DuplicateRow.NameSimilarity * 100))
+		}
+		if product.WeightValue != nil && product.WeightType != nil &&
+			row.Product.WeightValue != nil && row.Product.WeightType != nil &&
+			*product.WeightValue == *row.Product.WeightValue &&
+			*product.WeightType == *row.Product.WeightType {
+			candidate.Score += 0.1
+			candidate.Reasons = append(candidate.Reasons, "same weight")
+		}
+		candidates = append(candidates, candidate)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	if len(candidates) > max_candidates {
+		candidates = candidates[:max_candidates]
+	}
+	return candidates, nil
+}
+
+// Moves everything that references the source product to the target product and deletes the source product.
+// Source stocks at branches where the target product is already stocked are merged into the target stock
+func (s Service) MergeProducts(ctx context.Context, user gmodel.User, source_id int64, target_id int64) (gmodel.Product, error) {
+	if source_id == target_id {
+		return gmodel.Product{}, fmt.Errorf("cannot merge a product into itself")
+	}
+	source, err := s.FindProductById(ctx, source_id)
+	if err != nil {
+		return gmodel.Product{}, fmt.Errorf("source product does not exist")
+	}
+	target, err := s.FindProductById(ctx, target_id)
+	if err != nil {
+		return gmodel.Product{}, fmt.Errorf("target product does not exist")
+	}
+
+	s.TX, err = s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return gmodel.Product{}, err
+	}
+	defer s.TX.Rollback()
+
+	target_id_exp := postgres.Int(target.ID)
+	source_id_exp := postgres.Int(source.ID)
+
+	var target_stocks []model.Stock
+	target_stocks_qb := table.Stock.
+		SELECT(table.Stock.AllColumns).
+		FROM(table.Stock).
+		WHERE(table.Stock.ProductID.EQ(target_id_exp)).
+		FOR(postgres.UPDATE())
+	if err := target_stocks_qb.QueryContext(ctx, s.TX, &target_stocks); err != nil {
+		return gmodel.Product{}, err
+	}
+	target_stock_by_branch := map[int64]int64{}
+	for _, stock := range target_stocks {
+		target_stock_by_branch[stock.BranchID] = stock.ID
+	}
+
+	var source_stocks []model.Stock
+	source_stocks_qb := table.Stock.
+		SELECT(table.Stock.AllColumns).
+		FROM(table.Stock).
+		WHERE(table.Stock.ProductID.EQ(source_id_exp)).
+		FOR(postgres.UPDATE())
+	if err := source_stocks_qb.QueryContext(ctx, s.TX, &source_stocks); err != nil {
+		return gmodel.Product{}, err
+	}
+
+	for _, stock := range source_stocks {
+		target_stock_id, conflict := target_stock_by_branch[stock.BranchID]
+		if !conflict {
+			if _, err := table.Stock.
+				UPDATE(table.Stock.ProductID).
+				SET(target_id_exp).
+				WHERE(table.Stock.ID.EQ(postgres.Int(stock.ID))).
+				ExecContext(ctx, s.TX); err != nil {
+				return gmodel.Product{}, err
+			}
+			continue
+		}
+		if err := s.mergeStock(ctx, user, stock.ID, target_stock_id); err != nil {
+			return gmodel.Product{}, err
+		}
+	}
+
+	price_update := table.Price.
+		UPDATE(table.Price.ProductID).
+		SET(target_id_exp).
+		WHERE(table.Price.ProductID.EQ(source_id_exp))
+	price_res, err := price_update.ExecContext(ctx, s.TX)
+	if err != nil {
+		return gmodel.Product{}, err
+	}
+	moved_prices, _ := price_res.RowsAffected()
+
+	// source list entries already present in the same list with the same stock are dropped
+	target_list := table.ProductList.AS("target_product_list")
+	if _, err := table.ProductList.
+		DELETE().
+		WHERE(postgres.AND(
+			table.ProductList.ProductID.EQ(source_id_exp),
+			postgres.EXISTS(
+				target_list.
+					SELECT(target_list.ID).
+					WHERE(postgres.AND(
+						target_list.ProductID.EQ(target_id_exp),
+						target_list.ListID.EQ(table.ProductList.ListID),
+						target_list.StockID.IS_NOT_DISTINCT_FROM(table.ProductList.StockID),
+					)),
+			),
+		)).
+		ExecContext(ctx, s.TX); err != nil {
+		return gmodel.Product{}, err
+	}
+
+	updates := []postgres.UpdateStatement{
+		table.ProductList.
+			UPDATE(table.ProductList.ProductID).
+			SET(target_id_exp).
+			WHERE(table.ProductList.ProductID.EQ(source_id_exp)),
+		table.GroceryListItem.
+			UPDATE(table.GroceryListItem.ProductID).
+			SET(target_id_exp).
+			WHERE(table.GroceryListItem.ProductID.EQ(source_id_exp)),
+		table.ProductView.
+			UPDATE(table.ProductView.ProductID).
+			SET(target_id_exp).
+			WHERE(table.ProductView.ProductID.EQ(source_id_exp)),
+		table.ProductBilling.
+			UPDATE(table.ProductBilling.ProductID).
+			SET(target_id_exp).
+			WHERE(table.ProductBilling.ProductID.EQ(source_id_exp)),
+		table.ProductEditProposal.
+			UPDATE(table.ProductEditProposal.ProductID).
+			SET(target_id_exp).
+			WHERE(table.ProductEditProposal.ProductID.EQ(source_id_exp)),
+		// the source barcode image is deleted with the source product
+		table.ProductImage.
+			UPDATE(table.ProductImage.ProductID, table.ProductImage.IsPrimary).
+			SET(target_id_exp, postgres.Bool(false)).
+			WHERE(postgres.AND(
+				table.ProductImage.ProductID.EQ(source_id_exp),
+				table.ProductImage.PublicID.NOT_EQ(postgres.String(source.Code)),
+			)),
+		table.ImageHash.
+			UPDATE(table.ImageHash.Scope).
+			SET(postgres.String(ProductImageHashScope(target.ID))).
+			WHERE(table.ImageHash.Scope.EQ(postgres.String(ProductImageHashScope(source.ID)))),
+		// nutrition data is only kept if the target product has none
+		table.ProductNutrition.
+			UPDATE(table.ProductNutrition.ProductID).
+			SET(target_id_exp).
+			WHERE(postgres.AND(
+				table.ProductNutrition.ProductID.EQ(source_id_exp),
+				postgres.NOT(postgres.EXISTS(
+					table.ProductNutrition.
+						SELECT(table.ProductNutrition.ProductID).
+						WHERE(table.ProductNutrition.ProductID.EQ(target_id_exp)),
+				)),
+			)),
+		table.Product.
+			UPDATE(table.Product.Views, table.Product.UpdatedByID, table.Product.UpdatedAt).
+			SET(
+				table.Product.Views.ADD(postgres.Int(int64(source.Views))),
+				postgres.Int(user.ID),
+				postgres.NOW(),
+			).
+			WHERE(table.Product.ID.EQ(target_id_exp)),
+	}
+	for _, qb := range updates {
+		if _, err := qb.ExecContext(ctx, s.TX); err != nil {
+			return gmodel.Product{}, err
+		}
+	}
+
+	source_data, err := toJsonString(productRevisionData(source))
+	if err != nil {
+		return gmodel.Product{}, err
+	}
+	merge_qb := table.ProductMerge.
+		INSERT(
+			table.ProductMerge.SourceProductID,
+			table.ProductMerge.SourceCode,
+			table.ProductMerge.SourceData,
+			table.ProductMerge.TargetProductID,
+			table.ProductMerge.MergedByID,
+			table.ProductMerge.StockCount,
+			table.ProductMerge.PriceCount,
+		).
+		MODEL(model.ProductMerge{
+			SourceProductID: source.ID,
+			SourceCode: source.Code,
+			SourceData: *source_data,
+			TargetProductID: target.ID,
+			MergedByID: &user.ID,
+			StockCount: int32(len(source_stocks)),
+			PriceCount: int32(moved_prices),
+		})
+	if _, err := merge_qb.ExecContext(ctx, s.TX); err != nil {
+		return gmodel.Product{}, err
+	}
+	if _, err := table.Product.
+		DELETE().
+		WHERE(table.Product.ID.EQ(source_id_exp)).
+		ExecContext(ctx, s.TX); err != nil {
+		return gmodel.Product{}, err
+	}
+
+	if err := s.TX.Commit(); err != nil {
+		return gmodel.Product{}, err
+	}
+	s.TX = nil
+
+	s.DeleteImageUpload(ctx, source.Code)
+	return s.FindProductById(ctx, target.ID)
+}
+
+// Moves the price history of the source stock into the target stock and deletes the source stock.
+// Prices keep the source product id until they're re-pointed by `MergeProducts`.
+// The most recent price of the combined history becomes the latest price of the target stock
+func (s Service) mergeStock(ctx context.Context, user gmodel.User, source_stock_id int64, target_stock_id int64) error {
+	source_stock_exp := postgres.Int(source_stock_id)
+	target_stock_exp := postgres.Int(target_stock_id)
+	updates := []postgres.UpdateStatement{
+		table.Price.
+			UPDATE(table.Price.StockID).
+			SET(target_stock_exp).
+			WHERE(table.Price.StockID.EQ(source_stock_exp)),
+		table.ProductList.
+			UPDATE(table.ProductList.StockID).
+			SET(target_stock_exp).
+			WHERE(table.ProductList.StockID.EQ(source_stock_exp)),
+		table.ProductView.
+			UPDATE(table.ProductView.StockID).
+			SET(target_stock_exp).
+			WHERE(table.ProductView.StockID.EQ(source_stock_exp)),
+	}
+	for _, qb := range updates {
+		if _, err := qb.ExecContext(ctx, s.TX); err != nil {
+			return err
+		}
+	}
+
+	latest_price_qb := table.Price.
+		SELECT(table.Price.ID).
+		WHERE(table.Price.StockID.EQ(target_stock_exp)).
+		ORDER_BY(table.Price.CreatedAt.DESC(), table.Price.ID.DESC()).
+		LIMIT(1)
+	if _, err := table.Stock.
+		UPDATE(table.Stock.LatestPriceID, table.Stock.UpdatedByID, table.Stock.UpdatedAt).
+		SET(latest_price_qb, postgres.Int(user.ID), postgres.NOW()).
+		WHERE(table.Stock.ID.EQ(target_stock_exp)).
+		ExecContext(ctx, s.TX); err != nil {
+		return err
+	}
+	_, err := table.Stock.
+		DELETE().
+		WHERE(table.Stock.ID.EQ(source_stock_exp)).
+		ExecContext(ctx, s.TX)
+	return err
+}
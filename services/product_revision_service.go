@@ -0,0 +1,379 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+// Image uploads by the same user within this window are merged into their latest revision
+const PRODUCT_REVISION_COALESCE_WINDOW = time.Minute
+
+// Snapshot of the product fields stored on each revision
+type ProductRevisionData struct {
+	Name string `json:"name"`
+	Description string `json:"description"`
+	Brand string `json:"brand"`
+	Code string `json:"code"`
+	CategoryID int64 `json:"categoryId"`
+	WeightValue *float64 `json:"weightValue"`
+	WeightType *string `json:"weightType"`
+	QuantityValue int `json:"quantityValue"`
+	QuantityType string `json:"quantityType"`
+	Image string `json:"image"`
+}
+
+type ProductRevisionChange struct {
+	From any `json:"from"`
+	To any `json:"to"`
+}
+
+func productRevisionData(product gmodel.Product) ProductRevisionData {
+	return ProductRevisionData{
+		Name: product.Name,
+		Description: product.Description,
+		Brand: product.Brand,
+		Code: product.Code,
+		CategoryID: product.CategoryID,
+		WeightValue: product.WeightValue,
+		WeightType: product.WeightType,
+		QuantityValue: product.QuantityValue,
+		QuantityType: product.QuantityType,
+		Image: product.Image,
+	}
+}
+
+func productRevisionValues(data ProductRevisionData) map[string]any {
+	values := map[string]any{}
+	raw, _ := json.Marshal(data)
+	json.Unmarshal(raw, &values)
+	return values
+}
+
+// Returns the changed fields between two snapshots
+func productRevisionChanges(old_data ProductRevisionData, new_data ProductRevisionData) map[string]ProductRevisionChange {
+	old_values := productRevisionValues(old_data)
+	new_values := productRevisionValues(new_data)
+	changes := map[string]ProductRevisionChange{}
+	for key, new_val := range new_values {
+		if old_val := old_values[key]; !reflect.DeepEqual(old_val, new_val) {
+			changes[key] = ProductRevisionChange{ From: old_val, To: new_val }
+		}
+	}
+	return changes
+}
+
+func (s Service) LatestProductRevision(ctx context.Context, product_id int64) (*gmodel.ProductRevision, error) {
+	qb := table.ProductRevision.
+		SELECT(table.ProductRevision.AllColumns).
+		FROM(table.ProductRevision).
+		WHERE(table.ProductRevision.ProductID.EQ(postgres.Int(product_id))).
+		ORDER_BY(table.ProductRevision.Revision.DESC()).
+		LIMIT(1)
+	var revisions []gmodel.ProductRevision
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &revisions); err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, nil
+	}
+	return &revisions[0], nil
+}
+
+// Stores a snapshot of the product. Updates without any changes don't create a revision.
+// The image version of the previous revision is carried over when `image_version` is nil
+func (s Service) CreateProductRevision(
+	ctx context.Context,
+	user_id *int64,
+	product gmodel.Product,
+	action model.ProductRevisionAction,
+	image_version *string,
+	reverted_from_id *int64,
+) (gmodel.ProductRevision, error) {
+	latest, err := s.LatestProductRevision(ctx, product.ID)
+	if err != nil {
+		return gmodel.ProductRevision{}, err
+	}
+
+	data := productRevisionData(product)
+	changes := map[string]ProductRevisionChange{}
+	revision := 1
+	if latest != nil {
+		var latest_data ProductRevisionData
+		if err := json.Unmarshal([]byte(latest.Data), &latest_data); err != nil {
+			return gmodel.ProductRevision{}, err
+		}
+		changes = productRevisionChanges(latest_data, data)
+		if image_version == nil {
+			image_version = latest.ImageVersion
+		} else if latest.ImageVersion == nil || *latest.ImageVersion != *image_version {
+			changes["imageVersion"] = ProductRevisionChange{ From: latest.ImageVersion, To: *image_version }
+		}
+		revision = latest.Revision + 1
+	}
+	if latest != nil && len(changes) == 0 && action == model.ProductRevisionAction_Update {
+		return *latest, nil
+	}
+
+	data_json, err := toJsonString(data)
+	if err != nil {
+		return gmodel.ProductRevision{}, err
+	}
+	changes_json, err := toJsonString(changes)
+	if err != nil {
+		return gmodel.ProductRevision{}, err
+	}
+	qb := table.ProductRevision.
+		INSERT(
+			table.ProductRevision.ProductID,
+			table.ProductRevision.Revision,
+			table.ProductRevision.UserID,
+			table.ProductRevision.Action,
+			table.ProductRevision.Data,
+			table.ProductRevision.Changes,
+			table.ProductRevision.ImageVersion,
+			table.ProductRevision.RevertedFromID,
+		).
+		MODEL(model.ProductRevision{
+			ProductID: product.ID,
+			Revision: int32(revision),
+			UserID: user_id,
+			Action: action,
+			Data: *data_json,
+			Changes: *changes_json,
+			ImageVersion: image_version,
+			RevertedFromID: reverted_from_id,
+		}).
+		RETURNING(table.ProductRevision.AllColumns)
+	var res gmodel.ProductRevision
+	err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &res)
+	return res, err
+}
+
+// Records a new version of the image stored under the product code.
+// Merged into the user's latest revision if it was just created (i.e. the same create or update request)
+func (s Service) RecordProductImageRevision(
+	ctx context.Context,
+	user gmodel.User,
+	product gmodel.Product,
+	upload_result *ImageUploadResult,
+) (gmodel.ProductRevision, error) {
+	if upload_result == nil || upload_result.Version == 0 {
+		return gmodel.ProductRevision{}, fmt.Errorf("image was not uploaded")
+	}
+	return s.recordProductImageRevision(ctx, user, product, strconv.Itoa(upload_result.Version))
+}
+
+// Records a new primary product image. The image version of the revision is the public id of the product image
+func (s Service) RecordPrimaryProductImageRevision(
+	ctx context.Context,
+	user gmodel.User,
+	product gmodel.Product,
+	public_id string,
+) (gmodel.ProductRevision, error) {
+	return s.recordProductImageRevision(ctx, user, product, public_id)
+}
+
+func (s Service) recordProductImageRevision(
+	ctx context.Context,
+	user gmodel.User,
+	product gmodel.Product,
+	image_version string,
+) (gmodel.ProductRevision, error) {
+	latest, err := s.LatestProductRevision(ctx, product.ID)
+	if err != nil {
+		return gmodel.ProductRevision{}, err
+	}
+	if latest == nil ||
+		latest.UserID == nil ||
+		*latest.UserID != user.ID ||
+		latest.Action == gmodel.ProductRevisionActionRevert ||
+		time.Since(latest.CreatedAt) > PRODUCT_REVISION_COALESCE_WINDOW {
+		return s.CreateProductRevision(ctx, &user.ID, product, model.ProductRevisionAction_Update, &image_version, nil)
+	}
+
+	changes := map[string]ProductRevisionChange{}
+	if err := json.Unmarshal([]byte(latest.Changes), &changes); err != nil {
+		return gmodel.ProductRevision{}, err
+	}
+	if latest.Action != gmodel.ProductRevisionActionCreate {
+		change, ok := changes["imageVersion"]
+		if !ok {
+			change.From = latest.ImageVersion
+		}
+		change.To = image_version
+		changes["imageVersion"] = change
+	}
+	changes_json, err := toJsonString(changes)
+	if err != nil {
+		return gmodel.ProductRevision{}, err
+	}
+	qb := table.ProductRevision.
+		UPDATE(table.ProductRevision.ImageVersion, table.ProductRevision.Changes).
+		MODEL(model.ProductRevision{
+			ImageVersion: &image_version,
+			Changes: *changes_json,
+		}).
+		WHERE(table.ProductRevision.ID.EQ(postgres.Int(latest.ID))).
+		RETURNING(table.ProductRevision.AllColumns)
+	var res gmodel.ProductRevision
+	err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &res)
+	return res, err
+}
+
+func (s Service) FindProductRevisionById(ctx context.Context, id int64) (revision gmodel.ProductRevision, err error) {
+	qb := table.ProductRevision.
+		SELECT(table.ProductRevision.AllColumns).
+		FROM(table.ProductRevision).
+		WHERE(table.ProductRevision.ID.EQ(postgres.Int(id))).
+		LIMIT(1)
+	err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &revision)
+	return revision, err
+}
+
+func (s Service) PaginatedProductRevisions(
+	ctx context.Context,
+	paginator_input gmodel.PaginatorInput,
+	product_id int64,
+) (res gmodel.PaginatedProductRevisions, err error) {
+	my_table := table.ProductRevision.
+		LEFT_JOIN(table.User, table.User.ID.EQ(table.ProductRevision.UserID))
+	where_clause := table.ProductRevision.ProductID.EQ(postgres.Int(product_id))
+	paginator, err := s.Paginate(ctx, paginator_input, my_table, table.ProductRevision.ID, where_clause)
+	if err != nil {
+		return gmodel.PaginatedProductRevisions{
+			Data: []*gmodel.ProductRevision{},
+			Paginator: &gmodel.Paginator{},
+		}, nil
+	}
+
+	qb := table.ProductRevision.
+		SELECT(
+			table.ProductRevision.AllColumns,
+			table.User.ID,
+			table.User.Name,
+			table.User.Avatar,
+			table.User.Active,
+		).
+		FROM(my_table).
+		WHERE(where_clause).
+		ORDER_BY(table.ProductRevision.Revision.DESC()).
+		LIMIT(int64(paginator.Limit)).
+		OFFSET(int64(paginator.Offset))
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &res.Data); err != nil {
+		return gmodel.PaginatedProductRevisions{}, err
+	}
+	res.Paginator = &paginator.Paginator
+	return res, nil
+}
+
+// Restores the product fields of the revision. If the image of the revision differs from the current image
+// the product image of the revision is made primary again, or the stored version of the barcode image is re-uploaded
+func (s Service) RevertProduct(ctx context.Context, user gmodel.User, product_id int64, revision_id int64) (gmodel.Product, error) {
+	revision, err := s.FindProductRevisionById(ctx, revision_id)
+	if err != nil || revision.ProductID != product_id {
+		return gmodel.Product{}, fmt.Errorf("revision not found")
+	}
+	product, err := s.FindProductById(ctx, product_id)
+	if err != nil {
+		return gmodel.Product{}, fmt.Errorf("product with id does not exist")
+	}
+	var data ProductRevisionData
+	if err := json.Unmarshal([]byte(revision.Data), &data); err != nil {
+		return gmodel.Product{}, err
+	}
+	if data.Code != product.Code && s.BarcodeUsedByOtherProduct(ctx, data.Code, product.ID) {
+		return gmodel.Product{}, fmt.Errorf("barcode of the revision is already in use")
+	}
+	gtin, err := ParseProductCode(data.Code)
+	if err != nil {
+		return gmodel.Product{}, err
+	}
+
+	qb := table.Product.
+		UPDATE(
+			table.Product.Name,
+			table.Product.Description,
+			table.Product.Brand,
+			table.Product.Code,
+			table.Product.Gtin,
+			table.Product.CategoryID,
+			table.Product.WeightValue,
+			table.Product.WeightType,
+			table.Product.QuantityValue,
+			table.Product.QuantityType,
+			table.Product.UpdatedByID,
+			table.Product.UpdatedAt,
+		).
+		MODEL(model.Product{
+			Name: data.Name,
+			Description: data.Description,
+			Brand: data.Brand,
+			Code: data.Code,
+			Gtin: gtin,
+			CategoryID: &data.CategoryID,
+			WeightValue: data.WeightValue,
+			WeightType: data.WeightType,
+			QuantityValue: int32(data.QuantityValue),
+			QuantityType: data.QuantityType,
+			UpdatedByID: &user.ID,
+			UpdatedAt: time.Now(),
+		}).
+		WHERE(table.Product.ID.EQ(postgres.Int(product.ID))).
+		RETURNING(table.Product.AllColumns)
+	var updated_product gmodel.Product
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &updated_product); err != nil {
+		return gmodel.Product{}, err
+	}
+
+	var image_version *string
+	latest, err := s.LatestProductRevision(ctx, product.ID)
+	if err != nil {
+		return gmodel.Product{}, err
+	}
+	if revision.ImageVersion != nil && (latest == nil || latest.ImageVersion == nil || *latest.ImageVersion != *revision.ImageVersion) {
+		if _, err := strconv.Atoi(*revision.ImageVersion); err != nil {
+			// product image uploaded as the primary image
+			product_image, err := s.FindProductImageByPublicId(ctx, product.ID, *revision.ImageVersion)
+			if err != nil || product_image.Status != gmodel.ProductImageStatusApproved {
+				return gmodel.Product{}, fmt.Errorf("image of the revision no longer exists")
+			}
+			if err := s.setPrimaryProductImage(ctx, product_image); err != nil {
+				return gmodel.Product{}, err
+			}
+			image_version = revision.ImageVersion
+			updated_product.Image = product_image.URL
+		} else {
+			// version of the image stored under the product code
+			image_url := s.ImageStore.VersionUrl(data.Code, *revision.ImageVersion)
+			upload_result, err := s.ImageUrlUpload(ctx, image_url, ImageUploadParams{
+				PublicID: data.Code,
+				Tags: []string{"PRODUCT"},
+			})
+			if err != nil {
+				return gmodel.Product{}, fmt.Errorf("could not restore product image: %w", err)
+			}
+			if _, err := s.SetBarcodeProductImagePrimary(ctx, user, updated_product); err != nil {
+				return gmodel.Product{}, err
+			}
+			version := strconv.Itoa(upload_result.Version)
+			image_version = &version
+			updated_product.Image = s.ImageUrl(data.Code)
+		}
+	}
+	if _, err := s.CreateProductRevision(ctx, &user.ID, updated_product, model.ProductRevisionAction_Revert, image_version, &revision.ID); err != nil {
+		return gmodel.Product{}, err
+	}
+
+	category, _ := s.FindCategoryById(ctx, updated_product.CategoryID)
+	updated_product.Category = &category
+	return updated_product, nil
+}
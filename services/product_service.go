@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
@@ -24,6 +25,10 @@ func (s Service) CreateProduct(
 	if err := s.StructValidator.StructCtx(ctx, input); err != nil {
 		return gmodel.Product{}, err
 	}
+	gtin, err := ParseProductCode(input.Code)
+	if err != nil {
+		return gmodel.Product{}, err
+	}
 	if s.BarcodeExists(ctx, input.Code) {
 		return gmodel.Product{}, fmt.Errorf("barcode already exists in the database. please use the update method")
 	}
@@ -60,7 +65,7 @@ func (s Service) CreateProduct(
 	}
 
 	// product.image should always be pointed to the CDN with public_id == product.code 
-	image := fmt.Sprintf("%s/%s", CLOUDINARY_UPLOAD_BASE, input.Code)
+	image := s.ImageUrl(input.Code)
 
 	qb := table.Product.
 		INSERT(
@@ -77,9 +82,11 @@ func (s Service) CreateProduct(
 			table.Product.Source,
 			table.Product.CreatedByID,
 			table.Product.UpdatedByID,
+			table.Product.Gtin,
 		).
 		MODEL(struct{
 			gmodel.CreateProduct
+			Gtin *string
 			Source model.ProductSourceType
 			Image string
 			WeightValue *float64
@@ -90,6 +97,7 @@ func (s Service) CreateProduct(
 			UpdatedByID *int64
 		}{
 			CreateProduct: input,
+			Gtin: gtin,
 			Source: source_val,
 			Image: image,
 			WeightValue: weight_value,
@@ -101,6 +109,14 @@ func (s Service) CreateProduct(
 		}).
 		RETURNING(table.Product.AllColumns)
 	err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &product)
+	if err == nil {
+		if _, err := s.CreateProductRevision(ctx, &user.ID, product, model.ProductRevisionAction_Create, nil, nil); err != nil {
+			log.Printf("could not create revision for product %d. %s\n", product.ID, err.Error())
+		}
+		if _, err := s.SetBarcodeProductImagePrimary(ctx, user, product); err != nil {
+			log.Printf("could not create image for product %d. %s\n", product.ID, err.Error())
+		}
+	}
 
 	// Add category
 	category, _ := s.FindCategoryById(ctx, input.CategoryID)
@@ -134,16 +150,23 @@ func (s Service) FindProductWithCode(ctx context.Context, barcode string) (produ
 			table.Product.
 				INNER_JOIN(table.Category, table.Category.ID.EQ(table.Product.CategoryID)),
 		).
-		WHERE(table.Product.Code.EQ(postgres.String(barcode)))
+		WHERE(productCodeClause(barcode)).
+		ORDER_BY(table.Product.Code.EQ(postgres.String(barcode)).DESC()).
+		LIMIT(1)
 	
 	err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &product)
 	return product, err
 }
 
 func (s Service) BarcodeSearch(ctx context.Context, barcode string, exact bool) (product gmodel.Product, err error) {
-	where_clause := table.Product.Code.EQ(postgres.String(barcode))
+	where_clause := productCodeClause(barcode)
 	if !exact {
 		where_clause = table.Product.Code.LIKE(postgres.String(fmt.Sprintf("%%%s%%", barcode)))
+		if utils.IsNumericBarcode(barcode) {
+			where_clause = where_clause.OR(
+				table.Product.Gtin.LIKE(postgres.String(fmt.Sprintf("%%%s%%", utils.CleanBarcode(barcode)))),
+			)
+		}
 	}
 	qb := table.Product.
 		SELECT(
@@ -203,11 +226,49 @@ func (s Service) ProductSearch(ctx context.Context, paginator_input gmodel.Pagin
 	return paginated_products, nil
 }
 
+// Returns the canonical GTIN-14 of numeric barcodes. Non-numeric codes are kept as internal codes without a GTIN
+func ParseProductCode(code string) (*string, error) {
+	if !utils.IsNumericBarcode(code) {
+		return nil, nil
+	}
+	gtin, err := utils.ParseGtin(code)
+	if err != nil {
+		return nil, fmt.Errorf("invalid barcode: %w", err)
+	}
+	return &gtin, nil
+}
+
+// Matches the scanned code or any other format of the same GTIN
+func productCodeClause(barcode string) postgres.BoolExpression {
+	where_clause := table.Product.Code.EQ(postgres.String(barcode))
+	if gtin, err := ParseProductCode(barcode); err == nil && gtin != nil {
+		where_clause = where_clause.OR(table.Product.Gtin.EQ(postgres.String(*gtin)))
+	}
+	return where_clause
+}
+
 func (s Service) BarcodeExists(ctx context.Context, barcode string) bool {
 	qb := table.Product.
 		SELECT(table.Product.Code.AS("code")).
 		FROM(table.Product).
-		WHERE(table.Product.Code.EQ(postgres.String(barcode))).
+		WHERE(productCodeClause(barcode)).
+		LIMIT(1)
+	var product struct{
+		Code string
+	}
+	err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &product)
+	return err == nil
+}
+
+// Same as `BarcodeExists` but ignores the product with `product_id`, i.e. when it's re-entered in another format
+func (s Service) BarcodeUsedByOtherProduct(ctx context.Context, barcode string, product_id int64) bool {
+	qb := table.Product.
+		SELECT(table.Product.Code.AS("code")).
+		FROM(table.Product).
+		WHERE(postgres.AND(
+			productCodeClause(barcode),
+			table.Product.ID.NOT_EQ(postgres.Int(product_id)),
+		)).
 		LIMIT(1)
 	var product struct{
 		Code string
@@ -252,11 +313,13 @@ func (s Service) ProductFiltersBuilder(search *gmodel.ProductSearch) (where_clau
 
 	if search.SortByPrice != nil {
 		sort_by := strings.ToLower(*search.SortByPrice)
+		// deals such as "2 for $5" are sorted by their per-unit price
+		effective_price := postgres.FloatExp(postgres.COALESCE(table.Price.EffectivePrice, table.Price.Amount))
 		switch sort_by {
 		case "asc":
-			order_by = append(order_by, table.Price.Amount.ASC())
+			order_by = append(order_by, effective_price.ASC())
 		case "desc":
-			order_by = append(order_by, table.Price.Amount.DESC())
+			order_by = append(order_by, effective_price.DESC().NULLS_LAST())
 		}
 	}
 
@@ -380,13 +443,13 @@ func (s Service) PaginatedProducts(ctx context.Context, paginator_input gmodel.P
 		INNER_JOIN(table.Category, table.Category.ID.EQ(table.Product.CategoryID)).
 		INNER_JOIN(table.Store, table.Store.ID.EQ(table.Stock.StoreID)).
 		INNER_JOIN(table.Branch, table.Branch.ID.EQ(table.Stock.BranchID)).
-		INNER_JOIN(table.Price, table.Price.ID.EQ(table.Stock.LatestPriceID)).
+		LEFT_JOIN(table.Price, table.Price.ID.EQ(table.Stock.LatestPriceID)).
 		INNER_JOIN(table.Address, table.Address.ID.EQ(table.Branch.AddressID))
 
 	where_clause, order_by, filter_cols := s.ProductFiltersBuilder(search)
 	order_by = append(
 		order_by,
-		table.Price.CreatedAt.DESC(),
+		table.Price.CreatedAt.DESC().NULLS_LAST(),
 		table.Product.Views.DESC(),
 	)
 
@@ -435,6 +498,7 @@ func (s Service) UpdateProductById(ctx context.Context, user gmodel.User, id int
 	}
 
 	cols := postgres.ColumnList{}
+	var gtin *string
 	if input.Name != nil && *input.Name != product.Name {
 		cols = append(cols, table.Product.Name)
 	}
@@ -445,10 +509,13 @@ func (s Service) UpdateProductById(ctx context.Context, user gmodel.User, id int
 		cols = append(cols, table.Product.Brand)
 	}
 	if input.Code != nil && *input.Code != product.Code {
-		if s.BarcodeExists(ctx, *input.Code) {
+		if s.BarcodeUsedByOtherProduct(ctx, *input.Code, product.ID) {
 			return gmodel.Product{}, gmodel.Product{}, fmt.Errorf("new barcode is already in use")
 		}
-		cols = append(cols, table.Product.Code)
+		if gtin, err = ParseProductCode(*input.Code); err != nil {
+			return gmodel.Product{}, gmodel.Product{}, err
+		}
+		cols = append(cols, table.Product.Code, table.Product.Gtin)
 	}
 	if input.CategoryID != nil {
 		cols = append(cols, table.Product.CategoryID)
@@ -486,6 +553,7 @@ func (s Service) UpdateProductById(ctx context.Context, user gmodel.User, id int
 		UPDATE(cols).
 		MODEL(struct{
 			gmodel.UpdateProduct
+			Gtin *string
 			WeightValue *float64
 			WeightType *string
 			QuantityValue int
@@ -494,6 +562,7 @@ func (s Service) UpdateProductById(ctx context.Context, user gmodel.User, id int
 			UpdatedAt time.Time
 		}{
 			UpdateProduct: input,
+			Gtin: gtin,
 			WeightValue: weight_value,
 			WeightType: weight_type,
 			QuantityValue: quantity_value,
@@ -507,6 +576,9 @@ func (s Service) UpdateProductById(ctx context.Context, user gmodel.User, id int
 	if err != nil {
 		return gmodel.Product{}, gmodel.Product{}, err
 	}
+	if _, err := s.CreateProductRevision(ctx, &user.ID, updated_product, model.ProductRevisionAction_Update, nil, nil); err != nil {
+		log.Printf("could not create revision for product %d. %s\n", updated_product.ID, err.Error())
+	}
 
 	// Add category
 	category, _ := s.FindCategoryById(ctx, updated_product.CategoryID)
@@ -658,15 +730,12 @@ func (s Service) ExtractProductTextFromBase64Image(ctx context.Context, user gmo
 		}
 	}
 
-	go func() {
-		s.CreateAiResponseEntry(
-			context.Background(),
-			user,
-			gpt_req,
-			gpt_res,
-			model.AiPromptType_ProductDetails,
-		)
-	}()
+	s.EnqueueJobOrLog(ctx, JOB_AI_RESPONSE_ENTRY, AiResponseEntryJob{
+		UserID: user.ID,
+		PromptType: model.AiPromptType_ProductDetails,
+		Request: gpt_req,
+		Response: gpt_res,
+	}, EnqueueJobOptions{})
 	return extraction_ob, nil
 }
 
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+const DEFAULT_REPUTATION_SCORE = 50.0
+
+// Submissions from users at or above this score are auto-approved
+const TRUSTED_REPUTATION_SCORE = 80.0
+
+// Submissions from users below this score are held for moderation
+const LOW_REPUTATION_SCORE = 30.0
+
+// Minimum score difference for a conflicting price to not replace the latest stock price
+const REPUTATION_CONFLICT_MARGIN = 20.0
+
+// Prices reported within this window of each other are considered conflicting reports
+const PRICE_CONFLICT_WINDOW = 6 * time.Hour
+
+// Only activity within this window counts towards the reputation score
+const REPUTATION_LOOKBACK = 180 * 24 * time.Hour
+
+func (Service) IsTrustedContributor(user gmodel.User) bool {
+	return user.ReputationScore >= TRUSTED_REPUTATION_SCORE
+}
+
+func (Service) IsLowReputation(user gmodel.User) bool {
+	return user.ReputationScore < LOW_REPUTATION_SCORE
+}
+
+// Computes a score between 0 and 100 from the user's activity.
+// Accuracy ratios use add-one smoothing so new users start at `DEFAULT_REPUTATION_SCORE`
+func ReputationScore(reputation gmodel.UserReputation) float64 {
+	price_accuracy := float64(reputation.ConfirmedPrices + 1) / float64(reputation.ConfirmedPrices + reputation.DisputedPrices + 2)
+	edit_accuracy := float64(reputation.AcceptedEdits + 1) / float64(reputation.AcceptedEdits + reputation.RejectedEdits + 2)
+	age_bonus := math.Min(float64(reputation.AccountAgeDays) / 365, 1)
+
+	score := DEFAULT_REPUTATION_SCORE +
+		(price_accuracy - 0.5) * 60 +
+		(edit_accuracy - 0.5) * 40 +
+		age_bonus * 10
+	score = math.Max(0, math.Min(100, score))
+	return math.Round(score * 100) / 100
+}
+
+// Number of prices created by the user that were followed by another user's price
+// on the same stock within `BILLING_CONFIRMATION_WINDOW`.
+// `confirmed` selects follow-ups with the same amount, otherwise follow-ups with a different amount
+func (s Service) PriceFollowUpCount(ctx context.Context, user_id int64, confirmed bool, since time.Time) (int, error) {
+	follow_up := table.Price.AS("follow_up_price")
+	amount_clause := follow_up.Amount.EQ(table.Price.Amount)
+	if !confirmed {
+		amount_clause = follow_up.Amount.NOT_EQ(table.Price.Amount)
+	}
+	qb := table.Price.
+		SELECT(postgres.COUNT(postgres.DISTINCT(table.Price.ID)).AS("count")).
+		FROM(table.Price.INNER_JOIN(follow_up, postgres.AND(
+			follow_up.StockID.EQ(table.Price.StockID),
+			follow_up.CreatedAt.GT(table.Price.CreatedAt),
+			follow_up.CreatedAt.LT(table.Price.CreatedAt.ADD(postgres.INTERVALd(BILLING_CONFIRMATION_WINDOW))),
+			follow_up.CreatedByID.NOT_EQ(table.Price.CreatedByID),
+			amount_clause,
+		))).
+		WHERE(postgres.AND(
+			table.Price.CreatedByID.EQ(postgres.Int(user_id)),
+			table.Price.CreatedAt.GT(postgres.TimestampzT(since)),
+		))
+	var res struct{ Count int }
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &res); err != nil {
+		return 0, err
+	}
+	return res.Count, nil
+}
+
+// Number of product create/update billing rows with the given status
+func (s Service) ProductEditCount(ctx context.Context, user_id int64, status model.ProductBillingStatus, since time.Time) (int, error) {
+	qb := table.ProductBilling.
+		SELECT(postgres.COUNT(table.ProductBilling.ID).AS("count")).
+		FROM(table.ProductBilling).
+		WHERE(postgres.AND(
+			table.ProductBilling.UserID.EQ(postgres.Int(user_id)),
+			table.ProductBilling.BillingRateType.IN(
+				postgres.NewEnumValue(model.ProductBillingType_Create.String()),
+				postgres.NewEnumValue(model.ProductBillingType_Update.String()),
+			),
+			table.ProductBilling.Status.EQ(postgres.NewEnumValue(status.String())),
+			table.ProductBilling.CreatedAt.GT(postgres.TimestampzT(since)),
+		))
+	var res struct{ Count int }
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &res); err != nil {
+		return 0, err
+	}
+	return res.Count, nil
+}
+
+func (s Service) ComputeUserReputation(ctx context.Context, user gmodel.User) (reputation gmodel.UserReputation, err error) {
+	since := time.Now().Add(-REPUTATION_LOOKBACK)
+	reputation.UserID = user.ID
+	if reputation.ConfirmedPrices, err = s.PriceFollowUpCount(ctx, user.ID, true, since); err != nil {
+		return gmodel.UserReputation{}, err
+	}
+	if reputation.DisputedPrices, err = s.PriceFollowUpCount(ctx, user.ID, false, since); err != nil {
+		return gmodel.UserReputation{}, err
+	}
+	if reputation.AcceptedEdits, err = s.ProductEditCount(ctx, user.ID, model.ProductBillingStatus_Approved, since); err != nil {
+		return gmodel.UserReputation{}, err
+	}
+	if reputation.RejectedEdits, err = s.ProductEditCount(ctx, user.ID, model.ProductBillingStatus_Rejected, since); err != nil {
+		return gmodel.UserReputation{}, err
+	}
+	reputation.AccountAgeDays = int(time.Since(user.CreatedAt).Hours() / 24)
+	reputation.Score = ReputationScore(reputation)
+	reputation.UpdatedAt = time.Now()
+	user.ReputationScore = reputation.Score
+	reputation.Trusted = s.IsTrustedContributor(user)
+	return reputation, nil
+}
+
+// Recomputes the reputation of the user and stores the score on the user row
+func (s Service) RefreshUserReputation(ctx context.Context, user_id int64) (gmodel.UserReputation, error) {
+	user, err := s.FindUserById(ctx, user_id)
+	if err != nil {
+		return gmodel.UserReputation{}, err
+	}
+	reputation, err := s.ComputeUserReputation(ctx, user)
+	if err != nil {
+		return gmodel.UserReputation{}, err
+	}
+
+	qb := table.User.
+		UPDATE(table.User.ReputationScore, table.User.ReputationUpdatedAt).
+		SET(postgres.Float(reputation.Score), postgres.TimestampzT(reputation.UpdatedAt)).
+		WHERE(table.User.ID.EQ(postgres.Int(user.ID)))
+	if _, err := qb.ExecContext(ctx, s.DbOrTxExecutable()); err != nil {
+		return gmodel.UserReputation{}, err
+	}
+	return reputation, nil
+}
+
+// Recomputes the reputation of all users with recent price or product activity
+func (s Service) RefreshAllUserReputations(ctx context.Context) (refreshed int, err error) {
+	since := postgres.TimestampzT(time.Now().Add(-REPUTATION_LOOKBACK))
+	qb := table.User.
+		SELECT(table.User.ID.AS("id")).
+		FROM(table.User).
+		WHERE(postgres.OR(
+			postgres.EXISTS(
+				table.Price.
+					SELECT(table.Price.ID).
+					WHERE(postgres.AND(
+						table.Price.CreatedByID.EQ(table.User.ID),
+						table.Price.CreatedAt.GT(since),
+					)),
+			),
+			postgres.EXISTS(
+				table.ProductBilling.
+					SELECT(table.ProductBilling.ID).
+					WHERE(postgres.AND(
+						table.ProductBilling.UserID.EQ(table.User.ID),
+						table.ProductBilling.CreatedAt.GT(since),
+					)),
+			),
+		))
+	var user_ids []int64
+	if err := qb.QueryContext(ctx, s.DB, &user_ids); err != nil {
+		return 0, err
+	}
+
+	for _, user_id := range user_ids {
+		if _, err := s.RefreshUserReputation(ctx, user_id); err != nil {
+			log.Printf("could not refresh reputation for user %d. %s\n", user_id, err.Error())
+			continue
+		}
+		refreshed++
+	}
+	return refreshed, nil
+}
+
+// Decides whether a new price should become the latest stock price.
+// Recent conflicting prices from official sources or users with a much higher
+// reputation take precedence over the new price
+func (s Service) ShouldReplaceLatestPrice(ctx context.Context, user gmodel.User, price gmodel.Price, latest_price *gmodel.Price) bool {
+	if latest_price == nil || latest_price.ID == 0 || price.Official {
+		return true
+	}
+	if latest_price.Amount == price.Amount || time.Since(latest_price.CreatedAt) > PRICE_CONFLICT_WINDOW {
+		return true
+	}
+	if latest_price.CreatedByID == nil || *latest_price.CreatedByID == user.ID {
+		return true
+	}
+	if latest_price.Official {
+		return false
+	}
+
+	latest_creator, err := s.FindUserById(ctx, *latest_price.CreatedByID)
+	if err != nil {
+		return true
+	}
+	return latest_creator.ReputationScore - user.ReputationScore < REPUTATION_CONFLICT_MARGIN
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Stores images in an S3 compatible bucket (AWS S3, MinIO, R2, ...) using path-style requests.
+// Like `LocalImageStore`, every upload also keeps a copy under `v<version>/<public_id>`
+type S3ImageStore struct {
+	// e.g. https://s3.us-east-1.amazonaws.com
+	Endpoint string
+	Region string
+	Bucket string
+	AccessKeyID string
+	SecretAccessKey string
+	// URL the bucket is publicly served from. Defaults to `<Endpoint>/<Bucket>`
+	PublicUrl string
+}
+
+func (s3 S3ImageStore) objectUrl(key string) string {
+	return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(s3.Endpoint, "/"), s3.Bucket, key)
+}
+
+// Encodes the path as required by AWS signature v4. Only unreserved characters are kept
+func awsUriEncode(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// Signs the request with AWS signature v4
+func (s3 S3ImageStore) sign(req *http.Request, payload []byte, now time.Time) {
+	payload_hash := sha256.Sum256(payload)
+	payload_hash_hex := hex.EncodeToString(payload_hash[:])
+	amz_date := now.UTC().Format("20060102T150405Z")
+	date := amz_date[:8]
+	req.Header.Set("X-Amz-Date", amz_date)
+	req.Header.Set("X-Amz-Content-Sha256", payload_hash_hex)
+
+	signed_headers := "host;x-amz-content-sha256;x-amz-date"
+	canonical_request := strings.Join([]string{
+		req.Method,
+		awsUriEncode(req.URL.Path),
+		"",
+		fmt.Sprintf("host:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n", req.URL.Host, payload_hash_hex, amz_date),
+		signed_headers,
+		payload_hash_hex,
+	}, "\n")
+	canonical_hash := sha256.Sum256([]byte(canonical_request))
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, s3.Region)
+	string_to_sign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amz_date,
+		scope,
+		hex.EncodeToString(canonical_hash[:]),
+	}, "\n")
+
+	signing_key := hmacSha256([]byte("AWS4" + s3.SecretAccessKey), date)
+	signing_key = hmacSha256(signing_key, s3.Region)
+	signing_key = hmacSha256(signing_key, "s3")
+	signing_key = hmacSha256(signing_key, "aws4_request")
+	signature := hex.EncodeToString(hmacSha256(signing_key, string_to_sign))
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3.AccessKeyID,
+		scope,
+		signed_headers,
+		signature,
+	))
+}
+
+func (s3 S3ImageStore) do(ctx context.Context, method string, key string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, method, s3.objectUrl(key), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	if method == http.MethodPut {
+		req.Header.Set("Content-Type", http.DetectContentType(payload))
+	}
+	s3.sign(req, payload, time.Now())
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("s3 %s %s failed with status %d: %s", method, key, res.StatusCode, string(body))
+	}
+	return nil
+}
+
+func (s3 S3ImageStore) UploadBytes(ctx context.Context, data []byte, params ImageUploadParams) (*ImageUploadResult, error) {
+	key, err := imageStoreKey(params.PublicID)
+	if err != nil {
+		return nil, err
+	}
+	version := int(time.Now().UnixMilli())
+	if err := s3.do(ctx, http.MethodPut, imageVersionKey(key, strconv.Itoa(version)), data); err != nil {
+		return nil, err
+	}
+	if err := s3.do(ctx, http.MethodPut, key, data); err != nil {
+		return nil, err
+	}
+	return &ImageUploadResult{
+		PublicID: key,
+		Version: version,
+		URL: s3.Url(key),
+	}, nil
+}
+
+func (s3 S3ImageStore) UploadStream(ctx context.Context, stream io.Reader, params ImageUploadParams) (*ImageUploadResult, error) {
+	data, err := io.ReadAll(stream)
+	if err != nil {
+		return nil, err
+	}
+	return s3.UploadBytes(ctx, data, params)
+}
+
+func (s3 S3ImageStore) UploadUrl(ctx context.Context, image_url string, params ImageUploadParams) (*ImageUploadResult, error) {
+	data, err := fetchImageUrl(ctx, image_url)
+	if err != nil {
+		return nil, err
+	}
+	return s3.UploadBytes(ctx, data, params)
+}
+
+// Deletes the latest version. Previous versions are kept
+func (s3 S3ImageStore) Delete(ctx context.Context, public_id string) error {
+	key, err := imageStoreKey(public_id)
+	if err != nil {
+		return err
+	}
+	return s3.do(ctx, http.MethodDelete, key, nil)
+}
+
+func (s3 S3ImageStore) Url(public_id string) string {
+	if s3.PublicUrl == "" {
+		return s3.objectUrl(public_id)
+	}
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(s3.PublicUrl, "/"), public_id)
+}
+
+func (s3 S3ImageStore) VersionUrl(public_id string, version string) string {
+	return s3.Url(imageVersionKey(public_id, version))
+}
@@ -0,0 +1,302 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/graph/gmodel"
+
+	expo "github.com/oliveroneill/exponent-server-sdk-golang/sdk"
+)
+
+// Users watching the stock who logged in within the last 30 days and have a push token
+func (s Service) StockWatchers(ctx context.Context, product_id int64, stock_id int64, exclude_user_id int64) (users []gmodel.User, err error) {
+	qb := table.ProductList.
+		SELECT(table.User.AllColumns, table.AuthState.AllColumns).
+		FROM(
+			table.ProductList.
+				INNER_JOIN(table.List,
+					table.List.ID.EQ(table.ProductList.ListID).
+						AND(table.List.Type.EQ(
+							postgres.NewEnumValue(model.ListType_WatchList.String()),
+						)),
+				).
+				INNER_JOIN(table.Product, table.Product.ID.EQ(table.ProductList.ProductID)).
+				INNER_JOIN(table.Stock, table.Stock.ID.EQ(table.ProductList.StockID)).
+				INNER_JOIN(table.User, table.User.ID.EQ(table.ProductList.UserID)).
+				INNER_JOIN(table.AuthState,
+					table.AuthState.UserID.EQ(table.User.ID).
+						AND(table.AuthState.LoggedInAt.GT_EQ(
+							postgres.NOW().SUB(postgres.INTERVAL(30, postgres.DAY)),
+						)),
+				),
+		).
+		WHERE(
+			table.Product.ID.EQ(postgres.Int(product_id)).
+				AND(table.Stock.ID.EQ(postgres.Int(stock_id))).
+				AND(table.User.ID.NOT_EQ(postgres.Int(exclude_user_id))).
+				AND(table.AuthState.ExpoPushToken.IS_NOT_NULL()),
+		).
+		ORDER_BY(table.ProductList.CreatedAt.ASC())
+	err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &users)
+	return users, err
+}
+
+func expiredSaleClause(price *table.PriceTable) postgres.BoolExpression {
+	return postgres.AND(
+		price.Sale.IS_TRUE(),
+		price.ExpiresAt.LT_EQ(postgres.TimestampzT(time.Now())),
+	)
+}
+
+// Stocks whose latest price or latest member price is a sale past its expiration date
+func (s Service) ExpiredSaleStocks(ctx context.Context) (stocks []gmodel.Stock, err error) {
+	latest_member_price := table.Price.AS("latest_member_price")
+	qb := table.Stock.
+		SELECT(table.Stock.AllColumns, table.Price.AllColumns, latest_member_price.AllColumns).
+		FROM(table.Stock.
+			LEFT_JOIN(table.Price, table.Price.ID.EQ(table.Stock.LatestPriceID)).
+			LEFT_JOIN(latest_member_price, latest_member_price.ID.EQ(table.Stock.LatestMemberPriceID)),
+		).
+		WHERE(postgres.OR(
+			expiredSaleClause(table.Price),
+			expiredSaleClause(latest_member_price),
+		)).
+		ORDER_BY(table.Stock.ID.ASC())
+	err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &stocks)
+	return stocks, err
+}
+
+// Most recent price of the stock that isn't rejected or expired. Only member prices are
+// considered if `member_only` is set, otherwise only regular prices. Sale prices are skipped
+// if `skip_sales` is set. Returns nil if the stock has none
+func (s Service) FallbackStockPrice(
+	ctx context.Context,
+	stock_id int64,
+	exclude_price_id int64,
+	member_only bool,
+	skip_sales bool,
+) (*gmodel.Price, error) {
+	where_clause := postgres.AND(
+		table.Price.StockID.EQ(postgres.Int(stock_id)),
+		table.Price.ID.NOT_EQ(postgres.Int(exclude_price_id)),
+		table.Price.MemberOnly.EQ(postgres.Bool(member_only)),
+		table.Price.ReviewStatus.IS_DISTINCT_FROM(postgres.NewEnumValue(model.PriceReviewStatus_Rejected.String())),
+		postgres.OR(
+			table.Price.ExpiresAt.IS_NULL(),
+			table.Price.ExpiresAt.GT(postgres.TimestampzT(time.Now())),
+		),
+	)
+	if skip_sales {
+		where_clause = where_clause.AND(table.Price.Sale.IS_FALSE())
+	}
+	qb := table.Price.
+		SELECT(table.Price.AllColumns).
+		FROM(table.Price).
+		WHERE(where_clause).
+		ORDER_BY(table.Price.CreatedAt.DESC()).
+		LIMIT(1)
+	var prices []gmodel.Price
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &prices); err != nil {
+		return nil, err
+	}
+	if len(prices) == 0 {
+		return nil, nil
+	}
+	return &prices[0], nil
+}
+
+// Replaces `price` as the latest price (or latest member price if `member_only` is set) of the stock
+// with its fallback price, or no price if there is none. `replaced` is false if the stock doesn't use `price`
+func (s Service) replaceStockPrice(
+	ctx context.Context,
+	stock_id int64,
+	price_id int64,
+	member_only bool,
+	skip_sales bool,
+	updated_by_id *int64,
+) (replaced bool, fallback *gmodel.Price, err error) {
+	fallback, err = s.FallbackStockPrice(ctx, stock_id, price_id, member_only, skip_sales)
+	if err != nil {
+		return false, nil, err
+	}
+	price_column := table.Stock.LatestPriceID
+	if member_only {
+		price_column = table.Stock.LatestMemberPriceID
+	}
+	fallback_id := postgres.IntExp(postgres.NULL)
+	if fallback != nil {
+		fallback_id = postgres.Int(fallback.ID)
+	}
+	columns := postgres.ColumnList{price_column, table.Stock.UpdatedAt}
+	values := []any{fallback_id, postgres.TimestampzT(time.Now())}
+	if updated_by_id != nil {
+		columns = append(columns, table.Stock.UpdatedByID)
+		values = append(values, postgres.Int(*updated_by_id))
+	}
+	qb := table.Stock.
+		UPDATE(columns).
+		SET(values[0], values[1:]...).
+		WHERE(postgres.AND(
+			table.Stock.ID.EQ(postgres.Int(stock_id)),
+			price_column.EQ(postgres.Int(price_id)),
+		))
+	res, err := qb.ExecContext(ctx, s.DbOrTxExecutable())
+	if err != nil {
+		return false, nil, err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return false, nil, nil
+	}
+	return true, fallback, nil
+}
+
+// Reverts the expired sale prices of the stock (latest price and latest member price) to their
+// most recent non-sale price and records the transitions. Prices that changed in the meantime are skipped
+func (s Service) ExpireStockSale(ctx context.Context, stock gmodel.Stock) (transitions []gmodel.StockPriceTransition, err error) {
+	if s.TX, err = s.DB.BeginTx(ctx, nil); err != nil {
+		return nil, err
+	}
+	defer s.TX.Rollback()
+
+	transitions = []gmodel.StockPriceTransition{}
+	for _, expired := range []struct{
+		Price *gmodel.Price
+		MemberOnly bool
+	}{
+		{stock.LatestPrice, false},
+		{stock.LatestMemberPrice, true},
+	} {
+		price := expired.Price
+		if price == nil || !price.Sale || price.ExpiresAt == nil || price.ExpiresAt.After(time.Now()) {
+			continue
+		}
+		replaced, fallback, err := s.replaceStockPrice(ctx, stock.ID, price.ID, expired.MemberOnly, true, nil)
+		if err != nil {
+			return nil, err
+		}
+		if !replaced {
+			continue
+		}
+		var to_price_id *int64
+		if fallback != nil {
+			to_price_id = &fallback.ID
+		}
+		transition_qb := table.StockPriceTransition.
+			INSERT(
+				table.StockPriceTransition.StockID,
+				table.StockPriceTransition.FromPriceID,
+				table.StockPriceTransition.ToPriceID,
+				table.StockPriceTransition.Reason,
+			).
+			MODEL(model.StockPriceTransition{
+				StockID: stock.ID,
+				FromPriceID: &price.ID,
+				ToPriceID: to_price_id,
+				Reason: model.StockPriceTransitionReason_SaleExpired,
+			}).
+			RETURNING(table.StockPriceTransition.AllColumns)
+		var transition gmodel.StockPriceTransition
+		if err = transition_qb.QueryContext(ctx, s.TX, &transition); err != nil {
+			return nil, err
+		}
+		transition.FromPrice = price
+		transition.ToPrice = fallback
+		transitions = append(transitions, transition)
+	}
+	if err = s.TX.Commit(); err != nil {
+		return nil, err
+	}
+	s.TX = nil
+	return transitions, nil
+}
+
+func (s Service) SendSaleEndedPushNotifications(
+	ctx context.Context,
+	users []gmodel.User,
+	sale_price gmodel.Price,
+	new_price *gmodel.Price,
+) ([]expo.PushResponse, error) {
+	if len(users) == 0 || s.ExpoPushClient == nil {
+		return []expo.PushResponse{}, nil
+	}
+	product, err := s.FindProductById(ctx, sale_price.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	data := map[string]string{
+		"productId": fmt.Sprint(product.ID),
+		"stockId": fmt.Sprint(sale_price.StockID),
+	}
+	title := "Sale ended on your watched product"
+	body := fmt.Sprintf("The $%.2f sale on %s has ended", sale_price.Amount, product.Name)
+	if new_price != nil {
+		data["priceId"] = fmt.Sprint(new_price.ID)
+		data["priceAmount"] = fmt.Sprintf("$%.2f", new_price.Amount)
+		body += fmt.Sprintf(". Last reported price is $%.2f", new_price.Amount)
+	}
+	return s.publishPushNotifications(ctx, users, title, body, data)
+}
+
+// Reverts all stocks with an expired sale price and notifies their watchers.
+// Stocks that fail to revert are logged and skipped
+func (s Service) ExpireSalePrices(ctx context.Context) (transitions []gmodel.StockPriceTransition, err error) {
+	stocks, err := s.ExpiredSaleStocks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	transitions = []gmodel.StockPriceTransition{}
+	for _, stock := range stocks {
+		stock_transitions, err := s.ExpireStockSale(ctx, stock)
+		if err != nil {
+			log.Printf("could not expire sale of stock %d. %s\n", stock.ID, err.Error())
+			continue
+		}
+		transitions = append(transitions, stock_transitions...)
+
+		for _, transition := range stock_transitions {
+			// member sales only concern members, which watchers might not be
+			if transition.FromPrice.MemberOnly {
+				continue
+			}
+			users, err := s.StockWatchers(ctx, stock.ProductID, stock.ID, 0)
+			if err != nil {
+				log.Printf("could not find watchers of stock %d. %s\n", stock.ID, err.Error())
+				continue
+			}
+			if _, err := s.SendSaleEndedPushNotifications(ctx, users, *transition.FromPrice, transition.ToPrice); err != nil {
+				log.Printf("could not notify watchers of stock %d. %s\n", stock.ID, err.Error())
+			}
+		}
+	}
+	return transitions, nil
+}
+
+func (s Service) StockPriceTransitions(ctx context.Context, stock_id int64) (transitions []gmodel.StockPriceTransition, err error) {
+	from_price := table.Price.AS("from_price")
+	to_price := table.Price.AS("to_price")
+	qb := table.StockPriceTransition.
+		SELECT(
+			table.StockPriceTransition.AllColumns,
+			from_price.AllColumns,
+			to_price.AllColumns,
+		).
+		FROM(table.StockPriceTransition.
+			LEFT_JOIN(from_price, from_price.ID.EQ(table.StockPriceTransition.FromPriceID)).
+			LEFT_JOIN(to_price, to_price.ID.EQ(table.StockPriceTransition.ToPriceID)),
+		).
+		WHERE(table.StockPriceTransition.StockID.EQ(postgres.Int(stock_id))).
+		ORDER_BY(table.StockPriceTransition.CreatedAt.DESC())
+	if err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &transitions); err != nil {
+		return nil, err
+	}
+	if transitions == nil {
+		transitions = []gmodel.StockPriceTransition{}
+	}
+	return transitions, nil
+}
@@ -2,9 +2,9 @@ package services
 
 import (
 	"database/sql"
+	"net/netip"
 
 	vision "cloud.google.com/go/vision/apiv1"
-	"github.com/cloudinary/cloudinary-go/v2"
 	"github.com/go-jet/jet/v2/qrm"
 	"github.com/go-playground/validator/v10"
 	"github.com/openfoodfacts/openfoodfacts-go"
@@ -19,11 +19,13 @@ type Service struct {
 	TX *sql.Tx
 	StructValidator *validator.Validate
 	Tokens *types.Tokens
-	Cloudinary *cloudinary.Cloudinary
+	ImageStore ImageStore
 	ExpoPushClient *expo.PushClient
 	GoogleMapsClient *maps.Client
 	GoogleVisionApiClient *vision.ImageAnnotatorClient
 	OpenFoodFactsClient *openfoodfacts.Client
+	// Proxies allowed to set the "X-Forwarded-For" header
+	TrustedProxies []netip.Prefix
 }
 
 // Returns a transaction if present.
@@ -34,3 +36,12 @@ func (s *Service) DbOrTxQueryable() qrm.Queryable {
 	}
 	return s.DB
 }
+
+// Returns a transaction if present.
+// Otherwise returns the Database connection instance as qrm.Executable
+func (s *Service) DbOrTxExecutable() qrm.Executable {
+	if s.TX != nil {
+		return s.TX
+	}
+	return s.DB
+}
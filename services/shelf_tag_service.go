@@ -0,0 +1,199 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/utils"
+)
+
+// Possible product codes of a UPC printed on a shelf tag.
+// Tags often leave out the check digit, so a computed one is tried as well
+func ShelfTagProductCodes(upc string) []string {
+	digits := utils.CleanBarcode(upc)
+	if !utils.IsNumericBarcode(digits) {
+		return []string{}
+	}
+	codes := []string{digits}
+	if len(digits) >= 7 && len(digits) <= 13 {
+		codes = append(codes, fmt.Sprintf("%s%d", digits, utils.GtinCheckDigit(digits)))
+	}
+	return codes
+}
+
+// Builds the `CreatePrice` input of a shelf tag. Returns nil if the tag has no price
+func ShelfTagPriceDraft(fields gmodel.ShelfTagFields, product_id int64, branch_id int64) *gmodel.PriceDraft {
+	if fields.Price == nil || *fields.Price <= 0 {
+		return nil
+	}
+	draft := gmodel.PriceDraft{
+		ProductID: product_id,
+		BranchID: branch_id,
+		Amount: *fields.Price,
+		UnitType: "item",
+	}
+	if fields.PriceUnit != nil && strings.TrimSpace(*fields.PriceUnit) != "" {
+		draft.UnitType = strings.ToLower(strings.TrimSpace(*fields.PriceUnit))
+	}
+	if fields.RegularPrice != nil && *fields.RegularPrice > draft.Amount {
+		draft.Sale = true
+		draft.OriginalPrice = fields.RegularPrice
+	} else if fields.Sale != nil {
+		draft.Sale = *fields.Sale
+	}
+
+	conditions := []string{}
+	if fields.MemberOnly != nil && *fields.MemberOnly {
+		draft.MemberOnly = true
+		if fields.MemberProgram != nil && *fields.MemberProgram != "" {
+			conditions = append(conditions, fmt.Sprintf("%s member price", *fields.MemberProgram))
+		} else {
+			conditions = append(conditions, "member price")
+		}
+	}
+	if fields.Condition != nil && strings.TrimSpace(*fields.Condition) != "" {
+		conditions = append(conditions, strings.TrimSpace(*fields.Condition))
+	}
+	if len(conditions) > 0 {
+		condition := strings.Join(conditions, "; ")
+		draft.Condition = &condition
+	}
+
+	if fields.ExpiresAt != nil {
+		if expires_at, err := time.Parse(time.DateOnly, *fields.ExpiresAt); err == nil {
+			// tags are valid through the printed date
+			expires_at = expires_at.Add(24 * time.Hour - time.Second)
+			if expires_at.After(time.Now()) {
+				draft.ExpiresAt = &expires_at
+			}
+		}
+	}
+	return &draft
+}
+
+// Matches the product of a shelf tag by its UPC, falling back to a search by name.
+// Products stocked at the branch are preferred
+func (s Service) MatchShelfTagProduct(ctx context.Context, branch_id int64, fields gmodel.ShelfTagFields) *gmodel.Product {
+	if fields.Upc != nil {
+		for _, code := range ShelfTagProductCodes(*fields.Upc) {
+			if product, err := s.FindProductWithCode(ctx, code); err == nil {
+				return &product
+			}
+		}
+	}
+
+	search_terms := []string{}
+	if fields.Brand != nil {
+		search_terms = append(search_terms, *fields.Brand)
+	}
+	if fields.ProductName != nil {
+		search_terms = append(search_terms, *fields.ProductName)
+	}
+	query := strings.TrimSpace(strings.Join(search_terms, " "))
+	if query == "" {
+		return nil
+	}
+	paginator := gmodel.PaginatorInput{ Page: 1, Limit: 1 }
+	stocked, err := s.PaginatedProducts(ctx, paginator, &gmodel.ProductSearch{
+		Query: &query,
+		BranchID: &branch_id,
+	})
+	if err == nil && len(stocked.Products) > 0 {
+		return stocked.Products[0]
+	}
+	products, err := s.ProductSearch(ctx, paginator, query)
+	if err == nil && len(products.Products) > 0 {
+		return products.Products[0]
+	}
+	return nil
+}
+
+func (s Service) ShelfTagExtractionFromFields(
+	ctx context.Context,
+	branch_id int64,
+	fields gmodel.ShelfTagFields,
+) gmodel.ShelfTagExtraction {
+	extraction := gmodel.ShelfTagExtraction{
+		Fields: &fields,
+		Product: s.MatchShelfTagProduct(ctx, branch_id, fields),
+	}
+	if extraction.Product != nil {
+		extraction.Draft = ShelfTagPriceDraft(fields, extraction.Product.ID, branch_id)
+	}
+	return extraction
+}
+
+// Prices read from shelf tags by trusted contributors and store staff don't need a review
+func (s Service) CanSubmitShelfTagPrice(ctx context.Context, user gmodel.User, store_id int64) bool {
+	return !s.RequiresProductEditProposal(user) || s.HasStoreRole(ctx, user, store_id, gmodel.StoreRoleStaff)
+}
+
+// Input to submit the draft with the shelf tag photo as evidence
+func ShelfTagCreatePrice(draft gmodel.PriceDraft, image []byte) gmodel.CreatePrice {
+	return gmodel.CreatePrice{
+		ProductID: draft.ProductID,
+		BranchID: draft.BranchID,
+		Amount: draft.Amount,
+		CurrencyCode: draft.CurrencyCode,
+		Sale: draft.Sale,
+		OriginalPrice: draft.OriginalPrice,
+		Condition: draft.Condition,
+		UnitType: draft.UnitType,
+		ExpiresAt: draft.ExpiresAt,
+		MemberOnly: &draft.MemberOnly,
+		ImageFile: &graphql.Upload{
+			File: bytes.NewReader(image),
+			Filename: "shelf_tag",
+			Size: int64(len(image)),
+		},
+	}
+}
+
+// OCRs the shelf tag photo and extracts its fields with the `SHELF_TAG` prompt template.
+// Returns the decoded photo along with the extraction
+func (s Service) ExtractPriceFromShelfTag(
+	ctx context.Context,
+	user gmodel.User,
+	branch_id int64,
+	base64_image string,
+) (gmodel.ShelfTagExtraction, []byte, error) {
+	if _, err := s.FindBranchById(ctx, branch_id); err != nil {
+		return gmodel.ShelfTagExtraction{}, nil, fmt.Errorf("could not find branch")
+	}
+	image_bytes, err := utils.DecodeBase64Image(base64_image)
+	if err != nil {
+		return gmodel.ShelfTagExtraction{}, nil, fmt.Errorf("not a valid base64 encoded image")
+	}
+	ocr_data, err := s.GoogleVisionOcrData(ctx, image_bytes)
+	if err != nil {
+		return gmodel.ShelfTagExtraction{}, nil, fmt.Errorf("ocr error: %w", err)
+	}
+
+	template, err := s.GetAiTemplate(ctx, model.AiPromptType_ShelfTag)
+	if err != nil {
+		return gmodel.ShelfTagExtraction{}, nil, fmt.Errorf("template error")
+	}
+	template.Prompt = strings.ReplaceAll(template.Prompt, template.Variable, ocr_data)
+	gpt_req, gpt_res, err := s.GptResponse(ctx, template.Prompt, template.MaxTokens)
+	if err != nil {
+		return gmodel.ShelfTagExtraction{}, nil, fmt.Errorf("could not analyze ocr data: %w", err)
+	}
+	s.EnqueueJobOrLog(ctx, JOB_AI_RESPONSE_ENTRY, AiResponseEntryJob{
+		UserID: user.ID,
+		PromptType: model.AiPromptType_ShelfTag,
+		Request: gpt_req,
+		Response: gpt_res,
+	}, EnqueueJobOptions{})
+
+	fields, err := ParseRawGptResponse[gmodel.ShelfTagFields](gpt_res)
+	if err != nil {
+		return gmodel.ShelfTagExtraction{}, nil, err
+	}
+	return s.ShelfTagExtractionFromFields(ctx, branch_id, fields), image_bytes, nil
+}
@@ -50,7 +50,7 @@ func (s Service) FindStockById(ctx context.Context, id int64) (stock gmodel.Stoc
 				INNER_JOIN(table.Branch, table.Branch.ID.EQ(table.Stock.BranchID)).
 				INNER_JOIN(table.Store, table.Store.ID.EQ(table.Stock.StoreID)).
 				INNER_JOIN(table.Address, table.Address.ID.EQ(table.Branch.AddressID)).
-				INNER_JOIN(table.Price, table.Price.ID.EQ(table.Stock.LatestPriceID)).
+				LEFT_JOIN(table.Price, table.Price.ID.EQ(table.Stock.LatestPriceID)).
 				LEFT_JOIN(created_at_table, created_at_table.ID.EQ(table.Price.CreatedByID)).
 				LEFT_JOIN(updated_at_table, updated_at_table.ID.EQ(table.Price.UpdatedByID)),
 		).
@@ -131,6 +131,25 @@ func (s Service) UpdateStockWithLatestPrice(ctx context.Context, user gmodel.Use
 	return updated_stock, nil
 }
 
+func (s Service) UpdateStockWithLatestMemberPrice(ctx context.Context, user gmodel.User, stock_id int64, price_id int64) (updated_stock gmodel.Stock, err error) {
+	qb := table.Stock.
+		UPDATE(
+			table.Stock.LatestMemberPriceID,
+			table.Stock.UpdatedByID,
+			table.Stock.UpdatedAt,
+		).MODEL(model.Stock{
+			LatestMemberPriceID: &price_id,
+			UpdatedByID: &user.ID,
+			UpdatedAt: time.Now(),
+		}).WHERE(
+			table.Stock.ID.EQ(postgres.Int(stock_id)),
+		).RETURNING(table.Stock.AllColumns)
+	if err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &updated_stock); err != nil {
+		return gmodel.Stock{}, err
+	}
+	return updated_stock, nil
+}
+
 func (s Service) GetStocksForProduct(ctx context.Context, product_id int64, location *gmodel.LocationInput) (stocks []gmodel.Stock, err error) {
 	if !s.ProductExists(ctx, product_id) {
 		return nil, fmt.Errorf("invalid product id")
@@ -155,7 +174,7 @@ func (s Service) GetStocksForProduct(ctx context.Context, product_id int64, loca
 	}
 	order_by = append(
 		order_by,
-		table.Price.CreatedAt.DESC(),
+		table.Price.CreatedAt.DESC().NULLS_LAST(),
 		table.Stock.CreatedAt.DESC(),
 	)
 	qb := table.Stock.
@@ -165,7 +184,7 @@ func (s Service) GetStocksForProduct(ctx context.Context, product_id int64, loca
 			INNER_JOIN(table.Branch, table.Branch.ID.EQ(table.Stock.BranchID)).
 			INNER_JOIN(table.Store, table.Store.ID.EQ(table.Stock.StoreID)).
 			INNER_JOIN(table.Address, table.Address.ID.EQ(table.Branch.AddressID)).
-			INNER_JOIN(table.Price, table.Price.ID.EQ(table.Stock.LatestPriceID)).
+			LEFT_JOIN(table.Price, table.Price.ID.EQ(table.Stock.LatestPriceID)).
 			LEFT_JOIN(created_at_table, created_at_table.ID.EQ(table.Price.CreatedByID)).
 			LEFT_JOIN(updated_at_table, updated_at_table.ID.EQ(table.Price.UpdatedByID)),
 		).
@@ -201,7 +220,7 @@ func (s Service) PaginatedStocksForProduct(ctx context.Context, paginator_input
 	}
 	order_by = append(
 		order_by,
-		table.Price.CreatedAt.DESC(),
+		table.Price.CreatedAt.DESC().NULLS_LAST(),
 		table.Stock.CreatedAt.DESC(),
 	)
 	tables := table.Stock.
@@ -209,7 +228,7 @@ func (s Service) PaginatedStocksForProduct(ctx context.Context, paginator_input
 		INNER_JOIN(table.Branch, table.Branch.ID.EQ(table.Stock.BranchID)).
 		INNER_JOIN(table.Store, table.Store.ID.EQ(table.Stock.StoreID)).
 		INNER_JOIN(table.Address, table.Address.ID.EQ(table.Branch.AddressID)).
-		INNER_JOIN(table.Price, table.Price.ID.EQ(table.Stock.LatestPriceID)).
+		LEFT_JOIN(table.Price, table.Price.ID.EQ(table.Stock.LatestPriceID)).
 		LEFT_JOIN(created_at_table, created_at_table.ID.EQ(table.Price.CreatedByID)).
 		LEFT_JOIN(updated_at_table, updated_at_table.ID.EQ(table.Price.UpdatedByID))
 	
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+func (Service) StoreRoleValue(role gmodel.StoreRole) int {
+	switch role {
+		case gmodel.StoreRoleOwner: return 3
+		case gmodel.StoreRoleManager: return 2
+		case gmodel.StoreRoleStaff: return 1
+		default: return 0
+	}
+}
+
+func (s Service) IsStoreRoleAuthorized(minimum_required_role gmodel.StoreRole, member_role gmodel.StoreRole) bool {
+	return s.StoreRoleValue(member_role) >= s.StoreRoleValue(minimum_required_role)
+}
+
+func (s Service) FindStoreMember(ctx context.Context, user_id int64, store_id int64) (member gmodel.StoreMember, err error) {
+	qb := table.StoreMember.
+		SELECT(table.StoreMember.AllColumns).
+		FROM(table.StoreMember).
+		WHERE(postgres.AND(
+			table.StoreMember.UserID.EQ(postgres.Int(user_id)),
+			table.StoreMember.StoreID.EQ(postgres.Int(store_id)),
+		)).
+		LIMIT(1)
+	err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &member)
+	return member, err
+}
+
+// Returns `true` if the user is a member of the store with at least `minimum_required_role`
+func (s Service) HasStoreRole(ctx context.Context, user gmodel.User, store_id int64, minimum_required_role gmodel.StoreRole) bool {
+	member, err := s.FindStoreMember(ctx, user.ID, store_id)
+	if err != nil {
+		return false
+	}
+	return s.IsStoreRoleAuthorized(minimum_required_role, member.Role)
+}
+
+// Resolves the store id from the current field's `storeId` or `branchId` arguments.
+// Struct arguments (i.e. `input`) are checked for `StoreID` and `BranchID` fields as well
+func (s Service) StoreIdFromFieldArgs(ctx context.Context) (store_id int64, err error) {
+	field_ctx := graphql.GetFieldContext(ctx)
+	if field_ctx == nil {
+		return 0, fmt.Errorf("field context not found")
+	}
+
+	var store_ids, branch_ids []int64
+	collect := func(name string, value any) {
+		id, ok := value.(int64)
+		if !ok {
+			return
+		}
+		switch name {
+		case "storeId", "StoreID":
+			store_ids = append(store_ids, id)
+		case "branchId", "BranchID":
+			branch_ids = append(branch_ids, id)
+		}
+	}
+	for name, arg := range field_ctx.Args {
+		collect(name, arg)
+
+		v := reflect.Indirect(reflect.ValueOf(arg))
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+		for _, field_name := range []string{"StoreID", "BranchID"} {
+			field := v.FieldByName(field_name)
+			if field.IsValid() && field.CanInterface() {
+				collect(field_name, field.Interface())
+			}
+		}
+	}
+
+	for _, branch_id := range branch_ids {
+		branch, err := s.FindBranchById(ctx, branch_id)
+		if err != nil {
+			return 0, fmt.Errorf("invalid branch id")
+		}
+		store_ids = append(store_ids, branch.StoreID)
+	}
+	if len(store_ids) == 0 {
+		return 0, fmt.Errorf("store could not be resolved from arguments")
+	}
+	for _, id := range store_ids {
+		if id != store_ids[0] {
+			return 0, fmt.Errorf("arguments reference multiple stores")
+		}
+	}
+	return store_ids[0], nil
+}
+
+func (s Service) StoreMembers(ctx context.Context, store_id int64) (members []gmodel.StoreMember, err error) {
+	qb := table.StoreMember.
+		SELECT(
+			table.StoreMember.AllColumns,
+			table.User.ID,
+			table.User.Name,
+			table.User.Avatar,
+			table.User.Active,
+		).
+		FROM(
+			table.StoreMember.
+				INNER_JOIN(table.User, table.User.ID.EQ(table.StoreMember.UserID)),
+		).
+		WHERE(table.StoreMember.StoreID.EQ(postgres.Int(store_id))).
+		ORDER_BY(table.StoreMember.CreatedAt.ASC())
+	err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &members)
+	return members, err
+}
+
+func (s Service) UserStoreMemberships(ctx context.Context, user gmodel.User) (members []gmodel.StoreMember, err error) {
+	qb := table.StoreMember.
+		SELECT(
+			table.StoreMember.AllColumns,
+			table.Store.AllColumns,
+		).
+		FROM(
+			table.StoreMember.
+				INNER_JOIN(table.Store, table.Store.ID.EQ(table.StoreMember.StoreID)),
+		).
+		WHERE(table.StoreMember.UserID.EQ(postgres.Int(user.ID))).
+		ORDER_BY(table.StoreMember.CreatedAt.ASC())
+	err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &members)
+	return members, err
+}
+
+// Adds or updates a store membership. Users without a global ADMIN role can only
+// assign roles up to their own store role
+func (s Service) AddStoreMember(
+	ctx context.Context,
+	user gmodel.User,
+	store_id int64,
+	member_user_id int64,
+	role gmodel.StoreRole,
+) (member gmodel.StoreMember, err error) {
+	if !s.StoreExists(ctx, store_id) {
+		return gmodel.StoreMember{}, fmt.Errorf("store id is invalid")
+	}
+	if _, err := s.FindUserById(ctx, member_user_id); err != nil {
+		return gmodel.StoreMember{}, fmt.Errorf("user id is invalid")
+	}
+	if !s.IsRoleAuthorized(gmodel.UserRoleAdmin, user.Role) && !s.HasStoreRole(ctx, user, store_id, role) {
+		return gmodel.StoreMember{}, fmt.Errorf("cannot assign a role higher than your own")
+	}
+
+	var store_role model.StoreRoleType
+	if err := store_role.Scan(role.String()); err != nil {
+		return gmodel.StoreMember{}, err
+	}
+	qb := table.StoreMember.
+		INSERT(
+			table.StoreMember.UserID,
+			table.StoreMember.StoreID,
+			table.StoreMember.Role,
+			table.StoreMember.CreatedByID,
+		).
+		MODEL(model.StoreMember{
+			UserID: member_user_id,
+			StoreID: store_id,
+			Role: store_role,
+			CreatedByID: &user.ID,
+		}).
+		ON_CONFLICT(table.StoreMember.UserID, table.StoreMember.StoreID).
+		DO_UPDATE(postgres.SET(
+			table.StoreMember.Role.SET(table.StoreMember.EXCLUDED.Role),
+			table.StoreMember.UpdatedAt.SET(postgres.TimestampzT(time.Now())),
+		)).
+		RETURNING(table.StoreMember.AllColumns)
+	if err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &member); err != nil {
+		return gmodel.StoreMember{}, err
+	}
+	return member, nil
+}
+
+func (s Service) RemoveStoreMember(ctx context.Context, user gmodel.User, store_id int64, member_user_id int64) error {
+	member, err := s.FindStoreMember(ctx, member_user_id, store_id)
+	if err != nil {
+		return fmt.Errorf("store member not found")
+	}
+	if !s.IsRoleAuthorized(gmodel.UserRoleAdmin, user.Role) && !s.HasStoreRole(ctx, user, store_id, member.Role) {
+		return fmt.Errorf("cannot remove a member with a role higher than your own")
+	}
+
+	qb := table.StoreMember.
+		DELETE().
+		WHERE(table.StoreMember.ID.EQ(postgres.Int(member.ID)))
+	_, err = qb.ExecContext(ctx, s.DbOrTxExecutable())
+	return err
+}
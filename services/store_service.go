@@ -3,9 +3,11 @@ package services
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-jet/jet/v2/postgres"
 	"github.com/google/uuid"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
 	"github.com/pricetra/api/database/jet/postgres/public/table"
 	"github.com/pricetra/api/graph/gmodel"
 	"github.com/pricetra/api/utils"
@@ -133,3 +135,41 @@ func (s Service) StoreExists(ctx context.Context, id int64) bool {
 	err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &store)
 	return err == nil
 }
+
+func (s Service) UpdateStore(ctx context.Context, user gmodel.User, store_id int64, input gmodel.UpdateStore) (updated_store gmodel.Store, err error) {
+	if err := s.StructValidator.StructCtx(ctx, input); err != nil {
+		return gmodel.Store{}, err
+	}
+	if input.LogoBase64 != nil && !utils.IsValidBase64Image(*input.LogoBase64) {
+		return gmodel.Store{}, fmt.Errorf("invalid base64 image provided")
+	}
+
+	store := model.Store{}
+	columns := postgres.ColumnList{}
+	if input.Name != nil {
+		columns = append(columns, table.Store.Name)
+		store.Name = *input.Name
+	}
+	if input.Website != nil {
+		columns = append(columns, table.Store.Website)
+		store.Website = *input.Website
+	}
+	if input.LogoFile != nil || input.LogoBase64 != nil {
+		columns = append(columns, table.Store.Logo)
+		store.Logo = uuid.NewString()
+	}
+	if len(columns) == 0 {
+		return s.FindStore(ctx, store_id)
+	}
+	columns = append(columns, table.Store.UpdatedByID, table.Store.UpdatedAt)
+	store.UpdatedByID = &user.ID
+	store.UpdatedAt = time.Now()
+
+	qb := table.Store.
+		UPDATE(columns).
+		MODEL(store).
+		WHERE(table.Store.ID.EQ(postgres.Int(store_id))).
+		RETURNING(table.Store.AllColumns)
+	err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &updated_store)
+	return updated_store, err
+}
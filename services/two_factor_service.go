@@ -0,0 +1,399 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/utils"
+	"github.com/thanhpk/randstr"
+)
+
+const TWO_FACTOR_ISSUER = "Pricetra"
+const TWO_FACTOR_RECOVERY_CODE_COUNT = 10
+const TWO_FACTOR_CHALLENGE_MAX_TRIES = 5
+const TWO_FACTOR_CHALLENGE_TTL = 5 * time.Minute
+
+// Returns `true` if the user's role requires a second factor before any role restricted action
+func (s Service) IsTwoFactorRequired(user gmodel.User) bool {
+	return s.IsRoleAuthorized(gmodel.UserRoleAdmin, user.Role)
+}
+
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s Service) findUserWithTwoFactorSecret(ctx context.Context, user_id int64) (user model.User, err error) {
+	qb := table.User.
+		SELECT(table.User.AllColumns).
+		FROM(table.User).
+		WHERE(table.User.ID.EQ(postgres.Int(user_id))).
+		LIMIT(1)
+	if err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &user); err != nil {
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+// Generates a new TOTP secret for the user. The secret is not active
+// until it is confirmed with `ConfirmTwoFactor`
+func (s Service) EnrollTwoFactor(ctx context.Context, user gmodel.User) (enrollment gmodel.TwoFactorEnrollment, err error) {
+	if user.TwoFactorEnabled {
+		return gmodel.TwoFactorEnrollment{}, fmt.Errorf("two-factor authentication is already enabled")
+	}
+	secret, err := utils.GenerateTotpSecret()
+	if err != nil {
+		return gmodel.TwoFactorEnrollment{}, fmt.Errorf("could not generate secret")
+	}
+
+	qb := table.User.
+		UPDATE(table.User.TwoFactorSecret, table.User.UpdatedAt).
+		MODEL(model.User{
+			TwoFactorSecret: &secret,
+			UpdatedAt: time.Now(),
+		}).
+		WHERE(table.User.ID.EQ(postgres.Int(user.ID)))
+	if _, err = qb.ExecContext(ctx, s.DbOrTxExecutable()); err != nil {
+		return gmodel.TwoFactorEnrollment{}, err
+	}
+	return gmodel.TwoFactorEnrollment{
+		Secret: secret,
+		OtpauthURL: utils.TotpProvisioningUri(TWO_FACTOR_ISSUER, user.Email, secret),
+	}, nil
+}
+
+// Deletes all existing recovery codes for the user and creates a new set.
+// Returns the plain text codes which are only ever shown once
+func (s Service) CreateTwoFactorRecoveryCodes(ctx context.Context, user_id int64) (codes []string, err error) {
+	db := s.DbOrTxExecutable()
+	_, err = table.TwoFactorRecoveryCode.
+		DELETE().
+		WHERE(table.TwoFactorRecoveryCode.UserID.EQ(postgres.Int(user_id))).
+		ExecContext(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	codes = make([]string, TWO_FACTOR_RECOVERY_CODE_COUNT)
+	entries := make([]model.TwoFactorRecoveryCode, TWO_FACTOR_RECOVERY_CODE_COUNT)
+	for i := range codes {
+		code := strings.ToUpper(randstr.Hex(10))
+		codes[i] = fmt.Sprintf("%s-%s", code[:5], code[5:])
+		entries[i] = model.TwoFactorRecoveryCode{
+			UserID: user_id,
+			CodeHash: hashRecoveryCode(code),
+		}
+	}
+	qb := table.TwoFactorRecoveryCode.
+		INSERT(
+			table.TwoFactorRecoveryCode.UserID,
+			table.TwoFactorRecoveryCode.CodeHash,
+		).
+		MODELS(entries)
+	if _, err = qb.ExecContext(ctx, db); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Marks an unused recovery code as used. Returns `false` if the code is invalid
+func (s Service) UseTwoFactorRecoveryCode(ctx context.Context, user_id int64, code string) bool {
+	qb := table.TwoFactorRecoveryCode.
+		UPDATE(table.TwoFactorRecoveryCode.UsedAt).
+		SET(postgres.NOW()).
+		WHERE(postgres.AND(
+			table.TwoFactorRecoveryCode.UserID.EQ(postgres.Int(user_id)),
+			table.TwoFactorRecoveryCode.CodeHash.EQ(postgres.String(hashRecoveryCode(code))),
+			table.TwoFactorRecoveryCode.UsedAt.IS_NULL(),
+		)).
+		RETURNING(table.TwoFactorRecoveryCode.ID)
+	var res model.TwoFactorRecoveryCode
+	err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &res)
+	return err == nil
+}
+
+// Stores the time step of an accepted TOTP code. Returns `false` if a code
+// of the same or a later step was already accepted
+func (s Service) useTwoFactorStep(ctx context.Context, user_id int64, step int64) bool {
+	qb := table.User.
+		UPDATE(table.User.TwoFactorLastStep).
+		SET(postgres.Int(step)).
+		WHERE(postgres.AND(
+			table.User.ID.EQ(postgres.Int(user_id)),
+			postgres.OR(
+				table.User.TwoFactorLastStep.IS_NULL(),
+				table.User.TwoFactorLastStep.LT(postgres.Int(step)),
+			),
+		)).
+		RETURNING(table.User.ID)
+	var res model.User
+	err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &res)
+	return err == nil
+}
+
+// Verifies a TOTP code, or as a fallback, a one-time recovery code.
+// TOTP codes can only be used once
+func (s Service) VerifyTwoFactorCode(ctx context.Context, user model.User, code string, allow_recovery bool) bool {
+	if user.TwoFactorSecret == nil {
+		return false
+	}
+	if step, ok := utils.MatchTotpStep(*user.TwoFactorSecret, code, time.Now(), 1); ok {
+		return s.useTwoFactorStep(ctx, user.ID, step)
+	}
+	return allow_recovery && s.UseTwoFactorRecoveryCode(ctx, user.ID, code)
+}
+
+func (s Service) MarkAuthStateTwoFactorVerified(ctx context.Context, auth_state_id string) error {
+	auth_state_uuid, err := uuid.Parse(auth_state_id)
+	if err != nil {
+		return err
+	}
+	_, err = table.AuthState.
+		UPDATE(table.AuthState.TwoFactorVerifiedAt).
+		SET(postgres.NOW()).
+		WHERE(table.AuthState.ID.EQ(postgres.UUID(auth_state_uuid))).
+		ExecContext(ctx, s.DbOrTxExecutable())
+	return err
+}
+
+// Activates two-factor authentication after the user proves possession of the secret.
+// The current session is marked as verified since the user has just provided a valid code
+func (s Service) ConfirmTwoFactor(ctx context.Context, user gmodel.User, code string) (codes []string, err error) {
+	if user.TwoFactorEnabled {
+		return nil, fmt.Errorf("two-factor authentication is already enabled")
+	}
+	db_user, err := s.findUserWithTwoFactorSecret(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if db_user.TwoFactorSecret == nil {
+		return nil, fmt.Errorf("two-factor enrollment has not been started")
+	}
+	if !s.VerifyTwoFactorCode(ctx, db_user, code, false) {
+		return nil, fmt.Errorf("invalid two-factor code")
+	}
+
+	if s.TX, err = s.DB.BeginTx(ctx, nil); err != nil {
+		return nil, err
+	}
+	defer s.TX.Rollback()
+
+	qb := table.User.
+		UPDATE(table.User.TwoFactorEnabled, table.User.UpdatedAt).
+		MODEL(model.User{
+			TwoFactorEnabled: true,
+			UpdatedAt: time.Now(),
+		}).
+		WHERE(table.User.ID.EQ(postgres.Int(user.ID)))
+	if _, err = qb.ExecContext(ctx, s.TX); err != nil {
+		return nil, err
+	}
+	if codes, err = s.CreateTwoFactorRecoveryCodes(ctx, user.ID); err != nil {
+		return nil, fmt.Errorf("could not create recovery codes")
+	}
+	if user.AuthStateID != nil {
+		if err = s.MarkAuthStateTwoFactorVerified(ctx, *user.AuthStateID); err != nil {
+			return nil, err
+		}
+	}
+	if err = s.TX.Commit(); err != nil {
+		return nil, fmt.Errorf("could not commit changes")
+	}
+	return codes, nil
+}
+
+func (s Service) DisableTwoFactor(ctx context.Context, user gmodel.User, code string) (updated_user gmodel.User, err error) {
+	if !user.TwoFactorEnabled {
+		return gmodel.User{}, fmt.Errorf("two-factor authentication is not enabled")
+	}
+	if s.IsTwoFactorRequired(user) {
+		return gmodel.User{}, fmt.Errorf("two-factor authentication is required for this role")
+	}
+	db_user, err := s.findUserWithTwoFactorSecret(ctx, user.ID)
+	if err != nil {
+		return gmodel.User{}, err
+	}
+
+	if s.TX, err = s.DB.BeginTx(ctx, nil); err != nil {
+		return gmodel.User{}, err
+	}
+	defer s.TX.Rollback()
+
+	if !s.VerifyTwoFactorCode(ctx, db_user, code, true) {
+		return gmodel.User{}, fmt.Errorf("invalid two-factor code")
+	}
+	qb := table.User.
+		UPDATE(table.User.TwoFactorSecret, table.User.TwoFactorEnabled, table.User.UpdatedAt).
+		MODEL(model.User{
+			TwoFactorSecret: nil,
+			TwoFactorEnabled: false,
+			UpdatedAt: time.Now(),
+		}).
+		WHERE(table.User.ID.EQ(postgres.Int(user.ID)))
+	if _, err = qb.ExecContext(ctx, s.TX); err != nil {
+		return gmodel.User{}, err
+	}
+	_, err = table.TwoFactorRecoveryCode.
+		DELETE().
+		WHERE(table.TwoFactorRecoveryCode.UserID.EQ(postgres.Int(user.ID))).
+		ExecContext(ctx, s.TX)
+	if err != nil {
+		return gmodel.User{}, err
+	}
+	if err = s.TX.Commit(); err != nil {
+		return gmodel.User{}, fmt.Errorf("could not commit changes")
+	}
+	s.TX = nil
+
+	if user.AuthStateID == nil {
+		return s.FindUserById(ctx, user.ID)
+	}
+	return s.FindAuthUserById(ctx, user.ID, *user.AuthStateID)
+}
+
+func (s Service) RegenerateTwoFactorRecoveryCodes(ctx context.Context, user gmodel.User, code string) (codes []string, err error) {
+	if !user.TwoFactorEnabled {
+		return nil, fmt.Errorf("two-factor authentication is not enabled")
+	}
+	db_user, err := s.findUserWithTwoFactorSecret(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.VerifyTwoFactorCode(ctx, db_user, code, false) {
+		return nil, fmt.Errorf("invalid two-factor code")
+	}
+
+	if s.TX, err = s.DB.BeginTx(ctx, nil); err != nil {
+		return nil, err
+	}
+	defer s.TX.Rollback()
+
+	if codes, err = s.CreateTwoFactorRecoveryCodes(ctx, user.ID); err != nil {
+		return nil, fmt.Errorf("could not create recovery codes")
+	}
+	if err = s.TX.Commit(); err != nil {
+		return nil, fmt.Errorf("could not commit changes")
+	}
+	return codes, nil
+}
+
+// Creates a pending login which must be completed with `VerifyTwoFactorLogin`.
+// The returned auth object only carries the challenge id and no token
+func (s Service) CreateTwoFactorChallenge(
+	ctx context.Context,
+	user_id int64,
+	auth_platform model.UserAuthPlatformType,
+	ip_address *string,
+	device_type *model.AuthDeviceType,
+) (gmodel.Auth, error) {
+	qb := table.TwoFactorChallenge.
+		INSERT(
+			table.TwoFactorChallenge.ID,
+			table.TwoFactorChallenge.UserID,
+			table.TwoFactorChallenge.Platform,
+			table.TwoFactorChallenge.IPAddress,
+			table.TwoFactorChallenge.DeviceType,
+		).
+		MODEL(model.TwoFactorChallenge{
+			ID: uuid.New(),
+			UserID: user_id,
+			Platform: auth_platform,
+			IPAddress: ip_address,
+			DeviceType: device_type,
+		}).
+		RETURNING(table.TwoFactorChallenge.AllColumns)
+	var challenge model.TwoFactorChallenge
+	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &challenge); err != nil {
+		return gmodel.Auth{}, fmt.Errorf("could not create two-factor challenge")
+	}
+
+	user, err := s.FindUserById(ctx, user_id)
+	if err != nil {
+		return gmodel.Auth{}, fmt.Errorf("internal error")
+	}
+	challenge_id := challenge.ID.String()
+	return gmodel.Auth{
+		User: &user,
+		TwoFactorChallengeID: &challenge_id,
+	}, nil
+}
+
+func (s Service) deleteTwoFactorChallenge(ctx context.Context, challenge_id uuid.UUID) error {
+	_, err := table.TwoFactorChallenge.
+		DELETE().
+		WHERE(table.TwoFactorChallenge.ID.EQ(postgres.UUID(challenge_id))).
+		ExecContext(ctx, s.DB)
+	return err
+}
+
+// Completes a login started by `CreateTwoFactorChallenge` and returns a verified auth token
+func (s Service) VerifyTwoFactorLogin(ctx context.Context, challenge_id string, code string) (gmodel.Auth, error) {
+	challenge_uuid, err := uuid.Parse(challenge_id)
+	if err != nil {
+		return gmodel.Auth{}, fmt.Errorf("invalid challenge")
+	}
+
+	// counts the try in the same statement as the check so parallel requests can't exceed the limit
+	var challenge model.TwoFactorChallenge
+	qb := table.TwoFactorChallenge.
+		UPDATE(table.TwoFactorChallenge.Tries).
+		SET(table.TwoFactorChallenge.Tries.ADD(postgres.Int(1))).
+		WHERE(postgres.AND(
+			table.TwoFactorChallenge.ID.EQ(postgres.UUID(challenge_uuid)),
+			table.TwoFactorChallenge.Tries.LT(postgres.Int(TWO_FACTOR_CHALLENGE_MAX_TRIES)),
+		)).
+		RETURNING(table.TwoFactorChallenge.AllColumns)
+	if err := qb.QueryContext(ctx, s.DB, &challenge); err != nil {
+		// the challenge either doesn't exist or has no tries left
+		res, err := table.TwoFactorChallenge.
+			DELETE().
+			WHERE(table.TwoFactorChallenge.ID.EQ(postgres.UUID(challenge_uuid))).
+			ExecContext(ctx, s.DB)
+		if err != nil {
+			return gmodel.Auth{}, fmt.Errorf("invalid challenge")
+		}
+		if affected, _ := res.RowsAffected(); affected == 0 {
+			return gmodel.Auth{}, fmt.Errorf("invalid challenge")
+		}
+		return gmodel.Auth{}, fmt.Errorf("maximum number of tries reached for verification")
+	}
+	if time.Since(challenge.CreatedAt) > TWO_FACTOR_CHALLENGE_TTL {
+		s.deleteTwoFactorChallenge(ctx, challenge.ID)
+		return gmodel.Auth{}, fmt.Errorf("two-factor challenge has expired")
+	}
+
+	user, err := s.findUserWithTwoFactorSecret(ctx, challenge.UserID)
+	if err != nil {
+		return gmodel.Auth{}, fmt.Errorf("invalid challenge")
+	}
+	if !s.VerifyTwoFactorCode(ctx, user, code, true) {
+		return gmodel.Auth{}, fmt.Errorf("invalid two-factor code")
+	}
+	if err := s.deleteTwoFactorChallenge(ctx, challenge.ID); err != nil {
+		return gmodel.Auth{}, err
+	}
+
+	auth, err := s.CreateAuthStateWithJwt(ctx, user.ID, challenge.Platform, challenge.IPAddress, challenge.DeviceType)
+	if err != nil {
+		return gmodel.Auth{}, err
+	}
+	if err := s.MarkAuthStateTwoFactorVerified(ctx, *auth.User.AuthStateID); err != nil {
+		return gmodel.Auth{}, fmt.Errorf("could not verify auth state")
+	}
+	verified_user, err := s.FindAuthUserById(ctx, user.ID, *auth.User.AuthStateID)
+	if err != nil {
+		return gmodel.Auth{}, fmt.Errorf("internal error")
+	}
+	auth.User = &verified_user
+	return auth, nil
+}
@@ -10,7 +10,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
 	"github.com/pricetra/api/database/jet/postgres/public/model"
 	"github.com/pricetra/api/graph/gmodel"
 	"github.com/pricetra/api/utils"
@@ -181,7 +180,7 @@ func (s Service) UPCItemdbSaveSearchProducts(ctx context.Context, user gmodel.Us
 			}
 			// Upload image...
 			if input.ImageURL != nil {
-				_, err := s.ImageUrlUpload(ctx, *input.ImageURL, uploader.UploadParams{
+				_, err := s.ImageUrlUpload(ctx, *input.ImageURL, ImageUploadParams{
 					PublicID: product.Code,
 					Tags:     []string{"PRODUCT"},
 				})
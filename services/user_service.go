@@ -74,6 +74,7 @@ func (s Service) FindAuthUserById(ctx context.Context, user_id int64, auth_state
 			table.AuthState.Platform,
 			table.AuthState.DeviceType,
 			table.AuthState.ExpoPushToken,
+			table.AuthState.TwoFactorVerifiedAt,
 			table.Address.AllColumns,
 			table.Country.Name,
 		).
@@ -192,6 +193,9 @@ func (s Service) GoogleAuthentication(
 		}
 		new_user = true
 	}
+	if user.TwoFactorEnabled {
+		return s.CreateTwoFactorChallenge(ctx, user.ID, model.UserAuthPlatformType_Google, ip_address, device_type)
+	}
 	auth_state, err := s.CreateAuthStateWithJwt(ctx, user.ID, model.UserAuthPlatformType_Google, ip_address, device_type)
 	if err == nil && new_user {
 		auth_state.IsNewUser = &new_user
@@ -221,6 +225,9 @@ func (s Service) LoginInternal(
 	if !s.VerifyPasswordHash(password, *verify_user.Password) {
 		return gmodel.Auth{}, fmt.Errorf("incorrect email or password")
 	}
+	if verify_user.TwoFactorEnabled {
+		return s.CreateTwoFactorChallenge(ctx, verify_user.ID, model.UserAuthPlatformType_Internal, ip_address, device_type)
+	}
 
 	return s.CreateAuthStateWithJwt(ctx, verify_user.ID, model.UserAuthPlatformType_Internal, ip_address, device_type)
 }
@@ -311,7 +318,10 @@ func (s Service) ResendEmailVerification(ctx context.Context, user gmodel.User)
 	defer s.TX.Rollback()
 
 	_, err = table.EmailVerification.DELETE().
-		WHERE(table.EmailVerification.UserID.EQ(postgres.Int(user.ID))).
+		WHERE(postgres.AND(
+			table.EmailVerification.UserID.EQ(postgres.Int(user.ID)),
+			table.EmailVerification.NewEmail.IS_NULL(),
+		)).
 		ExecContext(ctx, s.TX)
 	if err != nil {
 		return model.EmailVerification{}, fmt.Errorf("user email verification entry deletion failed")
@@ -330,7 +340,10 @@ func (s Service) ResendEmailVerification(ctx context.Context, user gmodel.User)
 func (s Service) FindEmailVerificationByCode(ctx context.Context, verification_code string) (model.EmailVerification, error) {
 	qb := table.EmailVerification.
 		SELECT(table.EmailVerification.AllColumns).
-		WHERE(table.EmailVerification.Code.EQ(postgres.String(verification_code))).
+		WHERE(postgres.AND(
+			table.EmailVerification.Code.EQ(postgres.String(verification_code)),
+			table.EmailVerification.NewEmail.IS_NULL(),
+		)).
 		LIMIT(1)
 	var email_verification model.EmailVerification
 	if err := qb.QueryContext(ctx, s.DbOrTxQueryable(), &email_verification); err != nil {
@@ -459,7 +472,12 @@ func (s Service) UpdateUserFull(ctx context.Context, user gmodel.User, input gmo
 
 	u := model.User{}
 	columns := postgres.ColumnList{}
-	if input.Email != nil {
+	email_changed := false
+	if input.Email != nil && *input.Email != user.Email {
+		if s.UserEmailExists(ctx, *input.Email) {
+			return gmodel.User{}, fmt.Errorf("email already exists")
+		}
+		email_changed = true
 		columns = append(columns, table.User.Email)
 		u.Email = *input.Email
 	}
@@ -520,7 +538,13 @@ func (s Service) UpdateUserFull(ctx context.Context, user gmodel.User, input gmo
 	if err = qb.QueryContext(ctx, s.DbOrTxQueryable(), &updated_user); err != nil {
 		return gmodel.User{}, err
 	}
-	if user.AuthStateID == nil {
+	// Existing tokens carry the old email claim so all sessions are invalidated
+	if email_changed {
+		if err = s.LogoutAllForUser(ctx, user.ID); err != nil {
+			return gmodel.User{}, err
+		}
+	}
+	if user.AuthStateID == nil || email_changed {
 		if input.Address != nil {
 			updated_user.Address = &address
 		}
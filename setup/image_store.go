@@ -0,0 +1,56 @@
+package setup
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pricetra/api/services"
+	"github.com/pricetra/api/types"
+)
+
+// Path locally stored images are served from in development
+const LOCAL_IMAGES_ENDPOINT string = "/images"
+
+// Creates the image store selected by the IMAGE_STORE env variable.
+// Supports "cloudinary" (default), "s3" and "local"
+func NewImageStore(tokens *types.Tokens) (services.ImageStore, error) {
+	switch os.Getenv("IMAGE_STORE") {
+	case "", "cloudinary":
+		return services.NewCloudinaryImageStore(
+			tokens.Cloudinary.CloudName,
+			tokens.Cloudinary.ApiKey,
+			tokens.Cloudinary.ApiSecret,
+		)
+	case "s3":
+		if tokens.S3.Endpoint == "" || tokens.S3.Bucket == "" {
+			return nil, fmt.Errorf("S3_ENDPOINT and S3_BUCKET are required for the s3 image store")
+		}
+		return &services.S3ImageStore{
+			Endpoint: tokens.S3.Endpoint,
+			Region: tokens.S3.Region,
+			Bucket: tokens.S3.Bucket,
+			AccessKeyID: tokens.S3.AccessKeyID,
+			SecretAccessKey: tokens.S3.SecretAccessKey,
+			PublicUrl: tokens.S3.PublicUrl,
+		}, nil
+	case "local":
+		directory := os.Getenv("IMAGE_STORE_DIRECTORY")
+		if directory == "" {
+			directory = "./uploads"
+		}
+		base_url := os.Getenv("IMAGE_STORE_BASE_URL")
+		if base_url == "" {
+			port := os.Getenv("PORT")
+			if port == "" {
+				port = "8080"
+			}
+			base_url = fmt.Sprintf("http://localhost:%s%s", port, LOCAL_IMAGES_ENDPOINT)
+		}
+		return &services.LocalImageStore{
+			Directory: directory,
+			BaseUrl: base_url,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown image store %s", os.Getenv("IMAGE_STORE"))
+	}
+}
@@ -3,13 +3,13 @@ package setup
 import (
 	"context"
 	"database/sql"
+	"net/http"
 	"os"
 
 	vision "cloud.google.com/go/vision/apiv1"
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/ayaanqui/go-migration-tool/migration_tool"
-	"github.com/cloudinary/cloudinary-go/v2"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/cors"
 	"github.com/go-playground/validator/v10"
@@ -51,6 +51,14 @@ func NewServer(db_conn *sql.DB, router *chi.Mux) *types.ServerBase {
 			ApiKey: os.Getenv("CLOUDINARY_API_KEY"),
 			ApiSecret: os.Getenv("CLOUDINARY_API_SECRET"),
 		},
+		S3: types.S3Tokens{
+			Endpoint: os.Getenv("S3_ENDPOINT"),
+			Region: os.Getenv("S3_REGION"),
+			Bucket: os.Getenv("S3_BUCKET"),
+			AccessKeyID: os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			PublicUrl: os.Getenv("S3_PUBLIC_URL"),
+		},
 		UPCitemdbUserKey: os.Getenv("UPCITEMDB_USER_KEY"),
 		GoogleMapsApiKey: os.Getenv("GOOGLE_MAPS_API_KEY"),
 		ExpoPushNotificationClientKey: os.Getenv("EXPO_PUSH_NOTIFICATION_CLIENT_KEY"),
@@ -62,12 +70,8 @@ func NewServer(db_conn *sql.DB, router *chi.Mux) *types.ServerBase {
 		},
 	}
 
-	// Setup Cloudinary CDN
-	cloudinary, err := cloudinary.NewFromParams(
-		server.Tokens.Cloudinary.CloudName,
-		server.Tokens.Cloudinary.ApiKey,
-		server.Tokens.Cloudinary.ApiSecret,
-	)
+	// Setup image storage
+	image_store, err := NewImageStore(server.Tokens)
 	if err != nil {
 		panic(err)
 	}
@@ -89,21 +93,28 @@ func NewServer(db_conn *sql.DB, router *chi.Mux) *types.ServerBase {
 		openfoodfacts_client.Sandbox()
 	}
 
+	trusted_proxies, err := services.ParseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		panic(err)
+	}
+
 	service := services.Service{
 		DB: server.DB,
 		StructValidator: server.StructValidator,
 		Tokens: server.Tokens,
-		Cloudinary: cloudinary,
+		ImageStore: image_store,
 		GoogleMapsClient: maps_client,
 		ExpoPushClient: expo.NewPushClient(&expo.ClientConfig{
 			AccessToken: server.Tokens.ExpoPushNotificationClientKey,
 		}),
 		GoogleVisionApiClient: vision_client,
 		OpenFoodFactsClient: &openfoodfacts_client,
+		TrustedProxies: trusted_proxies,
 	}
 
 	// Startup utils...
-	StartupUtils(service)
+	job_worker := StartupUtils(service)
+	server.ShutdownHooks = append(server.ShutdownHooks, job_worker.Shutdown)
 
 	cors_options := cors.Options{}
 	if os.Getenv("ENV") == "production" {
@@ -127,6 +138,9 @@ func NewServer(db_conn *sql.DB, router *chi.Mux) *types.ServerBase {
 
 	if os.Getenv("ENV") != "production" {
 		server.Router.Handle("/playground", playground.Handler("GraphQL Playground", GRAPH_ENDPOINT))
+		if local_store, ok := image_store.(*services.LocalImageStore); ok {
+			server.Router.Handle(LOCAL_IMAGES_ENDPOINT + "/*", http.StripPrefix(LOCAL_IMAGES_ENDPOINT, local_store))
+		}
 	}
 
 	server.Router.Group(func(chi_router chi.Router) {
@@ -136,6 +150,7 @@ func NewServer(db_conn *sql.DB, router *chi.Mux) *types.ServerBase {
 			Service: service,
 		}
 		c.Directives.IsAuthenticated = service.IsAuthenticatedDirective
+		c.Directives.Audited = service.AuditedDirective
 		graphql_handler := handler.NewDefaultServer(graph.NewExecutableSchema(c))
 
 		chi_router.Use(service.AuthorizationMiddleware)
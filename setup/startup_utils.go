@@ -3,39 +3,43 @@ package setup
 import (
 	"context"
 	"log"
+	"time"
 
-	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
-	"github.com/go-jet/jet/v2/postgres"
-	"github.com/pricetra/api/database/jet/postgres/public/table"
 	"github.com/pricetra/api/services"
 )
 
-func MigrateAllRemoteProductImagesToCDN(service services.Service) {
+const (
+	JOB_WORKER_POLL_INTERVAL = 5 * time.Second
+	JOB_WORKER_BATCH_SIZE = 10
+)
+
+// Recurring jobs. Schedules are upserted on startup so changing the cron expression here reschedules them
+var job_schedules = []struct{
+	Name string
+	Type string
+	Cron string
+}{
+	{"delete-scheduled-accounts", services.JOB_DELETE_SCHEDULED_ACCOUNTS, "@hourly"},
+	{"refresh-user-reputations", services.JOB_REFRESH_ALL_USER_REPUTATIONS, "0 3 * * *"},
+	{"expire-sale-prices", services.JOB_EXPIRE_SALE_PRICES, "*/15 * * * *"},
+}
+
+// Registers recurring jobs, queues one-off startup jobs and starts the job worker
+func StartupUtils(service services.Service) *services.JobWorker {
 	ctx := context.Background()
-	qb := table.Product.
-		SELECT(table.Product.Image.AS("image"), table.Product.Code.AS("code")).
-		FROM(table.Product).
-		WHERE(table.Product.Image.NOT_EQ(postgres.String("")))
-	var res []struct{
-		Image string
-		Code string
-	}
-	err := qb.QueryContext(ctx, service.DB, &res)
-	if err != nil {
-		log.Fatal(err)
-		return
-	}
-	
-	for _, ob := range res {
-		_, err := service.ImageUrlUpload(ctx, ob.Image, uploader.UploadParams{
-			PublicID: ob.Code,
-			Tags: []string{"PRODUCT"},
-		})
-		if err != nil {
-			log.Fatal(err)
+	for _, schedule := range job_schedules {
+		if _, err := service.UpsertJobSchedule(ctx, schedule.Name, schedule.Type, schedule.Cron, nil); err != nil {
+			log.Printf("could not register job schedule %s. %s\n", schedule.Name, err.Error())
 		}
 	}
-}
 
-func StartupUtils(service services.Service) {
+	// Parses legacy price conditions into structured deals
+	backfill_key := services.JOB_BACKFILL_PRICE_DEALS
+	service.EnqueueJobOrLog(ctx, services.JOB_BACKFILL_PRICE_DEALS, nil, services.EnqueueJobOptions{
+		UniqueKey: &backfill_key,
+	})
+
+	worker := services.NewJobWorker(service, JOB_WORKER_POLL_INTERVAL, JOB_WORKER_BATCH_SIZE)
+	worker.Start()
+	return worker
 }
@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/services"
+)
+
+func TestAccount(t *testing.T) {
+	user_input := gmodel.CreateAccountInput{
+		Email: "account_user@email.com",
+		Name: "Account User",
+		Password: "password123",
+	}
+	user, _, err := service.CreateInternalUser(ctx, user_input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := service.LoginInternal(ctx, user_input.Email, user_input.Password, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user = *auth.User
+
+	t.Run("export json", func(t *testing.T) {
+		if _, err := service.CreateSearchHistoryEntry(ctx, "milk", &user); err != nil {
+			t.Fatal(err)
+		}
+
+		export, err := service.ExportUserData(ctx, user, gmodel.DataExportFormatJSON)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if export.ContentType != "application/json" {
+			t.Fatal("incorrect content type", export.ContentType)
+		}
+		raw, err := base64.StdEncoding.DecodeString(export.Data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var data services.UserDataExport
+		if err := json.Unmarshal(raw, &data); err != nil {
+			t.Fatal(err)
+		}
+		if data.Profile.ID != user.ID {
+			t.Fatal("profile does not match user")
+		}
+		if len(data.SearchHistory) != 1 || data.SearchHistory[0].SearchTerm != "milk" {
+			t.Fatal("search history was not exported", data.SearchHistory)
+		}
+	})
+
+	t.Run("export zip", func(t *testing.T) {
+		export, err := service.ExportUserData(ctx, user, gmodel.DataExportFormatZip)
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, err := base64.StdEncoding.DecodeString(export.Data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		archive, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, f := range archive.File {
+			if f.Name == "profile.json" {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatal("profile.json should be in the archive")
+		}
+	})
+
+	t.Run("request and cancel deletion", func(t *testing.T) {
+		updated_user, err := service.RequestAccountDeletion(ctx, user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if updated_user.DeletionScheduledAt == nil {
+			t.Fatal("deletion should be scheduled")
+		}
+		if updated_user.DeletionScheduledAt.Sub(*updated_user.DeletionRequestedAt) != services.ACCOUNT_DELETION_GRACE_PERIOD {
+			t.Fatal("deletion should be scheduled after the grace period")
+		}
+		if _, err := service.RequestAccountDeletion(ctx, updated_user); err == nil {
+			t.Fatal("should not be able to request deletion twice")
+		}
+
+		deleted, err := service.DeleteScheduledAccounts(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.FindUserById(ctx, user.ID); err != nil || deleted != 0 {
+			t.Fatal("account should not be deleted during the grace period")
+		}
+
+		updated_user, err = service.CancelAccountDeletion(ctx, updated_user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if updated_user.DeletionScheduledAt != nil {
+			t.Fatal("deletion should be cancelled")
+		}
+	})
+
+	t.Run("delete account", func(t *testing.T) {
+		if err := service.DeleteUserAccount(ctx, user.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.FindUserById(ctx, user.ID); err == nil {
+			t.Fatal("user should be deleted")
+		}
+		if _, err := service.LoginInternal(ctx, user_input.Email, user_input.Password, nil, nil); err == nil {
+			t.Fatal("deleted user should not be able to login")
+		}
+	})
+}
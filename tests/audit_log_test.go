@@ -0,0 +1,168 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/services"
+	"github.com/pricetra/api/types"
+)
+
+func TestAuditLog(t *testing.T) {
+	admin, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Audit admin",
+		Email: "audit_admin@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin.Role = gmodel.UserRoleSuperAdmin
+	target, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Audit target",
+		Email: "audit_target@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entity := "user"
+	id_arg := "userId"
+	new_role := gmodel.UserRoleContributor
+	avatar := "data:image/png;base64,iVBORw0KGgo="
+	input := gmodel.UpdateUserFull{
+		Role: &new_role,
+		AvatarBase64: &avatar,
+	}
+	audit_ctx := context.WithValue(ctx, types.AuthUserKey, admin)
+	audit_ctx = context.WithValue(audit_ctx, types.RequestMetadataKey, types.RequestMetadata{
+		IpAddress: "127.0.0.1",
+		UserAgent: "go-test",
+	})
+	audit_ctx = graphql.WithFieldContext(audit_ctx, &graphql.FieldContext{
+		Args: map[string]any{
+			"userId": target.ID,
+			"input": input,
+		},
+	})
+	action := "updateUserById"
+
+	t.Run("successful mutation", func(t *testing.T) {
+		_, err := service.AuditedDirective(audit_ctx, nil, func(ctx context.Context) (any, error) {
+			updated_user, err := service.UpdateUserFull(ctx, target, gmodel.UpdateUserFull{ Role: &new_role })
+			return &updated_user, err
+		}, &action, &entity, &id_arg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := service.PaginatedAuditLogs(ctx, gmodel.PaginatorInput{ Page: 1, Limit: 10 }, &gmodel.AuditLogFilter{
+			Entity: &entity,
+			EntityID: &target.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.AuditLogs) != 1 {
+			t.Fatal("expected 1 audit log entry", len(res.AuditLogs))
+		}
+		entry := res.AuditLogs[0]
+		if entry.ActorID == nil || *entry.ActorID != admin.ID {
+			t.Fatal("actor does not match")
+		}
+		if !entry.Success || entry.Action != action {
+			t.Fatal("incorrect action or status", entry)
+		}
+		if entry.Before == nil || !strings.Contains(*entry.Before, fmt.Sprintf(`"role":"%s"`, gmodel.UserRoleConsumer)) {
+			t.Fatal("before snapshot should contain the old role", entry.Before)
+		}
+		if entry.After == nil || !strings.Contains(*entry.After, fmt.Sprintf(`"role":"%s"`, new_role)) {
+			t.Fatal("after snapshot should contain the new role", entry.After)
+		}
+		if entry.Arguments == nil || strings.Contains(*entry.Arguments, avatar) {
+			t.Fatal("base64 arguments should be redacted", entry.Arguments)
+		}
+		if entry.IPAddress == nil || *entry.IPAddress != "127.0.0.1" {
+			t.Fatal("request metadata should be recorded")
+		}
+	})
+
+	t.Run("failed mutation", func(t *testing.T) {
+		_, err := service.AuditedDirective(audit_ctx, nil, func(ctx context.Context) (any, error) {
+			return nil, fmt.Errorf("something went wrong")
+		}, &action, &entity, &id_arg)
+		if err == nil {
+			t.Fatal("directive should return the resolver error")
+		}
+
+		success := false
+		res, err := service.PaginatedAuditLogs(ctx, gmodel.PaginatorInput{ Page: 1, Limit: 10 }, &gmodel.AuditLogFilter{
+			ActorID: &admin.ID,
+			Success: &success,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.AuditLogs) != 1 || res.AuditLogs[0].Error == nil {
+			t.Fatal("failed mutation should be recorded with an error")
+		}
+	})
+
+	t.Run("append only", func(t *testing.T) {
+		_, err := table.AuditLog.
+			DELETE().
+			WHERE(table.AuditLog.ActorID.EQ(postgres.Int(admin.ID))).
+			ExecContext(ctx, db)
+		if err == nil {
+			t.Fatal("audit log entries should not be deletable")
+		}
+	})
+
+	t.Run("client ip address", func(t *testing.T) {
+		proxies, err := services.ParseTrustedProxies("10.0.0.0/8, 127.0.0.1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := services.ParseTrustedProxies("not-an-ip"); err == nil {
+			t.Fatal("invalid proxies should not parse")
+		}
+		proxied_service := service
+		proxied_service.TrustedProxies = proxies
+		cases := []struct{
+			remote_addr string
+			forwarded_for string
+			ip string
+		}{
+			{"203.0.113.7:5000", "1.2.3.4", "203.0.113.7"},
+			{"10.1.2.3:5000", "", "10.1.2.3"},
+			{"10.1.2.3:5000", "1.2.3.4", "1.2.3.4"},
+			{"10.1.2.3:5000", "6.6.6.6, 1.2.3.4, 10.4.5.6", "1.2.3.4"},
+			{"127.0.0.1:5000", "10.4.5.6", "10.4.5.6"},
+		}
+		for _, c := range cases {
+			r := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+			r.RemoteAddr = c.remote_addr
+			if c.forwarded_for != "" {
+				r.Header.Set("X-Forwarded-For", c.forwarded_for)
+			}
+			if ip := proxied_service.RequestIpAddress(r); ip != c.ip {
+				t.Fatal("unexpected client ip", c.remote_addr, c.forwarded_for, ip)
+			}
+		}
+		r := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+		r.RemoteAddr = "10.1.2.3:5000"
+		r.Header.Set("X-Forwarded-For", "1.2.3.4")
+		if ip := service.RequestIpAddress(r); ip != "10.1.2.3" {
+			t.Fatal("forwarded header should be ignored without trusted proxies", ip)
+		}
+	})
+}
@@ -0,0 +1,216 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+func TestBillingRate(t *testing.T) {
+	admin, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Billing rate admin",
+		Email: "billing_rate_admin@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin.Role = gmodel.UserRoleSuperAdmin
+
+	subcategory, err := service.CategoryRecursiveInsert(ctx, "Billing Rate Test Category > Billing Rate Test Subcategory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent, err := service.CategoryRecursiveInsert(ctx, "Billing Rate Test Category")
+	if err != nil {
+		t.Fatal(err)
+	}
+	product, err := service.CreateProduct(ctx, admin, gmodel.CreateProduct{
+		Name: "Billing rate test product",
+		Description: "Some description",
+		Brand: "Pricetra",
+		Code: "BILLINGRATETESTBARCODE",
+		CategoryID: subcategory.ID,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var subcategory_rate gmodel.ProductBillingRate
+	t.Run("create rates", func(t *testing.T) {
+		backdated := time.Now().Add(-1 * time.Hour)
+		if _, err := service.CreateBillingRate(ctx, admin, gmodel.CreateBillingRate{
+			Type: gmodel.ProductBillingTypeCreate,
+			Rate: 1,
+			CurrencyCode: "USD",
+			CategoryID: &parent.ID,
+			EffectiveFrom: &backdated,
+		}); err == nil {
+			t.Fatal("rate versions should not be backdated")
+		}
+
+		if _, err := service.CreateBillingRate(ctx, admin, gmodel.CreateBillingRate{
+			Type: gmodel.ProductBillingTypeCreate,
+			Rate: 1,
+			CurrencyCode: "USD",
+			CategoryID: &parent.ID,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		subcategory_rate, err = service.CreateBillingRate(ctx, admin, gmodel.CreateBillingRate{
+			Type: gmodel.ProductBillingTypeCreate,
+			Rate: 12.5,
+			CurrencyCode: "USD",
+			CategoryID: &subcategory.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if subcategory_rate.Rate != 12.5 {
+			t.Fatal("rates above 9.99 should be supported", subcategory_rate.Rate)
+		}
+	})
+
+	t.Run("billing uses the most specific rate", func(t *testing.T) {
+		billing, err := service.CreateProductBilling(ctx, admin, model.ProductBillingType_Create, product, nil, product, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if billing.Rate != 12.5 || billing.RateID == nil || *billing.RateID != subcategory_rate.ID {
+			t.Fatal("billing should reference the subcategory rate", billing)
+		}
+	})
+
+	t.Run("new version closes the previous one", func(t *testing.T) {
+		new_rate, err := service.CreateBillingRate(ctx, admin, gmodel.CreateBillingRate{
+			Type: gmodel.ProductBillingTypeCreate,
+			Rate: 15,
+			CurrencyCode: "USD",
+			CategoryID: &subcategory.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		old_rate, err := service.FindBillingRateById(ctx, subcategory_rate.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if old_rate.EffectiveTo == nil || !old_rate.EffectiveTo.Equal(new_rate.EffectiveFrom) {
+			t.Fatal("previous version should end when the new version starts", old_rate.EffectiveTo)
+		}
+
+		billing, err := service.CreateProductBilling(ctx, admin, model.ProductBillingType_Create, product, nil, product, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if billing.Rate != 15 || *billing.RateID != new_rate.ID {
+			t.Fatal("billing should use the new rate version", billing)
+		}
+	})
+
+	t.Run("applied versions are immutable", func(t *testing.T) {
+		rate := 20.0
+		if _, err := service.UpdateBillingRate(ctx, subcategory_rate.ID, gmodel.UpdateBillingRate{ Rate: &rate }); err == nil {
+			t.Fatal("applied rate versions should not be editable")
+		}
+		if _, err := service.DeleteBillingRate(ctx, subcategory_rate.ID); err == nil {
+			t.Fatal("applied rate versions should not be deletable")
+		}
+	})
+
+	t.Run("delete scheduled version", func(t *testing.T) {
+		future := time.Now().Add(24 * time.Hour)
+		scheduled, err := service.CreateBillingRate(ctx, admin, gmodel.CreateBillingRate{
+			Type: gmodel.ProductBillingTypeCreate,
+			Rate: 20,
+			CurrencyCode: "USD",
+			CategoryID: &subcategory.ID,
+			EffectiveFrom: &future,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.DeleteBillingRate(ctx, scheduled.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		rate_type := gmodel.ProductBillingTypeCreate
+		rates, err := service.FindBillingRates(ctx, &gmodel.BillingRateFilter{
+			Type: &rate_type,
+			CategoryID: &subcategory.ID,
+			EffectiveAt: &future,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rates) != 1 || rates[0].Rate != 15 || rates[0].EffectiveTo != nil {
+			t.Fatal("previous version should be reopened", rates)
+		}
+	})
+
+	t.Run("bounded version inside an open one", func(t *testing.T) {
+		from := time.Now().Add(48 * time.Hour)
+		to := from.Add(24 * time.Hour)
+		bounded, err := service.CreateBillingRate(ctx, admin, gmodel.CreateBillingRate{
+			Type: gmodel.ProductBillingTypeCreate,
+			Rate: 5,
+			CurrencyCode: "USD",
+			CategoryID: &subcategory.ID,
+			EffectiveFrom: &from,
+			EffectiveTo: &to,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rate_type := gmodel.ProductBillingTypeCreate
+		for _, c := range []struct{
+			at time.Time
+			rate float64
+		}{
+			{from.Add(-1 * time.Hour), 15},
+			{from.Add(time.Hour), bounded.Rate},
+			{to.Add(time.Hour), 15},
+		} {
+			rates, err := service.FindBillingRates(ctx, &gmodel.BillingRateFilter{
+				Type: &rate_type,
+				CategoryID: &subcategory.ID,
+				EffectiveAt: &c.at,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rates) != 1 || rates[0].Rate != c.rate {
+				t.Fatal("unexpected rate versions", c.at, rates)
+			}
+		}
+	})
+
+	t.Run("deleting a category keeps billed amounts", func(t *testing.T) {
+		billing, err := service.CreateProductBilling(ctx, admin, model.ProductBillingType_Create, product, nil, product, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if billing.RateID == nil {
+			t.Fatal("billing should reference the subcategory rate", billing)
+		}
+		if _, _, err := service.UpdateProductById(ctx, admin, product.ID, gmodel.UpdateProduct{ CategoryID: &parent.ID }); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.ExecContext(ctx, `delete from "category" where "id" = $1`, subcategory.ID); err != nil {
+			t.Fatal("categories with billed rates should be deletable", err)
+		}
+		if _, err := service.FindBillingRateById(ctx, *billing.RateID); err == nil {
+			t.Fatal("rates of the category should be deleted")
+		}
+		deleted_billing, err := service.FindProductBillingById(ctx, billing.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if deleted_billing.RateID != nil || deleted_billing.Rate != billing.Rate {
+			t.Fatal("billing should keep its amount without the rate", deleted_billing)
+		}
+	})
+}
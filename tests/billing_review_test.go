@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+func TestBillingReview(t *testing.T) {
+	admin, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Billing review admin",
+		Email: "billing_review_admin@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin.Role = gmodel.UserRoleAdmin
+	contributor, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Billing review contributor",
+		Email: "billing_review_contributor@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	category, err := service.CategoryRecursiveInsert(ctx, "Billing Review Test Category")
+	if err != nil {
+		t.Fatal(err)
+	}
+	product, err := service.CreateProduct(ctx, contributor, gmodel.CreateProduct{
+		Name: "Billing review product",
+		Description: "Some description",
+		Brand: "Pricetra",
+		Code: "BILLINGREVIEWBARCODE",
+		CategoryID: category.ID,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update := func(input gmodel.UpdateProduct) model.ProductBilling {
+		billing, err := service.CreateProductBilling(ctx, contributor, model.ProductBillingType_Update, product, nil, input, product)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return billing
+	}
+
+	t.Run("no-op update is rejected", func(t *testing.T) {
+		billing := update(gmodel.UpdateProduct{ Name: &product.Name, Brand: &product.Brand })
+		if billing.Status != model.ProductBillingStatus_Rejected || billing.StatusReason == nil {
+			t.Fatal("no-op update should be rejected", billing.Status)
+		}
+	})
+
+	t.Run("trivial update is rejected", func(t *testing.T) {
+		name := strings.ToUpper(product.Name) + "  "
+		billing := update(gmodel.UpdateProduct{ Name: &name })
+		if billing.Status != model.ProductBillingStatus_Rejected || !strings.Contains(*billing.StatusReason, "trivial") {
+			t.Fatal("trivial update should be rejected", billing.Status, billing.StatusReason)
+		}
+	})
+
+	t.Run("real update is approved", func(t *testing.T) {
+		description := "A much better description"
+		billing := update(gmodel.UpdateProduct{ Description: &description })
+		if billing.Status != model.ProductBillingStatus_Approved {
+			t.Fatal("real update should be approved", billing.Status, billing.StatusReason)
+		}
+	})
+
+	var held model.ProductBilling
+	t.Run("repeated update is held", func(t *testing.T) {
+		brand := "Pricetra Foods"
+		held = update(gmodel.UpdateProduct{ Brand: &brand })
+		if held.Status != model.ProductBillingStatus_Held || !strings.Contains(*held.StatusReason, "repeated") {
+			t.Fatal("repeated update should be held", held.Status, held.StatusReason)
+		}
+
+		flagged, err := service.FlaggedProductBilling(ctx, gmodel.PaginatorInput{ Page: 1, Limit: 100 }, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, b := range flagged.Data {
+			if b.ID == held.ID {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatal("held billing row should be listed for admins")
+		}
+	})
+
+	t.Run("admin review", func(t *testing.T) {
+		reason := "verified manually"
+		billing, err := service.ReviewProductBilling(ctx, admin, held.ID, gmodel.ProductBillingStatusApproved, &reason)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if billing.Status != gmodel.ProductBillingStatusApproved || billing.ReviewedByID == nil || *billing.ReviewedByID != admin.ID {
+			t.Fatal("billing row should be approved by admin", billing)
+		}
+
+		summary, err := service.EarningsSummary(ctx, contributor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(summary) != 1 || summary[0].PendingCount != 2 || summary[0].HeldCount != 0 {
+			t.Fatal("rejected rows should be excluded from earnings", summary)
+		}
+	})
+}
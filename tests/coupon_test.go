@@ -0,0 +1,226 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/services"
+)
+
+func TestCoupons(t *testing.T) {
+	t.Run("best coupon combination", func(t *testing.T) {
+		one, ten, half := 1.0, 10.0, 50.0
+		store_coupon := gmodel.Coupon{ ID: 1, Type: gmodel.CouponTypeStore, DiscountAmount: &one, MinimumQuantity: 1, Stackable: true }
+		manufacturer_coupon := gmodel.Coupon{ ID: 2, Type: gmodel.CouponTypeManufacturer, DiscountPercent: &ten, MinimumQuantity: 1, Stackable: true }
+		price, applied := services.BestCouponCombination(4, []gmodel.Coupon{store_coupon, manufacturer_coupon})
+		if price != 2.6 || len(applied) != 2 {
+			t.Fatal("stackable store and manufacturer coupons should be combined", price, applied)
+		}
+
+		exclusive := gmodel.Coupon{ ID: 3, Type: gmodel.CouponTypeStore, DiscountPercent: &half, MinimumQuantity: 1 }
+		price, applied = services.BestCouponCombination(4, []gmodel.Coupon{store_coupon, manufacturer_coupon, exclusive})
+		if price != 2 || len(applied) != 1 || applied[0].ID != exclusive.ID {
+			t.Fatal("a better non-stackable coupon should be used on its own", price, applied)
+		}
+
+		multi_unit := gmodel.Coupon{ ID: 4, Type: gmodel.CouponTypeManufacturer, DiscountAmount: &one, MinimumQuantity: 2 }
+		if price, _ := services.BestCouponCombination(4, []gmodel.Coupon{multi_unit}); price != 3.5 {
+			t.Fatal("amounts off should be spread across the minimum quantity", price)
+		}
+	})
+
+	user, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Coupon user",
+		Email: "coupon@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Coupon admin",
+		Email: "coupon_admin@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin.Role = gmodel.UserRoleAdmin
+
+	img := "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAAC0lEQVR42mP8//8/AwAI/wH+9Q4AAAAASUVORK5CYII="
+	store, err := service.CreateStore(ctx, user, gmodel.CreateStore{
+		Name: "Coupon Store",
+		LogoBase64: &img,
+		Website: "https://pricetra.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other_store, err := service.CreateStore(ctx, user, gmodel.CreateStore{
+		Name: "Other Coupon Store",
+		LogoBase64: &img,
+		Website: "https://pricetra.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	branch, err := service.CreateBranch(ctx, user, gmodel.CreateBranch{
+		Name: "Coupon Branch",
+		StoreID: store.ID,
+		Address: &gmodel.CreateAddress{
+			Latitude: 41.900612,
+			Longitude: -88.3436658,
+			MapsLink: "https://maps.google.com",
+			FullAddress: "855 S Randall Rd, St. Charles, IL 60174, USA",
+			City: "St. Charles",
+			AdministrativeDivision: "Illinois",
+			CountryCode: "US",
+			ZipCode: 60174,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent_category, err := service.CategoryRecursiveInsert(ctx, "Coupon Category")
+	if err != nil {
+		t.Fatal(err)
+	}
+	category, err := service.CategoryRecursiveInsert(ctx, "Coupon Category > Coupon Subcategory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	product, err := service.CreateProduct(ctx, user, gmodel.CreateProduct{
+		Name: "Coupon Product",
+		Description: "Some description",
+		Brand: "Couponly",
+		Code: "036000291537",
+		CategoryID: category.ID,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	price, err := service.CreatePrice(ctx, user, gmodel.CreatePrice{
+		ProductID: product.ID,
+		BranchID: branch.ID,
+		Amount: 5,
+		UnitType: "item",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	coupon_price := func() *gmodel.Stock {
+		stock, err := service.FindStockById(ctx, price.StockID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := service.ApplyCouponPrices(ctx, []*gmodel.Stock{&stock}); err != nil {
+			t.Fatal(err)
+		}
+		return &stock
+	}
+
+	t.Run("invalid coupons", func(t *testing.T) {
+		amount := 1.0
+		if _, err := service.SubmitCoupon(ctx, user, gmodel.CreateCoupon{
+			Type: gmodel.CouponTypeStore,
+			Scope: gmodel.CouponScopeProduct,
+			ProductID: &product.ID,
+			Title: "No store",
+			DiscountAmount: &amount,
+		}); err == nil {
+			t.Fatal("store coupons should need a store")
+		}
+		percent := 10.0
+		if _, err := service.SubmitCoupon(ctx, user, gmodel.CreateCoupon{
+			Type: gmodel.CouponTypeManufacturer,
+			Scope: gmodel.CouponScopeProduct,
+			ProductID: &product.ID,
+			Title: "Both discounts",
+			DiscountAmount: &amount,
+			DiscountPercent: &percent,
+		}); err == nil {
+			t.Fatal("coupons should have a single discount")
+		}
+	})
+
+	t.Run("submitted coupons are moderated", func(t *testing.T) {
+		amount := 1.0
+		brand := "couponly"
+		coupon, err := service.SubmitCoupon(ctx, user, gmodel.CreateCoupon{
+			Type: gmodel.CouponTypeManufacturer,
+			Scope: gmodel.CouponScopeBrand,
+			Brand: &brand,
+			Title: "$1 off any Couponly product",
+			DiscountAmount: &amount,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if coupon.Status != gmodel.CouponStatusPending {
+			t.Fatal("coupons of new contributors should be reviewed", coupon.Status)
+		}
+		if coupon_price().CouponPrice != nil {
+			t.Fatal("pending coupons should not apply")
+		}
+		queue, err := service.PaginatedCouponReviewQueue(ctx, gmodel.PaginatorInput{ Page: 1, Limit: 10 })
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(queue.Coupons) != 1 || queue.Coupons[0].ID != coupon.ID {
+			t.Fatal("coupon should be in the review queue", queue.Coupons)
+		}
+
+		if _, err := service.ReviewCoupon(ctx, admin, coupon.ID, true, nil); err != nil {
+			t.Fatal(err)
+		}
+		stock := coupon_price()
+		if stock.CouponPrice == nil || *stock.CouponPrice != 4 || len(stock.AppliedCoupons) != 1 {
+			t.Fatal("approved brand coupons should apply", stock.CouponPrice)
+		}
+		coupons, err := service.PaginatedCoupons(ctx, gmodel.PaginatorInput{ Page: 1, Limit: 10 }, &gmodel.CouponFilter{
+			ProductID: &product.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(coupons.Coupons) != 1 {
+			t.Fatal("brand coupons should be listed for the product", coupons.Coupons)
+		}
+	})
+
+	t.Run("store and category coupons", func(t *testing.T) {
+		percent := 30.0
+		stackable := true
+		if _, err := service.SubmitCoupon(ctx, admin, gmodel.CreateCoupon{
+			Type: gmodel.CouponTypeStore,
+			StoreID: &other_store.ID,
+			Scope: gmodel.CouponScopeCategory,
+			CategoryID: &parent_category.ID,
+			Title: "30% off at another store",
+			DiscountPercent: &percent,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if stock := coupon_price(); *stock.CouponPrice != 4 {
+			t.Fatal("coupons of other stores should not apply", *stock.CouponPrice)
+		}
+
+		if _, err := service.SubmitCoupon(ctx, admin, gmodel.CreateCoupon{
+			Type: gmodel.CouponTypeStore,
+			StoreID: &store.ID,
+			Scope: gmodel.CouponScopeCategory,
+			CategoryID: &parent_category.ID,
+			Title: "30% off the category",
+			DiscountPercent: &percent,
+			Stackable: &stackable,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		// the manufacturer coupon isn't stackable, so the better coupon is used on its own
+		stock := coupon_price()
+		if *stock.CouponPrice != 3.5 || len(stock.AppliedCoupons) != 1 || stock.AppliedCoupons[0].Type != gmodel.CouponTypeStore {
+			t.Fatal("non-stackable coupons should not be combined", *stock.CouponPrice, stock.AppliedCoupons)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/services"
+	"github.com/pricetra/api/types"
+)
+
+func TestEmailChange(t *testing.T) {
+	user_input := gmodel.CreateAccountInput{
+		Email: "email_change_user@email.com",
+		Name: "Email Change User",
+		Password: "password123",
+	}
+	if _, _, err := service.CreateInternalUser(ctx, user_input); err != nil {
+		t.Fatal(err)
+	}
+	auth, err := service.LoginInternal(ctx, user_input.Email, user_input.Password, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other_auth, err := service.LoginInternal(ctx, user_input.Email, user_input.Password, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := *auth.User
+
+	t.Run("invalid requests", func(t *testing.T) {
+		if _, err := service.RequestEmailChange(ctx, user, "not-an-email"); err == nil {
+			t.Fatal("invalid email should fail")
+		}
+		if _, err := service.RequestEmailChange(ctx, user, user.Email); err == nil {
+			t.Fatal("same email should fail")
+		}
+	})
+
+	t.Run("request and confirm", func(t *testing.T) {
+		new_email := "email_change_user_new@email.com"
+		email_verification, err := service.RequestEmailChange(ctx, user, new_email)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.VerifyUserEmail(ctx, email_verification.Code); err == nil {
+			t.Fatal("email change code should not be usable for account verification")
+		}
+		if _, _, err := service.ConfirmEmailChange(ctx, user, "0000000"); err == nil {
+			t.Fatal("invalid code should fail")
+		}
+
+		new_auth, old_email, err := service.ConfirmEmailChange(ctx, user, email_verification.Code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if old_email != user_input.Email {
+			t.Fatal("old email does not match")
+		}
+		if new_auth.User.Email != new_email {
+			t.Fatal("email was not updated")
+		}
+
+		if _, err := service.VerifyJwt(ctx, types.AuthorizationKeyType("Bearer " + auth.Token)); err == nil {
+			t.Fatal("token with the old email claim should be rejected")
+		}
+		if _, err := service.VerifyJwt(ctx, types.AuthorizationKeyType("Bearer " + other_auth.Token)); err == nil {
+			t.Fatal("other sessions should be logged out")
+		}
+		if _, err := service.VerifyJwt(ctx, types.AuthorizationKeyType("Bearer " + new_auth.Token)); err != nil {
+			t.Fatal("new token should be valid", err)
+		}
+
+		if _, err := service.LoginInternal(ctx, new_email, user_input.Password, nil, nil); err != nil {
+			t.Fatal("should be able to login with the new email", err)
+		}
+	})
+	t.Run("tries limit", func(t *testing.T) {
+		user, err := service.FindUserByEmail(ctx, "email_change_user_new@email.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		new_auth, err := service.LoginInternal(ctx, user.Email, user_input.Password, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		user = *new_auth.User
+		email_verification, err := service.RequestEmailChange(ctx, user, "email_change_user_locked@email.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < services.EMAIL_CHANGE_MAX_TRIES; i++ {
+			if _, _, err := service.ConfirmEmailChange(ctx, user, "0000000"); err == nil {
+				t.Fatal("invalid code should fail")
+			}
+		}
+		if _, _, err := service.ConfirmEmailChange(ctx, user, email_verification.Code); err == nil {
+			t.Fatal("pending change should be locked after too many tries")
+		}
+		if _, _, err := service.ConfirmEmailChange(ctx, user, email_verification.Code); err == nil {
+			t.Fatal("locked change should be discarded")
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/pricetra/api/services"
+)
+
+func TestLocalImageStore(t *testing.T) {
+	img := testImageBase64(1)
+	handler := http.StripPrefix("/images", image_store)
+	get := func(url string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		return rec
+	}
+
+	first, err := service.Base64ImageUpload(ctx, img, services.ImageUploadParams{
+		PublicID: "image_store_test",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.URL != service.ImageUrl("image_store_test") {
+		t.Fatal("upload should return the image url", first.URL)
+	}
+
+	t.Run("serve image", func(t *testing.T) {
+		rec := get("/images/image_store_test")
+		if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/png" {
+			t.Fatal("image should be served as png", rec.Code, rec.Header())
+		}
+		if rec := get("/images/../setup_test.go"); rec.Code != http.StatusNotFound {
+			t.Fatal("paths outside of the store should not be served", rec.Code)
+		}
+	})
+
+	t.Run("restore previous version", func(t *testing.T) {
+		if _, err := service.ImageStore.UploadBytes(ctx, []byte("not an image"), services.ImageUploadParams{
+			PublicID: "image_store_test",
+		}); err != nil {
+			t.Fatal(err)
+		}
+		version_url := service.ImageStore.VersionUrl("image_store_test", strconv.Itoa(first.Version))
+		if _, err := service.ImageUrlUpload(ctx, version_url, services.ImageUploadParams{
+			PublicID: "image_store_test",
+		}); err != nil {
+			t.Fatal(err)
+		}
+		rec := get("/images/image_store_test")
+		if !bytes.HasPrefix(rec.Body.Bytes(), []byte("\x89PNG")) {
+			t.Fatal("previous version should be restored")
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if err := service.DeleteImageUpload(ctx, "image_store_test"); err != nil {
+			t.Fatal(err)
+		}
+		if rec := get("/images/image_store_test"); rec.Code != http.StatusNotFound {
+			t.Fatal("deleted image should not be served", rec.Code)
+		}
+	})
+
+	t.Run("invalid public id", func(t *testing.T) {
+		if _, err := service.Base64ImageUpload(ctx, img, services.ImageUploadParams{
+			PublicID: "../image_store_test",
+		}); err == nil {
+			t.Fatal("public ids should not escape the store directory")
+		}
+	})
+}
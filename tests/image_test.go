@@ -0,0 +1,218 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/services"
+	"github.com/pricetra/api/utils"
+)
+
+// Random 8x8 blocks so images with different seeds have different perceptual hashes
+func testImage(seed uint64, width int, height int) *image.RGBA {
+	r := rand.New(rand.NewPCG(seed, seed))
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	blocks := make(map[image.Point]uint8)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			block := image.Pt(x * 9 / width, y * 8 / height)
+			if _, ok := blocks[block]; !ok {
+				blocks[block] = uint8(r.IntN(256))
+			}
+			v := blocks[block]
+			img.Set(x, y, color.RGBA{v, v, v, 255})
+		}
+	}
+	return img
+}
+
+func testImageBase64(seed uint64) string {
+	var buf bytes.Buffer
+	png.Encode(&buf, testImage(seed, 72, 64))
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+// Inserts an EXIF segment with the orientation tag after the JPEG SOI marker
+func withExifOrientation(data []byte, orientation byte) []byte {
+	exif := []byte{
+		0xFF, 0xE1, 0x00, 0x22,
+		'E', 'x', 'i', 'f', 0x00, 0x00,
+		'M', 'M', 0x00, 0x2A, 0x00, 0x00, 0x00, 0x08,
+		0x00, 0x01,
+		0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, 0x00, orientation, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00,
+	}
+	res := append([]byte{}, data[:2]...)
+	res = append(res, exif...)
+	return append(res, data[2:]...)
+}
+
+func TestImageProcessing(t *testing.T) {
+	t.Run("invalid images", func(t *testing.T) {
+		var tiny bytes.Buffer
+		png.Encode(&tiny, testImage(1, 1, 1))
+		if _, err := utils.ProcessImage(tiny.Bytes(), false); err == nil {
+			t.Fatal("images below the min dimensions should be rejected")
+		}
+		var animated bytes.Buffer
+		gif.Encode(&animated, testImage(1, 72, 64), nil)
+		if _, err := utils.ProcessImage(animated.Bytes(), false); err == nil {
+			t.Fatal("gif should not be an allowed format")
+		}
+		if _, err := utils.ProcessImage([]byte("not an image"), false); err == nil {
+			t.Fatal("non images should be rejected")
+		}
+		var blank bytes.Buffer
+		png.Encode(&blank, image.NewRGBA(image.Rect(0, 0, 64, 64)))
+		if _, err := utils.ProcessImage(blank.Bytes(), false); err == nil {
+			t.Fatal("blank images should be rejected")
+		}
+	})
+
+	t.Run("resize and thumbnail", func(t *testing.T) {
+		var buf bytes.Buffer
+		png.Encode(&buf, testImage(2, 3000, 1500))
+		processed, err := utils.ProcessImage(buf.Bytes(), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if processed.Width != utils.IMAGE_MAX_DIMENSION || processed.Height != utils.IMAGE_MAX_DIMENSION / 2 {
+			t.Fatal("image should be scaled down to the standard size", processed.Width, processed.Height)
+		}
+		thumbnail, err := png.DecodeConfig(bytes.NewReader(processed.Thumbnail))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if thumbnail.Width != utils.IMAGE_THUMBNAIL_DIMENSION {
+			t.Fatal("thumbnail should be scaled down", thumbnail.Width)
+		}
+	})
+
+	t.Run("exif is stripped and orientation applied", func(t *testing.T) {
+		var buf bytes.Buffer
+		jpeg.Encode(&buf, testImage(3, 80, 40), nil)
+		data := withExifOrientation(buf.Bytes(), 6)
+		processed, err := utils.ProcessImage(data, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(processed.Data, []byte("Exif")) {
+			t.Fatal("exif data should be removed")
+		}
+		if processed.Width != 40 || processed.Height != 80 {
+			t.Fatal("image should be rotated", processed.Width, processed.Height)
+		}
+	})
+
+	t.Run("perceptual hash", func(t *testing.T) {
+		var a, b bytes.Buffer
+		png.Encode(&a, testImage(4, 72, 64))
+		jpeg.Encode(&b, testImage(4, 144, 128), nil)
+		first, err := utils.ProcessImage(a.Bytes(), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, err := utils.ProcessImage(b.Bytes(), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		distance, err := utils.PerceptualHashDistance(first.Hash, second.Hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if distance > services.IMAGE_DUPLICATE_MAX_DISTANCE {
+			t.Fatal("resized and re-encoded images should have similar hashes", first.Hash, second.Hash)
+		}
+	})
+}
+
+func TestImageDuplicates(t *testing.T) {
+	admin, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Image duplicate admin",
+		Email: "image_duplicate_admin@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin.Role = gmodel.UserRoleAdmin
+	category, err := service.CategoryRecursiveInsert(ctx, "Image Duplicate Category")
+	if err != nil {
+		t.Fatal(err)
+	}
+	product, err := service.CreateProduct(ctx, admin, gmodel.CreateProduct{
+		Name: "Image Duplicate Product",
+		Description: "Some description",
+		Brand: "Pricetra",
+		Code: "036000291476",
+		CategoryID: category.ID,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img := testImageBase64(10)
+	product_image, err := service.AddProductImage(ctx, admin, product.ID, gmodel.AddProductImage{
+		Role: gmodel.ProductImageRoleBack,
+		ImageBase64: &img,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	thumbnail_path := filepath.Join(image_store.Directory, services.ImageThumbnailId(product_image.PublicID))
+	if _, err := os.Stat(thumbnail_path); err != nil {
+		t.Fatal("thumbnail should be uploaded", err)
+	}
+
+	t.Run("duplicate product image", func(t *testing.T) {
+		if _, err := service.AddProductImage(ctx, admin, product.ID, gmodel.AddProductImage{
+			Role: gmodel.ProductImageRoleNutrition,
+			ImageBase64: &img,
+		}); err == nil {
+			t.Fatal("duplicate image should be rejected")
+		}
+		other := testImageBase64(11)
+		if _, err := service.AddProductImage(ctx, admin, product.ID, gmodel.AddProductImage{
+			Role: gmodel.ProductImageRoleNutrition,
+			ImageBase64: &other,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("re-upload same public id", func(t *testing.T) {
+		params := services.ImageUploadParams{
+			PublicID: product_image.PublicID,
+			HashScope: services.ProductImageHashScope(product.ID),
+			RejectDuplicates: true,
+		}
+		if _, err := service.Base64ImageUpload(ctx, img, params); err != nil {
+			t.Fatal("replacing an image with itself is not a duplicate", err)
+		}
+	})
+
+	t.Run("delete removes hash", func(t *testing.T) {
+		if _, err := service.RemoveProductImage(ctx, admin, product_image.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(thumbnail_path); !os.IsNotExist(err) {
+			t.Fatal("thumbnail should be deleted", err)
+		}
+		if _, err := service.AddProductImage(ctx, admin, product.ID, gmodel.AddProductImage{
+			Role: gmodel.ProductImageRoleBack,
+			ImageBase64: &img,
+		}); err != nil {
+			t.Fatal("deleted images should not be duplicates", err)
+		}
+	})
+}
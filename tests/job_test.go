@@ -0,0 +1,235 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/services"
+	"github.com/pricetra/api/utils"
+)
+
+func TestJobs(t *testing.T) {
+	t.Run("cron expressions", func(t *testing.T) {
+		from := time.Date(2026, 10, 19, 10, 7, 30, 0, time.UTC)
+		cases := []struct{
+			expression string
+			next time.Time
+		}{
+			{"*/15 * * * *", time.Date(2026, 10, 19, 10, 15, 0, 0, time.UTC)},
+			{"@hourly", time.Date(2026, 10, 19, 11, 0, 0, 0, time.UTC)},
+			{"0 3 * * *", time.Date(2026, 10, 20, 3, 0, 0, 0, time.UTC)},
+			{"0 0 * * 0", time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC)},
+			{"30 9 1 * 1-5", time.Date(2026, 10, 20, 9, 30, 0, 0, time.UTC)},
+		}
+		for _, c := range cases {
+			cron, err := utils.ParseCron(c.expression)
+			if err != nil {
+				t.Fatal(c.expression, err)
+			}
+			if next := cron.Next(from); !next.Equal(c.next) {
+				t.Fatal("unexpected next run", c.expression, next)
+			}
+		}
+		for _, expression := range []string{"* * *", "60 * * * *", "*/0 * * * *", "a * * * *"} {
+			if _, err := utils.ParseCron(expression); err == nil {
+				t.Fatal("invalid cron expression should not parse", expression)
+			}
+		}
+	})
+
+	runs := map[string]int{}
+	services.RegisterJobHandler("test_job", func(s services.Service, ctx context.Context, job gmodel.Job) error {
+		payload, err := services.JobPayload[struct{ Name string; Fail bool }](job)
+		if err != nil {
+			return err
+		}
+		runs[payload.Name]++
+		if payload.Fail {
+			return fmt.Errorf("%s failed", payload.Name)
+		}
+		return nil
+	})
+	// runs jobs that were rescheduled after a failure without waiting for the backoff
+	run_now := func(job gmodel.Job) {
+		if _, err := db.ExecContext(ctx, `update "job" set "run_at" = now() where "id" = $1`, job.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.RunPendingJobs(ctx, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("unknown job type", func(t *testing.T) {
+		if _, err := service.EnqueueJob(ctx, "unknown_job", nil, services.EnqueueJobOptions{}); err == nil {
+			t.Fatal("only registered job types should be queued")
+		}
+	})
+
+	t.Run("successful job", func(t *testing.T) {
+		job, err := service.EnqueueJob(ctx, "test_job", map[string]any{ "Name": "success" }, services.EnqueueJobOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.Status != gmodel.JobStatusPending {
+			t.Fatal("new jobs should be pending", job.Status)
+		}
+		run_now(job)
+		if job, err = service.FindJobById(ctx, job.ID); err != nil {
+			t.Fatal(err)
+		}
+		if job.Status != gmodel.JobStatusSucceeded || job.Attempts != 1 || job.CompletedAt == nil || runs["success"] != 1 {
+			t.Fatal("job should have run once", job.Status, job.Attempts, runs["success"])
+		}
+	})
+
+	t.Run("scheduled jobs wait until run at", func(t *testing.T) {
+		run_at := time.Now().Add(time.Hour)
+		job, err := service.EnqueueJob(ctx, "test_job", map[string]any{ "Name": "later" }, services.EnqueueJobOptions{
+			RunAt: &run_at,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.RunPendingJobs(ctx, 10); err != nil {
+			t.Fatal(err)
+		}
+		if runs["later"] != 0 {
+			t.Fatal("job should not run before its run at")
+		}
+		run_now(job)
+		if runs["later"] != 1 {
+			t.Fatal("job should run once it's due")
+		}
+	})
+
+	t.Run("unique keys", func(t *testing.T) {
+		key := "test_job:unique"
+		first, err := service.EnqueueJob(ctx, "test_job", map[string]any{ "Name": "unique" }, services.EnqueueJobOptions{ UniqueKey: &key })
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, err := service.EnqueueJob(ctx, "test_job", map[string]any{ "Name": "unique" }, services.EnqueueJobOptions{ UniqueKey: &key })
+		if err != nil {
+			t.Fatal(err)
+		}
+		if first.ID != second.ID {
+			t.Fatal("jobs with the same key should not be queued twice", first.ID, second.ID)
+		}
+		run_now(first)
+		third, err := service.EnqueueJob(ctx, "test_job", map[string]any{ "Name": "unique" }, services.EnqueueJobOptions{ UniqueKey: &key })
+		if err != nil {
+			t.Fatal(err)
+		}
+		if third.ID == first.ID {
+			t.Fatal("key should be reusable once the job is done")
+		}
+	})
+
+	t.Run("failed jobs are retried", func(t *testing.T) {
+		job, err := service.EnqueueJob(ctx, "test_job", map[string]any{ "Name": "failure", "Fail": true }, services.EnqueueJobOptions{
+			MaxAttempts: 2,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		run_now(job)
+		if job, err = service.FindJobById(ctx, job.ID); err != nil {
+			t.Fatal(err)
+		}
+		if job.Status != gmodel.JobStatusPending || job.LastError == nil || !job.RunAt.After(time.Now()) {
+			t.Fatal("failed job should be rescheduled with a backoff", job.Status, job.RunAt)
+		}
+
+		run_now(job)
+		if job, err = service.FindJobById(ctx, job.ID); err != nil {
+			t.Fatal(err)
+		}
+		if job.Status != gmodel.JobStatusFailed || job.Attempts != 2 || runs["failure"] != 2 {
+			t.Fatal("job should fail after its last attempt", job.Status, job.Attempts)
+		}
+
+		failed, err := service.PaginatedJobs(ctx, gmodel.PaginatorInput{ Page: 1, Limit: 10 }, &gmodel.JobFilter{
+			Status: &job.Status,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.ContainsFunc(failed.Jobs, func(j *gmodel.Job) bool { return j.ID == job.ID }) {
+			t.Fatal("failed job should be listed", failed.Jobs)
+		}
+
+		if job, err = service.RetryJob(ctx, job.ID); err != nil {
+			t.Fatal(err)
+		}
+		if job.Status != gmodel.JobStatusPending || job.Attempts != 0 {
+			t.Fatal("retried job should get a fresh set of attempts", job.Status, job.Attempts)
+		}
+		if _, err := service.RetryJob(ctx, job.ID); err == nil {
+			t.Fatal("only failed jobs can be retried")
+		}
+	})
+
+	t.Run("stale jobs are recovered", func(t *testing.T) {
+		job, err := service.EnqueueJob(ctx, "test_job", map[string]any{ "Name": "stale" }, services.EnqueueJobOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the worker running the job was killed
+		if _, err := db.ExecContext(
+			ctx,
+			`update "job" set "status" = 'RUNNING', "locked_at" = now() - interval '1 day' where "id" = $1`,
+			job.ID,
+		); err != nil {
+			t.Fatal(err)
+		}
+		if recovered, err := service.RecoverStaleJobs(ctx); err != nil || recovered == 0 {
+			t.Fatal("stale job should be requeued", recovered, err)
+		}
+		run_now(job)
+		if runs["stale"] != 1 {
+			t.Fatal("recovered job should run")
+		}
+	})
+
+	t.Run("job schedules", func(t *testing.T) {
+		if _, err := service.UpsertJobSchedule(ctx, "test-schedule", "test_job", "not a cron", nil); err == nil {
+			t.Fatal("invalid cron expressions should be rejected")
+		}
+		schedule, err := service.UpsertJobSchedule(ctx, "test-schedule", "test_job", "@daily", map[string]any{ "Name": "scheduled" })
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !schedule.NextRunAt.After(time.Now()) {
+			t.Fatal("next run should be in the future", schedule.NextRunAt)
+		}
+		if _, err := db.ExecContext(ctx, `update "job_schedule" set "next_run_at" = now() - interval '1 minute' where "id" = $1`, schedule.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.EnqueueDueScheduledJobs(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.EnqueueDueScheduledJobs(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.RunPendingJobs(ctx, 10); err != nil {
+			t.Fatal(err)
+		}
+		if runs["scheduled"] != 1 {
+			t.Fatal("due schedule should queue a single job", runs["scheduled"])
+		}
+
+		schedules, err := service.JobSchedules(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range schedules {
+			if s.ID == schedule.ID && (s.LastRunAt == nil || !s.NextRunAt.After(time.Now())) {
+				t.Fatal("schedule should move to its next run", s.NextRunAt)
+			}
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/services"
+)
+
+func TestLeaderboard(t *testing.T) {
+	contributor, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Leaderboard contributor",
+		Email: "leaderboard_contributor@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("period start", func(t *testing.T) {
+		now := time.Date(2025, time.March, 13, 15, 30, 0, 0, time.UTC) // thursday
+		weekly := services.LeaderboardPeriodStart(gmodel.LeaderboardPeriodWeekly, now)
+		if weekly == nil || !weekly.Equal(time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC)) {
+			t.Fatal("weekly period should start on monday", weekly)
+		}
+		monthly := services.LeaderboardPeriodStart(gmodel.LeaderboardPeriodMonthly, now)
+		if monthly == nil || !monthly.Equal(time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Fatal("monthly period should start on the first of the month", monthly)
+		}
+		if services.LeaderboardPeriodStart(gmodel.LeaderboardPeriodAllTime, now) != nil {
+			t.Fatal("all time period should not have a start")
+		}
+	})
+
+	category, err := service.CategoryRecursiveInsert(ctx, "Leaderboard Test Category")
+	if err != nil {
+		t.Fatal(err)
+	}
+	product, err := service.CreateProduct(ctx, contributor, gmodel.CreateProduct{
+		Name: "Leaderboard test product",
+		Description: "Some description",
+		Brand: "Pricetra",
+		Code: "LEADERBOARDTESTBARCODE",
+		CategoryID: category.ID,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.CreateProductBilling(ctx, contributor, model.ProductBillingType_Create, product, nil, product, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("achievements", func(t *testing.T) {
+		achievements, err := service.FindUserAchievements(ctx, contributor.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(achievements) != 1 || achievements[0].Achievement != gmodel.AchievementTypeFirstProduct {
+			t.Fatal("first product achievement should be awarded", achievements)
+		}
+		awarded, err := service.EvaluateAchievements(ctx, contributor.ID, model.ProductBillingType_Create, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(awarded) != 0 {
+			t.Fatal("achievements should only be awarded once", awarded)
+		}
+	})
+
+	t.Run("ranking", func(t *testing.T) {
+		entries, err := service.Leaderboard(ctx, gmodel.LeaderboardPeriodWeekly, nil, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var entry *gmodel.LeaderboardEntry
+		for i := range entries {
+			if i > 0 && entries[i].Points > entries[i - 1].Points {
+				t.Fatal("entries should be sorted by points")
+			}
+			if entries[i].User.ID == contributor.ID {
+				entry = &entries[i]
+			}
+		}
+		if entry == nil {
+			t.Fatal("contributor should be on the leaderboard")
+		}
+		if entry.Products != 1 || entry.Points < services.LEADERBOARD_PRODUCT_POINTS || entry.User.Name != contributor.Name {
+			t.Fatal("product contributions should be counted", entry)
+		}
+	})
+}
@@ -0,0 +1,229 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+func TestLoyaltyPrograms(t *testing.T) {
+	user, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Loyalty user",
+		Email: "loyalty@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	member, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Loyalty member",
+		Email: "loyalty_member@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img := "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAAC0lEQVR42mP8//8/AwAI/wH+9Q4AAAAASUVORK5CYII="
+	store, err := service.CreateStore(ctx, user, gmodel.CreateStore{
+		Name: "Loyalty Store",
+		LogoBase64: &img,
+		Website: "https://pricetra.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other_store, err := service.CreateStore(ctx, user, gmodel.CreateStore{
+		Name: "Other Loyalty Store",
+		LogoBase64: &img,
+		Website: "https://pricetra.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	branch, err := service.CreateBranch(ctx, user, gmodel.CreateBranch{
+		Name: "Loyalty Branch",
+		StoreID: store.ID,
+		Address: &gmodel.CreateAddress{
+			Latitude: 41.900612,
+			Longitude: -88.3436658,
+			MapsLink: "https://maps.google.com",
+			FullAddress: "855 S Randall Rd, St. Charles, IL 60174, USA",
+			City: "St. Charles",
+			AdministrativeDivision: "Illinois",
+			CountryCode: "US",
+			ZipCode: 60174,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	category, err := service.CategoryRecursiveInsert(ctx, "Loyalty Category")
+	if err != nil {
+		t.Fatal(err)
+	}
+	product, err := service.CreateProduct(ctx, user, gmodel.CreateProduct{
+		Name: "Loyalty Product",
+		Description: "Some description",
+		Brand: "Pricetra",
+		Code: "036000291520",
+		CategoryID: category.ID,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	program, err := service.CreateLoyaltyProgram(ctx, user, gmodel.CreateLoyaltyProgram{
+		StoreID: store.ID,
+		Name: "Loyalty Rewards",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other_program, err := service.CreateLoyaltyProgram(ctx, user, gmodel.CreateLoyaltyProgram{
+		StoreID: other_store.ID,
+		Name: "Other Rewards",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("duplicate program name", func(t *testing.T) {
+		if _, err := service.CreateLoyaltyProgram(ctx, user, gmodel.CreateLoyaltyProgram{
+			StoreID: store.ID,
+			Name: "Loyalty Rewards",
+		}); err == nil {
+			t.Fatal("program names should be unique per store")
+		}
+	})
+
+	regular, err := service.CreatePrice(ctx, user, gmodel.CreatePrice{
+		ProductID: product.ID,
+		BranchID: branch.ID,
+		Amount: 4.99,
+		UnitType: "item",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	member_only := true
+	member_price, err := service.CreatePrice(ctx, user, gmodel.CreatePrice{
+		ProductID: product.ID,
+		BranchID: branch.ID,
+		Amount: 3.99,
+		UnitType: "item",
+		MemberOnly: &member_only,
+		LoyaltyProgramID: &program.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("member price does not replace regular price", func(t *testing.T) {
+		if !member_price.MemberOnly || member_price.LoyaltyProgramID == nil {
+			t.Fatal("price should be a member price", member_price)
+		}
+		stock, err := service.FindStockById(ctx, member_price.StockID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stock.LatestPriceID == nil || *stock.LatestPriceID != regular.ID {
+			t.Fatal("regular price should stay the latest price", stock.LatestPriceID)
+		}
+		if stock.LatestMemberPriceID == nil || *stock.LatestMemberPriceID != member_price.ID {
+			t.Fatal("member price should be the latest member price", stock.LatestMemberPriceID)
+		}
+	})
+
+	t.Run("program of another store", func(t *testing.T) {
+		if _, err := service.CreatePrice(ctx, user, gmodel.CreatePrice{
+			ProductID: product.ID,
+			BranchID: branch.ID,
+			Amount: 3.49,
+			UnitType: "item",
+			LoyaltyProgramID: &other_program.ID,
+		}); err == nil {
+			t.Fatal("prices should only reference programs of their store")
+		}
+	})
+
+	applicable_price := func(user gmodel.User) gmodel.Stock {
+		stock, err := service.FindStockById(ctx, member_price.StockID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := service.ApplyMemberPrices(ctx, user, []*gmodel.Stock{&stock}); err != nil {
+			t.Fatal(err)
+		}
+		return stock
+	}
+
+	t.Run("non members get the regular price", func(t *testing.T) {
+		stock := applicable_price(member)
+		if stock.LatestPrice.ID != regular.ID || stock.NonMemberPrice != nil {
+			t.Fatal("non members should get the regular price", stock.LatestPrice)
+		}
+		if stock.LatestMemberPrice == nil || stock.LatestMemberPrice.ID != member_price.ID {
+			t.Fatal("member price should still be shown", stock.LatestMemberPrice)
+		}
+	})
+
+	t.Run("members get the member price", func(t *testing.T) {
+		if _, err := service.JoinLoyaltyProgram(ctx, member, other_program.ID); err != nil {
+			t.Fatal(err)
+		}
+		if stock := applicable_price(member); stock.LatestPrice.ID != regular.ID {
+			t.Fatal("members of other programs should get the regular price", stock.LatestPrice)
+		}
+
+		if _, err := service.JoinLoyaltyProgram(ctx, member, program.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.JoinLoyaltyProgram(ctx, member, program.ID); err != nil {
+			t.Fatal("joining twice should be a no-op", err)
+		}
+		programs, err := service.UserLoyaltyPrograms(ctx, member)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(programs) != 2 {
+			t.Fatal("user should be in both programs", programs)
+		}
+
+		stock := applicable_price(member)
+		if stock.LatestPrice.ID != member_price.ID || stock.LatestPriceID == nil || *stock.LatestPriceID != member_price.ID {
+			t.Fatal("members should get the member price", stock.LatestPrice)
+		}
+		if stock.NonMemberPrice == nil || stock.NonMemberPrice.ID != regular.ID {
+			t.Fatal("regular price should be kept as the non-member price", stock.NonMemberPrice)
+		}
+	})
+
+	t.Run("leave program", func(t *testing.T) {
+		if err := service.LeaveLoyaltyProgram(ctx, member, program.ID); err != nil {
+			t.Fatal(err)
+		}
+		if err := service.LeaveLoyaltyProgram(ctx, member, program.ID); err == nil {
+			t.Fatal("users can't leave programs they're not in")
+		}
+		if stock := applicable_price(member); stock.LatestPrice.ID != regular.ID {
+			t.Fatal("former members should get the regular price", stock.LatestPrice)
+		}
+	})
+
+	t.Run("rejected member price", func(t *testing.T) {
+		if _, err := service.ReviewPrice(ctx, user, member_price.ID, false, nil); err != nil {
+			t.Fatal(err)
+		}
+		stock, err := service.FindStockById(ctx, member_price.StockID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stock.LatestMemberPriceID != nil {
+			t.Fatal("rejected member price should not be the latest member price", *stock.LatestMemberPriceID)
+		}
+		if stock.LatestPriceID == nil || *stock.LatestPriceID != regular.ID {
+			t.Fatal("regular price should be kept", stock.LatestPriceID)
+		}
+	})
+}
@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+func TestPayout(t *testing.T) {
+	admin, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Payout admin",
+		Email: "payout_admin@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin.Role = gmodel.UserRoleSuperAdmin
+	contributor, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Payout contributor",
+		Email: "payout_contributor@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	category, err := service.CategoryRecursiveInsert(ctx, "Payout Test Category")
+	if err != nil {
+		t.Fatal(err)
+	}
+	product, err := service.CreateProduct(ctx, contributor, gmodel.CreateProduct{
+		Name: "Payout test product",
+		Description: "Some description",
+		Brand: "Pricetra",
+		Code: "PAYOUTTESTBARCODE",
+		CategoryID: category.ID,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.CreateProductBilling(ctx, contributor, model.ProductBillingType_Create, product, nil, product, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.CreateProductBilling(ctx, contributor, model.ProductBillingType_Scan, product, nil, product, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var batch gmodel.PayoutBatch
+	t.Run("pending earnings", func(t *testing.T) {
+		summary, err := service.EarningsSummary(ctx, contributor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(summary) != 1 || summary[0].PendingCount != 2 || summary[0].PaidCount != 0 {
+			t.Fatal("expected 2 pending billing rows", summary)
+		}
+		if summary[0].PendingAmount != 0.50 {
+			t.Fatal("incorrect pending amount", summary[0].PendingAmount)
+		}
+	})
+
+	t.Run("minimum amount carries over", func(t *testing.T) {
+		minimum := 1000.0
+		if _, err := service.CreatePayoutBatch(ctx, admin, time.Now(), &minimum); err == nil {
+			t.Fatal("batch should not be created when no user meets the minimum")
+		}
+	})
+
+	t.Run("create batch", func(t *testing.T) {
+		batch, err = service.CreatePayoutBatch(ctx, admin, time.Now(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if batch.BillingCount < 2 {
+			t.Fatal("batch should include the contributor billing rows", batch)
+		}
+
+		summary, err := service.EarningsSummary(ctx, contributor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if summary[0].PendingCount != 0 || summary[0].PaidCount != 2 || summary[0].LastPaidAt == nil {
+			t.Fatal("billing rows should be marked as paid", summary)
+		}
+
+		if _, err := service.CreatePayoutBatch(ctx, admin, time.Now(), nil); err == nil {
+			t.Fatal("rows should not be paid twice")
+		}
+	})
+
+	t.Run("statement", func(t *testing.T) {
+		payouts, err := service.PaginatedPayoutsByUser(ctx, gmodel.PaginatorInput{ Page: 1, Limit: 10 }, contributor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(payouts.Payouts) != 1 || payouts.Payouts[0].PayoutBatchID != batch.ID {
+			t.Fatal("expected a single payout", payouts.Payouts)
+		}
+		payout := payouts.Payouts[0]
+		if payout.Amount != 0.50 || payout.BillingCount != 2 {
+			t.Fatal("incorrect payout totals", payout)
+		}
+
+		statement, err := service.PayoutStatement(ctx, contributor, payout.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(statement.Items) != 2 {
+			t.Fatal("statement should contain 2 items", statement.Items)
+		}
+		csv_data, err := base64.StdEncoding.DecodeString(statement.File.Data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(csv_data), product.Code) || !strings.Contains(string(csv_data), "total,") {
+			t.Fatal("csv statement is missing line items or totals", string(csv_data))
+		}
+
+		if _, err := service.PayoutStatement(ctx, gmodel.User{ ID: admin.ID + 1000, Role: gmodel.UserRoleConsumer }, payout.ID); err == nil {
+			t.Fatal("other users should not see the statement")
+		}
+	})
+
+	t.Run("deleted payees keep their payouts", func(t *testing.T) {
+		payouts, err := service.PaginatedPayoutsByUser(ctx, gmodel.PaginatorInput{ Page: 1, Limit: 10 }, contributor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(payouts.Payouts) != 1 {
+			t.Fatal("expected a single payout", payouts.Payouts)
+		}
+		if err := service.DeleteUserAccount(ctx, contributor.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		payout, err := service.FindPayoutById(ctx, payouts.Payouts[0].ID)
+		if err != nil {
+			t.Fatal("payout should be kept after the account deletion", err)
+		}
+		if payout.UserID != nil || payout.Amount != 0.50 {
+			t.Fatal("payout should keep its amount without the user", payout)
+		}
+		batch_payouts, err := service.FindPayoutsByBatch(ctx, batch.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(batch_payouts) != batch.PayoutCount {
+			t.Fatal("batch should still list every payout", batch_payouts)
+		}
+	})
+}
@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/utils"
+)
+
+func TestPriceDeals(t *testing.T) {
+	t.Run("parse legacy conditions", func(t *testing.T) {
+		cases := []struct {
+			condition string
+			deal_type string
+			effective_price float64
+			description string
+		}{
+			{"2 for $5", utils.PRICE_DEAL_MULTI_BUY, 2.5, "2 for $5.00"},
+			{"Buy one, get one FREE", utils.PRICE_DEAL_BUY_X_GET_Y, 2, "buy 1 get 1 free"},
+			{"BOGO 50% off", utils.PRICE_DEAL_BUY_X_GET_Y, 3, "buy 1 get 1 50% off"},
+			{"$1 off with loyalty card", utils.PRICE_DEAL_DISCOUNT, 3, "$1.00 off, members only"},
+			{"limit 4", "", 4, "limit 4"},
+			{"must buy 2", "", 4, "must buy 2"},
+			{"$5 off with $25 purchase", utils.PRICE_DEAL_DISCOUNT, 4, "$5.00 off, with $25.00 purchase"},
+			{"3/$10 limit 6", utils.PRICE_DEAL_MULTI_BUY, 3.33, "3 for $10.00, limit 6"},
+		}
+		for _, c := range cases {
+			deal := utils.ParsePriceDeal(c.condition)
+			if deal.Type != c.deal_type {
+				t.Fatal("unexpected deal type", c.condition, deal.Type)
+			}
+			if effective_price := deal.EffectiveUnitPrice(4); effective_price != c.effective_price {
+				t.Fatal("unexpected effective price", c.condition, effective_price)
+			}
+			if description := deal.Description(); description != c.description {
+				t.Fatal("unexpected description", c.condition, description)
+			}
+		}
+		if !utils.ParsePriceDeal("Great value").IsEmpty() {
+			t.Fatal("unrelated conditions should not have a deal")
+		}
+	})
+
+	user, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Price deal user",
+		Email: "price_deal@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAAC0lEQVR42mP8//8/AwAI/wH+9Q4AAAAASUVORK5CYII="
+	store, err := service.CreateStore(ctx, user, gmodel.CreateStore{
+		Name: "Price Deal Store",
+		LogoBase64: &img,
+		Website: "https://pricetra.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	branch, err := service.CreateBranch(ctx, user, gmodel.CreateBranch{
+		Name: "Price Deal Branch",
+		StoreID: store.ID,
+		Address: &gmodel.CreateAddress{
+			Latitude: 41.900612,
+			Longitude: -88.3436658,
+			MapsLink: "https://maps.google.com",
+			FullAddress: "855 S Randall Rd, St. Charles, IL 60174, USA",
+			City: "St. Charles",
+			AdministrativeDivision: "Illinois",
+			CountryCode: "US",
+			ZipCode: 60174,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	category, err := service.CategoryRecursiveInsert(ctx, "Price Deal Category")
+	if err != nil {
+		t.Fatal(err)
+	}
+	product, err := service.CreateProduct(ctx, user, gmodel.CreateProduct{
+		Name: "Price Deal Product",
+		Description: "Some description",
+		Brand: "Pricetra",
+		Code: "036000291506",
+		CategoryID: category.ID,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("condition is parsed", func(t *testing.T) {
+		condition := "2 for $5 with card, limit 4"
+		price, err := service.CreatePrice(ctx, user, gmodel.CreatePrice{
+			ProductID: product.ID,
+			BranchID: branch.ID,
+			Amount: 3.49,
+			UnitType: "item",
+			Condition: &condition,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if price.DealType == nil || *price.DealType != gmodel.PriceDealTypeMultiBuy {
+			t.Fatal("condition should be parsed into a multi-buy deal", price.DealType)
+		}
+		if !price.RequiresMembership || price.PurchaseLimit == nil || *price.PurchaseLimit != 4 {
+			t.Fatal("restrictions should be parsed", price.RequiresMembership, price.PurchaseLimit)
+		}
+		if price.EffectivePrice == nil || *price.EffectivePrice != 2.5 {
+			t.Fatal("effective price should be the per-unit deal price", price.EffectivePrice)
+		}
+	})
+
+	t.Run("structured deal", func(t *testing.T) {
+		deal_type := gmodel.PriceDealTypeBuyXGetY
+		quantity, free_quantity := 1, 1
+		price, err := service.CreatePrice(ctx, user, gmodel.CreatePrice{
+			ProductID: product.ID,
+			BranchID: branch.ID,
+			Amount: 4,
+			UnitType: "item",
+			Deal: &gmodel.PriceDealInput{
+				Type: &deal_type,
+				Quantity: &quantity,
+				FreeQuantity: &free_quantity,
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if price.EffectivePrice == nil || *price.EffectivePrice != 2 {
+			t.Fatal("free units should lower the effective price", price.EffectivePrice)
+		}
+		if price.Condition == nil || *price.Condition != "buy 1 get 1 free" {
+			t.Fatal("condition should describe the deal", price.Condition)
+		}
+
+		multi_buy := gmodel.PriceDealTypeMultiBuy
+		if _, err := service.CreatePrice(ctx, user, gmodel.CreatePrice{
+			ProductID: product.ID,
+			BranchID: branch.ID,
+			Amount: 4,
+			UnitType: "item",
+			Deal: &gmodel.PriceDealInput{ Type: &multi_buy, Quantity: &quantity },
+		}); err == nil {
+			t.Fatal("multi-buy deals without a price should be rejected")
+		}
+	})
+
+	t.Run("backfill legacy prices", func(t *testing.T) {
+		// prices created before deals were structured
+		if _, err := db.ExecContext(
+			ctx,
+			`update "price" set "effective_price" = null, "condition" = '$1 off with loyalty card' where "product_id" = $1`,
+			product.ID,
+		); err != nil {
+			t.Fatal(err)
+		}
+		updated, err := service.BackfillPriceDeals(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if updated == 0 {
+			t.Fatal("legacy prices should be backfilled")
+		}
+		prices, err := service.FindPrices(ctx, product.ID, branch.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, price := range prices {
+			if price.EffectivePrice == nil || math.Abs(*price.EffectivePrice - (price.Amount - 1)) > 0.001 || !price.RequiresMembership {
+				t.Fatal("legacy condition should be parsed", price.ID, price.EffectivePrice)
+			}
+		}
+		if updated, _ := service.BackfillPriceDeals(ctx); updated != 0 {
+			t.Fatal("backfill should only update unparsed prices", updated)
+		}
+	})
+}
@@ -0,0 +1,213 @@
+package tests
+
+import (
+	"bytes"
+	"image/png"
+	"slices"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/utils"
+)
+
+func TestPriceEvidence(t *testing.T) {
+	user, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Price evidence user",
+		Email: "price_evidence@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Price evidence admin",
+		Email: "price_evidence_admin@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin.Role = gmodel.UserRoleAdmin
+
+	img := "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAAC0lEQVR42mP8//8/AwAI/wH+9Q4AAAAASUVORK5CYII="
+	store, err := service.CreateStore(ctx, user, gmodel.CreateStore{
+		Name: "Price Evidence Store",
+		LogoBase64: &img,
+		Website: "https://pricetra.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	branch, err := service.CreateBranch(ctx, user, gmodel.CreateBranch{
+		Name: "Price Evidence Branch",
+		StoreID: store.ID,
+		Address: &gmodel.CreateAddress{
+			Latitude: 41.900612,
+			Longitude: -88.3436658,
+			MapsLink: "https://maps.google.com",
+			FullAddress: "855 S Randall Rd, St. Charles, IL 60174, USA",
+			City: "St. Charles",
+			AdministrativeDivision: "Illinois",
+			CountryCode: "US",
+			ZipCode: 60174,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	category, err := service.CategoryRecursiveInsert(ctx, "Price Evidence Category")
+	if err != nil {
+		t.Fatal(err)
+	}
+	product, err := service.CreateProduct(ctx, user, gmodel.CreateProduct{
+		Name: "Price Evidence Product",
+		Description: "Some description",
+		Brand: "Pricetra",
+		Code: "036000291483",
+		CategoryID: category.ID,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	upload := func(seed uint64) *graphql.Upload {
+		var buf bytes.Buffer
+		png.Encode(&buf, testImage(seed, 72, 64))
+		return &graphql.Upload{
+			File: bytes.NewReader(buf.Bytes()),
+			Filename: "tag.png",
+			Size: int64(buf.Len()),
+			ContentType: "image/png",
+		}
+	}
+
+	first, err := service.CreatePrice(ctx, user, gmodel.CreatePrice{
+		ProductID: product.ID,
+		BranchID: branch.ID,
+		Amount: 3.99,
+		UnitType: "item",
+		ImageFile: upload(20),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("evidence photo is stored", func(t *testing.T) {
+		if first.ImageID == nil || first.ImageURL == nil {
+			t.Fatal("price should link to the evidence photo", first)
+		}
+		if *first.ImageURL != service.ImageUrl(*first.ImageID) {
+			t.Fatal("image url should point to the uploaded photo", *first.ImageURL)
+		}
+	})
+
+	t.Run("reused photo is rejected", func(t *testing.T) {
+		if _, err := service.CreatePrice(ctx, user, gmodel.CreatePrice{
+			ProductID: product.ID,
+			BranchID: branch.ID,
+			Amount: 2.99,
+			UnitType: "item",
+			ImageFile: upload(20),
+		}); err == nil {
+			t.Fatal("the same photo should not back another price of the stock")
+		}
+		if _, err := service.CreatePrice(ctx, user, gmodel.CreatePrice{
+			ProductID: product.ID,
+			BranchID: branch.ID,
+			Amount: 2.99,
+			UnitType: "item",
+			ImageFile: &graphql.Upload{ File: bytes.NewReader([]byte("not an image")) },
+		}); err == nil {
+			t.Fatal("invalid photos should be rejected")
+		}
+	})
+
+	t.Run("ocr amounts", func(t *testing.T) {
+		amounts := utils.ExtractPriceAmounts("SALE $3.99 reg 4,49 ea 2 for $5 99¢ 12oz")
+		for _, expected := range []float64{3.99, 4.49, 5, 0.99} {
+			if !slices.Contains(amounts, expected) {
+				t.Fatal("amount should be extracted", expected, amounts)
+			}
+		}
+		if slices.Contains(amounts, 12) || slices.Contains(amounts, 2) {
+			t.Fatal("quantities should not be amounts", amounts)
+		}
+	})
+
+	second, err := service.CreatePrice(ctx, user, gmodel.CreatePrice{
+		ProductID: product.ID,
+		BranchID: branch.ID,
+		Amount: 1.99,
+		UnitType: "item",
+		ImageFile: upload(21),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("review queue", func(t *testing.T) {
+		// normally flagged by the ocr check
+		if _, err := db.ExecContext(ctx, `update "price" set "review_status" = 'FLAGGED' where "id" = $1`, second.ID); err != nil {
+			t.Fatal(err)
+		}
+		queue, err := service.PaginatedPriceReviewQueue(ctx, gmodel.PaginatorInput{ Page: 1, Limit: 10 }, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(queue.Prices) != 1 || queue.Prices[0].ID != second.ID || queue.Prices[0].ImageURL == nil {
+			t.Fatal("flagged price should be in the review queue with its photo", queue.Prices)
+		}
+	})
+
+	t.Run("reject price", func(t *testing.T) {
+		reason := "photo shows a different price"
+		rejected, err := service.ReviewPrice(ctx, admin, second.ID, false, &reason)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rejected.ReviewStatus == nil || *rejected.ReviewStatus != gmodel.PriceReviewStatusRejected {
+			t.Fatal("price should be rejected", rejected.ReviewStatus)
+		}
+		stock, err := service.FindStock(ctx, product.ID, branch.ID, branch.StoreID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stock.LatestPriceID == nil || *stock.LatestPriceID != first.ID {
+			t.Fatal("stock should fall back to the previous price", stock.LatestPriceID)
+		}
+		queue, err := service.PaginatedPriceReviewQueue(ctx, gmodel.PaginatorInput{ Page: 1, Limit: 10 }, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(queue.Prices) != 0 {
+			t.Fatal("reviewed prices should leave the queue", queue.Prices)
+		}
+	})
+
+	t.Run("rejecting the only valid price clears the stock price", func(t *testing.T) {
+		if _, err := db.ExecContext(ctx, `update "price" set "expires_at" = now() - interval '1 day' where "id" = $1`, first.ID); err != nil {
+			t.Fatal(err)
+		}
+		third, err := service.CreatePrice(ctx, user, gmodel.CreatePrice{
+			ProductID: product.ID,
+			BranchID: branch.ID,
+			Amount: 2.49,
+			UnitType: "item",
+			ImageFile: upload(22),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.ReviewPrice(ctx, admin, third.ID, false, nil); err != nil {
+			t.Fatal(err)
+		}
+		stock, err := service.FindStockById(ctx, third.StockID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stock.LatestPriceID != nil || stock.LatestPrice != nil {
+			t.Fatal("expired and rejected prices should not be used as fallback", stock.LatestPrice)
+		}
+	})
+}
@@ -56,14 +56,14 @@ func TestPrice(t *testing.T) {
 		Name: "Random test product",
 		Description: "Some description",
 		Brand: "Pricetra",
-		Code: "12345678901",
+		Code: "12345678905",
 		CategoryID: category.ID,
 	}, nil)
 	product_2, _ := service.CreateProduct(ctx, user, gmodel.CreateProduct{
 		Name: "Random test product 2",
 		Description: "Some description",
 		Brand: "Pricetra",
-		Code: "12345678901234",
+		Code: "12345678901231",
 		CategoryID: category.ID,
 	}, nil)
 
@@ -104,7 +104,7 @@ func TestPrice(t *testing.T) {
 		if err != nil {
 			t.Fatal("stock should be created after creating price")
 		}
-		if stock.LatestPriceID != price.ID {
+		if stock.LatestPriceID == nil || *stock.LatestPriceID != price.ID {
 			t.Fatal("stock latest price should be set to the created price")
 		}
 
@@ -241,7 +241,7 @@ func TestPrice(t *testing.T) {
 			if err != nil {
 				t.Fatal("stock should be created after creating price")
 			}
-			if stock.LatestPriceID != new_price.ID {
+			if stock.LatestPriceID == nil || *stock.LatestPriceID != new_price.ID {
 				t.Fatal("stock latest price should be set to the created price")
 			}
 		})
@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+func TestProductEditProposal(t *testing.T) {
+	admin, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Proposal admin",
+		Email: "proposal_admin@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin.Role = gmodel.UserRoleAdmin
+	contributor, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Proposal contributor",
+		Email: "proposal_contributor@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !service.RequiresProductEditProposal(contributor) || service.RequiresProductEditProposal(admin) {
+		t.Fatal("only lower-trust users should require proposals")
+	}
+
+	category, err := service.CategoryRecursiveInsert(ctx, "Proposal Test Category")
+	if err != nil {
+		t.Fatal(err)
+	}
+	product, err := service.CreateProduct(ctx, admin, gmodel.CreateProduct{
+		Name: "Proposal test product",
+		Description: "Some description",
+		Brand: "Pricetra",
+		Code: "PROPOSALTESTBARCODE",
+		CategoryID: category.ID,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("no-op proposal", func(t *testing.T) {
+		if _, err := service.CreateProductEditProposal(ctx, contributor, product.ID, gmodel.UpdateProduct{
+			Name: &product.Name,
+		}); err == nil {
+			t.Fatal("proposal without changes should fail")
+		}
+	})
+
+	var proposal gmodel.ProductEditProposal
+	new_name := "Proposal test product renamed"
+	new_brand := "Pricetra Foods"
+	t.Run("propose", func(t *testing.T) {
+		proposal, err = service.CreateProductEditProposal(ctx, contributor, product.ID, gmodel.UpdateProduct{
+			Name: &new_name,
+			Brand: &new_brand,
+			Description: &product.Description,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if proposal.Status != gmodel.ProductEditProposalStatusPending {
+			t.Fatal("proposal should be pending", proposal.Status)
+		}
+		if strings.Contains(proposal.Changes, "description") || !strings.Contains(proposal.OldData, product.Name) {
+			t.Fatal("proposal should only contain changed fields", proposal.Changes, proposal.OldData)
+		}
+		unchanged, err := service.FindProductById(ctx, product.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if unchanged.Name != product.Name {
+			t.Fatal("proposal should not change the product")
+		}
+
+		queue, err := service.PaginatedProductEditProposals(ctx, gmodel.PaginatorInput{ Page: 1, Limit: 100 }, nil, &product.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(queue.Data) != 1 || queue.Data[0].ID != proposal.ID {
+			t.Fatal("proposal should be in the moderation queue", queue.Data)
+		}
+	})
+
+	t.Run("partial approval", func(t *testing.T) {
+		if _, err := service.ReviewProductEditProposal(ctx, admin, proposal.ID, gmodel.ReviewProductEditProposal{
+			Approve: true,
+			Fields: []string{"quantityType"},
+		}); err == nil {
+			t.Fatal("fields that were not proposed should not be applied")
+		}
+
+		reviewed, err := service.ReviewProductEditProposal(ctx, admin, proposal.ID, gmodel.ReviewProductEditProposal{
+			Approve: true,
+			Fields: []string{"name"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reviewed.Status != gmodel.ProductEditProposalStatusPartiallyApproved || reviewed.ReviewedByID == nil {
+			t.Fatal("proposal should be partially approved", reviewed.Status)
+		}
+		updated, err := service.FindProductById(ctx, product.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if updated.Name != new_name || updated.Brand != product.Brand {
+			t.Fatal("only the approved fields should be applied", updated.Name, updated.Brand)
+		}
+
+		billing, err := service.FindProductBillingByUser(ctx, gmodel.PaginatorInput{ Page: 1, Limit: 10 }, contributor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(billing.Data) != 1 || billing.Data[0].Status != gmodel.ProductBillingStatusApproved {
+			t.Fatal("approved proposal should be billed to the proposer", billing.Data)
+		}
+
+		if _, err := service.ReviewProductEditProposal(ctx, admin, proposal.ID, gmodel.ReviewProductEditProposal{
+			Approve: false,
+		}); err == nil {
+			t.Fatal("reviewed proposals cannot be reviewed again")
+		}
+	})
+
+	t.Run("rejection", func(t *testing.T) {
+		rejected, err := service.CreateProductEditProposal(ctx, contributor, product.ID, gmodel.UpdateProduct{
+			Brand: &new_brand,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		notes := "brand is correct"
+		rejected, err = service.ReviewProductEditProposal(ctx, admin, rejected.ID, gmodel.ReviewProductEditProposal{
+			Approve: false,
+			Notes: &notes,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rejected.Status != gmodel.ProductEditProposalStatusRejected || rejected.AppliedChanges != nil {
+			t.Fatal("proposal should be rejected", rejected.Status)
+		}
+		billing, err := service.FindProductBillingByUser(ctx, gmodel.PaginatorInput{ Page: 1, Limit: 10 }, contributor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(billing.Data) != 1 {
+			t.Fatal("rejected proposals should not be billed")
+		}
+	})
+}
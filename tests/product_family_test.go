@@ -0,0 +1,183 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/utils"
+)
+
+func TestProductFamily(t *testing.T) {
+	user, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Family contributor",
+		Email: "family_contributor@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img := "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAAC0lEQVR42mP8//8/AwAI/wH+9Q4AAAAASUVORK5CYII="
+	store, err := service.CreateStore(ctx, user, gmodel.CreateStore{
+		Name: "Family Test Store",
+		LogoBase64: &img,
+		Website: "https://pricetra.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	branch, err := service.CreateBranch(ctx, user, gmodel.CreateBranch{
+		Name: "Family Test Branch",
+		StoreID: store.ID,
+		Address: &gmodel.CreateAddress{
+			Latitude: 41.900612,
+			Longitude: -88.3436658,
+			MapsLink: "https://maps.google.com",
+			FullAddress: "855 S Randall Rd, St. Charles, IL 60174, USA",
+			City: "St. Charles",
+			AdministrativeDivision: "Illinois",
+			CountryCode: "US",
+			ZipCode: 60174,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	category, err := service.CategoryRecursiveInsert(ctx, "Family Test Category")
+	if err != nil {
+		t.Fatal(err)
+	}
+	small_weight := "12 oz"
+	small, err := service.CreateProduct(ctx, user, gmodel.CreateProduct{
+		Name: "Family Test Cereal 12 oz",
+		Description: "Some description",
+		Brand: "Pricetra",
+		Code: "016000027015",
+		CategoryID: category.ID,
+		Weight: &small_weight,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	large_weight := "18 oz"
+	large, err := service.CreateProduct(ctx, user, gmodel.CreateProduct{
+		Name: "Family Test Cereal 18 oz",
+		Description: "Some description",
+		Brand: "Pricetra",
+		Code: "016000027022",
+		CategoryID: category.ID,
+		Weight: &large_weight,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.CreatePrice(ctx, user, gmodel.CreatePrice{
+		ProductID: small.ID,
+		BranchID: branch.ID,
+		Amount: 3.60,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.CreatePrice(ctx, user, gmodel.CreatePrice{
+		ProductID: large.ID,
+		BranchID: branch.ID,
+		Amount: 4.50,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	family, err := service.CreateProductFamily(ctx, user, gmodel.CreateProductFamily{
+		Name: "Family Test Cereal",
+		Brand: "Pricetra",
+		CategoryID: &category.ID,
+		ProductIds: []int64{small.ID},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("product without family has no variants", func(t *testing.T) {
+		variants, err := service.ProductVariants(ctx, large.ID, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(variants) != 0 {
+			t.Fatal("product should not have variants", variants)
+		}
+	})
+
+	t.Run("add product to family", func(t *testing.T) {
+		pack_count := 0
+		if _, err := service.AddProductToFamily(ctx, user, family.ID, large.ID, gmodel.ProductVariantInput{
+			PackCount: &pack_count,
+		}); err == nil {
+			t.Fatal("pack count should be positive")
+		}
+		size := "Family Size"
+		variant, err := service.AddProductToFamily(ctx, user, family.ID, large.ID, gmodel.ProductVariantInput{
+			Size: &size,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if variant.PackCount != 1 || variant.ProductFamilyID != family.ID {
+			t.Fatal("variant should default to a pack count of 1", variant)
+		}
+	})
+
+	t.Run("variants with unit prices", func(t *testing.T) {
+		variants, err := service.ProductVariants(ctx, small.ID, &gmodel.LocationInput{
+			Latitude: 41.900612,
+			Longitude: -88.3436658,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(variants) != 1 || variants[0].ProductID != large.ID {
+			t.Fatal("siblings of the product should be returned", variants)
+		}
+		v := variants[0]
+		if v.Stock == nil || v.Stock.LatestPrice == nil || v.Stock.BranchID != branch.ID {
+			t.Fatal("latest price of the sibling should be returned", v.Stock)
+		}
+		if v.UnitPrice == nil || math.Abs(*v.UnitPrice - 0.25) > 0.0001 || *v.UnitPriceType != "oz" {
+			t.Fatal("unit price should be 0.25 per oz", v.UnitPrice, v.UnitPriceType)
+		}
+
+		all, err := service.ProductFamilyVariants(ctx, family.ID, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(all) != 2 || all[0].ProductID != small.ID {
+			t.Fatal("family should list all variants, smallest first", all)
+		}
+	})
+
+	t.Run("remove product from family", func(t *testing.T) {
+		if _, err := service.RemoveProductFromFamily(ctx, large.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.RemoveProductFromFamily(ctx, large.ID); err == nil {
+			t.Fatal("product is no longer part of a family")
+		}
+		variants, err := service.ProductVariants(ctx, small.ID, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(variants) != 0 {
+			t.Fatal("removed product should not be a variant", variants)
+		}
+	})
+
+	t.Run("unit price conversion", func(t *testing.T) {
+		unit_price, unit, err := utils.UnitPrice(6, 1, "lb", 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if unit != "oz" || math.Abs(unit_price - 0.1875) > 0.0001 {
+			t.Fatal("2 x 1 lb for 6 should be 0.1875 per oz", unit_price, unit)
+		}
+	})
+}
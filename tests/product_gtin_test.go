@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/utils"
+)
+
+func TestProductGtin(t *testing.T) {
+	t.Run("parse", func(t *testing.T) {
+		valid := map[string]string{
+			"012345678905": "00012345678905", // UPC-A
+			"12345678905": "00012345678905", // UPC-A without leading zero
+			"0012345678905": "00012345678905", // EAN-13
+			"00012345678905": "00012345678905", // GTIN-14
+			"4006381333931": "04006381333931", // EAN-13
+			"96385074": "00000096385074", // EAN-8
+			"04252614": "00042100005264", // UPC-E
+			"425261": "00042100005264", // UPC-E without number system and check digit
+			"0 12345-67890 5": "00012345678905",
+		}
+		for code, expected := range valid {
+			gtin, err := utils.ParseGtin(code)
+			if err != nil {
+				t.Fatal(code, err)
+			}
+			if gtin != expected {
+				t.Fatal(code, "should parse into", expected, "got", gtin)
+			}
+		}
+
+		for _, code := range []string{"012345678901", "4006381333932", "123", "ABC123"} {
+			if _, err := utils.ParseGtin(code); err == nil {
+				t.Fatal(code, "should be invalid")
+			}
+		}
+	})
+
+	user, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "GTIN test user",
+		Email: "gtin_test@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	category, err := service.CategoryRecursiveInsert(ctx, "GTIN Test Category")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("create", func(t *testing.T) {
+		if _, err := service.CreateProduct(ctx, user, gmodel.CreateProduct{
+			Name: "GTIN invalid product",
+			Brand: "Pricetra",
+			Code: "4006381333932",
+			CategoryID: category.ID,
+		}, nil); err == nil {
+			t.Fatal("barcode with an invalid check digit should be rejected")
+		}
+
+		product, err := service.CreateProduct(ctx, user, gmodel.CreateProduct{
+			Name: "GTIN test product",
+			Brand: "Pricetra",
+			Code: "4006381333931",
+			CategoryID: category.ID,
+		}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if product.Code != "4006381333931" || product.Gtin == nil || *product.Gtin != "04006381333931" {
+			t.Fatal("scanned code and canonical gtin should both be stored", product.Code, product.Gtin)
+		}
+
+		if _, err := service.CreateProduct(ctx, user, gmodel.CreateProduct{
+			Name: "GTIN duplicate product",
+			Brand: "Pricetra",
+			Code: "04006381333931",
+			CategoryID: category.ID,
+		}, nil); err == nil {
+			t.Fatal("same gtin in a different format should already exist")
+		}
+
+		found, err := service.FindProductWithCode(ctx, "04006381333931")
+		if err != nil || found.ID != product.ID {
+			t.Fatal("lookup should match across formats", err)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		product, err := service.CreateProduct(ctx, user, gmodel.CreateProduct{
+			Name: "GTIN update product",
+			Brand: "Pricetra",
+			Code: "012345678905",
+			CategoryID: category.ID,
+		}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ean := "0012345678905"
+		updated, _, err := service.UpdateProductById(ctx, user, product.ID, gmodel.UpdateProduct{ Code: &ean })
+		if err != nil {
+			t.Fatal("re-entering the barcode in another format should be allowed", err)
+		}
+		if updated.Code != ean || updated.Gtin == nil || *updated.Gtin != "00012345678905" {
+			t.Fatal("barcode should be updated", updated.Code, updated.Gtin)
+		}
+
+		other := "04006381333931"
+		if _, _, err := service.UpdateProductById(ctx, user, product.ID, gmodel.UpdateProduct{ Code: &other }); err == nil {
+			t.Fatal("barcode of another product should be rejected")
+		}
+	})
+}
@@ -0,0 +1,176 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+func TestProductImage(t *testing.T) {
+	admin, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Image admin",
+		Email: "image_admin@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin.Role = gmodel.UserRoleAdmin
+	contributor, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Image contributor",
+		Email: "image_contributor@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	category, err := service.CategoryRecursiveInsert(ctx, "Image Test Category")
+	if err != nil {
+		t.Fatal(err)
+	}
+	product, err := service.CreateProduct(ctx, admin, gmodel.CreateProduct{
+		Name: "Image Test Product",
+		Description: "Some description",
+		Brand: "Pricetra",
+		Code: "016000027107",
+		CategoryID: category.ID,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	approved := gmodel.ProductImageStatusApproved
+	t.Run("barcode image is the primary image", func(t *testing.T) {
+		images, err := service.FindProductImages(ctx, product.ID, &approved)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(images) != 1 || !images[0].IsPrimary || images[0].PublicID != product.Code {
+			t.Fatal("product should have its barcode image as primary image", images)
+		}
+		if images[0].URL != product.Image || images[0].Role != gmodel.ProductImageRoleFront {
+			t.Fatal("primary image should be the product image", images[0])
+		}
+	})
+
+	t.Run("image is required", func(t *testing.T) {
+		if _, err := service.AddProductImage(ctx, contributor, product.ID, gmodel.AddProductImage{
+			Role: gmodel.ProductImageRoleBack,
+		}); err == nil {
+			t.Fatal("image should be required")
+		}
+	})
+
+	old_images, err := service.FindProductImages(ctx, product.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old_image := old_images[0]
+	new_code := "016000027114"
+	updated_product, _, err := service.UpdateProductById(ctx, admin, product.ID, gmodel.UpdateProduct{
+		Code: &new_code,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	new_image, err := service.SetBarcodeProductImagePrimary(ctx, admin, updated_product)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("new barcode image becomes primary", func(t *testing.T) {
+		p, err := service.FindProductById(ctx, product.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Image != new_image.URL {
+			t.Fatal("product image should point to the new primary image", p.Image)
+		}
+		images, err := service.FindProductImages(ctx, product.ID, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(images) != 2 || images[0].ID != new_image.ID || images[1].IsPrimary {
+			t.Fatal("there should only be one primary image", images)
+		}
+	})
+
+	t.Run("reorder and set primary", func(t *testing.T) {
+		if _, err := service.ReorderProductImages(ctx, product.ID, []int64{new_image.ID, new_image.ID}); err == nil {
+			t.Fatal("duplicate ids should not be allowed")
+		}
+		images, err := service.ReorderProductImages(ctx, product.ID, []int64{new_image.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		positions := map[int64]int{}
+		for _, image := range images {
+			positions[image.ID] = image.Position
+		}
+		if positions[new_image.ID] != 0 || positions[old_image.ID] != 1 {
+			t.Fatal("unlisted images should be placed after listed images", positions)
+		}
+
+		primary, err := service.SetPrimaryProductImage(ctx, old_image.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !primary.IsPrimary {
+			t.Fatal("image should be primary")
+		}
+		p, err := service.FindProductById(ctx, product.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Image != old_image.URL {
+			t.Fatal("product image should point to the primary image", p.Image)
+		}
+	})
+
+	t.Run("contributors cannot remove approved images", func(t *testing.T) {
+		if _, err := service.RemoveProductImage(ctx, contributor, new_image.ID); err == nil {
+			t.Fatal("approved images can only be removed by admins")
+		}
+		if _, err := service.ReviewProductImage(ctx, admin, new_image.ID, false, nil); err == nil {
+			t.Fatal("approved images cannot be reviewed again")
+		}
+	})
+
+	t.Run("replacing the primary image keeps the barcode image", func(t *testing.T) {
+		base64_image := testImageBase64(41)
+		image, err := service.PrepareImage(nil, &base64_image, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		public_id, err := service.ReplacePrimaryProductImage(ctx, admin, updated_product, image, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if public_id == updated_product.Code {
+			t.Fatal("new image should not overwrite the barcode image")
+		}
+		images, err := service.FindProductImages(ctx, product.ID, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(images) != 3 || images[0].PublicID != public_id || images[0].URL == "" {
+			t.Fatal("new image should be the primary image", images)
+		}
+		for _, image := range images[1:] {
+			if image.IsPrimary {
+				t.Fatal("there should only be one primary image", images)
+			}
+			if image.ID == new_image.ID && image.URL != new_image.URL {
+				t.Fatal("barcode image should not change", image)
+			}
+		}
+		p, err := service.FindProductById(ctx, product.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Image != images[0].URL {
+			t.Fatal("product image should point to the new primary image", p.Image)
+		}
+	})
+}
@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/services"
+)
+
+func TestProductMerge(t *testing.T) {
+	admin, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Merge admin",
+		Email: "merge_admin@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin.Role = gmodel.UserRoleAdmin
+
+	img := "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAAC0lEQVR42mP8//8/AwAI/wH+9Q4AAAAASUVORK5CYII="
+	store, err := service.CreateStore(ctx, admin, gmodel.CreateStore{
+		Name: "Merge Test Store",
+		LogoBase64: &img,
+		Website: "https://pricetra.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	branch, err := service.CreateBranch(ctx, admin, gmodel.CreateBranch{
+		Name: "Merge Test Branch",
+		StoreID: store.ID,
+		Address: &gmodel.CreateAddress{
+			Latitude: 41.900612,
+			Longitude: -88.3436658,
+			MapsLink: "https://maps.google.com",
+			FullAddress: "855 S Randall Rd, St. Charles, IL 60174, USA",
+			City: "St. Charles",
+			AdministrativeDivision: "Illinois",
+			CountryCode: "US",
+			ZipCode: 60174,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	category, err := service.CategoryRecursiveInsert(ctx, "Merge Test Category")
+	if err != nil {
+		t.Fatal(err)
+	}
+	target, err := service.CreateProduct(ctx, admin, gmodel.CreateProduct{
+		Name: "Merge Test Cola 12oz",
+		Description: "Some description",
+		Brand: "Pricetra",
+		Code: "036000291452",
+		CategoryID: category.ID,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := service.CreateProduct(ctx, admin, gmodel.CreateProduct{
+		Name: "Merge Test Cola 12 oz",
+		Description: "Some description",
+		Brand: "Pricetra",
+		Code: "036000291469",
+		CategoryID: category.ID,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if services.NormalizedBarcode("0036000291452") != services.NormalizedBarcode(target.Code) {
+		t.Fatal("UPC-A and EAN-13 codes should normalize to the same barcode")
+	}
+
+	t.Run("duplicate candidates", func(t *testing.T) {
+		candidates, err := service.DuplicateProductCandidates(ctx, target.ID, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(candidates) == 0 || candidates[0].Product.ID != source.ID {
+			t.Fatal("source product should be the best duplicate candidate", candidates)
+		}
+		if !strings.Contains(candidates[0].Reasons[0], "brand and name similarity") {
+			t.Fatal("name similarity should be a reason", candidates[0].Reasons)
+		}
+	})
+
+	target_price, err := service.CreatePrice(ctx, admin, gmodel.CreatePrice{
+		ProductID: target.ID,
+		BranchID: branch.ID,
+		Amount: 1.99,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	source_price, err := service.CreatePrice(ctx, admin, gmodel.CreatePrice{
+		ProductID: source.ID,
+		BranchID: branch.ID,
+		Amount: 2.09,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("merge", func(t *testing.T) {
+		if _, err := service.MergeProducts(ctx, admin, target.ID, target.ID); err == nil {
+			t.Fatal("product should not be merged into itself")
+		}
+		merged, err := service.MergeProducts(ctx, admin, source.ID, target.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if merged.ID != target.ID {
+			t.Fatal("target product should be returned")
+		}
+		if _, err := service.FindProductById(ctx, source.ID); err == nil {
+			t.Fatal("source product should be deleted")
+		}
+
+		stock, err := service.FindStock(ctx, target.ID, branch.ID, branch.StoreID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stock.LatestPriceID == nil || *stock.LatestPriceID != source_price.ID {
+			t.Fatal("most recent price of the merged history should be the latest price", stock.LatestPriceID)
+		}
+		prices, err := service.FindPrices(ctx, target.ID, branch.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := map[int64]bool{}
+		for _, price := range prices {
+			found[price.ID] = true
+		}
+		if len(prices) != 2 || !found[target_price.ID] || !found[source_price.ID] {
+			t.Fatal("price histories should be merged", prices)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+func TestProductRevision(t *testing.T) {
+	admin, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Revision admin",
+		Email: "revision_admin@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin.Role = gmodel.UserRoleAdmin
+
+	category, err := service.CategoryRecursiveInsert(ctx, "Revision Test Category")
+	if err != nil {
+		t.Fatal(err)
+	}
+	product, err := service.CreateProduct(ctx, admin, gmodel.CreateProduct{
+		Name: "Revision test product",
+		Description: "Some description",
+		Brand: "Pricetra",
+		Code: "REVISIONTESTBARCODE",
+		CategoryID: category.ID,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	revisions := func() []*gmodel.ProductRevision {
+		res, err := service.PaginatedProductRevisions(ctx, gmodel.PaginatorInput{ Page: 1, Limit: 100 }, product.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.Data
+	}
+
+	t.Run("create and update", func(t *testing.T) {
+		data := revisions()
+		if len(data) != 1 || data[0].Action != gmodel.ProductRevisionActionCreate || data[0].Revision != 1 {
+			t.Fatal("product creation should be recorded", data)
+		}
+
+		new_name := "Revision test product renamed"
+		if _, _, err := service.UpdateProductById(ctx, admin, product.ID, gmodel.UpdateProduct{ Name: &new_name }); err != nil {
+			t.Fatal(err)
+		}
+		// no-op updates don't create revisions
+		if _, _, err := service.UpdateProductById(ctx, admin, product.ID, gmodel.UpdateProduct{ CategoryID: &category.ID }); err != nil {
+			t.Fatal(err)
+		}
+
+		data = revisions()
+		if len(data) != 2 || data[0].Revision != 2 || data[0].Action != gmodel.ProductRevisionActionUpdate {
+			t.Fatal("update should be recorded once", data)
+		}
+		if !strings.Contains(data[0].Changes, new_name) || strings.Contains(data[0].Changes, "brand") {
+			t.Fatal("revision should only contain the changed fields", data[0].Changes)
+		}
+		if data[0].User == nil || data[0].User.ID != admin.ID {
+			t.Fatal("revision should include the user")
+		}
+	})
+
+	t.Run("revert", func(t *testing.T) {
+		data := revisions()
+		first := data[len(data) - 1]
+		reverted, err := service.RevertProduct(ctx, admin, product.ID, first.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reverted.Name != product.Name {
+			t.Fatal("product should be reverted", reverted.Name)
+		}
+
+		data = revisions()
+		if len(data) != 3 || data[0].Action != gmodel.ProductRevisionActionRevert || data[0].RevertedFromID == nil || *data[0].RevertedFromID != first.ID {
+			t.Fatal("revert should be recorded", data[0])
+		}
+
+		if _, err := service.RevertProduct(ctx, admin, product.ID + 1000000, first.ID); err == nil {
+			t.Fatal("revision of another product should not be applied")
+		}
+	})
+}
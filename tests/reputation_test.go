@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/pricetra/api/database/jet/postgres/public/model"
+	"github.com/pricetra/api/database/jet/postgres/public/table"
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/services"
+)
+
+func TestReputation(t *testing.T) {
+	trusted, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Trusted contributor",
+		Email: "reputation_trusted@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	newcomer, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "New contributor",
+		Email: "reputation_newcomer@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("score", func(t *testing.T) {
+		if score := services.ReputationScore(gmodel.UserReputation{}); score != services.DEFAULT_REPUTATION_SCORE {
+			t.Fatal("new users should start with the default score", score)
+		}
+		good := services.ReputationScore(gmodel.UserReputation{ ConfirmedPrices: 40, AcceptedEdits: 20, AccountAgeDays: 400 })
+		bad := services.ReputationScore(gmodel.UserReputation{ DisputedPrices: 40, RejectedEdits: 20 })
+		if good < services.TRUSTED_REPUTATION_SCORE || bad >= services.LOW_REPUTATION_SCORE {
+			t.Fatal("scores are not weighted correctly", good, bad)
+		}
+	})
+
+	t.Run("refresh", func(t *testing.T) {
+		reputation, err := service.RefreshUserReputation(ctx, newcomer.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reputation.Score != services.DEFAULT_REPUTATION_SCORE || reputation.Trusted {
+			t.Fatal("new user should have the default score", reputation)
+		}
+		user, err := service.FindUserById(ctx, newcomer.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.ReputationScore != reputation.Score {
+			t.Fatal("score should be stored on the user", user.ReputationScore)
+		}
+	})
+
+	_, err = table.User.
+		UPDATE(table.User.ReputationScore).
+		SET(postgres.Float(95)).
+		WHERE(table.User.ID.EQ(postgres.Int(trusted.ID))).
+		ExecContext(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trusted.ReputationScore = 95
+
+	t.Run("trusted submissions are auto-approved", func(t *testing.T) {
+		category, err := service.CategoryRecursiveInsert(ctx, "Reputation Test Category")
+		if err != nil {
+			t.Fatal(err)
+		}
+		product, err := service.CreateProduct(ctx, trusted, gmodel.CreateProduct{
+			Name: "Reputation test product",
+			Description: "Some description",
+			Brand: "Pricetra",
+			Code: "REPUTATIONTESTBARCODE",
+			CategoryID: category.ID,
+		}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 2; i++ {
+			billing, err := service.CreateProductBilling(ctx, trusted, model.ProductBillingType_Scan, product, nil, product, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if billing.Status != model.ProductBillingStatus_Approved {
+				t.Fatal("trusted contributor submissions should be approved", billing.Status, billing.StatusReason)
+			}
+		}
+	})
+
+	t.Run("latest price weighting", func(t *testing.T) {
+		latest := gmodel.Price{
+			ID: 1,
+			Amount: 3.99,
+			CreatedByID: &trusted.ID,
+			CreatedAt: time.Now().Add(-1 * time.Hour),
+		}
+		if service.ShouldReplaceLatestPrice(ctx, newcomer, gmodel.Price{ Amount: 9.99 }, &latest) {
+			t.Fatal("conflicting report from a lower reputation user should not replace the latest price")
+		}
+		if !service.ShouldReplaceLatestPrice(ctx, trusted, gmodel.Price{ Amount: 4.49 }, &gmodel.Price{
+			ID: 2,
+			Amount: 3.99,
+			CreatedByID: &newcomer.ID,
+			CreatedAt: time.Now().Add(-1 * time.Hour),
+		}) {
+			t.Fatal("higher reputation report should replace the latest price")
+		}
+
+		latest.CreatedAt = time.Now().Add(-2 * services.PRICE_CONFLICT_WINDOW)
+		if !service.ShouldReplaceLatestPrice(ctx, newcomer, gmodel.Price{ Amount: 9.99 }, &latest) {
+			t.Fatal("stale prices should always be replaced")
+		}
+	})
+}
@@ -0,0 +1,286 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/pricetra/api/graph/gmodel"
+)
+
+func TestSaleExpiration(t *testing.T) {
+	user, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Sale expiration user",
+		Email: "sale_expiration@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAAC0lEQVR42mP8//8/AwAI/wH+9Q4AAAAASUVORK5CYII="
+	store, err := service.CreateStore(ctx, user, gmodel.CreateStore{
+		Name: "Sale Expiration Store",
+		LogoBase64: &img,
+		Website: "https://pricetra.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	branch, err := service.CreateBranch(ctx, user, gmodel.CreateBranch{
+		Name: "Sale Expiration Branch",
+		StoreID: store.ID,
+		Address: &gmodel.CreateAddress{
+			Latitude: 41.900612,
+			Longitude: -88.3436658,
+			MapsLink: "https://maps.google.com",
+			FullAddress: "855 S Randall Rd, St. Charles, IL 60174, USA",
+			City: "St. Charles",
+			AdministrativeDivision: "Illinois",
+			CountryCode: "US",
+			ZipCode: 60174,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	category, err := service.CategoryRecursiveInsert(ctx, "Sale Expiration Category")
+	if err != nil {
+		t.Fatal(err)
+	}
+	product, err := service.CreateProduct(ctx, user, gmodel.CreateProduct{
+		Name: "Sale Expiration Product",
+		Description: "Some description",
+		Brand: "Pricetra",
+		Code: "036000291544",
+		CategoryID: category.ID,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other_product, err := service.CreateProduct(ctx, user, gmodel.CreateProduct{
+		Name: "Sale Expiration Product Without Regular Price",
+		Description: "Some description",
+		Brand: "Pricetra",
+		Code: "036000291551",
+		CategoryID: category.ID,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	regular, err := service.CreatePrice(ctx, user, gmodel.CreatePrice{
+		ProductID: product.ID,
+		BranchID: branch.ID,
+		Amount: 5.99,
+		UnitType: "item",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sale_price, err := service.CreatePrice(ctx, user, gmodel.CreatePrice{
+		ProductID: product.ID,
+		BranchID: branch.ID,
+		Amount: 3.99,
+		UnitType: "item",
+		Sale: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	only_sale_price, err := service.CreatePrice(ctx, user, gmodel.CreatePrice{
+		ProductID: other_product.ID,
+		BranchID: branch.ID,
+		Amount: 1.99,
+		UnitType: "item",
+		Sale: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("active sales are kept", func(t *testing.T) {
+		if _, err := service.ExpireSalePrices(ctx); err != nil {
+			t.Fatal(err)
+		}
+		stock, err := service.FindStockById(ctx, sale_price.StockID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stock.LatestPriceID == nil || *stock.LatestPriceID != sale_price.ID {
+			t.Fatal("sale price should still be the latest price", stock.LatestPriceID)
+		}
+	})
+
+	t.Run("expired sales revert to the regular price", func(t *testing.T) {
+		if _, err := db.ExecContext(
+			ctx,
+			`update "price" set "expires_at" = now() - interval '1 day' where "id" in ($1, $2)`,
+			sale_price.ID,
+			only_sale_price.ID,
+		); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.ExpireSalePrices(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		stock, err := service.FindStockById(ctx, sale_price.StockID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stock.LatestPriceID == nil || *stock.LatestPriceID != regular.ID {
+			t.Fatal("stock should fall back to the regular price", stock.LatestPriceID)
+		}
+		transitions, err := service.StockPriceTransitions(ctx, stock.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(transitions) != 1 || transitions[0].Reason != gmodel.StockPriceTransitionReasonSaleExpired {
+			t.Fatal("transition should be recorded", transitions)
+		}
+		if transitions[0].FromPrice == nil || transitions[0].FromPrice.ID != sale_price.ID ||
+			transitions[0].ToPrice == nil || transitions[0].ToPrice.ID != regular.ID {
+			t.Fatal("transition should reference both prices", transitions[0])
+		}
+	})
+
+	t.Run("stocks without a regular price become unknown", func(t *testing.T) {
+		stock, err := service.FindStockById(ctx, only_sale_price.StockID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stock.LatestPriceID != nil || stock.LatestPrice != nil {
+			t.Fatal("stock should not have a latest price", stock.LatestPrice)
+		}
+		transitions, err := service.StockPriceTransitions(ctx, stock.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(transitions) != 1 || transitions[0].ToPriceID != nil {
+			t.Fatal("transition should not have a new price", transitions)
+		}
+	})
+
+	t.Run("expired member sales revert to the member price", func(t *testing.T) {
+		program, err := service.CreateLoyaltyProgram(ctx, user, gmodel.CreateLoyaltyProgram{
+			StoreID: store.ID,
+			Name: "Sale Expiration Rewards",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		member_only := true
+		member_price, err := service.CreatePrice(ctx, user, gmodel.CreatePrice{
+			ProductID: product.ID,
+			BranchID: branch.ID,
+			Amount: 4.99,
+			UnitType: "item",
+			MemberOnly: &member_only,
+			LoyaltyProgramID: &program.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		member_sale_price, err := service.CreatePrice(ctx, user, gmodel.CreatePrice{
+			ProductID: product.ID,
+			BranchID: branch.ID,
+			Amount: 2.99,
+			UnitType: "item",
+			Sale: true,
+			MemberOnly: &member_only,
+			LoyaltyProgramID: &program.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.ExecContext(
+			ctx,
+			`update "price" set "expires_at" = now() - interval '1 day' where "id" = $1`,
+			member_sale_price.ID,
+		); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.ExpireSalePrices(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		stock, err := service.FindStockById(ctx, member_sale_price.StockID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stock.LatestMemberPriceID == nil || *stock.LatestMemberPriceID != member_price.ID {
+			t.Fatal("stock should fall back to the member price", stock.LatestMemberPriceID)
+		}
+		if stock.LatestPriceID == nil || *stock.LatestPriceID != regular.ID {
+			t.Fatal("regular price should not change", stock.LatestPriceID)
+		}
+	})
+
+	t.Run("expired sales skip older open-ended sales", func(t *testing.T) {
+		sale_product, err := service.CreateProduct(ctx, user, gmodel.CreateProduct{
+			Name: "Sale Expiration Product With Older Sale",
+			Description: "Some description",
+			Brand: "Pricetra",
+			Code: "036000291568",
+			CategoryID: category.ID,
+		}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var prices []gmodel.Price
+		for _, input := range []struct{
+			Amount float64
+			Sale bool
+		}{
+			{6.99, false},
+			{4.49, true},
+			{3.49, true},
+		} {
+			price, err := service.CreatePrice(ctx, user, gmodel.CreatePrice{
+				ProductID: sale_product.ID,
+				BranchID: branch.ID,
+				Amount: input.Amount,
+				UnitType: "item",
+				Sale: input.Sale,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			prices = append(prices, price)
+		}
+		// the older sale never got an expiration date
+		if _, err := db.ExecContext(
+			ctx,
+			`update "price" set "expires_at" = null where "id" = $1`,
+			prices[1].ID,
+		); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.ExecContext(
+			ctx,
+			`update "price" set "expires_at" = now() - interval '1 day' where "id" = $1`,
+			prices[2].ID,
+		); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.ExpireSalePrices(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		stock, err := service.FindStockById(ctx, prices[2].StockID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stock.LatestPriceID == nil || *stock.LatestPriceID != prices[0].ID {
+			t.Fatal("stock should fall back to the regular price, not an older sale", stock.LatestPriceID)
+		}
+	})
+
+	t.Run("expired sales are only reverted once", func(t *testing.T) {
+		transitions, err := service.ExpireSalePrices(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(transitions) != 0 {
+			t.Fatal("no stock should be reverted again", transitions)
+		}
+	})
+}
@@ -9,7 +9,6 @@ import (
 	"testing"
 
 	"github.com/ayaanqui/go-migration-tool/migration_tool"
-	"github.com/cloudinary/cloudinary-go/v2"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 	"github.com/ory/dockertest/v3"
@@ -25,6 +24,7 @@ var (
 	app types.ServerBase
 	service services.Service
 	ctx context.Context
+	image_store *services.LocalImageStore
 )
 
 func NewMockServer() {
@@ -46,18 +46,22 @@ func NewMockServer() {
 	var tokens types.Tokens
 	tokens.JwtKey = os.Getenv("JWT_KEY")
 
-	// Cloudinary CDN
-	cloudinary, err := cloudinary.NewFromParams(tokens.Cloudinary.CloudName, tokens.Cloudinary.ApiKey, tokens.Cloudinary.ApiSecret)
+	// Store images on disk
+	image_directory, err := os.MkdirTemp("", "pricetra-images")
 	if err != nil {
 		panic(err)
 	}
+	image_store = &services.LocalImageStore{
+		Directory: image_directory,
+		BaseUrl: "http://localhost/images",
+	}
 
 	app.Tokens = &tokens
 	service = services.Service{
 		DB: app.DB,
 		StructValidator: app.StructValidator,
 		Tokens: &tokens,
-		Cloudinary: cloudinary,
+		ImageStore: image_store,
 	}
 }
 
@@ -111,6 +115,7 @@ func TestMain(m *testing.M) {
 
 	// run tests...
 	exitCode := m.Run()
+	os.RemoveAll(image_store.Directory)
 
 	os.Exit(exitCode)
 }
@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/services"
+)
+
+func TestShelfTag(t *testing.T) {
+	user, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Shelf tag user",
+		Email: "shelf_tag@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAAC0lEQVR42mP8//8/AwAI/wH+9Q4AAAAASUVORK5CYII="
+	store, err := service.CreateStore(ctx, user, gmodel.CreateStore{
+		Name: "Shelf Tag Store",
+		LogoBase64: &img,
+		Website: "https://pricetra.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	branch, err := service.CreateBranch(ctx, user, gmodel.CreateBranch{
+		Name: "Shelf Tag Branch",
+		StoreID: store.ID,
+		Address: &gmodel.CreateAddress{
+			Latitude: 41.900612,
+			Longitude: -88.3436658,
+			MapsLink: "https://maps.google.com",
+			FullAddress: "855 S Randall Rd, St. Charles, IL 60174, USA",
+			City: "St. Charles",
+			AdministrativeDivision: "Illinois",
+			CountryCode: "US",
+			ZipCode: 60174,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	category, err := service.CategoryRecursiveInsert(ctx, "Shelf Tag Category")
+	if err != nil {
+		t.Fatal(err)
+	}
+	product, err := service.CreateProduct(ctx, user, gmodel.CreateProduct{
+		Name: "Crunchy Peanut Butter 16 oz",
+		Description: "Some description",
+		Brand: "Shelfy",
+		Code: "036000291490",
+		CategoryID: category.ID,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	price := 3.99
+	regular_price := 4.99
+	member_only := true
+	program := "Circle"
+	condition := " limit 4 "
+	expires_at := time.Now().AddDate(0, 0, 3).Format(time.DateOnly)
+
+	t.Run("match by upc without check digit", func(t *testing.T) {
+		upc := "0 36000 29149"
+		extraction := service.ShelfTagExtractionFromFields(ctx, branch.ID, gmodel.ShelfTagFields{
+			Upc: &upc,
+			Price: &price,
+			RegularPrice: &regular_price,
+			MemberOnly: &member_only,
+			MemberProgram: &program,
+			Condition: &condition,
+			ExpiresAt: &expires_at,
+		})
+		if extraction.Product == nil || extraction.Product.ID != product.ID {
+			t.Fatal("product should be matched by its upc", extraction.Product)
+		}
+		draft := extraction.Draft
+		if draft == nil {
+			t.Fatal("draft should be created")
+		}
+		if draft.Amount != price || !draft.Sale || draft.OriginalPrice == nil || *draft.OriginalPrice != regular_price {
+			t.Fatal("draft should be a sale with the regular price as original price", draft)
+		}
+		if draft.Condition == nil || *draft.Condition != "Circle member price; limit 4" {
+			t.Fatal("member and other conditions should be combined", draft.Condition)
+		}
+		if draft.ExpiresAt == nil || draft.ExpiresAt.Format(time.DateOnly) != expires_at || draft.UnitType != "item" {
+			t.Fatal("draft should expire at the end of the printed date", draft.ExpiresAt)
+		}
+	})
+
+	t.Run("match by name", func(t *testing.T) {
+		brand := "Shelfy"
+		name := "Crunchy Peanut Butter"
+		extraction := service.ShelfTagExtractionFromFields(ctx, branch.ID, gmodel.ShelfTagFields{
+			Brand: &brand,
+			ProductName: &name,
+		})
+		if extraction.Product == nil || extraction.Product.ID != product.ID {
+			t.Fatal("product should be matched by its name", extraction.Product)
+		}
+		if extraction.Draft != nil {
+			t.Fatal("tags without a price should not have a draft")
+		}
+	})
+
+	t.Run("submit draft", func(t *testing.T) {
+		draft := services.ShelfTagPriceDraft(gmodel.ShelfTagFields{ Price: &price }, product.ID, branch.ID)
+		var buf bytes.Buffer
+		png.Encode(&buf, testImage(30, 72, 64))
+		created, err := service.CreatePrice(ctx, user, services.ShelfTagCreatePrice(*draft, buf.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if created.Amount != price || created.Sale || created.ImageURL == nil {
+			t.Fatal("price should be created with the shelf tag as evidence", created)
+		}
+		if service.CanSubmitShelfTagPrice(ctx, user, store.ID) {
+			t.Fatal("new contributors should get a draft")
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/types"
+)
+
+func TestStoreMember(t *testing.T) {
+	admin, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Store member admin",
+		Email: "store_member_admin@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin.Role = gmodel.UserRoleAdmin
+	manager, _, err := service.CreateInternalUser(ctx, gmodel.CreateAccountInput{
+		Name: "Store manager",
+		Email: "store_manager@pricetra.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img := "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAAC0lEQVR42mP8//8/AwAI/wH+9Q4AAAAASUVORK5CYII="
+	store, err := service.CreateStore(ctx, admin, gmodel.CreateStore{
+		Name: "Jewel Osco",
+		LogoBase64: &img,
+		Website: "https://www.jewelosco.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other_store, err := service.CreateStore(ctx, admin, gmodel.CreateStore{
+		Name: "Mariano's",
+		LogoBase64: &img,
+		Website: "https://www.marianos.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	role := gmodel.UserRoleAdmin
+	store_role := gmodel.StoreRoleManager
+	next := func(ctx context.Context) (any, error) { return true, nil }
+	directive_ctx := func(user gmodel.User, store_id int64) context.Context {
+		c := context.WithValue(ctx, types.AuthUserKey, user)
+		return graphql.WithFieldContext(c, &graphql.FieldContext{
+			Args: map[string]any{
+				"storeId": store_id,
+				"input": gmodel.UpdateStore{},
+			},
+		})
+	}
+
+	t.Run("non member is not authorized", func(t *testing.T) {
+		_, err := service.IsAuthenticatedDirective(directive_ctx(manager, store.ID), nil, next, &role, &store_role)
+		if err == nil {
+			t.Fatal("user without store membership should not be authorized")
+		}
+	})
+
+	t.Run("add member", func(t *testing.T) {
+		member, err := service.AddStoreMember(ctx, admin, store.ID, manager.ID, gmodel.StoreRoleManager)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if member.Role != gmodel.StoreRoleManager || member.StoreID != store.ID {
+			t.Fatal("incorrect membership", member)
+		}
+
+		if _, err := service.AddStoreMember(ctx, manager, store.ID, admin.ID, gmodel.StoreRoleOwner); err == nil {
+			t.Fatal("manager should not be able to assign owner role")
+		}
+
+		members, err := service.StoreMembers(ctx, store.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(members) != 1 || members[0].User == nil || members[0].User.ID != manager.ID {
+			t.Fatal("store members should include manager", members)
+		}
+	})
+
+	t.Run("member is authorized for own store only", func(t *testing.T) {
+		if _, err := service.IsAuthenticatedDirective(directive_ctx(manager, store.ID), nil, next, &role, &store_role); err != nil {
+			t.Fatal("store manager should be authorized", err)
+		}
+		if _, err := service.IsAuthenticatedDirective(directive_ctx(manager, other_store.ID), nil, next, &role, &store_role); err == nil {
+			t.Fatal("store manager should not be authorized for other stores")
+		}
+
+		owner_role := gmodel.StoreRoleOwner
+		if _, err := service.IsAuthenticatedDirective(directive_ctx(manager, store.ID), nil, next, &role, &owner_role); err == nil {
+			t.Fatal("store manager should not be authorized for owner fields")
+		}
+	})
+
+	t.Run("update store", func(t *testing.T) {
+		name := "Jewel-Osco"
+		updated_store, err := service.UpdateStore(ctx, manager, store.ID, gmodel.UpdateStore{
+			Name: &name,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if updated_store.Name != name || updated_store.Logo != store.Logo {
+			t.Fatal("store was not updated correctly", updated_store)
+		}
+	})
+
+	t.Run("remove member", func(t *testing.T) {
+		if err := service.RemoveStoreMember(ctx, admin, store.ID, manager.ID); err != nil {
+			t.Fatal(err)
+		}
+		if service.HasStoreRole(ctx, manager, store.ID, gmodel.StoreRoleStaff) {
+			t.Fatal("membership should be removed")
+		}
+	})
+}
@@ -0,0 +1,178 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pricetra/api/graph/gmodel"
+	"github.com/pricetra/api/services"
+	"github.com/pricetra/api/utils"
+)
+
+func TestTwoFactor(t *testing.T) {
+	t.Run("totp rfc 6238 vectors", func(t *testing.T) {
+		// base32 of the ascii secret "12345678901234567890"
+		secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+		vectors := map[int64]string{
+			59: "287082",
+			1111111109: "081804",
+			1234567890: "005924",
+		}
+		for ts, expected := range vectors {
+			code, err := utils.TotpCode(secret, time.Unix(ts, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if code != expected {
+				t.Fatalf("expected %s at %d, got %s", expected, ts, code)
+			}
+		}
+		if utils.ValidateTotp(secret, "287082", time.Unix(59 + 90, 0), 1) {
+			t.Fatal("code outside of the skew window should be invalid")
+		}
+	})
+
+	user_input := gmodel.CreateAccountInput{
+		Email: "two_factor_user@email.com",
+		Name: "Two Factor User",
+		Password: "password123",
+	}
+	user, _, err := service.CreateInternalUser(ctx, user_input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := service.LoginInternal(ctx, user_input.Email, user_input.Password, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user = *auth.User
+
+	var (
+		secret string
+		recovery_codes []string
+	)
+
+	t.Run("enroll", func(t *testing.T) {
+		enrollment, err := service.EnrollTwoFactor(ctx, user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if enrollment.Secret == "" || enrollment.OtpauthURL == "" {
+			t.Fatal("enrollment should return secret and otpauth url")
+		}
+		secret = enrollment.Secret
+
+		if _, err := service.ConfirmTwoFactor(ctx, user, "000000"); err == nil {
+			t.Fatal("confirm should fail with an invalid code")
+		}
+
+		// codes can only be used once, so each step of the test uses the code of a later time step
+		code, _ := utils.TotpCode(secret, time.Now().Add(-utils.TOTP_PERIOD * time.Second))
+		recovery_codes, err = service.ConfirmTwoFactor(ctx, user, code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(recovery_codes) != services.TWO_FACTOR_RECOVERY_CODE_COUNT {
+			t.Fatal("incorrect number of recovery codes", len(recovery_codes))
+		}
+
+		updated_user, err := service.FindAuthUserById(ctx, user.ID, *user.AuthStateID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !updated_user.TwoFactorEnabled {
+			t.Fatal("two factor should be enabled")
+		}
+		if updated_user.AuthTwoFactorVerifiedAt == nil {
+			t.Fatal("current auth state should be marked as verified")
+		}
+		user = updated_user
+	})
+
+	t.Run("login requires second factor", func(t *testing.T) {
+		challenge, err := service.LoginInternal(ctx, user_input.Email, user_input.Password, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if challenge.Token != "" {
+			t.Fatal("token should not be returned before the second factor is verified")
+		}
+		if challenge.TwoFactorChallengeID == nil {
+			t.Fatal("challenge id should be returned")
+		}
+
+		if _, err := service.VerifyTwoFactorLogin(ctx, *challenge.TwoFactorChallengeID, "000000"); err == nil {
+			t.Fatal("invalid code should not verify")
+		}
+
+		code, _ := utils.TotpCode(secret, time.Now())
+		verified, err := service.VerifyTwoFactorLogin(ctx, *challenge.TwoFactorChallengeID, code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if verified.Token == "" {
+			t.Fatal("token should be returned after verification")
+		}
+		if verified.User.AuthTwoFactorVerifiedAt == nil {
+			t.Fatal("auth state should be verified")
+		}
+
+		if _, err := service.VerifyTwoFactorLogin(ctx, *challenge.TwoFactorChallengeID, code); err == nil {
+			t.Fatal("challenge should only be usable once")
+		}
+
+		challenge, _ = service.LoginInternal(ctx, user_input.Email, user_input.Password, nil, nil)
+		if _, err := service.VerifyTwoFactorLogin(ctx, *challenge.TwoFactorChallengeID, code); err == nil {
+			t.Fatal("totp code should not be reusable")
+		}
+		for i := 1; i < services.TWO_FACTOR_CHALLENGE_MAX_TRIES; i++ {
+			service.VerifyTwoFactorLogin(ctx, *challenge.TwoFactorChallengeID, "000000")
+		}
+		if _, err := service.VerifyTwoFactorLogin(ctx, *challenge.TwoFactorChallengeID, "000000"); err == nil || err.Error() != "maximum number of tries reached for verification" {
+			t.Fatal("challenge should be locked after the maximum number of tries", err)
+		}
+	})
+
+	t.Run("recovery code", func(t *testing.T) {
+		challenge, err := service.LoginInternal(ctx, user_input.Email, user_input.Password, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.VerifyTwoFactorLogin(ctx, *challenge.TwoFactorChallengeID, recovery_codes[0]); err != nil {
+			t.Fatal("recovery code should verify", err)
+		}
+
+		challenge, _ = service.LoginInternal(ctx, user_input.Email, user_input.Password, nil, nil)
+		if _, err := service.VerifyTwoFactorLogin(ctx, *challenge.TwoFactorChallengeID, recovery_codes[0]); err == nil {
+			t.Fatal("recovery code should not be reusable")
+		}
+	})
+
+	t.Run("admins cannot disable", func(t *testing.T) {
+		admin := user
+		admin.Role = gmodel.UserRoleAdmin
+		code, _ := utils.TotpCode(secret, time.Now())
+		if _, err := service.DisableTwoFactor(ctx, admin, code); err == nil {
+			t.Fatal("admins should not be able to disable two-factor authentication")
+		}
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		code, _ := utils.TotpCode(secret, time.Now().Add(utils.TOTP_PERIOD * time.Second))
+		updated_user, err := service.DisableTwoFactor(ctx, user, code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if updated_user.TwoFactorEnabled {
+			t.Fatal("two factor should be disabled")
+		}
+
+		auth, err := service.LoginInternal(ctx, user_input.Email, user_input.Password, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if auth.Token == "" {
+			t.Fatal("login should return a token when two factor is disabled")
+		}
+	})
+}
@@ -11,6 +11,15 @@ type CloudinaryTokens struct {
 	CloudName string
 }
 
+type S3Tokens struct {
+	Endpoint string
+	Region string
+	Bucket string
+	AccessKeyID string
+	SecretAccessKey string
+	PublicUrl string
+}
+
 type OpenFoodFactsTokens struct {
 	Username string
 	Password string
@@ -20,6 +29,7 @@ type Tokens struct {
 	JwtKey string
 	EmailServer EmailServer
 	Cloudinary CloudinaryTokens
+	S3 S3Tokens
 	UPCitemdbUserKey string
 	GoogleMapsApiKey string
 	ExpoPushNotificationClientKey string
@@ -21,6 +21,14 @@ func (value AuthorizationKeyType) GetToken() (string, error) {
 type AuthUserKeyType string
 const AuthUserKey AuthUserKeyType = "AUTH_USER"
 
+type RequestMetadataKeyType string
+const RequestMetadataKey RequestMetadataKeyType = "REQUEST_METADATA"
+
+type RequestMetadata struct {
+	IpAddress string
+	UserAgent string
+}
+
 type IngredientLabelType string
 func (i IngredientLabelType) ToBool() *bool {
 	switch strings.ToLower(string(i)) {
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/go-chi/chi/v5"
@@ -15,4 +16,6 @@ type ServerBase struct {
 	MigrationDirectory string
 	Port string
 	Tokens *Tokens
+	// Called in order after the HTTP server stops accepting requests
+	ShutdownHooks []func(ctx context.Context) error
 }
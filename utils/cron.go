@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parsed 5 field cron expression (minute hour day-of-month month day-of-week).
+// Each field is a bit set of the allowed values
+type CronSchedule struct {
+	Minutes uint64
+	Hours uint64
+	Days uint64
+	Months uint64
+	Weekdays uint64
+	// Day of month and day of week match if either one matches when both are restricted
+	days_restricted bool
+	weekdays_restricted bool
+}
+
+type cron_field struct {
+	min int
+	max int
+}
+
+var cron_fields = []cron_field{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 7}, // day of week. 0 and 7 are both sunday
+}
+
+var cron_aliases = map[string]string{
+	"@yearly": "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly": "0 0 1 * *",
+	"@weekly": "0 0 * * 0",
+	"@daily": "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly": "0 * * * *",
+}
+
+// Parses cron expressions such as "*/15 * * * *", "0 3 * * 1-5" or "@daily"
+func ParseCron(expression string) (CronSchedule, error) {
+	expression = strings.TrimSpace(expression)
+	if alias, ok := cron_aliases[strings.ToLower(expression)]; ok {
+		expression = alias
+	}
+	parts := strings.Fields(expression)
+	if len(parts) != len(cron_fields) {
+		return CronSchedule{}, fmt.Errorf("cron expression must have %d fields", len(cron_fields))
+	}
+
+	sets := make([]uint64, len(parts))
+	for i, part := range parts {
+		set, err := parseCronField(part, cron_fields[i])
+		if err != nil {
+			return CronSchedule{}, fmt.Errorf("invalid cron field %q: %w", part, err)
+		}
+		sets[i] = set
+	}
+	weekdays := sets[4]
+	if weekdays & (1 << 7) != 0 {
+		weekdays |= 1
+	}
+	return CronSchedule{
+		Minutes: sets[0],
+		Hours: sets[1],
+		Days: sets[2],
+		Months: sets[3],
+		Weekdays: weekdays,
+		days_restricted: parts[2] != "*",
+		weekdays_restricted: parts[4] != "*",
+	}, nil
+}
+
+func parseCronField(field string, bounds cron_field) (set uint64, err error) {
+	for _, item := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			if step, err = strconv.Atoi(item[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step")
+			}
+			item = item[:i]
+		}
+
+		start, end := bounds.min, bounds.max
+		if item != "*" {
+			from, to, is_range := strings.Cut(item, "-")
+			if start, err = strconv.Atoi(from); err != nil {
+				return 0, fmt.Errorf("invalid value")
+			}
+			end = start
+			if is_range {
+				if end, err = strconv.Atoi(to); err != nil {
+					return 0, fmt.Errorf("invalid range")
+				}
+			} else if step > 1 {
+				// "5/10" runs from 5 until the end of the range
+				end = bounds.max
+			}
+		}
+		if start < bounds.min || end > bounds.max || start > end {
+			return 0, fmt.Errorf("value out of range %d-%d", bounds.min, bounds.max)
+		}
+		for v := start; v <= end; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+func (c CronSchedule) matchesDay(t time.Time) bool {
+	day := c.Days & (1 << t.Day()) != 0
+	weekday := c.Weekdays & (1 << int(t.Weekday())) != 0
+	if c.days_restricted && c.weekdays_restricted {
+		return day || weekday
+	}
+	return day && weekday
+}
+
+// First time after `t` matching the schedule, truncated to the minute.
+// Returns the zero time if nothing matches within 5 years (e.g. "0 0 30 2 *")
+func (c CronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if c.Months & (1 << int(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month() + 1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day() + 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.Hours & (1 << t.Hour()) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour() + 1, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.Minutes & (1 << t.Minute()) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
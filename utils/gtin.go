@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const GTIN_LENGTH = 14
+
+var barcode_separator_regex = regexp.MustCompile(`[\s-]`)
+var numeric_regex = regexp.MustCompile(`^[0-9]+$`)
+
+// Removes whitespace and hyphens from a scanned barcode
+func CleanBarcode(code string) string {
+	return barcode_separator_regex.ReplaceAllString(strings.TrimSpace(code), "")
+}
+
+// Returns true if the barcode only contains digits (ignoring whitespace and hyphens).
+// Non-numeric codes are internal codes and are never parsed as GTINs
+func IsNumericBarcode(code string) bool {
+	return numeric_regex.MatchString(CleanBarcode(code))
+}
+
+// Computes the GS1 check digit for the digits of a GTIN without its check digit
+func GtinCheckDigit(digits string) int {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		d := int(digits[len(digits) - 1 - i] - '0')
+		if i % 2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return (10 - sum % 10) % 10
+}
+
+func isValidGtinCheckDigit(code string) bool {
+	return GtinCheckDigit(code[:len(code) - 1]) == int(code[len(code) - 1] - '0')
+}
+
+// Expands a UPC-E code into its 12 digit UPC-A form.
+// Accepts 6 digits (number system 0, no check digit), 7 digits (no check digit) or 8 digits
+func ExpandUpcE(code string) (string, error) {
+	code = CleanBarcode(code)
+	if !numeric_regex.MatchString(code) {
+		return "", fmt.Errorf("upc-e must be numeric")
+	}
+	check := ""
+	switch len(code) {
+	case 6:
+		code = "0" + code
+	case 7:
+	case 8:
+		check = code[7:]
+		code = code[:7]
+	default:
+		return "", fmt.Errorf("upc-e must be 6, 7 or 8 digits")
+	}
+	number_system := code[0]
+	if number_system != '0' && number_system != '1' {
+		return "", fmt.Errorf("upc-e number system must be 0 or 1")
+	}
+
+	d := code[1:]
+	var body string
+	switch d[5] {
+	case '0', '1', '2':
+		body = d[0:2] + string(d[5]) + "0000" + d[2:5]
+	case '3':
+		body = d[0:3] + "00000" + d[3:5]
+	case '4':
+		body = d[0:4] + "00000" + d[4:5]
+	default:
+		body = d[0:5] + "0000" + d[5:6]
+	}
+	upc_a := string(number_system) + body
+	check_digit := fmt.Sprint(GtinCheckDigit(upc_a))
+	if check != "" && check != check_digit {
+		return "", fmt.Errorf("invalid upc-e check digit")
+	}
+	return upc_a + check_digit, nil
+}
+
+// Parses a scanned barcode into its canonical GTIN-14 form and validates the check digit.
+// Supports UPC-A (with or without leading zero), UPC-E, EAN-8, EAN-13 and GTIN-14
+func ParseGtin(code string) (string, error) {
+	code = CleanBarcode(code)
+	if !numeric_regex.MatchString(code) {
+		return "", fmt.Errorf("barcode must be numeric")
+	}
+
+	switch len(code) {
+	case 6, 7:
+		upc_a, err := ExpandUpcE(code)
+		if err != nil {
+			return "", err
+		}
+		code = upc_a
+	case 8:
+		// EAN-8 codes starting with 0 or 1 are reserved, so those are read as UPC-E
+		if code[0] == '0' || code[0] == '1' {
+			if upc_a, err := ExpandUpcE(code); err == nil {
+				code = upc_a
+			}
+		}
+	case 11, 12, 13, 14:
+	default:
+		return "", fmt.Errorf("invalid barcode length %d", len(code))
+	}
+
+	if !isValidGtinCheckDigit(code) {
+		return "", fmt.Errorf("invalid barcode check digit")
+	}
+	return strings.Repeat("0", GTIN_LENGTH - len(code)) + code, nil
+}
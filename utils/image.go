@@ -0,0 +1,316 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"math/bits"
+	"slices"
+	"strconv"
+)
+
+const MAX_IMAGE_BYTES = 15 << 20
+const MAX_IMAGE_PIXELS = 50_000_000
+const MIN_IMAGE_DIMENSION = 32
+
+// Standard sizes. Larger images are scaled down so their longest side fits
+const (
+	IMAGE_MAX_DIMENSION = 2048
+	IMAGE_THUMBNAIL_DIMENSION = 320
+)
+
+const IMAGE_JPEG_QUALITY = 85
+
+var ALLOWED_IMAGE_FORMATS = []string{"jpeg", "png"}
+
+type ProcessedImage struct {
+	// Re-encoded image without metadata, scaled down to `IMAGE_MAX_DIMENSION`
+	Data []byte
+	Thumbnail []byte
+	Format string
+	Width int
+	Height int
+	// 64 bit difference hash as hex. See `PerceptualHash`
+	Hash string
+}
+
+// Validates and decodes an image. Dimensions are checked before decoding
+// so oversized images are rejected without being loaded into memory
+func DecodeImage(data []byte) (image.Image, string, error) {
+	if len(data) == 0 {
+		return nil, "", fmt.Errorf("image is empty")
+	}
+	if len(data) > MAX_IMAGE_BYTES {
+		return nil, "", fmt.Errorf("image must be smaller than %d MB", MAX_IMAGE_BYTES >> 20)
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("unsupported image format")
+	}
+	if !slices.Contains(ALLOWED_IMAGE_FORMATS, format) {
+		return nil, "", fmt.Errorf("unsupported image format %s", format)
+	}
+	if config.Width < MIN_IMAGE_DIMENSION || config.Height < MIN_IMAGE_DIMENSION {
+		return nil, "", fmt.Errorf("image must be at least %dx%d", MIN_IMAGE_DIMENSION, MIN_IMAGE_DIMENSION)
+	}
+	if config.Width * config.Height > MAX_IMAGE_PIXELS {
+		return nil, "", fmt.Errorf("image dimensions are too large")
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("could not decode image: %w", err)
+	}
+	return img, format, nil
+}
+
+// Validates the image, applies its EXIF orientation, scales it down to the standard size and
+// re-encodes it. Re-encoding drops all metadata (EXIF, GPS location, ...) of the original image
+func ProcessImage(data []byte, thumbnail bool) (ProcessedImage, error) {
+	img, format, err := DecodeImage(data)
+	if err != nil {
+		return ProcessedImage{}, err
+	}
+	rgba := ResizeImage(img, IMAGE_MAX_DIMENSION)
+	if format == "jpeg" {
+		rgba = orientImage(rgba, jpegExifOrientation(data))
+	}
+	if IsBlankImage(rgba) {
+		return ProcessedImage{}, fmt.Errorf("image is blank")
+	}
+
+	processed := ProcessedImage{
+		Format: format,
+		Width: rgba.Bounds().Dx(),
+		Height: rgba.Bounds().Dy(),
+		Hash: PerceptualHash(rgba),
+	}
+	if processed.Data, err = EncodeImage(rgba, format); err != nil {
+		return ProcessedImage{}, err
+	}
+	if thumbnail {
+		if processed.Thumbnail, err = EncodeImage(ResizeImage(rgba, IMAGE_THUMBNAIL_DIMENSION), format); err != nil {
+			return ProcessedImage{}, err
+		}
+	}
+	return processed, nil
+}
+
+func EncodeImage(img image.Image, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, &jpeg.Options{ Quality: IMAGE_JPEG_QUALITY })
+	case "png":
+		err = png.Encode(&buf, img)
+	default:
+		err = fmt.Errorf("unsupported image format %s", format)
+	}
+	return buf.Bytes(), err
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Bounds().Min == (image.Point{}) {
+		return rgba
+	}
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+	return rgba
+}
+
+// Scales the image down so its longest side is at most `max_dimension`. Smaller images are not scaled up
+func ResizeImage(img image.Image, max_dimension int) *image.RGBA {
+	src := toRGBA(img)
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	if w <= max_dimension && h <= max_dimension {
+		return src
+	}
+	scale := float64(max_dimension) / float64(max(w, h))
+	return resizeRGBA(
+		src,
+		max(1, int(math.Round(float64(w) * scale))),
+		max(1, int(math.Round(float64(h) * scale))),
+	)
+}
+
+// Box filter resize. Every destination pixel is the average of the source pixels it covers
+func resizeRGBA(src *image.RGBA, dst_w int, dst_h int) *image.RGBA {
+	src_w, src_h := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, dst_w, dst_h))
+	for y := 0; y < dst_h; y++ {
+		y0 := y * src_h / dst_h
+		y1 := max(y0 + 1, (y + 1) * src_h / dst_h)
+		for x := 0; x < dst_w; x++ {
+			x0 := x * src_w / dst_w
+			x1 := max(x0 + 1, (x + 1) * src_w / dst_w)
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				i := sy * src.Stride + x0 * 4
+				for sx := x0; sx < x1; sx++ {
+					r += int(src.Pix[i])
+					g += int(src.Pix[i + 1])
+					b += int(src.Pix[i + 2])
+					a += int(src.Pix[i + 3])
+					n++
+					i += 4
+				}
+			}
+			j := y * dst.Stride + x * 4
+			dst.Pix[j] = uint8(r / n)
+			dst.Pix[j + 1] = uint8(g / n)
+			dst.Pix[j + 2] = uint8(b / n)
+			dst.Pix[j + 3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+func grayPixels(img *image.RGBA) []int {
+	gray := make([]int, 0, img.Bounds().Dx() * img.Bounds().Dy())
+	for i := 0; i + 3 < len(img.Pix); i += 4 {
+		gray = append(gray, (299 * int(img.Pix[i]) + 587 * int(img.Pix[i + 1]) + 114 * int(img.Pix[i + 2])) / 1000)
+	}
+	return gray
+}
+
+// Difference hash (dHash) of the image. Visually similar images have hashes with a small hamming distance
+func PerceptualHash(img image.Image) string {
+	gray := grayPixels(resizeRGBA(toRGBA(img), 9, 8))
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if gray[y * 9 + x] < gray[y * 9 + x + 1] {
+				hash |= 1
+			}
+		}
+	}
+	return fmt.Sprintf("%016x", hash)
+}
+
+// Number of differing bits between two perceptual hashes
+func PerceptualHashDistance(a string, b string) (int, error) {
+	x, err := strconv.ParseUint(a, 16, 64)
+	if err != nil {
+		return 0, err
+	}
+	y, err := strconv.ParseUint(b, 16, 64)
+	if err != nil {
+		return 0, err
+	}
+	return bits.OnesCount64(x ^ y), nil
+}
+
+// Returns true if the image is (almost) a single color
+func IsBlankImage(img image.Image) bool {
+	gray := grayPixels(resizeRGBA(toRGBA(img), 32, 32))
+	var sum, sum_sq float64
+	for _, v := range gray {
+		sum += float64(v)
+		sum_sq += float64(v * v)
+	}
+	n := float64(len(gray))
+	mean := sum / n
+	return math.Sqrt(math.Max(0, sum_sq / n - mean * mean)) < 2
+}
+
+// Reads the EXIF orientation tag (1-8) of a JPEG. Returns 1 if the image has no orientation
+func jpegExifOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	i := 2
+	for i + 4 <= len(data) && data[i] == 0xFF {
+		marker := data[i + 1]
+		if marker == 0xDA {
+			// image data starts, no more metadata
+			break
+		}
+		length := int(binary.BigEndian.Uint16(data[i + 2:]))
+		end := i + 2 + length
+		if end > len(data) {
+			break
+		}
+		if marker == 0xE1 && length > 8 && string(data[i + 4:i + 10]) == "Exif\x00\x00" {
+			return tiffOrientation(data[i + 10:end])
+		}
+		i = end
+	}
+	return 1
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd + 2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for k := 0; k < entries; k++ {
+		entry := ifd + 2 + k * 12
+		if entry + 12 > len(tiff) {
+			break
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry + 8:]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+	return 1
+}
+
+// Rotates and flips the image so it is displayed upright without its EXIF orientation
+func orientImage(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dst_w, dst_h := w, h
+	if orientation >= 5 {
+		dst_w, dst_h = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dst_w, dst_h))
+	for y := 0; y < dst_h; y++ {
+		for x := 0; x < dst_w; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = w - 1 - x, y
+			case 3:
+				sx, sy = w - 1 - x, h - 1 - y
+			case 4:
+				sx, sy = x, h - 1 - y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h - 1 - x
+			case 7:
+				sx, sy = w - 1 - y, h - 1 - x
+			case 8:
+				sx, sy = w - 1 - y, x
+			}
+			copy(dst.Pix[y * dst.Stride + x * 4:][:4], src.Pix[sy * src.Stride + sx * 4:][:4])
+		}
+	}
+	return dst
+}
@@ -0,0 +1,235 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	PRICE_DEAL_MULTI_BUY = "MULTI_BUY"
+	PRICE_DEAL_BUY_X_GET_Y = "BUY_X_GET_Y"
+	PRICE_DEAL_DISCOUNT = "DISCOUNT"
+)
+
+// Structured form of a price condition such as "2 for $5" or "limit 4".
+// `Type` is empty if the condition only has restrictions
+type PriceDeal struct {
+	Type string
+	Quantity *int
+	Price *float64
+	FreeQuantity *int
+	DiscountPercent *float64
+	DiscountAmount *float64
+	RequiresMembership bool
+	PurchaseLimit *int
+	MinimumQuantity *int
+	MinimumPurchaseAmount *float64
+}
+
+const deal_amount = `(\d+(?:\.\d{1,2})?)`
+const deal_count = `(\d+|one|two|three|four|five)`
+
+var (
+	multi_buy_regex = regexp.MustCompile(`\b(\d+)\s*(?:for|/)\s*\$?` + deal_amount + `\b`)
+	bogo_regex = regexp.MustCompile(`\bbogo\b`)
+	buy_get_regex = regexp.MustCompile(`\bbuy\s*` + deal_count + `\s*,?\s*get\s*` + deal_count + `\b`)
+	percent_off_regex = regexp.MustCompile(`\b(\d+(?:\.\d+)?)\s*%\s*off\b`)
+	half_off_regex = regexp.MustCompile(`\bhalf\s*(?:off|price)\b`)
+	amount_off_regex = regexp.MustCompile(`\$` + deal_amount + `\s*off\b`)
+	cents_off_regex = regexp.MustCompile(`\b(\d{1,2})\s*(?:¢|c|cents?)\s*off\b`)
+	membership_regex = regexp.MustCompile(`\b(?:loyalty|members?|membership|rewards?|club|card\s*holders?|with\s*(?:your\s*)?card)\b`)
+	limit_regex = regexp.MustCompile(`\b(?:limit|max(?:imum)?)\s*(?:of\s*)?(\d+)\b`)
+	minimum_quantity_regex = regexp.MustCompile(`\b(?:must\s*buy|when\s*you\s*buy|min(?:imum)?(?:\s*of)?)\s*(\d+)\b`)
+	minimum_purchase_regex = regexp.MustCompile(`\$` + deal_amount + `\s*(?:or\s*more\s*)?(?:purchase|order)\b`)
+)
+
+func parseDealCount(s string) int {
+	switch s {
+	case "one":
+		return 1
+	case "two":
+		return 2
+	case "three":
+		return 3
+	case "four":
+		return 4
+	case "five":
+		return 5
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func parseDealAmount(s string) float64 {
+	amount, _ := strconv.ParseFloat(s, 64)
+	return amount
+}
+
+// Parses a free-form price condition. Unrecognized parts of the condition are ignored
+func ParsePriceDeal(condition string) PriceDeal {
+	text := strings.ToLower(condition)
+	text = strings.ReplaceAll(text, "-", " ")
+	deal := PriceDeal{}
+
+	if m := minimum_purchase_regex.FindStringSubmatch(text); m != nil {
+		amount := parseDealAmount(m[1])
+		deal.MinimumPurchaseAmount = &amount
+		// so "with $25 purchase" isn't read as a deal amount
+		text = strings.Replace(text, m[0], " ", 1)
+	}
+	if m := limit_regex.FindStringSubmatch(text); m != nil {
+		limit := parseDealCount(m[1])
+		if limit > 0 {
+			deal.PurchaseLimit = &limit
+		}
+		text = strings.Replace(text, m[0], " ", 1)
+	}
+	if m := minimum_quantity_regex.FindStringSubmatch(text); m != nil {
+		quantity := parseDealCount(m[1])
+		if quantity > 0 {
+			deal.MinimumQuantity = &quantity
+		}
+		text = strings.Replace(text, m[0], " ", 1)
+	}
+	deal.RequiresMembership = membership_regex.MatchString(text)
+
+	var percent *float64
+	if m := percent_off_regex.FindStringSubmatch(text); m != nil {
+		p := parseDealAmount(m[1])
+		if p > 0 && p <= 100 {
+			percent = &p
+		}
+	} else if half_off_regex.MatchString(text) {
+		p := 50.0
+		percent = &p
+	}
+
+	buy, get := 0, 0
+	if bogo_regex.MatchString(text) {
+		buy, get = 1, 1
+	} else if m := buy_get_regex.FindStringSubmatch(text); m != nil {
+		buy, get = parseDealCount(m[1]), parseDealCount(m[2])
+	}
+	if buy > 0 && get > 0 {
+		if percent == nil {
+			free := 100.0
+			percent = &free
+		}
+		deal.Type = PRICE_DEAL_BUY_X_GET_Y
+		deal.Quantity = &buy
+		deal.FreeQuantity = &get
+		deal.DiscountPercent = percent
+		return deal
+	}
+
+	if m := multi_buy_regex.FindStringSubmatch(text); m != nil {
+		quantity := parseDealCount(m[1])
+		price := parseDealAmount(m[2])
+		if quantity > 1 && price > 0 {
+			deal.Type = PRICE_DEAL_MULTI_BUY
+			deal.Quantity = &quantity
+			deal.Price = &price
+			return deal
+		}
+	}
+
+	if m := amount_off_regex.FindStringSubmatch(text); m != nil {
+		amount := parseDealAmount(m[1])
+		deal.Type = PRICE_DEAL_DISCOUNT
+		deal.DiscountAmount = &amount
+	} else if m := cents_off_regex.FindStringSubmatch(text); m != nil {
+		amount := parseDealAmount(m[1]) / 100
+		deal.Type = PRICE_DEAL_DISCOUNT
+		deal.DiscountAmount = &amount
+	} else if percent != nil {
+		deal.Type = PRICE_DEAL_DISCOUNT
+		deal.DiscountPercent = percent
+	}
+	return deal
+}
+
+// True if the deal has neither a type nor any restrictions
+func (deal PriceDeal) IsEmpty() bool {
+	return deal.Type == "" &&
+		!deal.RequiresMembership &&
+		deal.PurchaseLimit == nil &&
+		deal.MinimumQuantity == nil &&
+		deal.MinimumPurchaseAmount == nil
+}
+
+// Price per unit when buying enough units to get the deal.
+// Restrictions such as a purchase limit don't change the unit price, and amounts off
+// a minimum purchase (i.e. "$5 off with $25 purchase") apply to the order rather than the unit
+func (deal PriceDeal) EffectiveUnitPrice(amount float64) float64 {
+	effective_price := amount
+	switch deal.Type {
+	case PRICE_DEAL_MULTI_BUY:
+		if deal.Quantity != nil && *deal.Quantity > 0 && deal.Price != nil {
+			effective_price = *deal.Price / float64(*deal.Quantity)
+		}
+	case PRICE_DEAL_BUY_X_GET_Y:
+		if deal.Quantity != nil && deal.FreeQuantity != nil {
+			percent := 100.0
+			if deal.DiscountPercent != nil {
+				percent = *deal.DiscountPercent
+			}
+			buy, get := float64(*deal.Quantity), float64(*deal.FreeQuantity)
+			effective_price = (buy * amount + get * amount * (1 - percent / 100)) / (buy + get)
+		}
+	case PRICE_DEAL_DISCOUNT:
+		if deal.DiscountAmount != nil && deal.MinimumPurchaseAmount != nil {
+			break
+		}
+		if deal.DiscountAmount != nil {
+			effective_price = amount - *deal.DiscountAmount
+		} else if deal.DiscountPercent != nil {
+			effective_price = amount * (1 - *deal.DiscountPercent / 100)
+		}
+	}
+	return math.Round(math.Max(effective_price, 0) * 100) / 100
+}
+
+func formatDealPercent(percent float64) string {
+	return strconv.FormatFloat(percent, 'f', -1, 64)
+}
+
+// Short human readable form of the deal. i.e. "2 for $5.00, limit 4"
+func (deal PriceDeal) Description() string {
+	parts := []string{}
+	switch deal.Type {
+	case PRICE_DEAL_MULTI_BUY:
+		if deal.Quantity != nil && deal.Price != nil {
+			parts = append(parts, fmt.Sprintf("%d for $%.2f", *deal.Quantity, *deal.Price))
+		}
+	case PRICE_DEAL_BUY_X_GET_Y:
+		if deal.Quantity != nil && deal.FreeQuantity != nil {
+			get := "free"
+			if deal.DiscountPercent != nil && *deal.DiscountPercent < 100 {
+				get = formatDealPercent(*deal.DiscountPercent) + "% off"
+			}
+			parts = append(parts, fmt.Sprintf("buy %d get %d %s", *deal.Quantity, *deal.FreeQuantity, get))
+		}
+	case PRICE_DEAL_DISCOUNT:
+		if deal.DiscountAmount != nil {
+			parts = append(parts, fmt.Sprintf("$%.2f off", *deal.DiscountAmount))
+		} else if deal.DiscountPercent != nil {
+			parts = append(parts, formatDealPercent(*deal.DiscountPercent) + "% off")
+		}
+	}
+	if deal.RequiresMembership {
+		parts = append(parts, "members only")
+	}
+	if deal.MinimumQuantity != nil {
+		parts = append(parts, fmt.Sprintf("must buy %d", *deal.MinimumQuantity))
+	}
+	if deal.MinimumPurchaseAmount != nil {
+		parts = append(parts, fmt.Sprintf("with $%.2f purchase", *deal.MinimumPurchaseAmount))
+	}
+	if deal.PurchaseLimit != nil {
+		parts = append(parts, fmt.Sprintf("limit %d", *deal.PurchaseLimit))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -1,8 +1,10 @@
 package utils
 
 import (
+	"encoding/base64"
 	"fmt"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -56,6 +58,15 @@ func IsValidBase64Image(base64 string) bool {
 	return strings.HasPrefix(base64, "data:image/") && strings.Contains(base64, ";base64,")
 }
 
+// Decodes the data of a base64 data URI ("data:image/png;base64,...")
+func DecodeBase64Image(data_uri string) ([]byte, error) {
+	if !IsValidBase64Image(data_uri) {
+		return nil, fmt.Errorf("invalid base64 image")
+	}
+	_, encoded, _ := strings.Cut(data_uri, ";base64,")
+	return base64.StdEncoding.DecodeString(encoded)
+}
+
 func buildWeightRegex(unitMap map[string]string) *regexp.Regexp {
 	// Extract all keys from the normalization map
 	units := make([]string, 0, len(unitMap))
@@ -151,3 +162,30 @@ func ParseWeightIntoStruct(raw_weight string) (c WeightComponents, err error) {
 	c.WeightType = parts[1]
 	return c, nil
 }
+
+var priceAmountRegex = regexp.MustCompile(`\$\s?(\d{1,4})(?:[.,](\d{2}))?\b|\b(\d{1,4})[.,](\d{2})\b|\b(\d{1,2})\s?¢`)
+
+// Finds all price amounts ("$3.99", "3,99", "$5", "99¢") in a text, e.g. OCR data of a price tag
+func ExtractPriceAmounts(text string) []float64 {
+	amounts := []float64{}
+	for _, m := range priceAmountRegex.FindAllStringSubmatch(text, -1) {
+		var amount float64
+		switch {
+		case m[1] != "":
+			dollars, _ := strconv.Atoi(m[1])
+			cents, _ := strconv.Atoi(m[2])
+			amount = float64(dollars) + float64(cents) / 100
+		case m[3] != "":
+			dollars, _ := strconv.Atoi(m[3])
+			cents, _ := strconv.Atoi(m[4])
+			amount = float64(dollars) + float64(cents) / 100
+		default:
+			cents, _ := strconv.Atoi(m[5])
+			amount = float64(cents) / 100
+		}
+		if amount > 0 && !slices.Contains(amounts, amount) {
+			amounts = append(amounts, amount)
+		}
+	}
+	return amounts
+}
@@ -0,0 +1,46 @@
+package utils
+
+import "fmt"
+
+// Factors to convert normalized weight units (see `ParseWeight`) into a common unit
+var unitPriceConversions = map[string]struct{
+	Unit string
+	Factor float64
+}{
+	"oz": { "oz", 1 },
+	"lb": { "oz", 16 },
+	"g": { "oz", 0.035274 },
+	"kg": { "oz", 35.274 },
+	"mg": { "oz", 0.000035274 },
+
+	"fl oz": { "fl oz", 1 },
+	"pt": { "fl oz", 16 },
+	"qt": { "fl oz", 32 },
+	"gal": { "fl oz", 128 },
+	"ml": { "fl oz", 0.033814 },
+	"l": { "fl oz", 33.814 },
+	"cup": { "fl oz", 8 },
+	"tbsp": { "fl oz", 0.5 },
+	"tsp": { "fl oz", 1.0 / 6 },
+}
+
+// Converts a weight into its comparable unit. Masses become `oz` and volumes `fl oz`.
+// Other units (ct, pack, serving, ...) are returned unchanged
+func NormalizeUnitWeight(value float64, unit string) (float64, string) {
+	if conversion, ok := unitPriceConversions[unit]; ok {
+		return value * conversion.Factor, conversion.Unit
+	}
+	return value, unit
+}
+
+// Price per unit of weight for `pack_count` items of `weight` each
+func UnitPrice(amount float64, weight float64, unit string, pack_count int) (float64, string, error) {
+	if pack_count < 1 {
+		pack_count = 1
+	}
+	total, normalized_unit := NormalizeUnitWeight(weight * float64(pack_count), unit)
+	if total <= 0 {
+		return 0, "", fmt.Errorf("weight must be positive")
+	}
+	return amount / total, normalized_unit, nil
+}